@@ -18,7 +18,14 @@ COLLECTIONS_CONFIG = {
     "document_chunk": {
         "dim": 1024,
         "description": "文档块向量集合",
-        "shards_num": 1
+        "shards_num": 1,
+        # 标量字段，检索时按用户、知识库和文档过滤；user_id 为分区键，每个用户的块落在同一分区
+        # 已有集合须删除重建，并清空 MySQL 的 document_chunk 表后重新索引全部文档
+        "scalar_fields": [
+            {"name": "user_id", "partition_key": True},
+            {"name": "doc_id"},
+            {"name": "collection_id"}
+        ]
     }
 }
 
@@ -69,6 +76,12 @@ def create_collection(collection_name):
         FieldSchema(name="id", dtype=DataType.INT64, is_primary=True, auto_id=False),
         FieldSchema(name="vector", dtype=DataType.FLOAT_VECTOR, dim=config["dim"])
     ]
+    for field in config.get("scalar_fields", []):
+        fields.append(FieldSchema(
+            name=field["name"],
+            dtype=DataType.INT64,
+            is_partition_key=field.get("partition_key", False)
+        ))
 
     # 创建集合模式
     schema = CollectionSchema(
@@ -93,18 +93,80 @@ CREATE TABLE IF NOT EXISTS `reminder` (
 CREATE TABLE IF NOT EXISTS `document` (
     `doc_id` bigint unsigned NOT NULL COMMENT '文档ID',
     `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+    `collection_id` bigint unsigned NOT NULL DEFAULT 0 COMMENT '所属知识库ID，0 表示未归入知识库，同时写入块向量的标量字段',
     `title` varchar(200) NOT NULL COMMENT '标题',
-    `status` varchar(20) NOT NULL DEFAULT 'active' COMMENT '文档状态(active/archived/deleted)',
+    `status` varchar(20) NOT NULL DEFAULT 'active' COMMENT '文档状态(pending/active/failed/archived/deleted)，pending 表示正在索引',
     `metadata` json DEFAULT NULL COMMENT '元数据',
     `paragraph_count` int unsigned NOT NULL DEFAULT 0 COMMENT '段落数',
     `sentence_count` int unsigned NOT NULL DEFAULT 0 COMMENT '句子数',
     `keywords` json DEFAULT NULL COMMENT '文档关键词及权重',
+    `chunker` json DEFAULT NULL COMMENT '切块策略及参数，重新索引时沿用',
+    `version` int unsigned NOT NULL DEFAULT 0 COMMENT '当前可检索的版本，新版本索引完成后切换',
     `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY (`doc_id`),
-    KEY `idx_user_status` (`user_id`, `status`)
+    KEY `idx_user_status` (`user_id`, `status`),
+    KEY `idx_user_collection` (`user_id`, `collection_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文档表';
 
+-- 知识库表，文档按知识库分组，检索和对话可以限定在选定的知识库中
+CREATE TABLE IF NOT EXISTS `knowledge_collection` (
+    `collection_id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '知识库ID',
+    `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+    `name` varchar(100) NOT NULL COMMENT '名称，同一用户下唯一',
+    `description` varchar(500) NOT NULL DEFAULT '' COMMENT '描述',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`collection_id`),
+    UNIQUE KEY `uk_user_name` (`user_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='知识库表';
+
+-- 文档标签表
+CREATE TABLE IF NOT EXISTS `document_tag` (
+    `doc_id` bigint unsigned NOT NULL COMMENT '文档ID',
+    `tag` varchar(50) NOT NULL COMMENT '标签',
+    `user_id` bigint unsigned NOT NULL COMMENT '文档所属用户ID',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`doc_id`, `tag`),
+    KEY `idx_tag` (`tag`) COMMENT '按标签过滤，共享的文档不属于检索用户，不能按 user_id 查找'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文档标签表';
+
+-- 用户组表，组可以作为知识库和文档的授权对象
+CREATE TABLE IF NOT EXISTS `user_group` (
+    `group_id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '用户组ID',
+    `owner_id` bigint unsigned NOT NULL COMMENT '创建者，管理组成员',
+    `name` varchar(100) NOT NULL COMMENT '名称，同一创建者下唯一',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`group_id`),
+    UNIQUE KEY `uk_owner_name` (`owner_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户组表';
+
+-- 用户组成员表，创建者同时是成员
+CREATE TABLE IF NOT EXISTS `user_group_member` (
+    `group_id` bigint unsigned NOT NULL COMMENT '用户组ID',
+    `user_id` bigint unsigned NOT NULL COMMENT '成员用户ID',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '加入时间',
+    PRIMARY KEY (`group_id`, `user_id`),
+    KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户组成员表';
+
+-- 授权表，把知识库或文档的角色授予用户或用户组；资源的创建者始终是所有者，不在此表中记录
+CREATE TABLE IF NOT EXISTS `access_grant` (
+    `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '记录ID',
+    `resource_type` varchar(20) NOT NULL COMMENT '资源类型(collection/document)，知识库的授权对其中的文档同样生效',
+    `resource_id` bigint unsigned NOT NULL COMMENT '知识库ID或文档ID',
+    `principal_type` varchar(20) NOT NULL COMMENT '授权对象类型(user/group)',
+    `principal_id` bigint unsigned NOT NULL COMMENT '用户ID或用户组ID',
+    `role` varchar(20) NOT NULL COMMENT '角色(owner/editor/viewer)',
+    `granted_by` bigint unsigned NOT NULL COMMENT '授权的用户ID',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_resource_principal` (`resource_type`, `resource_id`, `principal_type`, `principal_id`),
+    KEY `idx_principal` (`principal_type`, `principal_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='授权表';
+
 -- 段落表
 CREATE TABLE IF NOT EXISTS `document_paragraph` (
     `doc_id` bigint unsigned NOT NULL COMMENT '文档ID',
@@ -114,6 +176,7 @@ CREATE TABLE IF NOT EXISTS `document_paragraph` (
     `sentence_id_max` bigint unsigned NOT NULL COMMENT '最大句子ID',
     `keywords` JSON DEFAULT NULL COMMENT '段落关键词及权重',
     `keyword_text` VARCHAR(1024) AS (JSON_UNQUOTE(JSON_EXTRACT(`keywords`, '$[*].word'))) STORED COMMENT '关键词文本（用于全文索引）',
+    `metadata` JSON DEFAULT NULL COMMENT '段落结构信息（类型、标题路径、页码、表格行等）',
     `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY (`doc_id`, `paragraph_id`),
@@ -138,15 +201,72 @@ CREATE TABLE IF NOT EXISTS `document_chunk` (
     `paragraph_id` bigint unsigned NOT NULL COMMENT '段落ID',
     `sentence_id_min` bigint unsigned NOT NULL COMMENT '最小句子ID',
     `sentence_id_max` bigint unsigned NOT NULL COMMENT '最大句子ID',
-    `keywords` JSON DEFAULT NULL COMMENT '块关键词及权重',
-    `keyword_text` VARCHAR(1024) AS (JSON_UNQUOTE(JSON_EXTRACT(`keywords`, '$[*].word'))) STORED COMMENT '关键词文本（用于全文索引）',
+    `content` text DEFAULT NULL COMMENT '向量化的文本',
+    `version` int unsigned NOT NULL DEFAULT 0 COMMENT '所属文档版本，只检索与文档当前版本一致的块',
+    `keywords` JSON DEFAULT NULL COMMENT '块关键词',
     `embedding` blob DEFAULT NULL COMMENT '块的向量嵌入',
     `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
     PRIMARY KEY (`chunk_id`),
-    FULLTEXT KEY `idx_keyword_text` (`keyword_text`)
+    KEY `idx_doc_id` (`doc_id`),
+    FULLTEXT KEY `idx_content` (`content`) WITH PARSER ngram COMMENT '关键词检索，与向量检索按 RRF 融合'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文档块表';
 
+-- 文档版本表，每次内容或切块配置变化生成一个不可变的版本
+CREATE TABLE IF NOT EXISTS `document_version` (
+    `doc_id` bigint unsigned NOT NULL COMMENT '文档ID',
+    `version` int unsigned NOT NULL COMMENT '版本号，从 1 开始递增',
+    `user_id` bigint unsigned NOT NULL COMMENT '创建版本的用户ID',
+    `content_hash` char(64) NOT NULL COMMENT '段落内容的 SHA-256',
+    `content` longtext NOT NULL COMMENT '段落及结构信息，恢复版本时使用',
+    `chunker` json DEFAULT NULL COMMENT '切块策略及参数',
+    `embedding_model` varchar(100) NOT NULL DEFAULT '' COMMENT '向量化模型',
+    `paragraph_count` int unsigned NOT NULL DEFAULT 0 COMMENT '段落数',
+    `sentence_count` int unsigned NOT NULL DEFAULT 0 COMMENT '句子数',
+    `restored_from` int unsigned NOT NULL DEFAULT 0 COMMENT '从哪个版本恢复，0 表示编辑产生',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`doc_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文档版本表';
+
+-- 模型用量表
+CREATE TABLE IF NOT EXISTS `model_usage` (
+    `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '记录ID',
+    `user_id` bigint unsigned NOT NULL COMMENT '用户ID，0 表示系统调用',
+    `session_id` bigint unsigned NOT NULL DEFAULT 0 COMMENT '会话ID',
+    `model` varchar(100) NOT NULL COMMENT '模型名称',
+    `call_type` varchar(20) NOT NULL COMMENT '调用类型(chat/intent/sentiment/summary/embedding)',
+    `input_tokens` int NOT NULL DEFAULT 0 COMMENT '输入token数',
+    `output_tokens` int NOT NULL DEFAULT 0 COMMENT '输出token数',
+    `total_tokens` int NOT NULL DEFAULT 0 COMMENT '总token数',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_user_created` (`user_id`, `created_at`),
+    KEY `idx_session_id` (`session_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='模型用量表';
+
+-- 文档索引任务表
+CREATE TABLE IF NOT EXISTS `document_index_job` (
+    `job_id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '任务ID',
+    `doc_id` bigint unsigned NOT NULL COMMENT '文档ID',
+    `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+    `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT '任务状态(pending/running/succeeded/failed)',
+    `total_chunks` int unsigned NOT NULL DEFAULT 0 COMMENT '块总数',
+    `done_chunks` int unsigned NOT NULL DEFAULT 0 COMMENT '已完成的块数',
+    `reused_chunks` int unsigned NOT NULL DEFAULT 0 COMMENT '内容未变、沿用原向量的块数',
+    `attempts` int unsigned NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+    `max_attempts` int unsigned NOT NULL DEFAULT 3 COMMENT '最大尝试次数',
+    `last_error` text DEFAULT NULL COMMENT '最近一次失败原因',
+    `worker` varchar(64) NOT NULL DEFAULT '' COMMENT '执行任务的实例',
+    `next_run_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最早执行时间，失败重试时延后',
+    `started_at` timestamp NULL DEFAULT NULL COMMENT '最近一次开始时间',
+    `finished_at` timestamp NULL DEFAULT NULL COMMENT '完成时间',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间，运行中作为心跳',
+    PRIMARY KEY (`job_id`),
+    KEY `idx_status_next_run` (`status`, `next_run_at`),
+    KEY `idx_doc_id` (`doc_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文档索引任务表';
+
 -- ID生成器表
 CREATE TABLE IF NOT EXISTS `id_generator` (
     `id_name` varchar(50) NOT NULL COMMENT 'ID名称',
@@ -4,21 +4,29 @@ package api_service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	api_service "server/api_service/biz/model/api_service"
+	"server/framework/config"
 	"server/framework/logger"
+	"server/framework/mongodb"
+	"server/service/rag_svr/ingest"
 	"server/service/rag_svr/kitex_gen/rag_svr"
 	ragservice "server/service/rag_svr/kitex_gen/rag_svr/ragservice"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/common/utils"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/cloudwego/hertz/pkg/protocol/sse"
 	"github.com/cloudwego/kitex/client/callopt"
 	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // Ping .
@@ -32,7 +40,7 @@ func Ping(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	logger.Infof("Ping接口收到请求: %+v", req)
+	logger.Infof("Ping接口收到请求: %+v", &req)
 
 	resp := new(api_service.PingRsp)
 	resp.Code = 0
@@ -222,6 +230,53 @@ func EndSession(ctx context.Context, c *app.RequestContext) {
 	})
 }
 
+// UpdateSession 修改会话标题
+// @router /session/update [POST]
+func UpdateSession(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.UpdateSessionReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("请求参数错误: %v", err),
+		})
+		return
+	}
+	if req.SessionId == 0 || req.UserId == 0 || strings.TrimSpace(req.Title) == "" {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "session_id、user_id 和 title 不能为空",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.UpdateSession(ctx, &rag_svr.UpdateSessionReq{
+		SessionId: req.SessionId,
+		UserId:    req.UserId,
+		Title:     req.Title,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // AddDocument .
 // @router /document/add [POST]
 func AddDocument(ctx context.Context, c *app.RequestContext) {
@@ -230,205 +285,1242 @@ func AddDocument(ctx context.Context, c *app.RequestContext) {
 	var req api_service.AddDocumentReq
 	err = c.BindAndValidate(&req)
 	if err != nil {
-		logger.Errorf("AddDocument BindAndValidate error: %v", err)
-		c.String(consts.StatusBadRequest, err.Error())
+		logger.Errorf("AddDocument BindAndValidate error: %v", err)
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		logger.Errorf("AddDocument client not found")
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": "客户端未初始化",
+		})
+		return
+	}
+
+	// 切块配置、所属知识库和标签不在生成的模型中，从请求体中单独读取
+	var chunking struct {
+		Collection   string                 `json:"collection"`
+		Chunker      *rag_svr.ChunkerConfig `json:"chunker"`
+		CollectionId uint64                 `json:"collection_id"`
+		Tags         []string               `json:"tags"`
+	}
+	if err := json.Unmarshal(c.Request.Body(), &chunking); err != nil {
+		c.String(consts.StatusBadRequest, fmt.Sprintf("无效的切块配置: %v", err))
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.AddDocument(
+		ctx,
+		&rag_svr.AddDocumentReq{
+			UserId:       req.UserId,
+			Title:        req.Title,
+			Content:      req.Content,
+			Metadata:     req.Metadata,
+			MimeType:     c.Query("mime_type"),
+			Collection:   chunking.Collection,
+			Chunker:      chunking.Chunker,
+			CollectionId: chunking.CollectionId,
+			Tags:         chunking.Tags,
+		},
+		callopt.WithRPCTimeout(60*time.Second),
+	)
+	if err != nil {
+		logger.Errorf("AddDocument error: %v", err)
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	logger.Infof("AddDocument success,code=%d, msg=%s, doc_id=%d", resp.Code, resp.Msg, resp.DocId)
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":   resp.Code,
+		"msg":    resp.Msg,
+		"doc_id": resp.DocId,
+	})
+}
+
+// UploadDocument 上传文件并添加为文档，原始文件保存到 MongoDB GridFS，rag_svr 按文件ID读取
+// 表单字段：file（必填）、user_id（必填）、title、metadata、mime_type（为空时根据文件名和内容识别）、
+// collection（知识库名称）、chunker（JSON 格式的切块配置）、collection_id（所属知识库ID）、tags（逗号分隔的标签）
+// @router /document/upload [POST]
+func UploadDocument(ctx context.Context, c *app.RequestContext) {
+	userId, err := strconv.ParseUint(c.PostForm("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "user_id 不能为空",
+		})
+		return
+	}
+	var collectionId uint64
+	if raw := c.PostForm("collection_id"); raw != "" {
+		if collectionId, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+				Code: 1,
+				Msg:  "无效的 collection_id",
+			})
+			return
+		}
+	}
+	var chunkerConfig *rag_svr.ChunkerConfig
+	if raw := c.PostForm("chunker"); raw != "" {
+		chunkerConfig = &rag_svr.ChunkerConfig{}
+		if err := json.Unmarshal([]byte(raw), chunkerConfig); err != nil {
+			c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+				Code: 1,
+				Msg:  fmt.Sprintf("无效的切块配置: %v", err),
+			})
+			return
+		}
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "缺少上传文件 file",
+		})
+		return
+	}
+
+	// 限制文件大小，请求体大小已由服务端限制，这里按文件本身再校验一次
+	maxSize := config.MaxUploadSize()
+	if fileHeader.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("文件大小不能超过 %dMB", maxSize>>20),
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Errorf("UploadDocument open file error: %v", err)
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "读取上传文件失败",
+		})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	file.Close()
+	if err != nil || len(data) == 0 || int64(len(data)) > maxSize {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "上传文件为空或读取失败",
+		})
+		return
+	}
+
+	// 识别内容类型，并校验内容与类型一致
+	mimeType := ingest.DetectMIME(c.PostForm("mime_type"), fileHeader.Filename, data)
+	if !ingest.Supported(mimeType) {
+		c.JSON(http.StatusUnsupportedMediaType, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("不支持的文件类型: %s", mimeType),
+		})
+		return
+	}
+	if err := ingest.CheckContent(mimeType, data); err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, api_service.BaseRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	if mongodb.GetDB() == nil {
+		c.JSON(http.StatusServiceUnavailable, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "文件存储不可用",
+		})
+		return
+	}
+	fileID, err := mongodb.UploadFile(ctx, mongodb.DocumentBucket, fileHeader.Filename, data, bson.M{
+		"user_id":   userId,
+		"mime_type": mimeType,
+	})
+	if err != nil {
+		logger.Errorf("UploadDocument save file error: %v", err)
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "保存文件失败",
+		})
+		return
+	}
+	logger.Infof("UploadDocument file saved: file_id=%s, name=%s, size=%d, mime_type=%s", fileID.Hex(), fileHeader.Filename, len(data), mimeType)
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.AddDocumentFile(
+		ctx,
+		&rag_svr.AddDocumentFileReq{
+			UserId:       userId,
+			Title:        c.PostForm("title"),
+			Metadata:     c.PostForm("metadata"),
+			FileId:       fileID.Hex(),
+			FileName:     fileHeader.Filename,
+			MimeType:     mimeType,
+			Collection:   c.PostForm("collection"),
+			Chunker:      chunkerConfig,
+			CollectionId: collectionId,
+			Tags:         parseTagList(c.PostForm("tags")),
+		},
+		callopt.WithRPCTimeout(120*time.Second),
+	)
+	// 添加文档失败时删除已保存的文件
+	if err != nil || resp.Code != 0 {
+		if delErr := mongodb.DeleteFile(ctx, mongodb.DocumentBucket, fileID); delErr != nil {
+			logger.Warnf("UploadDocument delete file error: file_id=%s, err=%v", fileID.Hex(), delErr)
+		}
+	}
+	if err != nil {
+		logger.Errorf("UploadDocument error: %v", err)
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.H{
+		"code":    resp.Code,
+		"msg":     resp.Msg,
+		"doc_id":  resp.DocId,
+		"file_id": fileID.Hex(),
+	})
+}
+
+// SearchDocument .
+// @router /document/search [GET]
+func SearchDocument(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req api_service.SearchDocumentReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": "客户端未初始化",
+		})
+		return
+	}
+
+	// 检索权重、重排分数下限、相邻句子数、检索范围和过滤条件不在生成的模型中，从查询参数中单独读取
+	params := make(map[string]float32, 3)
+	for _, name := range []string{"vector_weight", "keyword_weight", "min_score"} {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.ParseFloat(raw, 32)
+			if err != nil || value < 0 {
+				c.String(consts.StatusBadRequest, fmt.Sprintf("无效的 %s", name))
+				return
+			}
+			params[name] = float32(value)
+		}
+	}
+	var contextSentences uint64
+	if raw := c.Query("context_sentences"); raw != "" {
+		var err error
+		if contextSentences, err = strconv.ParseUint(raw, 10, 32); err != nil {
+			c.String(consts.StatusBadRequest, "无效的 context_sentences")
+			return
+		}
+	}
+	scope := make(map[string][]uint64, 2)
+	for _, name := range []string{"collection_ids", "doc_ids"} {
+		ids, err := parseIDList(c.Query(name))
+		if err != nil {
+			c.String(consts.StatusBadRequest, fmt.Sprintf("无效的 %s", name))
+			return
+		}
+		scope[name] = ids
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	// 调用 rag_svr 的 SearchDocument 方法
+	resp, err := ragSvrClient.SearchDocument(ctx, &rag_svr.SearchDocumentReq{
+		UserId:           req.UserId,
+		Query:            req.Query,
+		TopK:             req.TopK,
+		VectorWeight:     params["vector_weight"],
+		KeywordWeight:    params["keyword_weight"],
+		MinScore:         params["min_score"],
+		ContextSentences: uint32(contextSentences),
+		CollectionIds:    scope["collection_ids"],
+		DocIds:           scope["doc_ids"],
+		Tags:             parseTagList(c.Query("tags")),
+		MetadataFilters:  parseMetadataFilters(c),
+	})
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	// 构建响应
+	results := make([]map[string]interface{}, 0)
+	for i, doc := range resp.Documents {
+		result := map[string]interface{}{
+			"doc_id":      doc.DocId,
+			"title":       doc.Title,
+			"content":     doc.Content,
+			"score":       resp.Scores[i],
+			"create_time": doc.CreateTime,
+			"role":        doc.Role,
+		}
+		if i < len(resp.Hits) {
+			hit := resp.Hits[i]
+			result["chunk_id"] = hit.ChunkId
+			result["paragraph_id"] = hit.ParagraphId
+			result["sentence_id_min"] = hit.SentenceIdMin
+			result["sentence_id_max"] = hit.SentenceIdMax
+			result["highlights"] = hit.Highlights
+			result["context_before"] = hit.ContextBefore
+			result["context_after"] = hit.ContextAfter
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    resp.Code,
+		"msg":     resp.Msg,
+		"results": results,
+	})
+}
+
+// parseIDList 解析逗号分隔的ID列表，为空时返回 nil
+func parseIDList(raw string) ([]uint64, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	ids := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("无效的ID: %s", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseTagList 解析逗号分隔的标签列表，为空时返回 nil
+func parseTagList(raw string) []string {
+	var tags []string
+	for _, tag := range strings.Split(raw, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// 元数据过滤的查询参数前缀，如 metadata.dept=HR 表示 metadata 中 dept 字段等于 HR
+const metadataFilterPrefix = "metadata."
+
+// parseMetadataFilters 从 metadata.<字段路径> 形式的查询参数中读取元数据过滤条件
+func parseMetadataFilters(c *app.RequestContext) map[string]string {
+	var filters map[string]string
+	c.QueryArgs().VisitAll(func(key, value []byte) {
+		name := string(key)
+		if !strings.HasPrefix(name, metadataFilterPrefix) || len(name) == len(metadataFilterPrefix) {
+			return
+		}
+		if filters == nil {
+			filters = make(map[string]string)
+		}
+		filters[strings.TrimPrefix(name, metadataFilterPrefix)] = string(value)
+	})
+	return filters
+}
+
+// CreateUser .
+// @router /user/create [POST]
+func CreateUser(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req api_service.CreateUserReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	// 调用 rag_svr 的 CreateUser 方法
+	resp, err := ragSvrClient.CreateUser(ctx, &rag_svr.CreateUserReq{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":        resp.Code,
+		"msg":         resp.Msg,
+		"user_id":     resp.UserInfo.UserId,
+		"username":    resp.UserInfo.UserName,
+		"email":       resp.UserInfo.Email,
+		"create_time": resp.UserInfo.CreateTime,
+	})
+}
+
+// ListDocument .
+// @router /document/list [GET]
+func ListDocument(ctx context.Context, c *app.RequestContext) {
+	var err error
+	var req api_service.ListDocumentReq
+	err = c.BindAndValidate(&req)
+	if err != nil {
+		c.String(consts.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	// 知识库、标签和元数据过滤不在生成的模型中，从查询参数中单独读取
+	var collectionId uint64
+	if raw := c.Query("collection_id"); raw != "" {
+		if collectionId, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			c.String(consts.StatusBadRequest, "无效的 collection_id")
+			return
+		}
+	}
+	collectionIds, err := parseIDList(c.Query("collection_ids"))
+	if err != nil {
+		c.String(consts.StatusBadRequest, "无效的 collection_ids")
+		return
+	}
+
+	// 调用 rag_svr 的 ListDocument 方法
+	resp, err := ragSvrClient.ListDocument(ctx, &rag_svr.ListDocumentReq{
+		UserId:          req.UserId,
+		Page:            req.Page,
+		PageSize:        req.PageSize,
+		CollectionId:    collectionId,
+		CollectionIds:   collectionIds,
+		Tags:            parseTagList(c.Query("tags")),
+		MetadataFilters: parseMetadataFilters(c),
+	})
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{
+			"error": fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	// 构建响应
+	documents := make([]map[string]interface{}, 0)
+	for _, doc := range resp.Documents {
+		documents = append(documents, map[string]interface{}{
+			"doc_id":        doc.DocId,
+			"title":         doc.Title,
+			"content":       doc.Content,
+			"metadata":      doc.Metadata,
+			"create_time":   doc.CreateTime,
+			"update_time":   doc.UpdateTime,
+			"status":        doc.Status,
+			"chunker":       doc.Chunker,
+			"version":       doc.Version,
+			"collection_id": doc.CollectionId,
+			"tags":          doc.Tags,
+			"role":          doc.Role,
+		})
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":      resp.Code,
+		"msg":       resp.Msg,
+		"total":     resp.Total,
+		"page":      resp.Page,
+		"page_size": resp.PageSize,
+		"documents": documents,
+	})
+}
+
+// GetDocumentStatus 查询文档状态和索引进度，文档添加后异步索引，status 为 active 时可以检索
+// @router /document/status [GET]
+func GetDocumentStatus(ctx context.Context, c *app.RequestContext) {
+	docId, err := strconv.ParseUint(c.Query("doc_id"), 10, 64)
+	if err != nil || docId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 doc_id",
+		})
+		return
+	}
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.GetDocumentStatus(ctx, &rag_svr.GetDocumentStatusReq{
+		DocId:  docId,
+		UserId: userId,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateDocument 更新文档，未指定的字段保持不变，请求体字段同 rag_svr.UpdateDocumentReq
+// @router /document/update [POST]
+func UpdateDocument(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.UpdateDocumentReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.DocId == 0 || req.UserId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 doc_id 或 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.UpdateDocument(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListDocumentVersions 获取文档保留的版本，按版本号从新到旧
+// @router /document/versions [GET]
+func ListDocumentVersions(ctx context.Context, c *app.RequestContext) {
+	docId, err := strconv.ParseUint(c.Query("doc_id"), 10, 64)
+	if err != nil || docId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 doc_id",
+		})
+		return
+	}
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.ListDocumentVersions(ctx, &rag_svr.ListDocumentVersionsReq{
+		DocId:  docId,
+		UserId: userId,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreDocumentVersion 将文档恢复为指定版本，恢复结果作为新版本重新索引，索引完成前检索的仍是当前版本
+// @router /document/restore [POST]
+func RestoreDocumentVersion(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.RestoreDocumentVersionReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.DocId == 0 || req.UserId == 0 || req.Version == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 doc_id、user_id 或 version",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.RestoreDocumentVersion(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MoveDocument 把文档移到另一个知识库，collection_id 为 0 表示移出知识库，请求体字段同 rag_svr.MoveDocumentReq
+// @router /document/move [POST]
+func MoveDocument(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.MoveDocumentReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.DocId == 0 || req.UserId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 doc_id 或 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	// 需要重写文档全部块的向量字段，大文档耗时较长
+	resp, err := ragSvrClient.MoveDocument(ctx, &req, callopt.WithRPCTimeout(60*time.Second))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetDocumentTags 替换文档的全部标签，tags 为空时清除标签，请求体字段同 rag_svr.SetDocumentTagsReq
+// @router /document/tags [POST]
+func SetDocumentTags(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.SetDocumentTagsReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.DocId == 0 || req.UserId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 doc_id 或 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.SetDocumentTags(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateCollection 创建知识库，名称在同一用户下唯一，请求体字段同 rag_svr.CreateCollectionReq
+// @router /collection/create [POST]
+func CreateCollection(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.CreateCollectionReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.UserId == 0 || strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id 或 name",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.CreateCollection(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListCollections 获取用户创建的和被共享的知识库、各知识库的文档数和用户的角色
+// @router /collection/list [GET]
+func ListCollections(ctx context.Context, c *app.RequestContext) {
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.ListCollections(ctx, &rag_svr.ListCollectionsReq{
+		UserId: userId,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateCollection 修改知识库名称和描述，name 为空时不修改名称，请求体字段同 rag_svr.UpdateCollectionReq
+// @router /collection/update [POST]
+func UpdateCollection(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.UpdateCollectionReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.CollectionId == 0 || req.UserId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 collection_id 或 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.UpdateCollection(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteCollection 删除知识库，知识库中还有文档时不能删除
+// @router /collection/delete [DELETE]
+func DeleteCollection(ctx context.Context, c *app.RequestContext) {
+	collectionId, err := strconv.ParseUint(c.Query("collection_id"), 10, 64)
+	if err != nil || collectionId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 collection_id",
+		})
+		return
+	}
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.DeleteCollection(ctx, &rag_svr.DeleteCollectionReq{
+		CollectionId: collectionId,
+		UserId:       userId,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GrantAccess 把知识库或文档的角色（owner/editor/viewer）授予用户或用户组，已有授权时改为新的角色，只有所有者可以授权，请求体字段同 rag_svr.GrantAccessReq
+// @router /access/grant [POST]
+func GrantAccess(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.GrantAccessReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.UserId == 0 || req.ResourceId == 0 || req.PrincipalId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id、resource_id 或 principal_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.GrantAccess(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeAccess 撤销用户或用户组在知识库或文档上的授权，请求体字段同 rag_svr.RevokeAccessReq
+// @router /access/revoke [POST]
+func RevokeAccess(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.RevokeAccessReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.UserId == 0 || req.ResourceId == 0 || req.PrincipalId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id、resource_id 或 principal_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.RevokeAccess(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListAccess 获取知识库或文档的创建者和授权列表，参数为 user_id、resource_type（collection/document）和 resource_id
+// @router /access/list [GET]
+func ListAccess(ctx context.Context, c *app.RequestContext) {
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
+		return
+	}
+	resourceId, err := strconv.ParseUint(c.Query("resource_id"), 10, 64)
+	if err != nil || resourceId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 resource_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.ListAccess(ctx, &rag_svr.ListAccessReq{
+		UserId:       userId,
+		ResourceType: c.Query("resource_type"),
+		ResourceId:   resourceId,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateGroup 创建用户组，创建者自动成为成员，请求体字段同 rag_svr.CreateGroupReq
+// @router /group/create [POST]
+func CreateGroup(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.CreateGroupReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.UserId == 0 || strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id 或 name",
+		})
 		return
 	}
 
 	// 从上下文中获取客户端
 	client, exists := c.Get("rag_svr_client")
 	if !exists {
-		logger.Errorf("AddDocument client not found")
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": "客户端未初始化",
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
 		})
 		return
 	}
 
 	ragSvrClient := client.(ragservice.Client)
 
-	resp, err := ragSvrClient.AddDocument(
-		ctx,
-		&rag_svr.AddDocumentReq{
-			UserId:   req.UserId,
-			Title:    req.Title,
-			Content:  req.Content,
-			Metadata: req.Metadata,
-		},
-		callopt.WithRPCTimeout(60*time.Second),
-	)
+	resp, err := ragSvrClient.CreateGroup(ctx, &req)
 	if err != nil {
-		logger.Errorf("AddDocument error: %v", err)
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": fmt.Sprintf("调用服务失败: %v", err),
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
 		})
 		return
 	}
 
-	logger.Infof("AddDocument success,code=%d, msg=%s, doc_id=%d", resp.Code, resp.Msg, resp.DocId)
-
-	c.JSON(consts.StatusOK, utils.H{
-		"code":   resp.Code,
-		"msg":    resp.Msg,
-		"doc_id": resp.DocId,
-	})
+	c.JSON(http.StatusOK, resp)
 }
 
-// SearchDocument .
-// @router /document/search [GET]
-func SearchDocument(ctx context.Context, c *app.RequestContext) {
-	var err error
-	var req api_service.SearchDocumentReq
-	err = c.BindAndValidate(&req)
-	if err != nil {
-		c.String(consts.StatusBadRequest, err.Error())
+// AddGroupMembers 向用户组添加成员，只有创建者可以操作，请求体字段同 rag_svr.AddGroupMembersReq
+// @router /group/members/add [POST]
+func AddGroupMembers(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.AddGroupMembersReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.UserId == 0 || req.GroupId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id 或 group_id",
+		})
 		return
 	}
 
 	// 从上下文中获取客户端
 	client, exists := c.Get("rag_svr_client")
 	if !exists {
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": "客户端未初始化",
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
 		})
 		return
 	}
 
 	ragSvrClient := client.(ragservice.Client)
 
-	// 调用 rag_svr 的 SearchDocument 方法
-	resp, err := ragSvrClient.SearchDocument(ctx, &rag_svr.SearchDocumentReq{
-		UserId: req.UserId,
-		Query:  req.Query,
-		TopK:   req.TopK,
-	})
+	resp, err := ragSvrClient.AddGroupMembers(ctx, &req)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": fmt.Sprintf("调用服务失败: %v", err),
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
 		})
 		return
 	}
 
-	// 构建响应
-	results := make([]map[string]interface{}, 0)
-	for i, doc := range resp.Documents {
-		results = append(results, map[string]interface{}{
-			"doc_id":      doc.DocId,
-			"title":       doc.Title,
-			"content":     doc.Content,
-			"score":       resp.Scores[i],
-			"create_time": doc.CreateTime,
-		})
-	}
-
-	c.JSON(consts.StatusOK, utils.H{
-		"code":    resp.Code,
-		"msg":     resp.Msg,
-		"results": results,
-	})
+	c.JSON(http.StatusOK, resp)
 }
 
-// CreateUser .
-// @router /user/create [POST]
-func CreateUser(ctx context.Context, c *app.RequestContext) {
-	var err error
-	var req api_service.CreateUserReq
-	err = c.BindAndValidate(&req)
-	if err != nil {
-		c.String(consts.StatusBadRequest, err.Error())
+// RemoveGroupMembers 从用户组移除成员，创建者不能被移除，请求体字段同 rag_svr.RemoveGroupMembersReq
+// @router /group/members/remove [POST]
+func RemoveGroupMembers(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.RemoveGroupMembersReq
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("无效的请求: %v", err),
+		})
+		return
+	}
+	if req.UserId == 0 || req.GroupId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id 或 group_id",
+		})
 		return
 	}
 
 	// 从上下文中获取客户端
 	client, exists := c.Get("rag_svr_client")
 	if !exists {
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": "客户端未初始化",
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
 		})
 		return
 	}
 
 	ragSvrClient := client.(ragservice.Client)
 
-	// 调用 rag_svr 的 CreateUser 方法
-	resp, err := ragSvrClient.CreateUser(ctx, &rag_svr.CreateUserReq{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: req.Password,
-	})
+	resp, err := ragSvrClient.RemoveGroupMembers(ctx, &req)
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": fmt.Sprintf("调用服务失败: %v", err),
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
 		})
 		return
 	}
 
-	c.JSON(consts.StatusOK, utils.H{
-		"code":        resp.Code,
-		"msg":         resp.Msg,
-		"user_id":     resp.UserInfo.UserId,
-		"username":    resp.UserInfo.UserName,
-		"email":       resp.UserInfo.Email,
-		"create_time": resp.UserInfo.CreateTime,
-	})
+	c.JSON(http.StatusOK, resp)
 }
 
-// ListDocument .
-// @router /document/list [GET]
-func ListDocument(ctx context.Context, c *app.RequestContext) {
-	var err error
-	var req api_service.ListDocumentReq
-	err = c.BindAndValidate(&req)
-	if err != nil {
-		c.String(consts.StatusBadRequest, err.Error())
+// ListGroups 获取用户所在的用户组及其成员
+// @router /group/list [GET]
+func ListGroups(ctx context.Context, c *app.RequestContext) {
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
 		return
 	}
 
 	// 从上下文中获取客户端
 	client, exists := c.Get("rag_svr_client")
 	if !exists {
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": "客户端未初始化",
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
 		})
 		return
 	}
 
 	ragSvrClient := client.(ragservice.Client)
 
-	// 调用 rag_svr 的 ListDocument 方法
-	resp, err := ragSvrClient.ListDocument(ctx, &rag_svr.ListDocumentReq{
-		UserId:   req.UserId,
-		Page:     req.Page,
-		PageSize: req.PageSize,
+	resp, err := ragSvrClient.ListGroups(ctx, &rag_svr.ListGroupsReq{
+		UserId: userId,
 	})
 	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{
-			"error": fmt.Sprintf("调用服务失败: %v", err),
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
 		})
 		return
 	}
 
-	// 构建响应
-	documents := make([]map[string]interface{}, 0)
-	for _, doc := range resp.Documents {
-		documents = append(documents, map[string]interface{}{
-			"doc_id":      doc.DocId,
-			"title":       doc.Title,
-			"content":     doc.Content,
-			"metadata":    doc.Metadata,
-			"create_time": doc.CreateTime,
-			"update_time": doc.UpdateTime,
-		})
-	}
-
-	c.JSON(consts.StatusOK, utils.H{
-		"code":      resp.Code,
-		"msg":       resp.Msg,
-		"total":     resp.Total,
-		"page":      resp.Page,
-		"page_size": resp.PageSize,
-		"documents": documents,
-	})
+	c.JSON(http.StatusOK, resp)
 }
 
 // GetSession .
@@ -907,6 +1999,77 @@ func AddChatRecord(ctx context.Context, c *app.RequestContext) {
 	})
 }
 
+// StreamChat 流式对话，以 SSE 推送回复分片
+// @router /chat/stream [POST]
+func StreamChat(ctx context.Context, c *app.RequestContext) {
+	var req rag_svr.ChatReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("解析请求失败: %v", err),
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_stream_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrStreamClient := client.(ragservice.StreamClient)
+
+	// 浏览器断开后取消下游流，rag_svr 会随之取消模型请求
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := ragSvrStreamClient.StreamChat(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	w := sse.NewWriter(c)
+	defer w.Close()
+
+	for {
+		rsp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			logger.Errorf("接收流式分片失败: %v", err)
+			data, _ := json.Marshal(api_service.BaseRsp{
+				Code: 1,
+				Msg:  fmt.Sprintf("接收流式分片失败: %v", err),
+			})
+			w.WriteEvent("", "error", data)
+			return
+		}
+
+		data, err := json.Marshal(rsp)
+		if err != nil {
+			logger.Errorf("序列化流式分片失败: %v", err)
+			return
+		}
+		if err := w.WriteEvent("", "message", data); err != nil {
+			logger.Infof("客户端已断开, 取消流式对话: session_id=%d, err=%v", req.SessionId, err)
+			return
+		}
+
+		if rsp.Code != 0 || rsp.Finished {
+			return
+		}
+	}
+}
+
 // GetChatRecords 获取聊天记录
 func GetChatRecords(ctx context.Context, c *app.RequestContext) {
 	sessionId := c.Query("session_id")
@@ -999,6 +2162,73 @@ func GetChatRecords(ctx context.Context, c *app.RequestContext) {
 	})
 }
 
+// GetUsage 查询用户的模型用量和配额
+// @router /usage/get [GET]
+func GetUsage(ctx context.Context, c *app.RequestContext) {
+	userId, err := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	if err != nil || userId == 0 {
+		c.JSON(http.StatusBadRequest, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "无效的 user_id",
+		})
+		return
+	}
+
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.GetUsage(ctx, &rag_svr.GetUsageReq{
+		UserId:    userId,
+		StartDate: c.Query("start_date"),
+		EndDate:   c.Query("end_date"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetModelStatus 查询对话和向量化模型的熔断状态和调用统计
+// @router /model/status [GET]
+func GetModelStatus(ctx context.Context, c *app.RequestContext) {
+	// 从上下文中获取客户端
+	client, exists := c.Get("rag_svr_client")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  "客户端未初始化",
+		})
+		return
+	}
+
+	ragSvrClient := client.(ragservice.Client)
+
+	resp, err := ragSvrClient.GetModelStatus(ctx, &rag_svr.GetModelStatusReq{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, api_service.BaseRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用服务失败: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // GetWeather .
 // @router /weather/get [GET]
 func GetWeather(ctx context.Context, c *app.RequestContext) {
@@ -20,20 +20,51 @@ func Register(r *server.Hertz) {
 	root.GET("/ping", append(_pingMw(), api_service.Ping)...)
 	root.GET("/test", append(_testMw(), api_service.Test)...)
 	root.POST("/test2", append(_test2Mw(), api_service.Test2)...)
+	{
+		_access := root.Group("/access", _accessMw()...)
+		_access.POST("/grant", append(_grantaccessMw(), api_service.GrantAccess)...)
+		_access.GET("/list", append(_listaccessMw(), api_service.ListAccess)...)
+		_access.POST("/revoke", append(_revokeaccessMw(), api_service.RevokeAccess)...)
+	}
 	{
 		_chat := root.Group("/chat", _chatMw()...)
 		_chat.POST("/record", append(_addchatrecordMw(), api_service.AddChatRecord)...)
+		_chat.POST("/stream", append(_streamchatMw(), api_service.StreamChat)...)
 		{
 			_records := _chat.Group("/records", _recordsMw()...)
 			_records.GET("/get", append(_getchatrecordsMw(), api_service.GetChatRecords)...)
 		}
 	}
+	{
+		_collection := root.Group("/collection", _collectionMw()...)
+		_collection.POST("/create", append(_createcollectionMw(), api_service.CreateCollection)...)
+		_collection.DELETE("/delete", append(_deletecollectionMw(), api_service.DeleteCollection)...)
+		_collection.GET("/list", append(_listcollectionsMw(), api_service.ListCollections)...)
+		_collection.POST("/update", append(_updatecollectionMw(), api_service.UpdateCollection)...)
+	}
 	{
 		_document := root.Group("/document", _documentMw()...)
 		_document.POST("/add", append(_adddocumentMw(), api_service.AddDocument)...)
 		_document.DELETE("/delete", append(_deletedocumentMw(), api_service.DeleteDocument)...)
 		_document.GET("/list", append(_listdocumentMw(), api_service.ListDocument)...)
+		_document.POST("/move", append(_movedocumentMw(), api_service.MoveDocument)...)
+		_document.POST("/restore", append(_restoredocumentversionMw(), api_service.RestoreDocumentVersion)...)
 		_document.GET("/search", append(_searchdocumentMw(), api_service.SearchDocument)...)
+		_document.GET("/status", append(_getdocumentstatusMw(), api_service.GetDocumentStatus)...)
+		_document.POST("/tags", append(_setdocumenttagsMw(), api_service.SetDocumentTags)...)
+		_document.POST("/update", append(_updatedocumentMw(), api_service.UpdateDocument)...)
+		_document.POST("/upload", append(_uploaddocumentMw(), api_service.UploadDocument)...)
+		_document.GET("/versions", append(_listdocumentversionsMw(), api_service.ListDocumentVersions)...)
+	}
+	{
+		_group := root.Group("/group", _groupMw()...)
+		_group.POST("/create", append(_creategroupMw(), api_service.CreateGroup)...)
+		_group.GET("/list", append(_listgroupsMw(), api_service.ListGroups)...)
+		{
+			_members := _group.Group("/members", _membersMw()...)
+			_members.POST("/add", append(_addgroupmembersMw(), api_service.AddGroupMembers)...)
+			_members.POST("/remove", append(_removegroupmembersMw(), api_service.RemoveGroupMembers)...)
+		}
 	}
 	{
 		_memory := root.Group("/memory", _memoryMw()...)
@@ -42,12 +73,21 @@ func Register(r *server.Hertz) {
 		_memory.GET("/get", append(_getmemoryMw(), api_service.GetMemory)...)
 		_memory.GET("/search", append(_searchmemoriesMw(), api_service.SearchMemories)...)
 	}
+	{
+		_model := root.Group("/model", _modelMw()...)
+		_model.GET("/status", append(_getmodelstatusMw(), api_service.GetModelStatus)...)
+	}
 	{
 		_session := root.Group("/session", _sessionMw()...)
 		_session.POST("/create", append(_createsessionMw(), api_service.CreateSession)...)
 		_session.POST("/end", append(_endsessionMw(), api_service.EndSession)...)
 		_session.GET("/get", append(_getsessionMw(), api_service.GetSession)...)
 		_session.GET("/list", append(_getsessionlistMw(), api_service.GetSessionList)...)
+		_session.POST("/update", append(_updatesessionMw(), api_service.UpdateSession)...)
+	}
+	{
+		_usage := root.Group("/usage", _usageMw()...)
+		_usage.GET("/get", append(_getusageMw(), api_service.GetUsage)...)
 	}
 	{
 		_user := root.Group("/user", _userMw()...)
@@ -36,6 +36,11 @@ func _addchatrecordMw() []app.HandlerFunc {
 	return nil
 }
 
+func _streamchatMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
 func _recordsMw() []app.HandlerFunc {
 	// your code...
 	return nil
@@ -150,3 +155,138 @@ func _gethourlyweatherMw() []app.HandlerFunc {
 	// your code...
 	return nil
 }
+
+func _usageMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _getusageMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _updatesessionMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _modelMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _getmodelstatusMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _uploaddocumentMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _getdocumentstatusMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _updatedocumentMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _listdocumentversionsMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _restoredocumentversionMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _movedocumentMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _setdocumenttagsMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _collectionMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _createcollectionMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _listcollectionsMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _updatecollectionMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _deletecollectionMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _accessMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _grantaccessMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _revokeaccessMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _listaccessMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _groupMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _creategroupMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _listgroupsMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _membersMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _addgroupmembersMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
+
+func _removegroupmembersMw() []app.HandlerFunc {
+	// your code...
+	return nil
+}
@@ -9,12 +9,15 @@ import (
 
 	"server/api_service/biz/router/api_service"
 	"server/framework"
+	"server/framework/config"
 	"server/framework/logger"
+	"server/framework/mongodb"
 	ragservice "server/service/rag_svr/kitex_gen/rag_svr/ragservice"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/client/streamclient"
 	"github.com/cloudwego/kitex/pkg/endpoint"
 	"github.com/cloudwego/kitex/pkg/loadbalance"
 	"github.com/cloudwego/kitex/pkg/rpcinfo"
@@ -27,10 +30,17 @@ func main() {
 		log.Fatalf("创建etcd解析器失败: %v", err)
 	}
 
+	// 初始化 MongoDB，用于保存上传的文档文件，失败时只影响文件上传接口
+	if err := mongodb.InitMongoDB(); err != nil {
+		logger.Errorf("初始化 MongoDB 失败，文件上传不可用: %v", err)
+	}
+
 	h := server.Default(
 		server.WithHostPorts("0.0.0.0:8081"),    // 允许所有网络接口访问
 		server.WithReadTimeout(time.Second*10),  // 设置读取超时
 		server.WithWriteTimeout(time.Second*10), // 设置写入超时
+		// 请求体上限为文件大小上限加 1MB 表单开销
+		server.WithMaxRequestBodySize(int(config.MaxUploadSize())+1<<20),
 	)
 
 	// 初始化Kitex客户端并集成etcd服务发现
@@ -115,9 +125,20 @@ func main() {
 		log.Fatalf("创建客户端失败: %v", err)
 	}
 
+	// 流式接口客户端，流式调用不受 RPC 超时限制
+	ragSvrStreamClient, err := ragservice.NewStreamClient(
+		"rag_svr",
+		streamclient.WithResolver(etcdResolver),
+		streamclient.WithLoadBalancer(loadbalance.NewWeightedBalancer()), // 负载均衡策略
+	)
+	if err != nil {
+		log.Fatalf("创建流式客户端失败: %v", err)
+	}
+
 	// 将客户端保存到上下文中，供handler使用
 	h.Use(func(ctx context.Context, c *app.RequestContext) {
 		c.Set("rag_svr_client", ragSvrClient)
+		c.Set("rag_svr_stream_client", ragSvrStreamClient)
 		c.Set("rag_svr_test_client", ragSvrTestClient)
 		c.Set("rag_svr_test2_client", ragSvrTest2Client)
 		c.Next(ctx)
@@ -51,6 +51,34 @@ type Config struct {
 		Database string `yaml:"database"`
 	} `yaml:"mongodb"`
 
+	Upload struct {
+		MaxFileSize int `yaml:"max_file_size"` // 上传文件最大大小（MB）
+	} `yaml:"upload"`
+
+	Indexer struct {
+		Workers      int `yaml:"workers"`       // 并发执行的索引任务数
+		BatchSize    int `yaml:"batch_size"`    // 每批写入 MySQL 和 Milvus 的块数，向量化时再按模型单次请求上限拆分
+		MaxAttempts  int `yaml:"max_attempts"`  // 任务最大尝试次数
+		PollInterval int `yaml:"poll_interval"` // 没有任务时的轮询间隔（毫秒）
+		LeaseTimeout int `yaml:"lease_timeout"` // 运行中的任务超过该时间（秒）没有进度视为中断，重新执行
+	} `yaml:"indexer"`
+
+	Chunker struct {
+		ChunkerConfig `yaml:",inline"`         // 默认切块配置
+		Collections   map[string]ChunkerConfig `yaml:"collections"` // 按知识库名称覆盖默认配置
+	} `yaml:"chunker"`
+
+	Retrieval struct {
+		VectorWeight    float64 `yaml:"vector_weight"`    // 向量检索在 RRF 融合中的权重，请求未指定权重时使用
+		KeywordWeight   float64 `yaml:"keyword_weight"`   // 关键词检索在 RRF 融合中的权重，请求未指定权重时使用
+		RRFK            int     `yaml:"rrf_k"`            // RRF 常数 k，越大各名次的分数越接近
+		CandidateFactor int     `yaml:"candidate_factor"` // 每路检索的候选数为 top_k 的倍数
+	} `yaml:"retrieval"`
+
+	DocumentVersion struct {
+		MaxVersions int `yaml:"max_versions"` // 每个文档保留的历史版本数，不含最新版本和当前可检索的版本
+	} `yaml:"document_version"`
+
 	Milvus struct {
 		Host      string `yaml:"host"`      // Milvus 服务地址
 		Port      int    `yaml:"port"`      // Milvus 服务端口
@@ -59,23 +87,61 @@ type Config struct {
 
 	AI struct {
 		ChatModel struct {
-			APIKey           string  `yaml:"-"`                 // 从环境变量读取 DASHSCOPE_API_KEY
+			APIKey           string  `yaml:"-"`                 // 从环境变量读取 DASHSCOPE_API_KEY 或 OPENAI_API_KEY
 			Provider         string  `yaml:"provider"`          // 支持 dashscope, openai 等
 			ModelName        string  `yaml:"model_name"`        // 模型名称
 			BaseURL          string  `yaml:"base_url"`          // API 基础 URL
 			Temperature      float64 `yaml:"temperature"`       // 温度参数
 			MaxTokens        int     `yaml:"max_tokens"`        // 最大生成token数
+			ContextWindow    int     `yaml:"context_window"`    // 模型上下文窗口token数
 			TopP             float64 `yaml:"top_p"`             // 采样阈值
 			FrequencyPenalty float64 `yaml:"frequency_penalty"` // 频率惩罚
 			PresencePenalty  float64 `yaml:"presence_penalty"`  // 存在惩罚
+			MaxToolRounds    int     `yaml:"max_tool_rounds"`   // 工具调用最大轮数
 		} `yaml:"chat_model"`
 		EmbeddingModel struct {
-			APIKey    string `yaml:"-"`          // 从环境变量读取 EMBEDDING_API_KEY
-			Provider  string `yaml:"provider"`   // 支持 dashscope, openai 等
-			ModelName string `yaml:"model_name"` // 向量化模型名称
-			BaseURL   string `yaml:"base_url"`   // API 基础 URL
-			Dimension int    `yaml:"dimension"`  // 向量维度
+			APIKey           string `yaml:"-"`                 // 从环境变量读取 EMBEDDING_API_KEY
+			Provider         string `yaml:"provider"`          // 支持 dashscope, openai 等
+			ModelName        string `yaml:"model_name"`        // 向量化模型名称
+			BaseURL          string `yaml:"base_url"`          // API 基础 URL
+			Dimension        int    `yaml:"dimension"`         // 向量维度
+			MaxBatchSize     int    `yaml:"max_batch_size"`    // 单次请求最多的文本数，超出时拆分为多次请求
+			BatchConcurrency int    `yaml:"batch_concurrency"` // 批量向量化时同时发出的请求数
 		} `yaml:"embedding_model"`
+		Reranker struct {
+			APIKey          string  `yaml:"-"`                // 从环境变量读取 RERANK_API_KEY，未设置时沿用 DASHSCOPE_API_KEY
+			Provider        string  `yaml:"provider"`         // 支持 dashscope, jina（Jina/Cohere 兼容的 /rerank 接口），为空时不重排
+			ModelName       string  `yaml:"model_name"`       // 重排模型名称
+			BaseURL         string  `yaml:"base_url"`         // API 基础 URL
+			MinScore        float64 `yaml:"min_score"`        // 重排分数低于该值的结果不返回，请求未指定时使用
+			CandidateFactor int     `yaml:"candidate_factor"` // 送入重排的候选数为 top_k 的倍数
+		} `yaml:"reranker"`
+		Gateway struct {
+			Timeout            int            `yaml:"timeout"`             // 非流式请求超时（秒）
+			MaxRetries         int            `yaml:"max_retries"`         // 最大尝试次数
+			RetryBaseDelay     int            `yaml:"retry_base_delay"`    // 重试基础间隔（毫秒），按指数增长
+			RetryMaxDelay      int            `yaml:"retry_max_delay"`     // 重试最大间隔（毫秒）
+			DefaultConcurrency int            `yaml:"default_concurrency"` // 未单独配置的接口的最大并发数
+			Concurrency        map[string]int `yaml:"concurrency"`         // 各接口最大并发数，如 chat、chat_stream、embedding
+		} `yaml:"gateway"`
+		Quota struct {
+			DailyTokens   int64 `yaml:"daily_tokens"`   // 每个用户每日 token 配额，0 表示不限制
+			MonthlyTokens int64 `yaml:"monthly_tokens"` // 每个用户每月 token 配额，0 表示不限制
+		} `yaml:"quota"`
+		Summary struct {
+			TriggerTurns int `yaml:"trigger_turns"` // 未摘要的对话超出保留轮数达到该值时触发摘要
+			KeepTurns    int `yaml:"keep_turns"`    // 保留原文的最近轮数
+		} `yaml:"summary"`
+		Fallback struct {
+			ChatModels            []FallbackModel `yaml:"chat_models"`             // 备用对话模型，主模型不可用时按顺序尝试
+			EmbeddingModels       []FallbackModel `yaml:"embedding_models"`        // 备用向量化接入点，模型名称和维度须与主模型一致
+			RetriesBeforeFailover int             `yaml:"retries_before_failover"` // 切换到下一个模型前的尝试次数，最后一个模型使用网关配置
+			Breaker               struct {
+				FailureThreshold int `yaml:"failure_threshold"` // 连续失败达到该次数后熔断
+				OpenTimeout      int `yaml:"open_timeout"`      // 熔断持续时间（秒），之后进入半开状态
+				HalfOpenProbes   int `yaml:"half_open_probes"`  // 半开状态放行的探测请求数，全部成功后恢复
+			} `yaml:"breaker"`
+		} `yaml:"fallback"`
 	} `yaml:"ai"`
 
 	Log struct {
@@ -88,6 +154,28 @@ type Config struct {
 	} `yaml:"log"`
 }
 
+// FallbackModel 备用模型配置
+type FallbackModel struct {
+	Name         string `yaml:"name"`           // 名称，用于日志和监控，默认为 provider/model_name
+	Provider     string `yaml:"provider"`       // 支持 dashscope, openai
+	ModelName    string `yaml:"model_name"`     // 模型名称
+	BaseURL      string `yaml:"base_url"`       // API 基础 URL
+	APIKeyEnv    string `yaml:"api_key_env"`    // API Key 所在的环境变量，为空时沿用主模型的 Key
+	Dimension    int    `yaml:"dimension"`      // 向量维度，仅向量化模型使用
+	MaxBatchSize int    `yaml:"max_batch_size"` // 单次请求最多的文本数，仅向量化模型使用，为 0 时沿用主模型的配置
+	APIKey       string `yaml:"-"`
+}
+
+// ChunkerConfig 文档切块配置，参数为 0 时使用策略的默认值
+type ChunkerConfig struct {
+	Strategy   string   `yaml:"strategy"`    // sentence_window, fixed_token, markdown, recursive
+	WindowSize int      `yaml:"window_size"` // sentence_window：每块的句子数
+	Step       int      `yaml:"step"`        // sentence_window：窗口步长
+	ChunkSize  int      `yaml:"chunk_size"`  // fixed_token、markdown 为 token 数，recursive 为字符数
+	Overlap    int      `yaml:"overlap"`     // 相邻块重叠的 token 数或字符数，小于 0 表示不重叠
+	Separators []string `yaml:"separators"`  // recursive：分隔符，按顺序尝试
+}
+
 // LoadConfig 加载配置文件
 func LoadConfig(configPath string) error {
 	// 读取配置文件
@@ -112,10 +200,15 @@ func LoadConfig(configPath string) error {
 
 // loadAIConfig 从环境变量加载 AI 配置
 func loadAIConfig() error {
-	// 加载 API Key
-	GlobalConfig.AI.ChatModel.APIKey = os.Getenv("DASHSCOPE_API_KEY")
-	if GlobalConfig.AI.ChatModel.APIKey == "" {
-		return fmt.Errorf("环境变量 DASHSCOPE_API_KEY 未设置")
+	// 加载 API Key，OpenAI 兼容接口（如本地 vLLM、Ollama）可以不设置
+	switch GlobalConfig.AI.ChatModel.Provider {
+	case "openai":
+		GlobalConfig.AI.ChatModel.APIKey = os.Getenv("OPENAI_API_KEY")
+	default:
+		GlobalConfig.AI.ChatModel.APIKey = os.Getenv("DASHSCOPE_API_KEY")
+		if GlobalConfig.AI.ChatModel.APIKey == "" {
+			return fmt.Errorf("环境变量 DASHSCOPE_API_KEY 未设置")
+		}
 	}
 
 	// 加载 Embedding API Key
@@ -124,6 +217,28 @@ func loadAIConfig() error {
 		return fmt.Errorf("环境变量 EMBEDDING_API_KEY 未设置")
 	}
 
+	// 加载重排模型 API Key
+	GlobalConfig.AI.Reranker.APIKey = os.Getenv("RERANK_API_KEY")
+	if GlobalConfig.AI.Reranker.APIKey == "" {
+		GlobalConfig.AI.Reranker.APIKey = os.Getenv("DASHSCOPE_API_KEY")
+	}
+
+	// 加载备用模型的 API Key
+	for i := range GlobalConfig.AI.Fallback.ChatModels {
+		model := &GlobalConfig.AI.Fallback.ChatModels[i]
+		model.APIKey = GlobalConfig.AI.ChatModel.APIKey
+		if model.APIKeyEnv != "" {
+			model.APIKey = os.Getenv(model.APIKeyEnv)
+		}
+	}
+	for i := range GlobalConfig.AI.Fallback.EmbeddingModels {
+		model := &GlobalConfig.AI.Fallback.EmbeddingModels[i]
+		model.APIKey = GlobalConfig.AI.EmbeddingModel.APIKey
+		if model.APIKeyEnv != "" {
+			model.APIKey = os.Getenv(model.APIKeyEnv)
+		}
+	}
+
 	// 加载模型名称
 	if modelName := os.Getenv("MODEL_NAME"); modelName != "" {
 		GlobalConfig.AI.ChatModel.ModelName = modelName
@@ -139,6 +254,14 @@ func loadAIConfig() error {
 	return nil
 }
 
+// MaxUploadSize 上传文件最大字节数，未配置时为 20MB
+func MaxUploadSize() int64 {
+	if GlobalConfig == nil || GlobalConfig.Upload.MaxFileSize <= 0 {
+		return 20 << 20
+	}
+	return int64(GlobalConfig.Upload.MaxFileSize) << 20
+}
+
 // ValidateConfig 验证配置
 func ValidateConfig() error {
 	// 验证 service 配置
@@ -106,12 +106,19 @@ func DeleteVector(ctx context.Context, collectionName string, id int64) error {
 	return nil
 }
 
-// SearchVector 搜索向量
-func SearchVector(ctx context.Context, collectionName string, queryVector []float32, topK int) ([]int64, []float32, error) {
+// SearchVector 搜索向量，expr 为标量字段的过滤表达式（如 user_id == 1），为空时不过滤；
+// outputFields 指定的标量字段按结果顺序返回在 fields 中，与 ids 一一对应
+func SearchVector(ctx context.Context, collectionName string, queryVector []float32, topK int, expr string, outputFields []string) ([]int64, []float32, []map[string]interface{}, error) {
+	start := time.Now()
+	defer func() {
+		stats.SearchLatency = time.Since(start)
+		stats.SearchCount++
+	}()
+
 	// 准备搜索参数
 	searchParam, err := entity.NewIndexHNSWSearchParam(64) // ef = 64
 	if err != nil {
-		return nil, nil, fmt.Errorf("创建搜索参数失败: %v", err)
+		return nil, nil, nil, fmt.Errorf("创建搜索参数失败: %v", err)
 	}
 
 	// 执行搜索
@@ -119,8 +126,8 @@ func SearchVector(ctx context.Context, collectionName string, queryVector []floa
 		ctx,
 		collectionName,
 		[]string{},
-		"",
-		[]string{"id"},
+		expr,
+		append([]string{"id"}, outputFields...),
 		[]entity.Vector{entity.FloatVector(queryVector)},
 		"vector",
 		entity.L2,
@@ -128,16 +135,18 @@ func SearchVector(ctx context.Context, collectionName string, queryVector []floa
 		searchParam,
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("搜索向量失败: %v", err)
+		stats.ErrorCount++
+		return nil, nil, nil, fmt.Errorf("搜索向量失败: %v", err)
 	}
 
 	// 解析结果
 	if len(results) == 0 {
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 
 	ids := make([]int64, 0)
 	scores := make([]float32, 0)
+	fields := make([]map[string]interface{}, 0)
 
 	for _, result := range results {
 		for i := 0; i < result.ResultCount; i++ {
@@ -145,10 +154,31 @@ func SearchVector(ctx context.Context, collectionName string, queryVector []floa
 			score := result.Scores[i]
 			ids = append(ids, id)
 			scores = append(scores, score)
+
+			row := make(map[string]interface{}, len(outputFields))
+			for _, name := range outputFields {
+				column := result.Fields.GetColumn(name)
+				if column == nil {
+					continue
+				}
+				if value, err := column.Get(i); err == nil {
+					row[name] = value
+				}
+			}
+			fields = append(fields, row)
 		}
 	}
 
-	return ids, scores, nil
+	return ids, scores, fields, nil
+}
+
+// InExpr 生成 field in [v1, v2, ...] 形式的过滤表达式
+func InExpr(field string, values []int64) string {
+	strValues := make([]string, len(values))
+	for i, value := range values {
+		strValues[i] = fmt.Sprintf("%d", value)
+	}
+	return field + " in [" + strings.Join(strValues, ",") + "]"
 }
 
 // UpdateVector 更新向量
@@ -189,6 +219,11 @@ func Close() error {
 
 // BatchInsertVectors 批量插入向量
 func BatchInsertVectors(ctx context.Context, collectionName string, ids []int64, vectors [][]float32) error {
+	return BatchInsertVectorsWithFields(ctx, collectionName, ids, vectors, nil)
+}
+
+// BatchInsertVectorsWithFields 批量插入向量及标量字段，fields 与 ids 一一对应，字段须在集合的 schema 中定义
+func BatchInsertVectorsWithFields(ctx context.Context, collectionName string, ids []int64, vectors [][]float32, fields []map[string]interface{}) error {
 	start := time.Now()
 	defer func() {
 		stats.InsertLatency = time.Since(start)
@@ -198,10 +233,16 @@ func BatchInsertVectors(ctx context.Context, collectionName string, ids []int64,
 	// 准备数据
 	rows := make([]interface{}, len(ids))
 	for i := range ids {
-		rows[i] = map[string]interface{}{
+		row := map[string]interface{}{
 			"id":     ids[i],
 			"vector": vectors[i],
 		}
+		if i < len(fields) {
+			for name, value := range fields[i] {
+				row[name] = value
+			}
+		}
+		rows[i] = row
 	}
 
 	// 插入数据
@@ -221,6 +262,66 @@ func BatchInsertVectors(ctx context.Context, collectionName string, ids []int64,
 	return nil
 }
 
+// UpsertVectorsWithFields 按 ID 覆盖写入向量及标量字段，标量字段取 int64 或 string，各行的字段须一致
+func UpsertVectorsWithFields(ctx context.Context, collectionName string, ids []int64, vectors [][]float32, fields []map[string]interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if len(vectors) != len(ids) || len(fields) != len(ids) {
+		return fmt.Errorf("向量和标量字段数量与 ID 不一致")
+	}
+	start := time.Now()
+	defer func() {
+		stats.InsertLatency = time.Since(start)
+		stats.InsertCount++
+	}()
+
+	// 按列组织数据
+	columns := []entity.Column{
+		entity.NewColumnInt64("id", ids),
+		entity.NewColumnFloatVector("vector", len(vectors[0]), vectors),
+	}
+	for name, first := range fields[0] {
+		switch first.(type) {
+		case int64:
+			values := make([]int64, len(ids))
+			for i := range fields {
+				value, ok := fields[i][name].(int64)
+				if !ok {
+					return fmt.Errorf("标量字段 %s 类型不一致", name)
+				}
+				values[i] = value
+			}
+			columns = append(columns, entity.NewColumnInt64(name, values))
+		case string:
+			values := make([]string, len(ids))
+			for i := range fields {
+				value, ok := fields[i][name].(string)
+				if !ok {
+					return fmt.Errorf("标量字段 %s 类型不一致", name)
+				}
+				values[i] = value
+			}
+			columns = append(columns, entity.NewColumnVarChar(name, values))
+		default:
+			return fmt.Errorf("不支持的标量字段类型: %s", name)
+		}
+	}
+
+	if _, err := milvusClient.Upsert(ctx, collectionName, "", columns...); err != nil {
+		stats.ErrorCount++
+		return fmt.Errorf("批量更新向量失败: %v", err)
+	}
+
+	// 刷新数据
+	if err := milvusClient.Flush(ctx, collectionName, false); err != nil {
+		stats.ErrorCount++
+		return fmt.Errorf("刷新数据失败: %v", err)
+	}
+
+	return nil
+}
+
 // BatchDeleteVectors 批量删除向量
 func BatchDeleteVectors(ctx context.Context, collectionName string, ids []int64) error {
 	start := time.Now()
@@ -230,13 +331,7 @@ func BatchDeleteVectors(ctx context.Context, collectionName string, ids []int64)
 	}()
 
 	// 构建删除条件
-	expr := "id in [" + strings.Join(func() []string {
-		strIDs := make([]string, len(ids))
-		for i, id := range ids {
-			strIDs[i] = fmt.Sprintf("%d", id)
-		}
-		return strIDs
-	}(), ",") + "]"
+	expr := InExpr("id", ids)
 
 	// 删除数据
 	err := milvusClient.Delete(ctx, collectionName, "", expr)
@@ -255,6 +350,25 @@ func BatchDeleteVectors(ctx context.Context, collectionName string, ids []int64)
 	return nil
 }
 
+// DeleteVectorsByExpr 删除满足标量字段过滤表达式（如 doc_id == 1）的向量
+func DeleteVectorsByExpr(ctx context.Context, collectionName, expr string) error {
+	start := time.Now()
+	defer func() {
+		stats.DeleteLatency = time.Since(start)
+		stats.DeleteCount++
+	}()
+
+	if err := milvusClient.Delete(ctx, collectionName, "", expr); err != nil {
+		stats.ErrorCount++
+		return fmt.Errorf("删除向量失败: %v", err)
+	}
+	if err := milvusClient.Flush(ctx, collectionName, false); err != nil {
+		stats.ErrorCount++
+		return fmt.Errorf("刷新数据失败: %v", err)
+	}
+	return nil
+}
+
 // GetClient 获取 Milvus 客户端
 func GetClient() client.Client {
 	return milvusClient
@@ -0,0 +1,107 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DocumentBucket 存放上传文档原始文件的 GridFS bucket
+const DocumentBucket = "document_files"
+
+// GetBucket 获取 GridFS bucket，ctx 的截止时间作为读写超时
+func GetBucket(ctx context.Context, name string) (*gridfs.Bucket, error) {
+	if db == nil {
+		return nil, fmt.Errorf("MongoDB 未初始化")
+	}
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(name))
+	if err != nil {
+		return nil, fmt.Errorf("打开 GridFS bucket 失败: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		bucket.SetReadDeadline(deadline)
+		bucket.SetWriteDeadline(deadline)
+	}
+	return bucket, nil
+}
+
+// UploadFile 上传文件到 GridFS，返回文件ID
+func UploadFile(ctx context.Context, bucketName, fileName string, data []byte, metadata interface{}) (primitive.ObjectID, error) {
+	bucket, err := GetBucket(ctx, bucketName)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	fileID, err := bucket.UploadFromStream(fileName, bytes.NewReader(data), options.GridFSUpload().SetMetadata(metadata))
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("上传文件失败: %v", err)
+	}
+	return fileID, nil
+}
+
+// DownloadFile 下载 GridFS 文件，返回文件信息和内容
+func DownloadFile(ctx context.Context, bucketName string, fileID primitive.ObjectID) (*gridfs.File, []byte, error) {
+	bucket, err := GetBucket(ctx, bucketName)
+	if err != nil {
+		return nil, nil, err
+	}
+	stream, err := bucket.OpenDownloadStream(fileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开文件失败: %v", err)
+	}
+	defer stream.Close()
+
+	file := stream.GetFile()
+	var buf bytes.Buffer
+	buf.Grow(int(file.Length))
+	if _, err := buf.ReadFrom(stream); err != nil {
+		return nil, nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	return file, buf.Bytes(), nil
+}
+
+// DeleteFile 删除 GridFS 文件及其分块
+func DeleteFile(ctx context.Context, bucketName string, fileID primitive.ObjectID) error {
+	bucket, err := GetBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+	if err := bucket.Delete(fileID); err != nil {
+		return fmt.Errorf("删除文件失败: %v", err)
+	}
+	return nil
+}
+
+// SetFileMetadata 设置 GridFS 文件元数据中的字段
+func SetFileMetadata(ctx context.Context, bucketName string, fileID primitive.ObjectID, key string, value interface{}) error {
+	_, err := UpdateOne(ctx, bucketName+".files", bson.M{"_id": fileID}, bson.M{"$set": bson.M{"metadata." + key: value}})
+	return err
+}
+
+// DeleteFilesByMetadata 删除元数据字段等于 value 的 GridFS 文件，返回删除的文件数
+func DeleteFilesByMetadata(ctx context.Context, bucketName, key string, value interface{}) (int, error) {
+	bucket, err := GetBucket(ctx, bucketName)
+	if err != nil {
+		return 0, err
+	}
+	cursor, err := bucket.Find(bson.M{"metadata." + key: value})
+	if err != nil {
+		return 0, fmt.Errorf("查找文件失败: %v", err)
+	}
+	var files []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &files); err != nil {
+		return 0, fmt.Errorf("读取文件列表失败: %v", err)
+	}
+	for i, file := range files {
+		if err := bucket.Delete(file.ID); err != nil {
+			return i, fmt.Errorf("删除文件失败: file_id=%s, err=%v", file.ID.Hex(), err)
+		}
+	}
+	return len(files), nil
+}
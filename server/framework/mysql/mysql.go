@@ -253,12 +253,15 @@ func (ChatRecord) TableName() string {
 type Document struct {
 	DocID          uint64 `gorm:"column:doc_id;primaryKey"`
 	UserID         uint64 `gorm:"column:user_id;not null"`
+	CollectionID   uint64 `gorm:"column:collection_id;not null;default:0"` // 所属知识库，0 表示未归入知识库
 	Title          string `gorm:"column:title;size:200;not null"`
 	Status         string `gorm:"column:status;size:20;not null;default:'active'"`
 	Metadata       string `gorm:"column:metadata;type:json"`
 	ParagraphCount uint32 `gorm:"column:paragraph_count;not null;default:0"`
 	SentenceCount  uint32 `gorm:"column:sentence_count;not null;default:0"`
 	Keywords       string `gorm:"column:keywords;type:json"`
+	Chunker        string `gorm:"column:chunker;type:json;default:null"` // 切块策略及参数，见 chunker.Config
+	Version        uint32 `gorm:"column:version;not null;default:0"`     // 当前可检索的版本，新版本索引完成后切换
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -267,6 +270,73 @@ func (Document) TableName() string {
 	return "document"
 }
 
+// KnowledgeCollection 知识库表，文档按知识库分组
+type KnowledgeCollection struct {
+	CollectionID uint64 `gorm:"column:collection_id;primaryKey;autoIncrement"`
+	UserID       uint64 `gorm:"column:user_id;not null"`
+	Name         string `gorm:"column:name;size:100;not null"`
+	Description  string `gorm:"column:description;size:500;not null;default:''"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (KnowledgeCollection) TableName() string {
+	return "knowledge_collection"
+}
+
+// DocumentTag 文档标签表
+type DocumentTag struct {
+	DocID     uint64 `gorm:"column:doc_id;primaryKey"`
+	Tag       string `gorm:"column:tag;primaryKey;size:50"`
+	UserID    uint64 `gorm:"column:user_id;not null"`
+	CreatedAt time.Time
+}
+
+func (DocumentTag) TableName() string {
+	return "document_tag"
+}
+
+// UserGroup 用户组表，组可以作为知识库和文档的授权对象
+type UserGroup struct {
+	GroupID   uint64 `gorm:"column:group_id;primaryKey;autoIncrement"`
+	OwnerID   uint64 `gorm:"column:owner_id;not null"`
+	Name      string `gorm:"column:name;size:100;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (UserGroup) TableName() string {
+	return "user_group"
+}
+
+// UserGroupMember 用户组成员表
+type UserGroupMember struct {
+	GroupID   uint64 `gorm:"column:group_id;primaryKey"`
+	UserID    uint64 `gorm:"column:user_id;primaryKey"`
+	CreatedAt time.Time
+}
+
+func (UserGroupMember) TableName() string {
+	return "user_group_member"
+}
+
+// AccessGrant 授权表，把知识库或文档的角色授予用户或用户组
+type AccessGrant struct {
+	ID            uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	ResourceType  string `gorm:"column:resource_type;size:20;not null"` // collection/document
+	ResourceID    uint64 `gorm:"column:resource_id;not null"`
+	PrincipalType string `gorm:"column:principal_type;size:20;not null"` // user/group
+	PrincipalID   uint64 `gorm:"column:principal_id;not null"`
+	Role          string `gorm:"column:role;size:20;not null"` // owner/editor/viewer
+	GrantedBy     uint64 `gorm:"column:granted_by;not null"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (AccessGrant) TableName() string {
+	return "access_grant"
+}
+
 // ChatMemory 对话记忆表
 type ChatMemory struct {
 	ID          uint64  `gorm:"primaryKey"`
@@ -386,6 +456,7 @@ type DocumentParagraph struct {
 	SentenceIDMin uint64 `gorm:"column:sentence_id_min;not null"`
 	SentenceIDMax uint64 `gorm:"column:sentence_id_max;not null"`
 	Keywords      string `gorm:"column:keywords;type:json"`
+	Metadata      string `gorm:"column:metadata;type:json;default:null"` // 段落结构信息，见 ingest.ParagraphMeta
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -394,6 +465,66 @@ func (DocumentParagraph) TableName() string {
 	return "document_paragraph"
 }
 
+// ModelUsage 模型用量表，每次模型调用一条记录
+type ModelUsage struct {
+	ID           uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID       uint64 `gorm:"column:user_id;not null"`
+	SessionID    uint64 `gorm:"column:session_id;not null;default:0"`
+	Model        string `gorm:"column:model;size:100;not null"`
+	CallType     string `gorm:"column:call_type;size:20;not null"` // chat/intent/sentiment/summary/embedding
+	InputTokens  int    `gorm:"column:input_tokens;not null;default:0"`
+	OutputTokens int    `gorm:"column:output_tokens;not null;default:0"`
+	TotalTokens  int    `gorm:"column:total_tokens;not null;default:0"`
+	CreatedAt    time.Time
+}
+
+func (ModelUsage) TableName() string {
+	return "model_usage"
+}
+
+// DocumentIndexJob 文档索引任务表，添加文档后由后台任务切块、向量化并写入 Milvus
+type DocumentIndexJob struct {
+	JobID        uint64     `gorm:"column:job_id;primaryKey;autoIncrement"`
+	DocID        uint64     `gorm:"column:doc_id;not null"`
+	UserID       uint64     `gorm:"column:user_id;not null"`
+	Status       string     `gorm:"column:status;size:20;not null;default:'pending'"` // pending/running/succeeded/failed
+	TotalChunks  uint32     `gorm:"column:total_chunks;not null;default:0"`
+	DoneChunks   uint32     `gorm:"column:done_chunks;not null;default:0"`
+	ReusedChunks uint32     `gorm:"column:reused_chunks;not null;default:0"` // 内容未变、沿用原向量的块数
+	Attempts     uint32     `gorm:"column:attempts;not null;default:0"`
+	MaxAttempts  uint32     `gorm:"column:max_attempts;not null;default:3"`
+	LastError    string     `gorm:"column:last_error;type:text"`
+	Worker       string     `gorm:"column:worker;size:64;not null;default:''"`
+	NextRunAt    time.Time  `gorm:"column:next_run_at;not null"`
+	StartedAt    *time.Time `gorm:"column:started_at"`
+	FinishedAt   *time.Time `gorm:"column:finished_at"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (DocumentIndexJob) TableName() string {
+	return "document_index_job"
+}
+
+// DocumentVersion 文档版本表，每次内容或切块配置变化生成一个版本，创建后不再修改
+type DocumentVersion struct {
+	DocID          uint64 `gorm:"column:doc_id;primaryKey"`
+	Version        uint32 `gorm:"column:version;primaryKey"`
+	UserID         uint64 `gorm:"column:user_id;not null"`
+	ContentHash    string `gorm:"column:content_hash;size:64;not null"`
+	Content        string `gorm:"column:content;type:longtext;not null"` // 段落及结构信息的 JSON
+	Chunker        string `gorm:"column:chunker;type:json;default:null"`
+	EmbeddingModel string `gorm:"column:embedding_model;size:100;not null;default:''"`
+	ParagraphCount uint32 `gorm:"column:paragraph_count;not null;default:0"`
+	SentenceCount  uint32 `gorm:"column:sentence_count;not null;default:0"`
+	RestoredFrom   uint32 `gorm:"column:restored_from;not null;default:0"` // 从哪个版本恢复，0 表示编辑产生
+	CreatedAt      time.Time
+}
+
+func (DocumentVersion) TableName() string {
+	return "document_version"
+}
+
 // DocumentSentence 文档句子表
 type DocumentSentence struct {
 	DocID       uint64 `gorm:"column:doc_id;primaryKey"`
@@ -415,6 +546,8 @@ type DocumentChunk struct {
 	ParagraphID   uint64 `gorm:"column:paragraph_id;not null"`
 	SentenceIDMin uint64 `gorm:"column:sentence_id_min;not null"`
 	SentenceIDMax uint64 `gorm:"column:sentence_id_max;not null"`
+	Content       string `gorm:"column:content;type:text"`          // 向量化的文本
+	Version       uint32 `gorm:"column:version;not null;default:0"` // 所属文档版本
 	Keywords      string `gorm:"column:keywords;type:json"`
 	Embedding     []byte `gorm:"column:embedding;type:blob"`
 	CreatedAt     time.Time
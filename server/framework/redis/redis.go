@@ -13,6 +13,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// Nil 键不存在时返回的错误
+const Nil = redis.Nil
+
 var (
 	redisClient *redis.Client
 )
@@ -68,6 +71,16 @@ func Get(ctx context.Context, key string) (string, error) {
 	return redisClient.Get(ctx, key).Result()
 }
 
+// SetNX 键不存在时设置缓存
+func SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return redisClient.SetNX(ctx, key, value, expiration).Result()
+}
+
+// IncrBy 将键的整数值增加指定数值
+func IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return redisClient.IncrBy(ctx, key, value).Result()
+}
+
 // Del 删除缓存
 func Del(ctx context.Context, keys ...string) error {
 	return redisClient.Del(ctx, keys...).Err()
@@ -133,6 +146,16 @@ func LTrim(ctx context.Context, key string, start, stop int64) error {
 	return redisClient.LTrim(ctx, key, start, stop).Err()
 }
 
+// SAdd 向集合添加成员
+func SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return redisClient.SAdd(ctx, key, members...).Err()
+}
+
+// SMembers 获取集合所有成员
+func SMembers(ctx context.Context, key string) ([]string, error) {
+	return redisClient.SMembers(ctx, key).Result()
+}
+
 // Keys 获取匹配的键
 func Keys(ctx context.Context, pattern string) ([]string, error) {
 	return redisClient.Keys(ctx, pattern).Result()
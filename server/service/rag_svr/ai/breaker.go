@@ -0,0 +1,194 @@
+package ai
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"server/framework/logger"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常放行
+	BreakerOpen     = "open"      // 熔断，拒绝请求
+	BreakerHalfOpen = "half_open" // 半开，放行少量探测请求
+)
+
+// 熔断器默认配置
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenTimeout      = 30 * time.Second
+	defaultBreakerHalfOpenProbes   = 2
+)
+
+// ErrBreakerOpen 熔断器处于打开状态
+var ErrBreakerOpen = errors.New("模型已熔断")
+
+// BreakerStats 熔断器和调用统计
+type BreakerStats struct {
+	Name                string
+	State               string
+	ConsecutiveFailures int
+	OpenedAt            time.Time // 最近一次熔断时间
+	Requests            int64     // 放行的请求数
+	Failures            int64     // 失败的请求数
+	Rejected            int64     // 因熔断拒绝的请求数
+	Fallbacks           int64     // 失败或熔断后切换到下一个模型的次数
+}
+
+// CircuitBreaker 模型熔断器
+// 连续失败达到阈值后打开，经过 openTimeout 进入半开状态，
+// 半开状态最多放行 halfOpenProbes 个探测请求，全部成功后关闭，任一失败重新打开
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+
+	mu             sync.Mutex
+	state          string
+	failures       int
+	openedAt       time.Time
+	probesInFlight int
+	probeSuccesses int
+	stats          BreakerStats
+}
+
+// BreakerConfig 熔断器配置，字段不大于 0 时使用默认值
+type BreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenProbes   int
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(name string, cfg BreakerConfig) *CircuitBreaker {
+	b := &CircuitBreaker{
+		name:             name,
+		failureThreshold: defaultBreakerFailureThreshold,
+		openTimeout:      defaultBreakerOpenTimeout,
+		halfOpenProbes:   defaultBreakerHalfOpenProbes,
+		state:            BreakerClosed,
+	}
+	if cfg.FailureThreshold > 0 {
+		b.failureThreshold = cfg.FailureThreshold
+	}
+	if cfg.OpenTimeout > 0 {
+		b.openTimeout = cfg.OpenTimeout
+	}
+	if cfg.HalfOpenProbes > 0 {
+		b.halfOpenProbes = cfg.HalfOpenProbes
+	}
+	return b
+}
+
+// Allow 判断是否放行请求，放行后必须调用 Success 或 Failure
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.openTimeout {
+		b.setState(BreakerHalfOpen)
+		b.probesInFlight = 0
+		b.probeSuccesses = 0
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		b.stats.Rejected++
+		return false
+	case BreakerHalfOpen:
+		if b.probesInFlight+b.probeSuccesses >= b.halfOpenProbes {
+			b.stats.Rejected++
+			return false
+		}
+		b.probesInFlight++
+	}
+	b.stats.Requests++
+	return true
+}
+
+// Success 记录一次成功
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state != BreakerHalfOpen {
+		return
+	}
+	b.probesInFlight--
+	b.probeSuccesses++
+	if b.probeSuccesses >= b.halfOpenProbes {
+		b.setState(BreakerClosed)
+	}
+}
+
+// Failure 记录一次失败
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stats.Failures++
+	b.failures++
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probesInFlight--
+		b.open()
+	case BreakerClosed:
+		if b.failures >= b.failureThreshold {
+			b.open()
+		}
+	}
+}
+
+// Release 放行的请求因调用方取消等与模型无关的原因结束时调用，不计入成功或失败
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen && b.probesInFlight > 0 {
+		b.probesInFlight--
+	}
+}
+
+// Stats 获取熔断器状态和统计
+func (b *CircuitBreaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.Name = b.name
+	stats.State = b.state
+	// 熔断已到期但还没有新请求触发状态切换时，按半开展示
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.openTimeout {
+		stats.State = BreakerHalfOpen
+	}
+	stats.ConsecutiveFailures = b.failures
+	stats.OpenedAt = b.openedAt
+	return stats
+}
+
+func (b *CircuitBreaker) addFallback() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stats.Fallbacks++
+}
+
+func (b *CircuitBreaker) open() {
+	b.openedAt = time.Now()
+	b.setState(BreakerOpen)
+}
+
+func (b *CircuitBreaker) setState(state string) {
+	if b.state == state {
+		return
+	}
+	logger.Warnf("模型熔断器状态变更: name=%s, %s -> %s, consecutive_failures=%d", b.name, b.state, state, b.failures)
+	b.state = state
+	if state == BreakerClosed {
+		b.failures = 0
+	}
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"server/framework/config"
+	"server/framework/mysql"
+	"server/service/rag_svr/chunker"
+	rag_svr "server/service/rag_svr/kitex_gen/rag_svr"
+)
+
+// resolveChunkerConfig 确定文档的切块配置：请求中指定的优先，其次是知识库的配置，最后是全局配置
+func resolveChunkerConfig(collection string, req *rag_svr.ChunkerConfig) (chunker.Config, error) {
+	var cfg chunker.Config
+	switch {
+	case req != nil && req.Strategy != "":
+		cfg = chunker.Config{
+			Strategy:   req.Strategy,
+			WindowSize: int(req.WindowSize),
+			Step:       int(req.Step),
+			ChunkSize:  int(req.ChunkSize),
+			Overlap:    int(req.Overlap),
+			Separators: req.Separators,
+		}
+	case config.GlobalConfig != nil:
+		global := config.GlobalConfig.Chunker
+		selected := global.ChunkerConfig
+		if c, ok := global.Collections[collection]; ok && collection != "" && c.Strategy != "" {
+			selected = c
+		}
+		cfg = chunker.Config{
+			Strategy:   selected.Strategy,
+			WindowSize: selected.WindowSize,
+			Step:       selected.Step,
+			ChunkSize:  selected.ChunkSize,
+			Overlap:    selected.Overlap,
+			Separators: selected.Separators,
+		}
+	}
+
+	resolved, err := chunker.Resolve(cfg)
+	if err != nil {
+		return chunker.Config{}, fmt.Errorf("切块配置无效: %v", err)
+	}
+	return resolved, nil
+}
+
+// documentChunker 按文档记录的切块配置创建切块器，早期没有记录配置的文档使用默认策略
+func documentChunker(doc *mysql.Document) (chunker.Chunker, chunker.Config, error) {
+	var cfg chunker.Config
+	if doc.Chunker != "" {
+		if err := json.Unmarshal([]byte(doc.Chunker), &cfg); err != nil {
+			return nil, cfg, fmt.Errorf("解析文档切块配置失败: %v", err)
+		}
+	} else {
+		var err error
+		if cfg, err = chunker.Resolve(chunker.Config{Strategy: chunker.DefaultStrategy}); err != nil {
+			return nil, cfg, err
+		}
+	}
+
+	c, err := chunker.New(cfg, DefaultTokenizer)
+	if err != nil {
+		return nil, cfg, fmt.Errorf("文档切块配置无效: %v", err)
+	}
+	return c, cfg, nil
+}
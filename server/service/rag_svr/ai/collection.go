@@ -0,0 +1,460 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"server/framework/logger"
+	"server/framework/milvus"
+	"server/framework/mysql"
+	"server/framework/redis"
+	"server/service/rag_svr/authz"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxCollectionNameLength        = 100
+	maxCollectionDescriptionLength = 500
+	maxTagLength                   = 50
+	maxDocumentTags                = 20
+	// 移动文档时每批重写的块数
+	moveChunkBatchSize = 200
+)
+
+// 元数据过滤的字段路径，以点分隔嵌套字段，如 dept 或 source.type
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// ErrCollectionNotFound 知识库不存在或用户无权访问
+var ErrCollectionNotFound = errors.New("知识库不存在")
+
+// DocumentFilter 文档列表和检索的过滤条件，各条件同时满足
+type DocumentFilter struct {
+	Scope         *authz.Scope // 只包含用户能访问的文档
+	CollectionIDs []uint64
+	DocIDs        []uint64
+	Tags          []string          // 带有全部这些标签
+	Metadata      map[string]string // metadata 中对应字段的值相等，数字和布尔值按其文本比较
+}
+
+// IsScoped 是否有检索范围之外、需要先在 MySQL 中筛选文档的条件
+func (f *DocumentFilter) IsScoped() bool {
+	return len(f.Tags) > 0 || len(f.Metadata) > 0
+}
+
+// FilterDocuments 在 document 表的查询上加上过滤条件
+func FilterDocuments(db *gorm.DB, filter *DocumentFilter) (*gorm.DB, error) {
+	db = filter.Scope.FilterDocuments(db)
+	if len(filter.CollectionIDs) > 0 {
+		db = db.Where("document.collection_id IN ?", filter.CollectionIDs)
+	}
+	if len(filter.DocIDs) > 0 {
+		db = db.Where("document.doc_id IN ?", filter.DocIDs)
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := normalizeTags(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		// 标签表主键为 (doc_id, tag)，命中数等于标签数即带有全部标签；共享的文档由文档条件限定，不按用户过滤
+		tagged := mysql.GetDB().Table("document_tag").Select("doc_id").
+			Where("tag IN ?", tags).
+			Group("doc_id").Having("COUNT(*) = ?", len(tags))
+		db = db.Where("document.doc_id IN (?)", tagged)
+	}
+	keys := make([]string, 0, len(filter.Metadata))
+	for key := range filter.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path, err := metadataPath(key)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("JSON_UNQUOTE(JSON_EXTRACT(document.metadata, ?)) = ?", path, filter.Metadata[key])
+	}
+	return db, nil
+}
+
+// metadataPath 将以点分隔的字段路径转换为 JSON 路径，如 source.type 转换为 $."source"."type"
+func metadataPath(key string) (string, error) {
+	if !metadataKeyPattern.MatchString(key) {
+		return "", fmt.Errorf("元数据字段无效: %s", key)
+	}
+	return `$."` + strings.ReplaceAll(key, ".", `"."`) + `"`, nil
+}
+
+// FilterDocumentIDs 返回满足过滤条件的文档ID，用于在检索前按标签和元数据缩小范围
+func FilterDocumentIDs(ctx context.Context, filter *DocumentFilter) ([]uint64, error) {
+	db, err := FilterDocuments(mysql.GetDB().WithContext(ctx).Table("document"), filter)
+	if err != nil {
+		return nil, err
+	}
+	var docIDs []uint64
+	if err := db.Pluck("document.doc_id", &docIDs).Error; err != nil {
+		return nil, fmt.Errorf("筛选文档失败: %v", err)
+	}
+	return docIDs, nil
+}
+
+// normalizeTags 去掉首尾空白和空标签，不区分大小写去重，保持原有顺序
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("标签长度不能超过%d个字符: %s", maxTagLength, tag)
+		}
+		// 标签列的排序规则不区分大小写
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxDocumentTags {
+		return nil, fmt.Errorf("每个文档最多%d个标签", maxDocumentTags)
+	}
+	return result, nil
+}
+
+// replaceDocumentTags 在事务中替换文档的全部标签
+func replaceDocumentTags(tx *gorm.DB, doc *mysql.Document, tags []string) error {
+	if err := tx.Table("document_tag").Where("doc_id = ?", doc.DocID).Delete(&mysql.DocumentTag{}).Error; err != nil {
+		return fmt.Errorf("删除文档标签失败: %v", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]mysql.DocumentTag, len(tags))
+	for i, tag := range tags {
+		rows[i] = mysql.DocumentTag{DocID: doc.DocID, Tag: tag, UserID: doc.UserID}
+	}
+	if err := tx.Table("document_tag").Create(&rows).Error; err != nil {
+		return fmt.Errorf("保存文档标签失败: %v", err)
+	}
+	return nil
+}
+
+// LoadDocumentTags 批量获取文档的标签
+func LoadDocumentTags(ctx context.Context, docIDs []uint64) (map[uint64][]string, error) {
+	result := make(map[uint64][]string, len(docIDs))
+	if len(docIDs) == 0 {
+		return result, nil
+	}
+	var rows []mysql.DocumentTag
+	if err := mysql.GetDB().WithContext(ctx).Table("document_tag").
+		Where("doc_id IN ?", docIDs).Order("doc_id, created_at, tag").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("获取文档标签失败: %v", err)
+	}
+	for _, row := range rows {
+		result[row.DocID] = append(result[row.DocID], row.Tag)
+	}
+	return result, nil
+}
+
+// SetDocumentTags 替换文档的全部标签，返回规范化后的标签
+func (s *DocumentService) SetDocumentTags(ctx context.Context, doc *mysql.Document, tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceDocumentTags(tx, doc, tags)
+	}); err != nil {
+		return nil, err
+	}
+	invalidateDocumentCache(ctx, doc.DocID)
+	return tags, nil
+}
+
+// getCollection 获取知识库，不检查权限
+func getCollection(db *gorm.DB, collectionID uint64) (*mysql.KnowledgeCollection, error) {
+	var collection mysql.KnowledgeCollection
+	if err := db.Table("knowledge_collection").Where("collection_id = ?", collectionID).
+		First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("获取知识库失败: %v", err)
+	}
+	return &collection, nil
+}
+
+// lockCollection 在事务中以共享锁读取知识库并检查用户至少是编辑者，防止文档加入的同时知识库被删除
+func lockCollection(ctx context.Context, tx *gorm.DB, collectionID, userID uint64) error {
+	collection, err := getCollection(tx.Clauses(clause.Locking{Strength: "SHARE"}), collectionID)
+	if err != nil {
+		return err
+	}
+	_, err = checkCollectionRole(ctx, userID, collection, authz.RoleEditor)
+	return err
+}
+
+// checkCollectionRole 检查用户在知识库上的角色，没有任何角色时返回 ErrCollectionNotFound
+func checkCollectionRole(ctx context.Context, userID uint64, collection *mysql.KnowledgeCollection, need authz.Role) (authz.Role, error) {
+	role, err := authz.CheckCollection(ctx, userID, collection, need)
+	if errors.Is(err, authz.ErrNotFound) {
+		return role, ErrCollectionNotFound
+	}
+	return role, err
+}
+
+// GetCollection 获取知识库并检查用户的角色不低于 need，角色不足时返回 authz.ErrForbidden
+func (s *DocumentService) GetCollection(ctx context.Context, collectionID, userID uint64, need authz.Role) (*mysql.KnowledgeCollection, authz.Role, error) {
+	collection, err := getCollection(s.db.WithContext(ctx), collectionID)
+	if err != nil {
+		return nil, authz.RoleNone, err
+	}
+	role, err := checkCollectionRole(ctx, userID, collection, need)
+	if err != nil {
+		return nil, role, err
+	}
+	return collection, role, nil
+}
+
+// checkCollectionFields 校验知识库名称和描述，返回去掉首尾空白的名称
+func checkCollectionFields(name, description string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("知识库名称不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "", fmt.Errorf("知识库名称不能超过%d个字符", maxCollectionNameLength)
+	}
+	if utf8.RuneCountInString(description) > maxCollectionDescriptionLength {
+		return "", fmt.Errorf("知识库描述不能超过%d个字符", maxCollectionDescriptionLength)
+	}
+	return name, nil
+}
+
+// collectionNameTaken 同一创建者下是否已有同名的其他知识库
+func (s *DocumentService) collectionNameTaken(ctx context.Context, userID uint64, name string, excludeID uint64) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Table("knowledge_collection").
+		Where("user_id = ? AND name = ? AND collection_id <> ?", userID, name, excludeID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("检查知识库名称失败: %v", err)
+	}
+	return count > 0, nil
+}
+
+// CreateCollection 创建知识库，名称在同一用户下唯一
+func (s *DocumentService) CreateCollection(ctx context.Context, userID uint64, name, description string) (*mysql.KnowledgeCollection, error) {
+	name, err := checkCollectionFields(name, description)
+	if err != nil {
+		return nil, err
+	}
+	taken, err := s.collectionNameTaken(ctx, userID, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, fmt.Errorf("知识库名称已存在: %s", name)
+	}
+
+	collection := &mysql.KnowledgeCollection{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+	}
+	// 并发创建同名知识库时由唯一索引拦截
+	if err := s.db.WithContext(ctx).Table("knowledge_collection").Create(collection).Error; err != nil {
+		return nil, fmt.Errorf("创建知识库失败: %v", err)
+	}
+	logger.Infof("知识库创建成功: collection_id=%d, user_id=%d, name=%s", collection.CollectionID, userID, name)
+	return collection, nil
+}
+
+// ListCollections 获取用户创建的和被共享的知识库及各知识库的文档数
+func (s *DocumentService) ListCollections(ctx context.Context, scope *authz.Scope) ([]mysql.KnowledgeCollection, map[uint64]int64, error) {
+	collectionIDs := scope.CollectionIDs()
+	if len(collectionIDs) == 0 {
+		return nil, map[uint64]int64{}, nil
+	}
+	var collections []mysql.KnowledgeCollection
+	if err := s.db.WithContext(ctx).Table("knowledge_collection").Where("collection_id IN ?", collectionIDs).
+		Order("created_at, collection_id").Find(&collections).Error; err != nil {
+		return nil, nil, fmt.Errorf("获取知识库列表失败: %v", err)
+	}
+	counts, err := s.countCollectionDocuments(ctx, collectionIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return collections, counts, nil
+}
+
+// countCollectionDocuments 按知识库统计文档数，共享的知识库中其他用户的文档同样计入
+func (s *DocumentService) countCollectionDocuments(ctx context.Context, collectionIDs []uint64) (map[uint64]int64, error) {
+	var rows []struct {
+		CollectionID uint64
+		Count        int64
+	}
+	if err := s.db.WithContext(ctx).Table("document").
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", collectionIDs).
+		Group("collection_id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计知识库文档数失败: %v", err)
+	}
+	counts := make(map[uint64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountCollectionDocuments 知识库中的文档数
+func (s *DocumentService) CountCollectionDocuments(ctx context.Context, collectionID uint64) (int64, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Table("document").Where("collection_id = ?", collectionID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计知识库文档数失败: %v", err)
+	}
+	return count, nil
+}
+
+// UpdateCollection 修改知识库名称和描述，名称为空时只修改描述；编辑者即可修改
+func (s *DocumentService) UpdateCollection(ctx context.Context, collectionID, userID uint64, name, description string) (*mysql.KnowledgeCollection, authz.Role, error) {
+	collection, role, err := s.GetCollection(ctx, collectionID, userID, authz.RoleEditor)
+	if err != nil {
+		return nil, role, err
+	}
+	if strings.TrimSpace(name) == "" {
+		name = collection.Name
+	}
+	name, err = checkCollectionFields(name, description)
+	if err != nil {
+		return nil, role, err
+	}
+	if name != collection.Name {
+		taken, err := s.collectionNameTaken(ctx, collection.UserID, name, collectionID)
+		if err != nil {
+			return nil, role, err
+		}
+		if taken {
+			return nil, role, fmt.Errorf("知识库名称已存在: %s", name)
+		}
+	}
+
+	collection.Name = name
+	collection.Description = description
+	if err := s.db.WithContext(ctx).Table("knowledge_collection").
+		Where("collection_id = ?", collectionID).
+		Updates(map[string]interface{}{"name": name, "description": description}).Error; err != nil {
+		return nil, role, fmt.Errorf("修改知识库失败: %v", err)
+	}
+	return collection, role, nil
+}
+
+// DeleteCollection 删除知识库及其授权，只有所有者可以删除，知识库中还有文档时不能删除
+func (s *DocumentService) DeleteCollection(ctx context.Context, collectionID, userID uint64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 排他锁与加入文档时的共享锁互斥，统计期间不会有新文档加入
+		collection, err := getCollection(tx.Clauses(clause.Locking{Strength: "UPDATE"}), collectionID)
+		if err != nil {
+			return err
+		}
+		if _, err := checkCollectionRole(ctx, userID, collection, authz.RoleOwner); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Table("document").Where("collection_id = ?", collectionID).Count(&count).Error; err != nil {
+			return fmt.Errorf("统计知识库文档数失败: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("知识库中还有%d个文档，不能删除", count)
+		}
+		if err := tx.Table("knowledge_collection").Delete(&mysql.KnowledgeCollection{}, collectionID).Error; err != nil {
+			return fmt.Errorf("删除知识库失败: %v", err)
+		}
+		return authz.DeleteGrants(tx, authz.ResourceCollection, collectionID)
+	})
+}
+
+// MoveDocument 把文档移到另一个知识库，collectionID 为 0 表示移出知识库，userID 须是目标知识库的编辑者
+// 块向量的标量字段一并重写；Milvus 写入失败时可以重试，移到同一知识库会重新写入
+func (s *DocumentService) MoveDocument(ctx context.Context, doc *mysql.Document, userID, collectionID uint64) error {
+	// 与索引任务共用文档锁，避免索引中的块仍按原知识库写入 Milvus
+	if redis.GetClient() != nil {
+		lockKey := fmt.Sprintf("%s%d", indexLockPrefix, doc.DocID)
+		locked, err := redis.SetNX(ctx, lockKey, "move", getIndexerConfig().leaseTimeout)
+		if err == nil && !locked {
+			return fmt.Errorf("文档正在索引，请稍后再试")
+		}
+		if err == nil {
+			defer redis.Del(context.Background(), lockKey)
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if collectionID != 0 {
+			if err := lockCollection(ctx, tx, collectionID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Table("document").Where("doc_id = ?", doc.DocID).Update("collection_id", collectionID).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrCollectionNotFound) || errors.Is(err, authz.ErrForbidden) {
+			return err
+		}
+		return fmt.Errorf("移动文档失败: %v", err)
+	}
+	doc.CollectionID = collectionID
+	invalidateDocumentCache(ctx, doc.DocID)
+
+	if err := rewriteChunkVectorFields(ctx, doc); err != nil {
+		return err
+	}
+	logger.Infof("文档移动成功: doc_id=%d, collection_id=%d", doc.DocID, collectionID)
+	return nil
+}
+
+// rewriteChunkVectorFields 按 MySQL 中保存的向量重写文档全部块在 Milvus 中的标量字段
+func rewriteChunkVectorFields(ctx context.Context, doc *mysql.Document) error {
+	fields := documentVectorFields(doc)
+	var lastChunkID uint64
+	for {
+		var chunks []mysql.DocumentChunk
+		if err := mysql.GetDB().WithContext(ctx).Table("document_chunk").Select("chunk_id", "embedding").
+			Where("doc_id = ? AND chunk_id > ?", doc.DocID, lastChunkID).
+			Order("chunk_id").Limit(moveChunkBatchSize).Find(&chunks).Error; err != nil {
+			return fmt.Errorf("获取文档块失败: %v", err)
+		}
+		if len(chunks) == 0 {
+			return nil
+		}
+		lastChunkID = chunks[len(chunks)-1].ChunkID
+
+		ids := make([]int64, 0, len(chunks))
+		vectors := make([][]float32, 0, len(chunks))
+		rows := make([]map[string]interface{}, 0, len(chunks))
+		for _, chunk := range chunks {
+			if len(chunk.Embedding) == 0 {
+				continue
+			}
+			var vector []float32
+			if err := json.Unmarshal(chunk.Embedding, &vector); err != nil {
+				return fmt.Errorf("解析块向量失败: chunk_id=%d, err=%v", chunk.ChunkID, err)
+			}
+			ids = append(ids, int64(chunk.ChunkID))
+			vectors = append(vectors, vector)
+			rows = append(rows, fields)
+		}
+		if err := milvus.UpsertVectorsWithFields(ctx, milvus.DocumentCollectionName, ids, vectors, rows); err != nil {
+			return fmt.Errorf("更新块向量失败: %v", err)
+		}
+	}
+}
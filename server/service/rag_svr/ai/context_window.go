@@ -0,0 +1,271 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// 未配置上下文窗口时使用的默认值
+	defaultContextWindow = 8192
+	// 未配置最大输出时为回复预留的 token 数
+	defaultReservedOutputTokens = 1024
+	// 每条消息的角色、分隔符等额外开销
+	messageOverheadTokens = 4
+	// 知识（记忆和文档）最多占可分配预算的比例，剩余留给历史对话
+	knowledgeBudgetRatio = 0.5
+	// 截断后剩余不足该值的条目直接丢弃
+	minItemTokens = 32
+	// 截断标记
+	truncatedMark = "…"
+)
+
+// Tokenizer token 计数器
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// mixedTokenizer 中英文混合文本的 token 估算
+// 汉字、假名、全角符号按 1 个 token 计，英文和数字按每 4 个字符 1 个 token 计，
+// 与通义千问、GPT 系列分词器相比略偏多，保证不超出上下文窗口
+type mixedTokenizer struct{}
+
+// DefaultTokenizer 默认的 token 估算器
+var DefaultTokenizer Tokenizer = mixedTokenizer{}
+
+func (mixedTokenizer) CountTokens(text string) int {
+	tokens := 0
+	asciiRun := 0
+	flush := func() {
+		if asciiRun > 0 {
+			tokens += (asciiRun + 3) / 4
+			asciiRun = 0
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			asciiRun++
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			// 标点、汉字及其他字符
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// PromptTurn 一轮历史对话
+type PromptTurn struct {
+	User      string
+	Assistant string
+}
+
+// KnowledgeItem 注入提示词的一条知识
+type KnowledgeItem struct {
+	Label   string // 来源说明，如 "记忆/fact"、"文档《标题》"
+	Content string
+}
+
+// PromptInput 构建提示词的输入，列表均按优先级或时间排列
+type PromptInput struct {
+	SystemPrompt  string
+	Summary       string          // 会话摘要，可选，优先于历史对话
+	History       []PromptTurn    // 按时间正序
+	Memories      []KnowledgeItem // 按相关性降序
+	Documents     []KnowledgeItem // 按相关性降序
+	UserID        uint64
+	SessionID     uint64
+	Message       string
+	Now           time.Time
+	ReserveTokens int // 额外预留，如工具定义
+}
+
+// PromptResult 提示词构建结果
+type PromptResult struct {
+	Messages        []QwenMessage
+	UsedMemories    []int // 实际注入的记忆下标，与知识编号顺序一致
+	UsedDocuments   []int // 实际注入的文档下标，编号接在记忆之后
+	HistoryTurns    int   // 保留的历史轮数
+	DroppedTurns    int   // 因预算丢弃的较早轮数
+	EstimatedTokens int   // 提示词估算 token 数
+}
+
+// PromptBuilder 按上下文窗口预算组装对话提示词
+// 优先级：系统提示词、当前消息 > 会话摘要 > 记忆 > 文档 > 近期对话
+type PromptBuilder struct {
+	tokenizer     Tokenizer
+	contextWindow int
+	outputTokens  int
+}
+
+// NewPromptBuilder 创建提示词构建器，outputTokens 为回复预留的 token 数
+func NewPromptBuilder(contextWindow, outputTokens int) *PromptBuilder {
+	if contextWindow <= 0 {
+		contextWindow = defaultContextWindow
+	}
+	if outputTokens <= 0 {
+		outputTokens = defaultReservedOutputTokens
+	}
+	return &PromptBuilder{
+		tokenizer:     DefaultTokenizer,
+		contextWindow: contextWindow,
+		outputTokens:  outputTokens,
+	}
+}
+
+// CountTokens 估算文本的 token 数
+func (b *PromptBuilder) CountTokens(text string) int {
+	return b.tokenizer.CountTokens(text)
+}
+
+// Build 组装提示词，超出预算时截断或丢弃低优先级内容，不会返回错误
+func (b *PromptBuilder) Build(input *PromptInput) *PromptResult {
+	now := input.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	result := &PromptResult{
+		UsedMemories:  make([]int, 0, len(input.Memories)),
+		UsedDocuments: make([]int, 0, len(input.Documents)),
+	}
+
+	budget := b.contextWindow - b.outputTokens - input.ReserveTokens
+
+	// 系统提示词和当前消息必须保留，当前消息过长时截断
+	budget -= b.CountTokens(input.SystemPrompt) + messageOverheadTokens
+	template := b.formatUserPrompt(input, now, "", "")
+	budget -= b.CountTokens(template) + messageOverheadTokens
+	message := input.Message
+	if msgTokens := b.CountTokens(message); msgTokens > budget {
+		message = b.truncate(message, max(budget, minItemTokens))
+	}
+	budget -= b.CountTokens(message)
+
+	// 会话摘要
+	summary := ""
+	if input.Summary != "" && budget > minItemTokens {
+		summary = b.truncate(input.Summary, budget/4)
+		budget -= b.CountTokens(summary) + messageOverheadTokens
+	}
+
+	// 知识：先记忆后文档，最多占剩余预算的一半
+	var knowledge strings.Builder
+	knowledgeBudget := int(float64(max(budget, 0)) * knowledgeBudgetRatio)
+	used := 0
+	addKnowledge := func(item KnowledgeItem) bool {
+		line := fmt.Sprintf("[%d] (%s) ", used+1, item.Label)
+		remain := knowledgeBudget - b.CountTokens(line) - 1
+		if remain < minItemTokens {
+			return false
+		}
+		content := b.truncate(strings.TrimSpace(item.Content), remain)
+		line += content + "\n"
+		knowledgeBudget -= b.CountTokens(line)
+		budget -= b.CountTokens(line)
+		knowledge.WriteString(line)
+		used++
+		return true
+	}
+	for i, item := range input.Memories {
+		if addKnowledge(item) {
+			result.UsedMemories = append(result.UsedMemories, i)
+		}
+	}
+	for i, item := range input.Documents {
+		if addKnowledge(item) {
+			result.UsedDocuments = append(result.UsedDocuments, i)
+		}
+	}
+	knowledgeText := knowledge.String()
+	if knowledgeText == "" {
+		knowledgeText = "无"
+	}
+
+	// 近期对话：从最新一轮往前保留，放不下的较早轮次丢弃
+	kept := make([]QwenMessage, 0, len(input.History)*2)
+	for i := len(input.History) - 1; i >= 0; i-- {
+		turn := input.History[i]
+		userTokens := b.CountTokens(turn.User) + messageOverheadTokens
+		assistantTokens := b.CountTokens(turn.Assistant) + messageOverheadTokens
+		if userTokens+assistantTokens > budget {
+			// 最近一轮放不下时截断回复，较早的轮次直接丢弃
+			remain := budget - userTokens - messageOverheadTokens
+			if len(kept) > 0 || remain < minItemTokens {
+				result.DroppedTurns = i + 1
+				break
+			}
+			turn.Assistant = b.truncate(turn.Assistant, remain)
+			assistantTokens = b.CountTokens(turn.Assistant) + messageOverheadTokens
+		}
+		budget -= userTokens + assistantTokens
+		kept = append(kept,
+			QwenMessage{Role: "assistant", Content: turn.Assistant},
+			QwenMessage{Role: "user", Content: turn.User},
+		)
+		result.HistoryTurns++
+	}
+
+	messages := make([]QwenMessage, 0, len(kept)+3)
+	messages = append(messages, QwenMessage{Role: "system", Content: input.SystemPrompt})
+	if summary != "" {
+		messages = append(messages, QwenMessage{Role: "system", Content: "之前对话的摘要：\n" + summary})
+	}
+	for i := len(kept) - 1; i >= 0; i-- {
+		messages = append(messages, kept[i])
+	}
+	messages = append(messages, QwenMessage{
+		Role:    "user",
+		Content: b.formatUserPrompt(input, now, knowledgeText, message),
+	})
+	result.Messages = messages
+
+	for _, msg := range messages {
+		result.EstimatedTokens += b.CountTokens(msg.Content) + messageOverheadTokens
+	}
+	return result
+}
+
+// EstimateToolTokens 估算工具定义占用的 token 数，带工具调用时作为 ReserveTokens 传入
+func (b *PromptBuilder) EstimateToolTokens() int {
+	data, err := json.Marshal(buildQwenTools())
+	if err != nil {
+		return 0
+	}
+	return b.CountTokens(string(data))
+}
+
+func (b *PromptBuilder) formatUserPrompt(input *PromptInput, now time.Time, knowledge, message string) string {
+	return fmt.Sprintf(UserPrompt,
+		input.UserID,
+		input.SessionID,
+		now.Format("2006-01-02 15:04:05"),
+		knowledge,
+		message,
+	)
+}
+
+// truncate 将文本截断到不超过 maxTokens，保留开头部分
+func (b *PromptBuilder) truncate(text string, maxTokens int) string {
+	if b.CountTokens(text) <= maxTokens {
+		return text
+	}
+	runes := []rune(text)
+	// 二分查找能放下的最长前缀，为截断标记预留 1 个 token
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if b.CountTokens(string(runes[:mid])) <= maxTokens-1 {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo]) + truncatedMark
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +13,9 @@ import (
 	"server/framework/milvus"
 	"server/framework/mysql"
 	"server/framework/redis"
+	"server/service/rag_svr/authz"
+	"server/service/rag_svr/chunker"
+	"server/service/rag_svr/ingest"
 	"server/service/rag_svr/kitex_gen/rag_svr"
 
 	"github.com/yanyiwu/gojieba"
@@ -29,8 +31,6 @@ const (
 	// 文档相关配置
 	documentCachePrefix = "doc:"
 	documentCacheTTL    = 24 * time.Hour // 文档缓存24小时
-	searchCachePrefix   = "search:"
-	searchCacheTTL      = 1 * time.Hour // 搜索结果缓存1小时
 )
 
 // Document 文档结构
@@ -49,20 +49,6 @@ type DocumentMetadata struct {
 	Embedding []float32 `json:"embedding"`
 }
 
-// SearchResult 搜索结果
-type SearchResult struct {
-	Document   *mysql.Document `json:"document"`
-	Score      float32         `json:"score"`
-	Highlights []string        `json:"highlights"`
-}
-
-// DocumentSearchParams 文档搜索参数
-type DocumentSearchParams struct {
-	Query    string  `json:"query"`
-	TopK     int     `json:"top_k"`
-	MinScore float32 `json:"min_score"`
-}
-
 // Keyword 关键词结构体
 type Keyword struct {
 	Word   string  `json:"word"`
@@ -140,7 +126,8 @@ func (s *DocumentService) SetChunkEmbedding(chunk *mysql.DocumentChunk, vector [
 	return nil
 }
 
-// AddDocument 添加文档（参数为 req，返回 docID 和 error）
+// AddDocument 添加文档
+// 同步解析内容并保存文档、段落和句子，文档状态为 pending；切块、向量化和写入 Milvus 由后台索引任务完成
 func (s *DocumentService) AddDocument(ctx context.Context, req *rag_svr.AddDocumentReq) (uint64, error) {
 	// 生成新的文档ID
 	docID := id_generator.GetInstance().GetDocumentID()
@@ -150,6 +137,37 @@ func (s *DocumentService) AddDocument(ctx context.Context, req *rag_svr.AddDocum
 
 	logger.Infof("开始添加文档: docID=%d, userID=%d, title=%s", docID, req.UserId, req.Title)
 
+	// 解析文档内容，失败时不创建任何记录
+	paragraphs, err := parseDocument(req.Content, req.Data, req.MimeType, req.FileName)
+	if err != nil {
+		logger.Errorf("解析文档失败: docID=%d, err=%v", docID, err)
+		return 0, err
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return 0, err
+	}
+	// 未指定切块配置使用的知识库名称时，按所属知识库的名称选择
+	collectionName := req.Collection
+	if req.CollectionId != 0 {
+		// 向知识库添加文档至少需要编辑者角色
+		collection, _, err := s.GetCollection(ctx, req.CollectionId, req.UserId, authz.RoleEditor)
+		if err != nil {
+			return 0, err
+		}
+		if collectionName == "" {
+			collectionName = collection.Name
+		}
+	}
+
+	// 切块配置记录在文档上，重新索引时沿用
+	chunkerConfig, err := resolveChunkerConfig(collectionName, req.Chunker)
+	if err != nil {
+		return 0, err
+	}
+	chunkerJSON, _ := json.Marshal(chunkerConfig)
+
 	metadata := "{}"
 	if req.Metadata != "" {
 		metadata = req.Metadata
@@ -157,12 +175,14 @@ func (s *DocumentService) AddDocument(ctx context.Context, req *rag_svr.AddDocum
 
 	// 组装文档结构体
 	doc := &mysql.Document{
-		DocID:    docID,
-		UserID:   req.UserId,
-		Title:    req.Title,
-		Status:   "active",
-		Metadata: metadata,
-		Keywords: "{}",
+		DocID:        docID,
+		UserID:       req.UserId,
+		CollectionID: req.CollectionId,
+		Title:        req.Title,
+		Status:       DocumentStatusPending,
+		Metadata:     metadata,
+		Keywords:     "{}",
+		Chunker:      string(chunkerJSON),
 	}
 	// 开启事务
 	tx := s.db.Begin()
@@ -183,130 +203,99 @@ func (s *DocumentService) AddDocument(ctx context.Context, req *rag_svr.AddDocum
 	}
 	logger.Infof("文档记录创建成功: docID=%d", docID)
 
-	// 使用 neurosnap/sentences 切分段落和句子
-	paragraphs := splitIntoParagraphs(req.Content)
+	// 持有知识库的共享锁直到提交，知识库不会在此期间被删除
+	if req.CollectionId != 0 {
+		if err := lockCollection(ctx, tx, req.CollectionId, req.UserId); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	if err := replaceDocumentTags(tx, doc, tags); err != nil {
+		logger.Errorf("保存文档标签失败: %v", err)
+		tx.Rollback()
+		return 0, err
+	}
+
+	paragraphCount, sentenceCount, err := saveParagraphs(tx, docID, paragraphs)
+	if err != nil {
+		logger.Errorf("保存段落失败: %v", err)
+		tx.Rollback()
+		return 0, err
+	}
+	doc.ParagraphCount = paragraphCount
+	doc.SentenceCount = sentenceCount
+	// 明确指定表名保存文档
+	if err := tx.Table("document").Save(doc).Error; err != nil {
+		logger.Errorf("保存文档失败: %v", err)
+		tx.Rollback()
+		return 0, fmt.Errorf("保存文档失败: %v", err)
+	}
+	if _, err := createDocumentVersion(tx, doc, req.UserId, paragraphs, 0); err != nil {
+		logger.Errorf("创建文档版本失败: %v", err)
+		tx.Rollback()
+		return 0, err
+	}
+	// 与文档在同一事务中创建索引任务，保证每个文档都会被索引
+	if err := enqueueIndexJob(tx, docID, req.UserId); err != nil {
+		logger.Errorf("创建索引任务失败: %v", err)
+		tx.Rollback()
+		return 0, fmt.Errorf("创建索引任务失败: %v", err)
+	}
+	logger.Infof("准备提交事务: docID=%d", docID)
+	if err := tx.Commit().Error; err != nil {
+		logger.Errorf("提交事务失败: %v", err)
+		return 0, fmt.Errorf("提交事务失败: %v", err)
+	}
+	notifyIndexWorkers()
+
+	logger.Infof("添加文档成功，等待索引: docID=%d, 段落数=%d, 句子数=%d, 切块=%s", docID, doc.ParagraphCount, doc.SentenceCount, chunkerConfig)
+	return docID, nil
+}
+
+// saveParagraphs 保存段落和句子，段落和句子ID在文档内从 1 开始按顺序编号
+func saveParagraphs(tx *gorm.DB, docID uint64, paragraphs []ingest.Paragraph) (paragraphCount, sentenceCount uint32, err error) {
 	jieba := gojieba.NewJieba()
 	defer jieba.Free()
 	globalSentenceID := uint64(1)
-	for i, paraContent := range paragraphs {
+	for i, paragraph := range paragraphs {
 		paraID := uint64(i + 1)
+		paraContent := paragraph.Content
 		keywords := jieba.Extract(paraContent, 5)
 		keywordsJSON, _ := json.Marshal(keywords)
 
 		// 使用更安全的句子分割方式
 		sentences := splitIntoSentences(paraContent)
-		sentenceIDs := make([]uint64, len(sentences))
+		sentenceRows := make([]mysql.DocumentSentence, 0, len(sentences))
 		sentenceIDMin := globalSentenceID
-		for j, sentContent := range sentences {
-			sentID := globalSentenceID
-			sentenceIDs[j] = sentID
-			sentRow := &mysql.DocumentSentence{
+		for _, sentContent := range sentences {
+			sentenceRows = append(sentenceRows, mysql.DocumentSentence{
 				DocID:       docID,
-				SentenceID:  sentID,
+				SentenceID:  globalSentenceID,
 				ParagraphID: paraID,
 				Content:     sentContent,
-			}
-			// 明确指定表名创建句子
-			if err := tx.Table("document_sentence").Create(sentRow).Error; err != nil {
-				logger.Errorf("创建句子失败: %v", err)
-				tx.Rollback()
-				return 0, fmt.Errorf("创建句子失败: %v", err)
-			}
+			})
 			globalSentenceID++
 		}
-		sentenceIDMax := globalSentenceID - 1
+		// 明确指定表名批量创建句子
+		if err := tx.Table("document_sentence").Create(&sentenceRows).Error; err != nil {
+			return 0, 0, fmt.Errorf("创建句子失败: %v", err)
+		}
+		metaJSON, _ := json.Marshal(paragraph.Meta)
 		para := &mysql.DocumentParagraph{
 			ParagraphID:   paraID,
 			DocID:         docID,
 			Content:       paraContent,
 			SentenceIDMin: sentenceIDMin,
-			SentenceIDMax: sentenceIDMax,
+			SentenceIDMax: globalSentenceID - 1,
 			Keywords:      string(keywordsJSON),
+			Metadata:      string(metaJSON),
 		}
 		// 明确指定表名创建段落
 		if err := tx.Table("document_paragraph").Create(para).Error; err != nil {
-			logger.Errorf("创建段落失败: %v", err)
-			tx.Rollback()
-			return 0, fmt.Errorf("创建段落失败: %v", err)
-		}
-		// 统一滑动窗口生成块，每块3句，步长2，最后不足3句也生成一块
-		window := 3
-		step := 2
-		for j := 0; j < len(sentences); j += step {
-			end := j + window
-			if end > len(sentences) {
-				end = len(sentences)
-			}
-			chunkID := id_generator.GetInstance().GetDocumentChunkID()
-			if chunkID == 0 {
-				logger.Errorf("获取块ID失败")
-				tx.Rollback()
-				return 0, fmt.Errorf("获取块ID失败")
-			}
-			// 合并 [j:end) 这些句子
-			var chunkContent string
-			for k := j; k < end; k++ {
-				chunkContent += sentences[k] + " "
-			}
-			chunkContent = strings.TrimSpace(chunkContent)
-			chunkKeywords := jieba.Extract(chunkContent, 5)
-			chunkKeywordsJSON, _ := json.Marshal(chunkKeywords)
-			embedding, err := GetEmbedding(chunkContent)
-			if err != nil {
-				logger.Errorf("生成块向量失败: %v", err)
-				tx.Rollback()
-				return 0, fmt.Errorf("生成块向量失败: %v", err)
-			}
-			embeddingBytes, err := json.Marshal(embedding)
-			if err != nil {
-				logger.Errorf("序列化向量失败: %v", err)
-				tx.Rollback()
-				return 0, fmt.Errorf("序列化向量失败: %v", err)
-			}
-			chunk := &mysql.DocumentChunk{
-				ChunkID:       chunkID,
-				DocID:         docID,
-				ParagraphID:   paraID,
-				SentenceIDMin: sentenceIDs[j],
-				SentenceIDMax: sentenceIDs[end-1],
-				Keywords:      string(chunkKeywordsJSON),
-				Embedding:     embeddingBytes,
-			}
-			// 明确指定表名创建块
-			if err := tx.Table("document_chunk").Create(chunk).Error; err != nil {
-				logger.Errorf("创建块失败: %v", err)
-				tx.Rollback()
-				return 0, fmt.Errorf("创建块失败: %v", err)
-			}
-			if err := milvus.InsertVector(ctx, milvus.DocumentCollectionName, int64(chunkID), embedding); err != nil {
-				logger.Errorf("存储向量到 Milvus 失败: %v", err)
-				tx.Rollback()
-				return 0, fmt.Errorf("存储向量到 Milvus 失败: %v", err)
-			}
-			if end == len(sentences) {
-				break // 最后一块
-			}
+			return 0, 0, fmt.Errorf("创建段落失败: %v", err)
 		}
-		logger.Infof("段落: %d, 句子: %d", i, globalSentenceID)
-	}
-	doc.ParagraphCount = uint32(len(paragraphs))
-	doc.SentenceCount = uint32(globalSentenceID - 1)
-	if doc.Keywords == "" {
-		doc.Keywords = "{}"
-	}
-	// 明确指定表名保存文档
-	if err := tx.Table("document").Save(doc).Error; err != nil {
-		logger.Errorf("保存文档失败: %v", err)
-		tx.Rollback()
-		return 0, fmt.Errorf("保存文档失败: %v", err)
 	}
-	logger.Infof("准备提交事务: docID=%d", docID)
-	if err := tx.Commit().Error; err != nil {
-		logger.Errorf("提交事务失败: %v", err)
-		return 0, fmt.Errorf("提交事务失败: %v", err)
-	}
-	logger.Infof("添加文档成功: docID=%d", docID)
-	return docID, nil
+	return uint32(len(paragraphs)), uint32(globalSentenceID - 1), nil
 }
 
 // IndexDocument 索引文档
@@ -326,7 +315,7 @@ func IndexDocument(ctx context.Context, doc *mysql.Document) error {
 	content := contentBuilder.String()
 
 	// 3. 生成文档向量
-	embedding, err := GetEmbedding(content)
+	embedding, err := GetEmbedding(ctx, content)
 	if err != nil {
 		return fmt.Errorf("生成文档向量失败: %v", err)
 	}
@@ -362,303 +351,225 @@ func IndexDocument(ctx context.Context, doc *mysql.Document) error {
 	return nil
 }
 
-// SearchDocuments 搜索文档
-func SearchDocuments(ctx context.Context, params *DocumentSearchParams) ([]*SearchResult, error) {
-	// 1. 尝试从缓存获取
-	cacheKey := fmt.Sprintf("%s%s:%d:%.2f", searchCachePrefix, params.Query, params.TopK, params.MinScore)
-	if cached, err := redis.Get(ctx, cacheKey); err == nil {
-		var results []*SearchResult
-		if err := json.Unmarshal([]byte(cached), &results); err == nil {
-			return results, nil
+// UpdateDocumentResult 更新文档的段落比对结果
+type UpdateDocumentResult struct {
+	ParagraphsAdded     uint32
+	ParagraphsRemoved   uint32
+	ParagraphsUnchanged uint32
+	Reindexing          bool   // 内容或切块配置有变化，已创建索引任务
+	Version             uint32 // 内容或切块配置有变化时生成的新版本
+}
+
+// UpdateDocument 更新文档的标题、元数据、内容或切块配置，未指定的字段保持不变
+// 新内容与已保存的段落比对，有变化时重新保存段落并创建索引任务，索引任务只为内容变化的块重新生成向量
+func (s *DocumentService) UpdateDocument(ctx context.Context, doc *mysql.Document, req *rag_svr.UpdateDocumentReq) (*UpdateDocumentResult, error) {
+	result := &UpdateDocumentResult{}
+
+	var paragraphs []ingest.Paragraph
+	contentChanged := false
+	if req.Content != "" || len(req.Data) > 0 {
+		var err error
+		if paragraphs, err = parseDocument(req.Content, req.Data, req.MimeType, req.FileName); err != nil {
+			return nil, err
+		}
+		if contentChanged, err = s.diffParagraphs(ctx, doc.DocID, paragraphs, result); err != nil {
+			return nil, err
 		}
 	}
 
-	// 2. 生成查询向量
-	queryEmbedding, err := GetEmbedding(params.Query)
-	if err != nil {
-		return nil, fmt.Errorf("生成查询向量失败: %v", err)
+	chunkerChanged := false
+	if req.Chunker != nil && req.Chunker.Strategy != "" {
+		chunkerConfig, err := resolveChunkerConfig("", req.Chunker)
+		if err != nil {
+			return nil, err
+		}
+		chunkerJSON, _ := json.Marshal(chunkerConfig)
+		var current chunker.Config
+		currentJSON := []byte(nil)
+		if doc.Chunker != "" && json.Unmarshal([]byte(doc.Chunker), &current) == nil {
+			currentJSON, _ = json.Marshal(current)
+		}
+		if string(chunkerJSON) != string(currentJSON) {
+			doc.Chunker = string(chunkerJSON)
+			chunkerChanged = true
+		}
 	}
 
-	// 3. 在 Milvus 中搜索相似向量
-	ids, scores, err := milvus.SearchVector(ctx, milvus.DocumentCollectionName, queryEmbedding, params.TopK*2) // 获取更多结果用于重排序
-	if err != nil {
-		return nil, fmt.Errorf("搜索向量失败: %v", err)
+	if req.Title != "" {
+		doc.Title = req.Title
 	}
-
-	// 4. 获取对应的文档块记录
-	var chunks []*mysql.DocumentChunk
-	if err := mysql.GetDB().Table("document_chunk").Where("chunk_id IN ?", ids).Find(&chunks).Error; err != nil {
-		return nil, fmt.Errorf("获取文档块记录失败: %v", err)
+	if req.Metadata != "" {
+		doc.Metadata = req.Metadata
 	}
+	result.Reindexing = contentChanged || chunkerChanged
 
-	// 5. 创建 ID 到分数的映射
-	scoreMap := make(map[uint64]float32)
-	for i, id := range ids {
-		scoreMap[uint64(id)] = scores[i]
+	if result.Reindexing && !contentChanged {
+		// 只修改切块配置时，新版本沿用当前的段落
+		var err error
+		if paragraphs, err = currentParagraphs(ctx, doc.DocID); err != nil {
+			return nil, err
+		}
 	}
-
-	// 6. 构建搜索结果
-	results := make([]*SearchResult, 0, len(chunks))
-	for _, chunk := range chunks {
-		score := scoreMap[chunk.ChunkID]
-		if score < params.MinScore {
-			continue
+	if doc.Chunker == "" {
+		// 早期文档没有记录切块配置，记录实际使用的默认配置
+		if _, cfg, err := documentChunker(doc); err == nil {
+			chunkerJSON, _ := json.Marshal(cfg)
+			doc.Chunker = string(chunkerJSON)
 		}
+	}
 
-		// 查询块的句子
-		var sentences []mysql.DocumentSentence
-		if err := mysql.GetDB().Where("sentence_id IN ? AND doc_id = ?", []uint64{chunk.SentenceIDMin, chunk.SentenceIDMax}, chunk.DocID).
-			Order("sentence_id").Find(&sentences).Error; err != nil {
-			continue
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if contentChanged {
+			if err := replaceParagraphs(tx, doc, paragraphs); err != nil {
+				return err
+			}
 		}
-
-		// 提取句子内容
-		sentenceContents := make([]string, len(sentences))
-		for i, sent := range sentences {
-			sentenceContents[i] = sent.Content
+		if result.Reindexing {
+			doc.Status = DocumentStatusPending
 		}
-
-		// 合并句子内容用于生成高亮
-		content := strings.Join(sentenceContents, " ")
-
-		// 生成高亮文本
-		highlights := generateHighlights(content, params.Query)
-
-		// 获取文档信息
-		var doc mysql.Document
-		if err := mysql.GetDB().First(&doc, chunk.DocID).Error; err != nil {
-			continue
+		if err := tx.Table("document").Save(doc).Error; err != nil {
+			return fmt.Errorf("更新文档失败: %v", err)
 		}
-
-		results = append(results, &SearchResult{
-			Document:   &doc,
-			Score:      score,
-			Highlights: highlights,
-		})
-	}
-
-	// 7. 按分数排序
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
+		if !result.Reindexing {
+			return nil
+		}
+		// 新版本索引完成前继续检索当前版本
+		var err error
+		if result.Version, err = createDocumentVersion(tx, doc, req.UserId, paragraphs, 0); err != nil {
+			return err
+		}
+		// 块由索引任务按文档记录的切块配置重新生成，内容不变的块沿用原向量
+		if err := enqueueIndexJob(tx, doc.DocID, doc.UserID); err != nil {
+			return fmt.Errorf("创建索引任务失败: %v", err)
+		}
+		return nil
 	})
-
-	// 8. 只返回前 TopK 个结果
-	if len(results) > params.TopK {
-		results = results[:params.TopK]
+	if err != nil {
+		return nil, err
 	}
-
-	// 9. 缓存搜索结果
-	if resultsJSON, err := json.Marshal(results); err == nil {
-		redis.Set(ctx, cacheKey, string(resultsJSON), searchCacheTTL)
+	if result.Reindexing {
+		notifyIndexWorkers()
 	}
+	invalidateDocumentCache(ctx, doc.DocID)
 
-	return results, nil
+	logger.Infof("更新文档成功: docID=%d, 新增段落=%d, 删除段落=%d, 未变段落=%d, 新版本=%d",
+		doc.DocID, result.ParagraphsAdded, result.ParagraphsRemoved, result.ParagraphsUnchanged, result.Version)
+	return result, nil
 }
 
-// UpdateDocument 更新文档
-func UpdateDocument(ctx context.Context, doc *mysql.Document, content string) error {
-	// 开启事务
-	tx := mysql.GetDB().Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("开启事务失败: %v", tx.Error)
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// diffParagraphs 按内容和结构信息比对新旧段落，统计新增、删除和未变的段落数
+// 段落顺序变化也视为内容有变化
+func (s *DocumentService) diffParagraphs(ctx context.Context, docID uint64, paragraphs []ingest.Paragraph, result *UpdateDocumentResult) (bool, error) {
+	var stored []mysql.DocumentParagraph
+	if err := s.db.WithContext(ctx).Table("document_paragraph").Where("doc_id = ?", docID).Order("paragraph_id").Find(&stored).Error; err != nil {
+		return false, fmt.Errorf("获取文档段落失败: %v", err)
+	}
+
+	// 元数据存在 JSON 列中，格式可能被数据库改写，统一重新序列化后比较
+	paragraphKey := func(content string, meta ingest.ParagraphMeta) string {
+		metaJSON, _ := json.Marshal(meta)
+		return content + "\x00" + string(metaJSON)
+	}
+	oldKeys := make([]string, len(stored))
+	remaining := make(map[string]int, len(stored))
+	for i, paragraph := range stored {
+		var meta ingest.ParagraphMeta
+		if paragraph.Metadata != "" {
+			_ = json.Unmarshal([]byte(paragraph.Metadata), &meta)
 		}
-	}()
-
-	// 1. 删除旧的段落、句子和块
-	if err := tx.Table("document_paragraph").Where("doc_id = ?", doc.DocID).Delete(&mysql.DocumentParagraph{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除旧段落失败: %v", err)
+		oldKeys[i] = paragraphKey(paragraph.Content, meta)
+		remaining[oldKeys[i]]++
 	}
 
-	// 2. 分割段落
-	paragraphs := splitIntoParagraphs(content)
-	for idx, paraContent := range paragraphs {
-		_ = idx // 如果后续不用 i，直接忽略
-		paragraph := &mysql.DocumentParagraph{
-			ParagraphID: id_generator.GetInstance().GetDocumentParagraphID(),
-			DocID:       doc.DocID,
-			Content:     paraContent,
-		}
-		// 明确指定表名创建段落
-		if err := tx.Table("document_paragraph").Create(paragraph).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("创建段落失败: %v", err)
-		}
-
-		// 3. 分割句子
-		sentences := splitIntoSentences(paraContent)
-		sentenceIDs := make([]uint64, len(sentences))
-
-		for j, sentContent := range sentences {
-			sentenceID := id_generator.GetInstance().GetDocumentSentenceID()
-			sentenceIDs[j] = sentenceID
-
-			sentence := &mysql.DocumentSentence{
-				SentenceID:  sentenceID,
-				DocID:       doc.DocID,
-				ParagraphID: paragraph.ParagraphID,
-				Content:     sentContent,
-			}
-			// 明确指定表名创建句子
-			if err := tx.Table("document_sentence").Create(sentence).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("创建句子失败: %v", err)
-			}
+	changed := len(paragraphs) != len(stored)
+	for i, paragraph := range paragraphs {
+		key := paragraphKey(paragraph.Content, paragraph.Meta)
+		if i < len(oldKeys) && oldKeys[i] != key {
+			changed = true
 		}
-
-		// 4. 创建文档块
-		if len(sentences) >= 3 {
-			for j := 0; j < len(sentences)-2; j += 2 {
-				chunk := &mysql.DocumentChunk{
-					ChunkID:       id_generator.GetInstance().GetDocumentChunkID(),
-					DocID:         doc.DocID,
-					ParagraphID:   paragraph.ParagraphID,
-					SentenceIDMin: sentenceIDs[j],
-					SentenceIDMax: sentenceIDs[j+2],
-				}
-
-				// 生成块向量
-				chunkContent := sentences[j] + " " + sentences[j+1] + " " + sentences[j+2]
-				embedding, err := GetEmbedding(chunkContent)
-				if err != nil {
-					tx.Rollback()
-					return fmt.Errorf("生成块向量失败: %v", err)
-				}
-
-				// 设置块向量
-				data := make([]byte, len(embedding)*4)
-				for i, v := range embedding {
-					binary.LittleEndian.PutUint32(data[i*4:], uint32(v))
-				}
-				chunk.Embedding = data
-
-				// 明确指定表名创建块
-				if err := tx.Table("document_chunk").Create(chunk).Error; err != nil {
-					tx.Rollback()
-					return fmt.Errorf("创建块失败: %v", err)
-				}
-			}
+		if remaining[key] > 0 {
+			remaining[key]--
+			result.ParagraphsUnchanged++
+		} else {
+			result.ParagraphsAdded++
 		}
 	}
+	result.ParagraphsRemoved = uint32(len(stored)) - result.ParagraphsUnchanged
+	return changed, nil
+}
 
-	// 5. 生成新的文档向量
-	var contentBuilder strings.Builder
-	for _, para := range paragraphs {
-		contentBuilder.WriteString(para)
-		contentBuilder.WriteString("\n\n")
+// invalidateDocumentCache 删除文档缓存
+func invalidateDocumentCache(ctx context.Context, docID uint64) {
+	if redis.GetClient() == nil {
+		return
 	}
-	content = contentBuilder.String()
-
-	embedding, err := GetEmbedding(content)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("生成文档向量失败: %v", err)
+	if err := redis.Del(ctx, fmt.Sprintf("%s%d", documentCachePrefix, docID)); err != nil {
+		logger.Warnf("删除文档缓存失败: doc_id=%d, err=%v", docID, err)
 	}
+}
 
-	// 6. 更新元数据
-	metadata := DocumentMetadata{
-		Embedding: embedding,
-	}
-	metadataJSON, err := json.Marshal(metadata)
+// deleteDocumentFiles 删除 AddDocumentFile 关联到文档的 GridFS 原始文件，失败只打日志
+func deleteDocumentFiles(ctx context.Context, docID uint64) {
+	count, err := mongodb.DeleteFilesByMetadata(ctx, mongodb.DocumentBucket, "doc_id", docID)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("序列化元数据失败: %v", err)
-	}
-	doc.Metadata = string(metadataJSON)
-
-	// 7. 更新数据库
-	if err := tx.Table("document").Save(doc).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("更新文档失败: %v", err)
+		logger.Errorf("删除文档原始文件失败: doc_id=%d, err=%v", docID, err)
+		return
 	}
-
-	// 8. 更新 Milvus
-	if err := milvus.UpdateVector(ctx, milvus.DocumentCollectionName, int64(doc.DocID), embedding); err != nil {
-		// 如果 Milvus 更新失败，回滚数据库操作
-		tx.Rollback()
-		return fmt.Errorf("更新向量失败: %v", err)
+	if count > 0 {
+		logger.Infof("删除文档原始文件: doc_id=%d, files=%d", docID, count)
 	}
-
-	// 9. 更新缓存
-	cacheKey := fmt.Sprintf("%s%d", documentCachePrefix, doc.DocID)
-	if docJSON, err := json.Marshal(doc); err == nil {
-		redis.Set(ctx, cacheKey, string(docJSON), documentCacheTTL)
-	}
-
-	// 10. 删除相关的搜索结果缓存
-	pattern := fmt.Sprintf("%s*", searchCachePrefix)
-	keys, err := redis.Keys(ctx, pattern)
-	if err == nil {
-		for _, key := range keys {
-			redis.Del(ctx, key)
-		}
-	}
-
-	return tx.Commit().Error
 }
 
-// DeleteDocument 删除文档
-func DeleteDocument(ctx context.Context, docID uint64) error {
-	// 开启事务
-	tx := mysql.GetDB().Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("开启事务失败: %v", tx.Error)
+// deleteDocumentData 在事务中删除文档及其段落、句子、块、版本、索引任务、标签和授权，
+// 最后按 doc_id 删除 Milvus 中的块向量，向量删除失败时回滚
+func deleteDocumentData(ctx context.Context, tx *gorm.DB, docID uint64) error {
+	tables := []struct {
+		name  string
+		model interface{}
+	}{
+		{"document_paragraph", &mysql.DocumentParagraph{}},
+		{"document_sentence", &mysql.DocumentSentence{}},
+		{"document_chunk", &mysql.DocumentChunk{}},
+		{"document_version", &mysql.DocumentVersion{}},
+		{"document_index_job", &mysql.DocumentIndexJob{}},
+		{"document_tag", &mysql.DocumentTag{}},
+	}
+	if err := tx.Table("document").Where("doc_id = ?", docID).Delete(&mysql.Document{}).Error; err != nil {
+		return fmt.Errorf("删除文档失败: %v", err)
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	for _, table := range tables {
+		if err := tx.Table(table.name).Where("doc_id = ?", docID).Delete(table.model).Error; err != nil {
+			return fmt.Errorf("删除 %s 失败: %v", table.name, err)
 		}
-	}()
-
-	// 1. 删除文档
-	if err := tx.Table("document").Delete(&mysql.Document{}, docID).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除文档失败: %v", err)
 	}
-
-	// 2. 删除段落
-	if err := tx.Table("document_paragraph").Where("doc_id = ?", docID).Delete(&mysql.DocumentParagraph{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除段落失败: %v", err)
+	if err := authz.DeleteGrants(tx, authz.ResourceDocument, docID); err != nil {
+		return err
 	}
-
-	// 3. 删除句子
-	if err := tx.Table("document_sentence").Where("doc_id = ?", docID).Delete(&mysql.DocumentSentence{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除句子失败: %v", err)
+	if err := milvus.DeleteVectorsByExpr(ctx, milvus.DocumentCollectionName, fmt.Sprintf("%s == %d", vectorFieldDocID, docID)); err != nil {
+		return fmt.Errorf("删除块向量失败: %v", err)
 	}
+	return nil
+}
 
-	// 4. 删除文档块
-	if err := tx.Table("document_chunk").Where("doc_id = ?", docID).Delete(&mysql.DocumentChunk{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除文档块失败: %v", err)
+// parseDocument 按内容类型解析文档，优先使用原始文件内容，未指定类型时根据文件名和内容识别
+func parseDocument(content string, data []byte, declaredMIME, fileName string) ([]ingest.Paragraph, error) {
+	if len(data) == 0 {
+		data = []byte(content)
 	}
-
-	// 5. 从 Milvus 删除
-	if err := milvus.DeleteVector(ctx, milvus.DocumentCollectionName, int64(docID)); err != nil {
-		// 如果 Milvus 删除失败，回滚数据库操作
-		tx.Rollback()
-		return fmt.Errorf("删除向量失败: %v", err)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("文档内容不能为空")
 	}
 
-	// 6. 删除缓存
-	cacheKey := fmt.Sprintf("%s%d", documentCachePrefix, docID)
-	redis.Del(ctx, cacheKey)
-
-	// 7. 删除相关的搜索结果缓存
-	pattern := fmt.Sprintf("%s*", searchCachePrefix)
-	keys, err := redis.Keys(ctx, pattern)
-	if err == nil {
-		for _, key := range keys {
-			redis.Del(ctx, key)
-		}
+	mimeType := ingest.DetectMIME(declaredMIME, fileName, data)
+	if !ingest.Supported(mimeType) {
+		return nil, fmt.Errorf("不支持的文档类型: %s", mimeType)
 	}
-
-	return tx.Commit().Error
+	paragraphs, err := ingest.Extract(mimeType, data)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("文档解析完成: mime_type=%s, 段落数=%d", mimeType, len(paragraphs))
+	return paragraphs, nil
 }
 
 // splitIntoParagraphs 将文本分割成段落
@@ -703,106 +614,179 @@ func splitIntoSentences(content string) []string {
 	return result
 }
 
-// generateHighlights 生成高亮文本
-func generateHighlights(content, query string) []string {
-	// TODO: 实现高亮文本生成
-	return nil
-}
-
-// SearchDocument 搜索文档（迁移自 handler.go）
+// SearchDocument 在用户创建的和被共享的文档中检索，向量检索与关键词检索按 RRF 融合，配置了重排模型时再按重排分数排序和过滤（迁移自 handler.go）
 func (s *DocumentService) SearchDocument(ctx context.Context, req *rag_svr.SearchDocumentReq) (*rag_svr.SearchDocumentRsp, error) {
-	logger.Infof("搜索文档请求: user_id=%d, query=%s, top_k=%d", req.UserId, req.Query, req.TopK)
+	logger.Infof("搜索文档请求: user_id=%d, query=%s, top_k=%d, vector_weight=%.2f, keyword_weight=%.2f, min_score=%.2f",
+		req.UserId, req.Query, req.TopK, req.VectorWeight, req.KeywordWeight, req.MinScore)
 
-	queryEmbedding, err := GetEmbedding(req.Query)
+	topK := int(req.TopK)
+	if topK <= 0 {
+		topK = defaultRetrievalTopK
+	}
+	// 配置了重排模型时多取一些候选，由重排模型决定最终顺序
+	reranker := GetReranker()
+	candidates := topK
+	if reranker != nil {
+		candidates = topK * rerankCandidateFactor()
+	}
+
+	scope, err := authz.LoadScope(ctx, req.UserId)
 	if err != nil {
-		logger.Errorf("生成查询向量失败: %v", err)
+		logger.Errorf("加载授权范围失败: %v", err)
 		return &rag_svr.SearchDocumentRsp{
 			Code: 1,
-			Msg:  fmt.Sprintf("生成查询向量失败: %v", err),
+			Msg:  err.Error(),
 		}, nil
 	}
 
-	ids, scores, err := milvus.SearchVector(ctx, milvus.DocumentCollectionName, queryEmbedding, int(req.TopK*2))
+	// 按标签和元数据过滤时先在 MySQL 中筛出文档，再只检索这些文档的块
+	docScope := req.DocIds
+	filter := &DocumentFilter{
+		Scope:         scope,
+		CollectionIDs: req.CollectionIds,
+		DocIDs:        req.DocIds,
+		Tags:          req.Tags,
+		Metadata:      req.MetadataFilters,
+	}
+	if filter.IsScoped() {
+		if docScope, err = FilterDocumentIDs(ctx, filter); err != nil {
+			logger.Errorf("筛选文档失败: %v", err)
+			return &rag_svr.SearchDocumentRsp{
+				Code: 1,
+				Msg:  err.Error(),
+			}, nil
+		}
+		if len(docScope) == 0 {
+			logger.Infof("没有满足过滤条件的文档: user_id=%d, tags=%v, metadata=%v", req.UserId, req.Tags, req.MetadataFilters)
+			return &rag_svr.SearchDocumentRsp{
+				Code: 0,
+				Msg:  "success",
+			}, nil
+		}
+	}
+
+	results, err := HybridSearch(ctx, req.Query, RetrievalOptions{
+		TopK:          candidates,
+		UserID:        req.UserId,
+		Scope:         scope,
+		CollectionIDs: req.CollectionIds,
+		DocIDs:        docScope,
+		VectorWeight:  float64(req.VectorWeight),
+		KeywordWeight: float64(req.KeywordWeight),
+	})
 	if err != nil {
-		logger.Errorf("向量搜索失败: %v", err)
+		logger.Errorf("检索文档失败: %v", err)
 		return &rag_svr.SearchDocumentRsp{
 			Code: 1,
-			Msg:  fmt.Sprintf("向量搜索失败: %v", err),
+			Msg:  fmt.Sprintf("检索文档失败: %v", err),
 		}, nil
 	}
+	for _, result := range results {
+		result.Chunk.Content = s.chunkContent(ctx, &result.Chunk)
+	}
+
+	reranked := false
+	if reranker != nil {
+		rerankedResults, err := RerankChunks(ctx, reranker, req.Query, results, topK, rerankMinScore(float64(req.MinScore)))
+		if err != nil {
+			// 重排失败不影响检索，按 RRF 分数返回
+			logger.Errorf("重排检索结果失败，按 RRF 分数排序: %v", err)
+		} else {
+			results, reranked = rerankedResults, true
+		}
+	}
+	if len(results) > topK {
+		results = results[:topK]
+	}
 
-	// 直接根据向量搜索的结果获取文档块，不查询keywords
-	var chunks []mysql.DocumentChunk
-	if err := mysql.GetDB().Table("document_chunk").Where("chunk_id IN ?", ids).Find(&chunks).Error; err != nil {
-		logger.Errorf("获取文档块详情失败: %v", err)
+	docIDs := make([]uint64, 0, len(results))
+	for _, result := range results {
+		docIDs = append(docIDs, result.Chunk.DocID)
+	}
+	var docs []mysql.Document
+	if err := s.db.WithContext(ctx).Table("document").Where("doc_id IN ?", docIDs).Find(&docs).Error; err != nil {
+		logger.Errorf("获取文档信息失败: %v", err)
 		return &rag_svr.SearchDocumentRsp{
 			Code: 1,
-			Msg:  fmt.Sprintf("获取文档块详情失败: %v", err),
+			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
 		}, nil
 	}
+	docMap := make(map[uint64]*mysql.Document, len(docs))
+	for i := range docs {
+		docMap[docs[i].DocID] = &docs[i]
+	}
 
-	var documents []*rag_svr.Document
-	var finalScores []float32
-	for i, chunk := range chunks {
-		var doc mysql.Document
-		if err := mysql.GetDB().Table("document").First(&doc, chunk.DocID).Error; err != nil {
-			logger.Errorf("获取文档信息失败: %v", err)
-			continue
-		}
-		var sentences []mysql.DocumentSentence
-		if err := mysql.GetDB().Table("document_sentence").Where("sentence_id IN ?",
-			[]uint64{chunk.SentenceIDMin, chunk.SentenceIDMax}).
-			Find(&sentences).Error; err != nil {
-			logger.Errorf("获取句子信息失败: %v", err)
+	documents := make([]*rag_svr.Document, 0, len(results))
+	scores := make([]float32, 0, len(results))
+	hits := make([]*rag_svr.SearchHit, 0, len(results))
+	for _, result := range results {
+		doc, ok := docMap[result.Chunk.DocID]
+		if !ok {
 			continue
 		}
-		content := ""
-		for _, sent := range sentences {
-			content += sent.Content + " "
+		hit := &rag_svr.SearchHit{
+			DocId:         result.Chunk.DocID,
+			ChunkId:       result.Chunk.ChunkID,
+			ParagraphId:   result.Chunk.ParagraphID,
+			SentenceIdMin: result.Chunk.SentenceIDMin,
+			SentenceIdMax: result.Chunk.SentenceIDMax,
+			Content:       result.Chunk.Content,
+			Highlights:    generateHighlights(result.Chunk.Content, req.Query),
 		}
-		// 使用向量搜索的分数
-		if i < len(scores) {
-			finalScores = append(finalScores, scores[i])
-		} else {
-			finalScores = append(finalScores, 0.0)
+		// 重新索引期间句子表已是新版本的内容，与当前检索的块对不上，不附带相邻句子
+		if req.ContextSentences > 0 && doc.Status == DocumentStatusActive {
+			before, after, err := s.chunkContext(ctx, &result.Chunk, int(req.ContextSentences))
+			if err != nil {
+				logger.Errorf("附带相邻句子失败: doc_id=%d, chunk_id=%d, err=%v", result.Chunk.DocID, result.Chunk.ChunkID, err)
+			}
+			hit.ContextBefore, hit.ContextAfter = before, after
 		}
+		hits = append(hits, hit)
 		documents = append(documents, &rag_svr.Document{
 			DocId:      doc.DocID,
 			UserId:     doc.UserID,
 			Title:      doc.Title,
-			Content:    content,
+			Content:    result.Chunk.Content,
 			Metadata:   doc.Metadata,
 			CreateTime: uint64(doc.CreatedAt.Unix()),
 			UpdateTime: uint64(doc.UpdatedAt.Unix()),
+			Role:       scope.DocumentRole(doc).String(),
 		})
+		if reranked {
+			scores = append(scores, result.RerankScore)
+		} else {
+			scores = append(scores, float32(result.Score))
+		}
 	}
 
-	logger.Infof("文档搜索完成: 找到%d个结果", len(documents))
+	logger.Infof("文档搜索完成: 找到%d个结果, reranked=%v", len(documents), reranked)
 
 	return &rag_svr.SearchDocumentRsp{
 		Code:      0,
 		Msg:       "success",
 		Documents: documents,
-		Scores:    finalScores,
+		Scores:    scores,
+		Hits:      hits,
 	}, nil
 }
 
-// calculateRelevanceScore 计算相关性分数（迁移自 handler.go）
-func calculateRelevanceScore(chunk mysql.DocumentChunk, queryKeywords []string) float32 {
-	var chunkKeywords []string
-	if err := json.Unmarshal([]byte(chunk.Keywords), &chunkKeywords); err != nil {
-		return 0
-	}
-	matchedKeywords := 0
-	for _, qk := range queryKeywords {
-		for _, ck := range chunkKeywords {
-			if qk == ck {
-				matchedKeywords++
-				break
-			}
-		}
+// chunkContent 块的文本，早期没有记录内容的块由覆盖的句子拼接
+func (s *DocumentService) chunkContent(ctx context.Context, chunk *mysql.DocumentChunk) string {
+	if chunk.Content != "" {
+		return chunk.Content
 	}
-	return float32(matchedKeywords) / float32(len(queryKeywords))
+	var sentences []mysql.DocumentSentence
+	if err := s.db.WithContext(ctx).Table("document_sentence").
+		Where("doc_id = ? AND sentence_id BETWEEN ? AND ?", chunk.DocID, chunk.SentenceIDMin, chunk.SentenceIDMax).
+		Order("sentence_id").Find(&sentences).Error; err != nil {
+		logger.Errorf("获取句子信息失败: %v", err)
+		return ""
+	}
+	contents := make([]string, len(sentences))
+	for i, sentence := range sentences {
+		contents[i] = sentence.Content
+	}
+	return strings.Join(contents, " ")
 }
 
 // DeleteDocument 删除文档（迁移自 handler.go）
@@ -817,27 +801,47 @@ func (s *DocumentService) DeleteDocument(ctx context.Context, req *rag_svr.Delet
 			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
 		}, nil
 	}
-	if doc.UserID != req.UserId {
-		logger.Errorf("用户无权限删除该文档: user_id=%d, doc_user_id=%d", req.UserId, doc.UserID)
+	// 只有所有者可以删除文档
+	if _, err := authz.CheckDocument(ctx, req.UserId, &doc, authz.RoleOwner); err != nil {
+		logger.Errorf("用户无权限删除该文档: user_id=%d, doc_id=%d, err=%v", req.UserId, req.DocId, err)
+		code, msg := AuthzFailure(err, "无权限删除该文档")
 		return &rag_svr.DeleteDocumentRsp{
-			Code: 1,
-			Msg:  "无权限删除该文档",
+			Code: code,
+			Msg:  msg,
 		}, nil
 	}
-	if err := mysql.GetDB().Table("document").Delete(&doc).Error; err != nil {
-		logger.Errorf("从 MySQL 删除文档失败: %v", err)
+	// 与索引任务共用文档锁，避免删除后索引中的任务继续写入块
+	if redis.GetClient() != nil {
+		lockKey := fmt.Sprintf("%s%d", indexLockPrefix, req.DocId)
+		locked, err := redis.SetNX(ctx, lockKey, "delete", getIndexerConfig().leaseTimeout)
+		if err == nil && !locked {
+			return &rag_svr.DeleteDocumentRsp{
+				Code: 1,
+				Msg:  "文档正在索引，请稍后再试",
+			}, nil
+		}
+		if err == nil {
+			defer redis.Del(context.Background(), lockKey)
+		}
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteDocumentData(ctx, tx, req.DocId)
+	}); err != nil {
+		logger.Errorf("删除文档失败: doc_id=%d, err=%v", req.DocId, err)
 		return &rag_svr.DeleteDocumentRsp{
 			Code: 1,
 			Msg:  fmt.Sprintf("删除文档失败: %v", err),
 		}, nil
 	}
+	invalidateDocumentCache(ctx, req.DocId)
 	filter := bson.M{
 		"doc_id":  req.DocId,
-		"user_id": req.UserId,
+		"user_id": doc.UserID,
 	}
 	if _, err := mongodb.DeleteOne(ctx, "document", filter); err != nil {
 		logger.Errorf("从 MongoDB 删除文档失败: %v", err)
 	}
+	deleteDocumentFiles(ctx, req.DocId)
 	logger.Infof("文档删除成功: doc_id=%d", req.DocId)
 	return &rag_svr.DeleteDocumentRsp{
 		Code: 0,
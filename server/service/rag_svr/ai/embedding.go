@@ -1,178 +1,76 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
+	"sync"
 	"time"
 
-	"crypto/tls"
 	"server/framework/config"
+	"server/framework/logger"
 	"server/framework/redis"
+	"server/service/rag_svr/memory"
+	"server/service/rag_svr/usage"
 )
 
 const (
-	// 请求配置
-	maxRetries     = 3
-	retryInterval  = time.Second
-	requestTimeout = 10 * time.Second
-
 	// 缓存配置
 	vectorCachePrefix = "vector:"
 	vectorCacheTTL    = 24 * time.Hour // 向量缓存24小时
-)
 
-var (
-	// 并发控制
-	requestSemaphore = make(chan struct{}, 10) // 限制最大并发请求数
+	embeddingsAPI = "/embeddings"
+
+	// 批量向量化默认配置，DashScope text-embedding-v3/v4 单次请求最多 10 条
+	defaultEmbeddingBatchSize   = 10
+	defaultEmbeddingConcurrency = 4
 )
 
-// EmbeddingResponse 向量表示响应
-type EmbeddingResponse struct {
-	Output struct {
-		Embeddings []struct {
-			Embedding []float32 `json:"embedding"`
-		} `json:"embeddings"`
-	} `json:"output"`
+// embeddingResponse OpenAI 兼容的 /embeddings 响应
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *openAIUsage `json:"usage,omitempty"`
 }
 
-// GetEmbedding 获取文本的向量表示
-func GetEmbedding(text string) ([]float32, error) {
-	ctx := context.Background()
+// memory 包不能引用 ai 包，记忆的向量化在此注册，与文档共用模型链、缓存和用量记录
+func init() {
+	memory.SetEmbedder(BatchGetEmbedding)
+}
 
+// GetEmbedding 获取文本的向量表示
+func GetEmbedding(ctx context.Context, text string) ([]float32, error) {
 	// 尝试从缓存获取
-	cacheKey := vectorCachePrefix + text
-	if cached, err := redis.Get(ctx, cacheKey); err == nil {
-		var vector []float32
-		if err := json.Unmarshal([]byte(cached), &vector); err == nil {
-			return vector, nil
-		}
+	cacheKey := vectorCacheKey(text)
+	if cached, ok := getCachedVector(ctx, cacheKey); ok {
+		return cached, nil
 	}
 
-	// 获取配置
-	cfg := config.GlobalConfig
-	if cfg == nil {
-		return nil, fmt.Errorf("全局配置未初始化")
-	}
-
-	// 检查 embedding 模型配置
-	if cfg.AI.EmbeddingModel.APIKey == "" {
-		return nil, fmt.Errorf("embedding 模型 API Key 未配置")
-	}
-	if cfg.AI.EmbeddingModel.ModelName == "" {
-		return nil, fmt.Errorf("embedding 模型名称未配置")
-	}
-	if cfg.AI.EmbeddingModel.BaseURL == "" {
-		return nil, fmt.Errorf("embedding 模型 Base URL 未配置")
+	vectors, err := getEmbeddingBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
 	}
+	vector := vectors[0]
 
-	// 获取并发令牌
-	requestSemaphore <- struct{}{}
-	defer func() { <-requestSemaphore }()
-
-	var lastErr error
-	for i := 0; i < maxRetries; i++ {
-		if i > 0 {
-			time.Sleep(retryInterval)
-		}
-
-		// 构建请求体
-		reqBody := map[string]interface{}{
-			"model": cfg.AI.EmbeddingModel.ModelName,
-			"input": text,
-		}
-		jsonData, err := json.Marshal(reqBody)
-		if err != nil {
-			return nil, fmt.Errorf("序列化请求失败: %v", err)
-		}
-
-		// 创建请求
-		req, err := http.NewRequest("POST", cfg.AI.EmbeddingModel.BaseURL+"/embeddings", bytes.NewBuffer(jsonData))
-		if err != nil {
-			return nil, fmt.Errorf("创建请求失败: %v", err)
-		}
-
-		// 设置请求头
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.AI.EmbeddingModel.APIKey))
-
-		// 发送请求
-		client := &http.Client{
-			Timeout: requestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true, // 跳过TLS证书验证
-				},
-			},
-		}
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("发送请求失败: %v", err)
-			continue
-		}
-
-		// 读取响应
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("读取响应失败: %v", err)
-			continue
-		}
-
-		// 检查响应状态码
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-			continue
-		}
-
-		// 解析响应
-		var result struct {
-			Data []struct {
-				Embedding []float32 `json:"embedding"`
-			} `json:"data"`
-		}
-		if err := json.Unmarshal(body, &result); err != nil {
-			lastErr = fmt.Errorf("解析响应失败: %v", err)
-			continue
-		}
-
-		if len(result.Data) == 0 {
-			lastErr = fmt.Errorf("未获取到向量表示")
-			continue
-		}
-
-		vector := result.Data[0].Embedding
-
-		// 缓存向量
-		if vectorJSON, err := json.Marshal(vector); err == nil {
-			redis.Set(ctx, cacheKey, string(vectorJSON), vectorCacheTTL)
-		}
-
-		return vector, nil
-	}
+	// 缓存向量
+	setCachedVector(ctx, cacheKey, vector)
 
-	return nil, fmt.Errorf("重试%d次后仍然失败: %v", maxRetries, lastErr)
+	return vector, nil
 }
 
 // BatchGetEmbedding 批量获取文本的向量表示
-func BatchGetEmbedding(texts []string) ([][]float32, error) {
-	ctx := context.Background()
+func BatchGetEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
 	vectors := make([][]float32, len(texts))
 	missedIndices := make([]int, 0)
 	missedTexts := make([]string, 0)
 
 	// 尝试从缓存获取
 	for i, text := range texts {
-		cacheKey := vectorCachePrefix + text
-		if cached, err := redis.Get(ctx, cacheKey); err == nil {
-			var vector []float32
-			if err := json.Unmarshal([]byte(cached), &vector); err == nil {
-				vectors[i] = vector
-				continue
-			}
+		if cached, ok := getCachedVector(ctx, vectorCacheKey(text)); ok {
+			vectors[i] = cached
+			continue
 		}
 		missedIndices = append(missedIndices, i)
 		missedTexts = append(missedTexts, text)
@@ -184,7 +82,7 @@ func BatchGetEmbedding(texts []string) ([][]float32, error) {
 	}
 
 	// 获取未命中的向量
-	missedVectors, err := getEmbeddingBatch(missedTexts)
+	missedVectors, err := getEmbeddingBatch(ctx, missedTexts)
 	if err != nil {
 		return nil, err
 	}
@@ -193,105 +91,254 @@ func BatchGetEmbedding(texts []string) ([][]float32, error) {
 	for i, idx := range missedIndices {
 		vectors[idx] = missedVectors[i]
 		// 缓存向量
-		if vectorJSON, err := json.Marshal(missedVectors[i]); err == nil {
-			cacheKey := vectorCachePrefix + missedTexts[i]
-			redis.Set(ctx, cacheKey, string(vectorJSON), vectorCacheTTL)
-		}
+		setCachedVector(ctx, vectorCacheKey(missedTexts[i]), missedVectors[i])
 	}
 
 	return vectors, nil
 }
 
-// getEmbeddingBatch 批量获取向量（内部方法）
-func getEmbeddingBatch(texts []string) ([][]float32, error) {
-	// 获取配置
+// vectorCacheKey 向量缓存的键，包含模型名称，切换模型后不会读到旧模型的向量
+func vectorCacheKey(text string) string {
+	return vectorCachePrefix + embeddingModelName() + ":" + text
+}
+
+// getCachedVector 从缓存读取向量，未初始化 Redis 时（如离线测试）直接跳过
+func getCachedVector(ctx context.Context, cacheKey string) ([]float32, bool) {
+	if redis.GetClient() == nil {
+		return nil, false
+	}
+	cached, err := redis.Get(ctx, cacheKey)
+	if err != nil {
+		return nil, false
+	}
+	var vector []float32
+	if err := json.Unmarshal([]byte(cached), &vector); err != nil {
+		return nil, false
+	}
+	return vector, true
+}
+
+func setCachedVector(ctx context.Context, cacheKey string, vector []float32) {
+	if redis.GetClient() == nil {
+		return
+	}
+	if vectorJSON, err := json.Marshal(vector); err == nil {
+		redis.Set(ctx, cacheKey, string(vectorJSON), vectorCacheTTL)
+	}
+}
+
+// embeddingModel 一个 OpenAI 兼容的 /embeddings 接入点
+type embeddingModel struct {
+	modelName string
+	url       string
+	apiKey    string
+}
+
+// embedder 向量化模型链，主模型不可用时切换到备用接入点
+type embedder struct {
+	models      []*embeddingModel
+	dimension   int
+	batchSize   int // 单次请求最多的文本数，取链上所有模型的最小值
+	concurrency int // 批量向量化时同时发出的请求数
+	chain       *modelChain
+}
+
+var (
+	embedderInstance *embedder
+	embedderErr      error
+	embedderOnce     sync.Once
+)
+
+// getEmbedder 获取按全局配置创建的向量化模型链
+func getEmbedder() (*embedder, error) {
+	embedderOnce.Do(func() {
+		embedderInstance, embedderErr = newEmbedder()
+	})
+	return embedderInstance, embedderErr
+}
+
+// embeddingModelName 主向量化模型名称，记录在文档版本上
+func embeddingModelName() string {
+	if config.GlobalConfig == nil {
+		return ""
+	}
+	return config.GlobalConfig.AI.EmbeddingModel.ModelName
+}
+
+func newEmbedder() (*embedder, error) {
 	cfg := config.GlobalConfig
 	if cfg == nil {
 		return nil, fmt.Errorf("全局配置未初始化")
 	}
 
-	// 获取并发令牌
-	requestSemaphore <- struct{}{}
-	defer func() { <-requestSemaphore }()
+	// 检查 embedding 模型配置
+	primary := cfg.AI.EmbeddingModel
+	if primary.APIKey == "" {
+		return nil, fmt.Errorf("embedding 模型 API Key 未配置")
+	}
+	if primary.ModelName == "" {
+		return nil, fmt.Errorf("embedding 模型名称未配置")
+	}
+	if primary.BaseURL == "" {
+		return nil, fmt.Errorf("embedding 模型 Base URL 未配置")
+	}
 
-	var lastErr error
-	for i := 0; i < maxRetries; i++ {
-		if i > 0 {
-			time.Sleep(retryInterval)
+	e := &embedder{
+		models: []*embeddingModel{{
+			modelName: primary.ModelName,
+			url:       strings.TrimRight(primary.BaseURL, "/") + embeddingsAPI,
+			apiKey:    primary.APIKey,
+		}},
+		dimension:   primary.Dimension,
+		batchSize:   primary.MaxBatchSize,
+		concurrency: primary.BatchConcurrency,
+	}
+	if e.batchSize <= 0 {
+		e.batchSize = defaultEmbeddingBatchSize
+	}
+	if e.concurrency <= 0 {
+		e.concurrency = defaultEmbeddingConcurrency
+	}
+	names := []string{modelDisplayName("", primary.Provider, primary.ModelName)}
+
+	// 备用接入点必须是同一个模型：向量写入同一个集合、按主模型名称缓存和记录在文档版本上，
+	// 不同模型的向量空间不同，相似度无法比较；维度不一致的同样无法写入同一个集合
+	for _, fallback := range cfg.AI.Fallback.EmbeddingModels {
+		name := modelDisplayName(fallback.Name, fallback.Provider, fallback.ModelName)
+		if fallback.ModelName != primary.ModelName {
+			logger.Errorf("备用向量化模型与主模型不同，已忽略: name=%s, model_name=%s, 主模型=%s", name, fallback.ModelName, primary.ModelName)
+			continue
 		}
-
-		// 构建请求体
-		reqBody := map[string]interface{}{
-			"model": cfg.AI.EmbeddingModel.ModelName,
-			"input": texts,
+		if fallback.Dimension != primary.Dimension {
+			logger.Errorf("备用向量化模型维度不一致，已忽略: name=%s, dimension=%d, 主模型维度=%d", name, fallback.Dimension, primary.Dimension)
+			continue
 		}
-		jsonData, err := json.Marshal(reqBody)
-		if err != nil {
-			return nil, fmt.Errorf("序列化请求失败: %v", err)
+		if fallback.BaseURL == "" {
+			logger.Errorf("备用向量化模型配置不完整，已忽略: name=%s", name)
+			continue
 		}
-
-		// 创建请求
-		req, err := http.NewRequest("POST", cfg.AI.EmbeddingModel.BaseURL+"/embeddings", bytes.NewBuffer(jsonData))
-		if err != nil {
-			return nil, fmt.Errorf("创建请求失败: %v", err)
+		e.models = append(e.models, &embeddingModel{
+			modelName: fallback.ModelName,
+			url:       strings.TrimRight(fallback.BaseURL, "/") + embeddingsAPI,
+			apiKey:    fallback.APIKey,
+		})
+		names = append(names, name)
+		// 请求可能切换到任一模型，批大小按最小的上限拆分
+		if fallback.MaxBatchSize > 0 && fallback.MaxBatchSize < e.batchSize {
+			e.batchSize = fallback.MaxBatchSize
 		}
+	}
+	e.chain = newModelChain(names)
 
-		// 设置请求头
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.AI.EmbeddingModel.APIKey))
-
-		// 发送请求
-		client := &http.Client{
-			Timeout: requestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true, // 跳过TLS证书验证
-				},
-			},
-		}
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("发送请求失败: %v", err)
-			continue
-		}
+	logger.Infof("向量化模型链: %v, batch_size=%d, concurrency=%d", names, e.batchSize, e.concurrency)
+	return e, nil
+}
 
-		// 读取响应
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("读取响应失败: %v", err)
-			continue
-		}
+// getEmbeddingBatch 批量获取向量（内部方法）
+// 超出模型单次请求上限时拆分为多个请求并发调用，任一请求失败时取消其余请求
+func getEmbeddingBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	e, err := getEmbedder()
+	if err != nil {
+		return nil, err
+	}
+	if len(texts) <= e.batchSize {
+		return e.embedBatch(ctx, texts)
+	}
 
-		// 检查响应状态码
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-			continue
-		}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		// 解析响应
-		var result struct {
-			Data []struct {
-				Embedding []float32 `json:"embedding"`
-			} `json:"data"`
+	vectors := make([][]float32, len(texts))
+	sem := make(chan struct{}, e.concurrency)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for start := 0; start < len(texts); start += e.batchSize {
+		end := min(start+e.batchSize, len(texts))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
 		}
-		if err := json.Unmarshal(body, &result); err != nil {
-			lastErr = fmt.Errorf("解析响应失败: %v", err)
-			continue
+		if ctx.Err() != nil {
+			break
 		}
 
-		if len(result.Data) == 0 {
-			lastErr = fmt.Errorf("未获取到向量表示")
-			continue
-		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batch, err := e.embedBatch(ctx, texts[start:end])
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			copy(vectors[start:end], batch)
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
 
-		vectors := make([][]float32, len(result.Data))
-		for i, embedding := range result.Data {
-			vectors[i] = embedding.Embedding
+// embedBatch 通过模型链获取一批不超过单次请求上限的向量
+func (e *embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	var vectors [][]float32
+	err := e.chain.do(ctx, func(ctx context.Context, i int) error {
+		var err error
+		vectors, err = e.embed(ctx, e.models[i], texts)
+		return err
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
+
+// embed 调用一个接入点获取向量，数量或维度不符时视为响应异常
+func (e *embedder) embed(ctx context.Context, model *embeddingModel, texts []string) ([][]float32, error) {
+	reqBody := map[string]interface{}{
+		"model": model.modelName,
+		"input": texts,
+	}
+	var result embeddingResponse
+	if err := GetModelGateway().PostJSON(ctx, EndpointEmbedding, model.url, model.apiKey, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	recordUsage(ctx, usage.CallTypeEmbedding, model.modelName, result.Usage.toQwenUsage())
+
+	if len(result.Data) != len(texts) {
+		return nil, &ModelError{
+			Endpoint: EndpointEmbedding,
+			Kind:     ErrModelResponse,
+			Err:      fmt.Errorf("向量数量不匹配: 期望%d, 实际%d", len(texts), len(result.Data)),
 		}
+	}
 
-		return vectors, nil
+	vectors := make([][]float32, len(result.Data))
+	for i, embedding := range result.Data {
+		if e.dimension > 0 && len(embedding.Embedding) != e.dimension {
+			return nil, &ModelError{
+				Endpoint: EndpointEmbedding,
+				Kind:     ErrModelResponse,
+				Err:      fmt.Errorf("向量维度不匹配: model=%s, 期望%d, 实际%d", model.modelName, e.dimension, len(embedding.Embedding)),
+			}
+		}
+		vectors[i] = embedding.Embedding
 	}
 
-	return nil, fmt.Errorf("重试%d次后仍然失败: %v", maxRetries, lastErr)
+	return vectors, nil
 }
@@ -0,0 +1,230 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/framework/config"
+	"server/framework/logger"
+)
+
+// 有备用模型时，切换前在当前模型上的默认尝试次数
+const defaultRetriesBeforeFailover = 1
+
+// modelChain 按顺序排列的模型，每个模型独立熔断
+type modelChain struct {
+	names                 []string
+	breakers              []*CircuitBreaker
+	retriesBeforeFailover int
+}
+
+func newModelChain(names []string) *modelChain {
+	cfg := BreakerConfig{}
+	retries := defaultRetriesBeforeFailover
+	if config.GlobalConfig != nil {
+		fallback := config.GlobalConfig.AI.Fallback
+		cfg.FailureThreshold = fallback.Breaker.FailureThreshold
+		cfg.OpenTimeout = time.Duration(fallback.Breaker.OpenTimeout) * time.Second
+		cfg.HalfOpenProbes = fallback.Breaker.HalfOpenProbes
+		if fallback.RetriesBeforeFailover > 0 {
+			retries = fallback.RetriesBeforeFailover
+		}
+	}
+
+	c := &modelChain{
+		names:                 names,
+		breakers:              make([]*CircuitBreaker, len(names)),
+		retriesBeforeFailover: retries,
+	}
+	for i, name := range names {
+		c.breakers[i] = NewCircuitBreaker(name, cfg)
+	}
+	return c
+}
+
+// do 依次在未熔断的模型上执行 call，模型故障时切换到下一个
+// committed 不为空且返回 true 时不再切换，用于流式输出已经开始的情况
+func (c *modelChain) do(ctx context.Context, call func(ctx context.Context, i int) error, committed func() bool) error {
+	var lastErr error
+	for i, breaker := range c.breakers {
+		last := i == len(c.breakers)-1
+		if !breaker.Allow() {
+			lastErr = fmt.Errorf("%w: %s", ErrBreakerOpen, c.names[i])
+			if !last {
+				breaker.addFallback()
+			}
+			continue
+		}
+
+		callCtx := ctx
+		if !last {
+			callCtx = withMaxAttempts(ctx, c.retriesBeforeFailover)
+		}
+		err := call(callCtx, i)
+		switch {
+		case err == nil:
+			breaker.Success()
+			return nil
+		case ctx.Err() != nil:
+			// 调用方取消，与模型健康无关
+			breaker.Release()
+			return err
+		case !isModelFailure(err):
+			// 请求本身有问题，换模型也无济于事
+			breaker.Success()
+			return err
+		}
+
+		breaker.Failure()
+		lastErr = err
+		if committed != nil && committed() {
+			return err
+		}
+		if !last {
+			breaker.addFallback()
+			logger.Warnf("模型调用失败，切换到下一个模型: from=%s, to=%s, err=%v", c.names[i], c.names[i+1], err)
+		}
+	}
+	return lastErr
+}
+
+func (c *modelChain) stats() []BreakerStats {
+	stats := make([]BreakerStats, 0, len(c.breakers))
+	for _, breaker := range c.breakers {
+		stats = append(stats, breaker.Stats())
+	}
+	return stats
+}
+
+// isModelFailure 判断错误是否说明模型不可用，需要计入熔断并切换模型
+func isModelFailure(err error) bool {
+	return !errors.Is(err, ErrModelBadRequest)
+}
+
+// FallbackProvider 按顺序使用多个大模型提供方，主模型不可用时自动切换到备用模型
+type FallbackProvider struct {
+	providers []LLMProvider
+	chain     *modelChain
+}
+
+// NewFallbackProvider 创建带熔断的备用模型链，names 与 providers 一一对应
+func NewFallbackProvider(names []string, providers []LLMProvider) *FallbackProvider {
+	return &FallbackProvider{
+		providers: providers,
+		chain:     newModelChain(names),
+	}
+}
+
+// ModelName 主模型名称
+func (p *FallbackProvider) ModelName() string {
+	return p.providers[0].ModelName()
+}
+
+// Chat 非流式对话，返回结果中的 Model 为实际使用的模型
+func (p *FallbackProvider) Chat(ctx context.Context, messages []QwenMessage, tools []QwenTool) (*ChatResult, error) {
+	var result *ChatResult
+	err := p.chain.do(ctx, func(ctx context.Context, i int) error {
+		var err error
+		result, err = p.providers[i].Chat(ctx, messages, tools)
+		return err
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamChat 流式对话，只在输出第一个分片之前切换模型
+func (p *FallbackProvider) StreamChat(ctx context.Context, messages []QwenMessage) (<-chan StreamChunk, <-chan error) {
+	responseChan := make(chan StreamChunk)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(errorChan)
+		defer close(responseChan)
+
+		started := false
+		err := p.chain.do(ctx, func(callCtx context.Context, i int) error {
+			chunks, errs := p.providers[i].StreamChat(callCtx, messages)
+			for chunk := range chunks {
+				started = true
+				select {
+				case responseChan <- chunk:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return <-errs
+		}, func() bool { return started })
+		if err != nil {
+			errorChan <- err
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// Stats 各模型的熔断状态
+func (p *FallbackProvider) Stats() []BreakerStats {
+	return p.chain.stats()
+}
+
+// newFallbackLLMProvider 根据主模型和备用模型配置创建提供方
+func newFallbackLLMProvider(primary *ChatModelConfig, fallbacks []config.FallbackModel) (*FallbackProvider, error) {
+	provider, err := NewLLMProvider(primary)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{modelDisplayName("", primary.Provider, primary.ModelName)}
+	providers := []LLMProvider{provider}
+
+	for _, fallback := range fallbacks {
+		cfg := *primary
+		cfg.Provider = fallback.Provider
+		cfg.ModelName = fallback.ModelName
+		cfg.BaseURL = fallback.BaseURL
+		cfg.APIKey = fallback.APIKey
+		provider, err := NewLLMProvider(&cfg)
+		if err != nil {
+			return nil, fmt.Errorf("创建备用模型失败: name=%s, err=%v", fallback.Name, err)
+		}
+		names = append(names, modelDisplayName(fallback.Name, fallback.Provider, fallback.ModelName))
+		providers = append(providers, provider)
+	}
+
+	logger.Infof("对话模型链: %v", names)
+	return NewFallbackProvider(names, providers), nil
+}
+
+// modelDisplayName 模型在日志和监控中的名称
+func modelDisplayName(name, provider, modelName string) string {
+	if name != "" {
+		return name
+	}
+	if provider == "" {
+		provider = ProviderDashScope
+	}
+	return provider + "/" + modelName
+}
+
+// ModelStatus 对话和向量化模型的熔断状态
+type ModelStatus struct {
+	Chat      []BreakerStats
+	Embedding []BreakerStats
+}
+
+// GetModelStatus 获取对话和向量化模型的熔断状态和调用统计
+func GetModelStatus() *ModelStatus {
+	status := &ModelStatus{}
+	if provider, err := GetLLMProvider(); err == nil {
+		if chain, ok := provider.(*FallbackProvider); ok {
+			status.Chat = chain.Stats()
+		}
+	}
+	if embedder, err := getEmbedder(); err == nil {
+		status.Embedding = embedder.chain.stats()
+	}
+	return status
+}
@@ -12,6 +12,7 @@ import (
 	"server/framework/milvus"
 	"server/framework/mysql"
 	"server/framework/redis"
+	"server/service/rag_svr/kitex_gen/rag_svr"
 	"server/service/rag_svr/memory"
 )
 
@@ -39,6 +40,11 @@ type FunctionHandler interface {
 	GetDefinition() FunctionDefinition
 }
 
+// SideEffectFunction 有副作用的函数，多个工具调用时不与其他函数并发执行
+type SideEffectFunction interface {
+	HasSideEffect() bool
+}
+
 var registry *FunctionRegistry
 
 // GetFunctionRegistry 获取函数注册表实例
@@ -77,37 +83,53 @@ type SearchDocumentFunction struct{}
 func (f *SearchDocumentFunction) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	logger.Infof("开始执行搜索文档函数: args=%+v", args)
 
+	caller, err := toolCallerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query, ok := args["query"].(string)
 	if !ok {
 		logger.Errorf("query参数缺失或类型错误")
 		return nil, fmt.Errorf("query参数缺失或类型错误")
 	}
 	topK, ok := args["top_k"].(float64)
-	if !ok {
+	if !ok || topK <= 0 {
 		topK = 5 // 默认值
 		logger.Infof("使用默认top_k值: %d", int(topK))
 	}
 
-	// 使用 query 和 topK 变量
-	result := map[string]interface{}{
-		"results": []map[string]interface{}{
-			{
-				"title":   fmt.Sprintf("搜索结果: %s", query),
-				"content": "这是搜索结果",
-				"score":   0.95,
-				"top_k":   int(topK),
-			},
-		},
+	// 按发起对话的用户的授权范围和对话请求指定的知识库检索
+	rsp, err := GetDocumentServiceInstance().SearchDocument(ctx, &rag_svr.SearchDocumentReq{
+		UserId:        caller.UserID,
+		Query:         query,
+		TopK:          int32(topK),
+		CollectionIds: caller.CollectionIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Code != 0 {
+		return nil, fmt.Errorf("搜索文档失败: %s", rsp.Msg)
 	}
 
-	logger.Infof("搜索文档函数执行成功: query=%s, top_k=%d", query, int(topK))
-	return result, nil
+	results := make([]map[string]interface{}, 0, len(rsp.Hits))
+	for i, hit := range rsp.Hits {
+		results = append(results, map[string]interface{}{
+			"doc_id":  hit.DocId,
+			"title":   rsp.Documents[i].Title,
+			"content": hit.Content,
+			"score":   rsp.Scores[i],
+		})
+	}
+
+	logger.Infof("搜索文档函数执行成功: query=%s, top_k=%d, 结果数=%d", query, int(topK), len(results))
+	return map[string]interface{}{"results": results}, nil
 }
 
 func (f *SearchDocumentFunction) GetDefinition() FunctionDefinition {
 	return FunctionDefinition{
 		Name:        "search_document",
-		Description: "搜索知识库中的文档",
+		Description: "在当前用户可以访问的知识库文档中搜索",
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -228,7 +250,9 @@ func (f *SetReminderFunction) Execute(ctx context.Context, args map[string]inter
 		"status":      "pending",
 	}
 
-	err = memory.GetInstance().AddMemory(ctx, 0, 0, content, memory.MemoryTypeReminder, 0.9, metadata, nil)
+	// 提醒记忆归属发起对话的用户
+	caller, _ := toolCallerFrom(ctx)
+	err = memory.GetInstance().AddMemory(ctx, caller.SessionID, caller.UserID, content, memory.MemoryTypeReminder, 0.9, metadata, nil)
 	if err != nil {
 		logger.Errorf("添加提醒记忆失败: error=%v", err)
 		// 继续执行，不影响提醒的创建
@@ -245,6 +269,8 @@ func (f *SetReminderFunction) Execute(ctx context.Context, args map[string]inter
 	return result, nil
 }
 
+func (f *SetReminderFunction) HasSideEffect() bool { return true }
+
 func (f *SetReminderFunction) GetDefinition() FunctionDefinition {
 	return FunctionDefinition{
 		Name:        "set_reminder",
@@ -325,6 +351,9 @@ func (f *GetReminderFunction) Execute(ctx context.Context, args map[string]inter
 	return result, nil
 }
 
+// HasSideEffect 获取提醒会把提醒状态更新为 triggered
+func (f *GetReminderFunction) HasSideEffect() bool { return true }
+
 func (f *GetReminderFunction) GetDefinition() FunctionDefinition {
 	return FunctionDefinition{
 		Name:        "get_reminders",
@@ -343,14 +372,14 @@ type GetUserPreferencesFunction struct{}
 func (f *GetUserPreferencesFunction) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	logger.Infof("开始执行获取用户偏好函数: args=%+v", args)
 
-	userID, ok := args["user_id"].(float64)
-	if !ok {
-		logger.Errorf("user_id参数缺失或类型错误")
-		return nil, fmt.Errorf("user_id参数缺失或类型错误")
+	caller, err := toolCallerFrom(ctx)
+	if err != nil {
+		return nil, err
 	}
+	userID := caller.UserID
 
 	result := map[string]interface{}{
-		"user_id": int(userID),
+		"user_id": userID,
 		"preferences": map[string]interface{}{
 			"language":     "zh-CN",
 			"theme":        "dark",
@@ -358,23 +387,18 @@ func (f *GetUserPreferencesFunction) Execute(ctx context.Context, args map[strin
 		},
 	}
 
-	logger.Infof("获取用户偏好函数执行成功: user_id=%d", int(userID))
+	logger.Infof("获取用户偏好函数执行成功: user_id=%d", userID)
 	return result, nil
 }
 
 func (f *GetUserPreferencesFunction) GetDefinition() FunctionDefinition {
 	return FunctionDefinition{
 		Name:        "get_user_preferences",
-		Description: "获取用户偏好设置",
+		Description: "获取当前用户的偏好设置",
 		Parameters: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"user_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "用户ID",
-				},
-			},
-			"required": []string{"user_id"},
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
 		},
 	}
 }
@@ -417,13 +441,13 @@ func ParseFunctionCall(text string) (*FunctionCall, error) {
 // SearchSimilarDocuments 搜索相似文档
 func (c *QwenClient) SearchSimilarDocuments(ctx context.Context, query string, limit int) ([]string, error) {
 	// 生成查询向量
-	queryEmbedding, err := GetEmbedding(query)
+	queryEmbedding, err := GetEmbedding(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("生成查询向量失败: %v", err)
 	}
 
 	// 在 Milvus 中搜索相似向量
-	ids, scores, err := milvus.SearchVector(ctx, "document", queryEmbedding, limit*2) // 获取更多结果用于重排序
+	ids, scores, _, err := milvus.SearchVector(ctx, "document", queryEmbedding, limit*2, "", nil) // 获取更多结果用于重排序
 	if err != nil {
 		return nil, fmt.Errorf("搜索向量失败: %v", err)
 	}
@@ -516,6 +540,10 @@ type GetMemoryFunction struct{}
 func (f *GetMemoryFunction) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	logger.Infof("开始执行获取记忆函数: args=%+v", args)
 
+	caller, err := toolCallerFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
 	memoryID, ok := args["memory_id"].(float64)
 	if !ok {
 		logger.Errorf("memory_id参数缺失或类型错误")
@@ -523,29 +551,29 @@ func (f *GetMemoryFunction) Execute(ctx context.Context, args map[string]interfa
 	}
 
 	// 获取记忆信息
-	memories, err := memory.GetInstance().SearchMemories(ctx, fmt.Sprintf("id:%d", uint64(memoryID)), 1)
+	mem, err := memory.GetInstance().GetMemory(ctx, uint64(memoryID))
 	if err != nil {
 		logger.Errorf("获取记忆失败: memory_id=%d, error=%v", uint64(memoryID), err)
 		return nil, fmt.Errorf("获取记忆失败: %v", err)
 	}
-
-	if len(memories) == 0 {
+	// 其他用户的记忆与不存在的记忆返回相同的错误
+	if mem == nil || mem.UserID != caller.UserID {
+		logger.Warnf("记忆不存在或不属于当前用户: memory_id=%d, user_id=%d", uint64(memoryID), caller.UserID)
 		return nil, fmt.Errorf("记忆不存在: memory_id=%d", uint64(memoryID))
 	}
 
-	memory := memories[0]
 	result := map[string]interface{}{
-		"memory_id":    memory.ID,
-		"session_id":   memory.SessionID,
-		"user_id":      memory.UserID,
-		"content":      memory.Content,
-		"memory_type":  memory.Type,
-		"importance":   memory.Importance,
-		"metadata":     memory.Metadata,
-		"create_time":  memory.CreatedAt.Format("2006-01-02 15:04:05"),
-		"update_time":  memory.LastAccessed.Format("2006-01-02 15:04:05"),
-		"expire_time":  memory.ExpiresAt.Format("2006-01-02 15:04:05"),
-		"access_count": memory.AccessCount,
+		"memory_id":    mem.ID,
+		"session_id":   mem.SessionID,
+		"user_id":      mem.UserID,
+		"content":      mem.Content,
+		"memory_type":  mem.Type,
+		"importance":   mem.Importance,
+		"metadata":     mem.Metadata,
+		"create_time":  mem.CreatedAt.Format("2006-01-02 15:04:05"),
+		"update_time":  mem.LastAccessed.Format("2006-01-02 15:04:05"),
+		"expire_time":  mem.ExpiresAt.Format("2006-01-02 15:04:05"),
+		"access_count": mem.AccessCount,
 	}
 
 	logger.Infof("获取记忆函数执行成功: memory_id=%d", uint64(memoryID))
@@ -555,7 +583,7 @@ func (f *GetMemoryFunction) Execute(ctx context.Context, args map[string]interfa
 func (f *GetMemoryFunction) GetDefinition() FunctionDefinition {
 	return FunctionDefinition{
 		Name:        "get_memory",
-		Description: "获取指定ID的记忆信息",
+		Description: "获取当前用户指定ID的记忆信息",
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -575,10 +603,9 @@ type AddMemoryFunction struct{}
 func (f *AddMemoryFunction) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	logger.Infof("开始执行添加记忆函数: args=%+v", args)
 
-	userID, ok := args["user_id"].(float64)
-	if !ok {
-		logger.Errorf("user_id参数缺失或类型错误")
-		return nil, fmt.Errorf("user_id参数缺失或类型错误")
+	caller, err := toolCallerFrom(ctx)
+	if err != nil {
+		return nil, err
 	}
 	content, ok := args["content"].(string)
 	if !ok {
@@ -596,10 +623,10 @@ func (f *AddMemoryFunction) Execute(ctx context.Context, args map[string]interfa
 		return nil, fmt.Errorf("importance参数缺失或类型错误")
 	}
 
-	// 添加记忆
-	err := memory.GetInstance().AddMemory(ctx, 0, uint64(userID), content, memoryType, importance, nil, nil)
+	// 添加记忆，归属发起对话的用户
+	err = memory.GetInstance().AddMemory(ctx, caller.SessionID, caller.UserID, content, memoryType, importance, nil, nil)
 	if err != nil {
-		logger.Errorf("添加记忆失败: user_id=%d, content=%s, error=%v", uint64(userID), content, err)
+		logger.Errorf("添加记忆失败: user_id=%d, content=%s, error=%v", caller.UserID, content, err)
 		return nil, fmt.Errorf("添加记忆失败: %v", err)
 	}
 
@@ -608,21 +635,19 @@ func (f *AddMemoryFunction) Execute(ctx context.Context, args map[string]interfa
 		"message": "记忆添加成功",
 	}
 
-	logger.Infof("添加记忆函数执行成功: user_id=%d, memory_type=%s", uint64(userID), memoryType)
+	logger.Infof("添加记忆函数执行成功: user_id=%d, memory_type=%s", caller.UserID, memoryType)
 	return result, nil
 }
 
+func (f *AddMemoryFunction) HasSideEffect() bool { return true }
+
 func (f *AddMemoryFunction) GetDefinition() FunctionDefinition {
 	return FunctionDefinition{
 		Name:        "add_memory",
-		Description: "添加新的记忆",
+		Description: "为当前用户添加新的记忆",
 		Parameters: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"user_id": map[string]interface{}{
-					"type":        "number",
-					"description": "用户ID",
-				},
 				"content": map[string]interface{}{
 					"type":        "string",
 					"description": "记忆内容",
@@ -636,7 +661,7 @@ func (f *AddMemoryFunction) GetDefinition() FunctionDefinition {
 					"description": "重要性(0-1)",
 				},
 			},
-			"required": []string{"user_id", "content", "memory_type", "importance"},
+			"required": []string{"content", "memory_type", "importance"},
 		},
 	}
 }
@@ -0,0 +1,407 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"server/framework/config"
+	"server/framework/logger"
+)
+
+// 模型网关接口名称，用于区分并发限制
+const (
+	EndpointChat       = "chat"        // 非流式对话
+	EndpointChatStream = "chat_stream" // 流式对话
+	EndpointEmbedding  = "embedding"   // 向量化
+	EndpointRerank     = "rerank"      // 检索结果重排
+)
+
+// 网关默认配置
+const (
+	defaultGatewayTimeout     = 30 * time.Second
+	defaultGatewayMaxRetries  = 3
+	defaultGatewayBaseDelay   = 500 * time.Millisecond
+	defaultGatewayMaxDelay    = 8 * time.Second
+	defaultGatewayConcurrency = 10
+	maxErrorBodyLength        = 512
+)
+
+// 模型调用错误类型，可通过 errors.Is 判断
+var (
+	ErrModelRateLimited = errors.New("模型接口限流")
+	ErrModelUnavailable = errors.New("模型服务不可用")
+	ErrModelTimeout     = errors.New("模型请求超时")
+	ErrModelBadRequest  = errors.New("模型请求参数错误")
+	ErrModelAuth        = errors.New("模型鉴权失败")
+	ErrModelResponse    = errors.New("模型响应异常")
+)
+
+// ModelError 模型调用错误
+type ModelError struct {
+	Endpoint   string // 网关接口名称
+	StatusCode int    // HTTP 状态码，网络错误时为 0
+	Attempts   int    // 已尝试次数
+	Body       string // 响应体（截断）
+	Kind       error  // 错误类型
+	Err        error  // 原始错误
+	retryAfter time.Duration
+}
+
+func (e *ModelError) Error() string {
+	msg := fmt.Sprintf("%v: endpoint=%s, attempts=%d", e.Kind, e.Endpoint, e.Attempts)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(", status=%d", e.StatusCode)
+	}
+	if e.Body != "" {
+		msg += ", body=" + e.Body
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(", err=%v", e.Err)
+	}
+	return msg
+}
+
+func (e *ModelError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Retryable 是否可以重试
+func (e *ModelError) Retryable() bool {
+	return e.Kind == ErrModelRateLimited || e.Kind == ErrModelUnavailable || e.Kind == ErrModelTimeout
+}
+
+// ModelGateway 模型网关，所有大模型和向量化 HTTP 调用共用
+// 负责超时、并发限制、指数退避重试和错误归类
+type ModelGateway struct {
+	httpClient   *http.Client
+	streamClient *http.Client // 流式请求不设置整体超时，由 ctx 控制
+
+	maxRetries         int
+	baseDelay          time.Duration
+	maxDelay           time.Duration
+	defaultConcurrency int
+	concurrency        map[string]int
+
+	mu       sync.Mutex
+	limiters map[string]chan struct{}
+}
+
+var (
+	modelGatewayInstance *ModelGateway
+	modelGatewayOnce     sync.Once
+)
+
+// GetModelGateway 获取按全局配置创建的模型网关
+func GetModelGateway() *ModelGateway {
+	modelGatewayOnce.Do(func() {
+		modelGatewayInstance = newModelGateway()
+	})
+	return modelGatewayInstance
+}
+
+func newModelGateway() *ModelGateway {
+	g := &ModelGateway{
+		maxRetries:         defaultGatewayMaxRetries,
+		baseDelay:          defaultGatewayBaseDelay,
+		maxDelay:           defaultGatewayMaxDelay,
+		defaultConcurrency: defaultGatewayConcurrency,
+		concurrency:        make(map[string]int),
+		limiters:           make(map[string]chan struct{}),
+	}
+	timeout := defaultGatewayTimeout
+
+	if config.GlobalConfig != nil {
+		cfg := config.GlobalConfig.AI.Gateway
+		if cfg.Timeout > 0 {
+			timeout = time.Duration(cfg.Timeout) * time.Second
+		}
+		if cfg.MaxRetries > 0 {
+			g.maxRetries = cfg.MaxRetries
+		}
+		if cfg.RetryBaseDelay > 0 {
+			g.baseDelay = time.Duration(cfg.RetryBaseDelay) * time.Millisecond
+		}
+		if cfg.RetryMaxDelay > 0 {
+			g.maxDelay = time.Duration(cfg.RetryMaxDelay) * time.Millisecond
+		}
+		if cfg.DefaultConcurrency > 0 {
+			g.defaultConcurrency = cfg.DefaultConcurrency
+		}
+		for endpoint, limit := range cfg.Concurrency {
+			g.concurrency[endpoint] = limit
+		}
+	}
+
+	// 请求携带模型服务的 API Key，默认验证证书；只有本地调试时可以通过环境变量显式跳过
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if os.Getenv("SKIP_TLS_VERIFY") == "true" {
+		logger.Warnf("模型网关已跳过 TLS 证书验证（SKIP_TLS_VERIFY=true），不要在生产环境使用")
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	g.httpClient = &http.Client{Timeout: timeout, Transport: transport}
+	g.streamClient = &http.Client{Transport: transport}
+
+	logger.Infof("模型网关初始化: timeout=%v, max_retries=%d, concurrency=%v", timeout, g.maxRetries, g.concurrency)
+	return g
+}
+
+// PostJSON 发送 JSON 请求并解析响应，可重试的错误按指数退避重试
+func (g *ModelGateway) PostJSON(ctx context.Context, endpoint, url, apiKey string, request, response interface{}) error {
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("序列化请求失败: %v", err)
+	}
+
+	release, err := g.acquire(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return g.withRetry(ctx, endpoint, func() *ModelError {
+		body, modelErr := g.doPost(ctx, endpoint, url, apiKey, reqBody)
+		if modelErr != nil {
+			return modelErr
+		}
+		if err := json.Unmarshal(body, response); err != nil {
+			return &ModelError{Endpoint: endpoint, Kind: ErrModelResponse, Body: truncateBody(body), Err: err}
+		}
+		return nil
+	})
+}
+
+// OpenStream 发起流式请求，建立连接前的失败会重试
+// 调用方负责关闭响应体，关闭时释放并发令牌
+func (g *ModelGateway) OpenStream(ctx context.Context, endpoint, url, apiKey string, request interface{}, headers map[string]string) (*http.Response, error) {
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %v", err)
+	}
+
+	release, err := g.acquire(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+	err = g.withRetry(ctx, endpoint, func() *ModelError {
+		httpReq, err := newJSONRequest(ctx, url, apiKey, reqBody)
+		if err != nil {
+			return &ModelError{Endpoint: endpoint, Kind: ErrModelBadRequest, Err: err}
+		}
+		httpReq.Header.Set("Accept", "text/event-stream")
+		for k, v := range headers {
+			httpReq.Header.Set(k, v)
+		}
+
+		r, err := g.streamClient.Do(httpReq)
+		if err != nil {
+			return classifyTransportError(endpoint, err)
+		}
+		if r.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(r.Body)
+			r.Body.Close()
+			return classifyStatus(endpoint, r, body)
+		}
+		resp = r
+		return nil
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type maxAttemptsKey struct{}
+
+// withMaxAttempts 限制本次调用的最大尝试次数，有备用模型时尽快切换
+func withMaxAttempts(ctx context.Context, attempts int) context.Context {
+	return context.WithValue(ctx, maxAttemptsKey{}, attempts)
+}
+
+// withRetry 执行请求，可重试的错误按带抖动的指数退避重试
+func (g *ModelGateway) withRetry(ctx context.Context, endpoint string, do func() *ModelError) error {
+	maxRetries := g.maxRetries
+	if attempts, ok := ctx.Value(maxAttemptsKey{}).(int); ok && attempts > 0 && attempts < maxRetries {
+		maxRetries = attempts
+	}
+
+	var lastErr *ModelError
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := g.backoff(attempt, lastErr.retryAfter)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		lastErr = do()
+		if lastErr == nil {
+			return nil
+		}
+		lastErr.Attempts = attempt + 1
+
+		// 调用方取消或超时时直接返回
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !lastErr.Retryable() {
+			break
+		}
+		logger.Warnf("调用模型失败(第%d次)，准备重试: %v", attempt+1, lastErr)
+	}
+
+	logger.Errorf("调用模型失败: %v", lastErr)
+	return lastErr
+}
+
+// backoff 计算第 attempt 次重试前的等待时间，服务端指定 Retry-After 时取较大值
+func (g *ModelGateway) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := g.baseDelay << uint(attempt-1)
+	if delay <= 0 || delay > g.maxDelay {
+		delay = g.maxDelay
+	}
+	// 在 [delay/2, delay] 之间随机，避免同时重试
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// acquire 获取接口的并发令牌
+func (g *ModelGateway) acquire(ctx context.Context, endpoint string) (func(), error) {
+	limiter := g.limiter(endpoint)
+	select {
+	case limiter <- struct{}{}:
+		return func() { <-limiter }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *ModelGateway) limiter(endpoint string) chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	limiter, ok := g.limiters[endpoint]
+	if !ok {
+		limit := g.concurrency[endpoint]
+		if limit <= 0 {
+			limit = g.defaultConcurrency
+		}
+		limiter = make(chan struct{}, limit)
+		g.limiters[endpoint] = limiter
+	}
+	return limiter
+}
+
+// doPost 发送一次 POST 请求并返回响应体
+func (g *ModelGateway) doPost(ctx context.Context, endpoint, url, apiKey string, reqBody []byte) ([]byte, *ModelError) {
+	httpReq, err := newJSONRequest(ctx, url, apiKey, reqBody)
+	if err != nil {
+		return nil, &ModelError{Endpoint: endpoint, Kind: ErrModelBadRequest, Err: err}
+	}
+
+	resp, err := g.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, classifyTransportError(endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, classifyTransportError(endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, classifyStatus(endpoint, resp, body)
+	}
+	return body, nil
+}
+
+func newJSONRequest(ctx context.Context, url, apiKey string, reqBody []byte) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return httpReq, nil
+}
+
+// classifyStatus 根据状态码归类错误，408、429 和 5xx 可以重试
+func classifyStatus(endpoint string, resp *http.Response, body []byte) *ModelError {
+	modelErr := &ModelError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		Body:       truncateBody(body),
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		modelErr.Kind = ErrModelRateLimited
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			modelErr.retryAfter = time.Duration(seconds) * time.Second
+		}
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusGatewayTimeout:
+		modelErr.Kind = ErrModelTimeout
+	case resp.StatusCode >= 500:
+		modelErr.Kind = ErrModelUnavailable
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		modelErr.Kind = ErrModelAuth
+	default:
+		modelErr.Kind = ErrModelBadRequest
+	}
+	return modelErr
+}
+
+// classifyTransportError 归类网络错误，超时和连接失败可以重试
+func classifyTransportError(endpoint string, err error) *ModelError {
+	kind := ErrModelUnavailable
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		kind = ErrModelTimeout
+	}
+	return &ModelError{Endpoint: endpoint, Kind: kind, Err: err}
+}
+
+func truncateBody(body []byte) string {
+	if len(body) > maxErrorBodyLength {
+		return string(body[:maxErrorBodyLength]) + "..."
+	}
+	return string(body)
+}
+
+// releaseOnClose 关闭响应体时释放并发令牌
+type releaseOnClose struct {
+	io.ReadCloser
+	release   func()
+	closeOnce sync.Once
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.closeOnce.Do(r.release)
+	return err
+}
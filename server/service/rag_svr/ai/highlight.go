@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"server/framework/mysql"
+
+	"github.com/yanyiwu/gojieba"
+)
+
+// 每个检索结果前后最多附带的相邻句子数
+const maxContextSentences = 5
+
+// 高亮时忽略的常见虚词
+var highlightStopWords = map[string]bool{
+	"的": true, "了": true, "是": true, "在": true, "和": true, "与": true, "及": true, "或": true,
+	"吗": true, "呢": true, "吧": true, "啊": true, "把": true, "被": true, "给": true, "对": true,
+	"什么": true, "怎么": true, "怎样": true, "如何": true, "哪些": true, "为什么": true,
+	"the": true, "a": true, "an": true, "of": true, "to": true, "in": true, "and": true, "or": true, "is": true,
+}
+
+var (
+	jiebaInstance *gojieba.Jieba
+	jiebaOnce     sync.Once
+)
+
+// getJieba 检索路径共用的分词器，词典只加载一次
+func getJieba() *gojieba.Jieba {
+	jiebaOnce.Do(func() {
+		jiebaInstance = gojieba.NewJieba()
+	})
+	return jiebaInstance
+}
+
+// queryTerms 用 jieba 搜索引擎模式切分查询，去掉标点、空白和虚词，英文统一小写
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0)
+	for _, word := range getJieba().CutForSearch(query, true) {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] || highlightStopWords[word] || !hasWordRune(word) {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+func hasWordRune(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateHighlights 返回文本中出现的查询词，保留文本中的原始大小写，按首次出现的位置排列；
+// 一个词是另一个命中词的一部分时（如“向量”和“向量检索”）只保留较长的词
+func generateHighlights(content, query string) []string {
+	lower := strings.ToLower(content)
+	// 小写后字节长度不变时才能按下标取原文
+	sameLength := len(lower) == len(content)
+	type match struct {
+		term  string
+		start int
+	}
+	matches := make([]match, 0)
+	for _, term := range queryTerms(query) {
+		if start := strings.Index(lower, term); start >= 0 {
+			if sameLength {
+				term = content[start : start+len(term)]
+			}
+			matches = append(matches, match{term: term, start: start})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return len(matches[i].term) > len(matches[j].term)
+	})
+
+	highlights := make([]string, 0, len(matches))
+	for i, m := range matches {
+		covered := false
+		for j, other := range matches {
+			if i != j && len(other.term) > len(m.term) && strings.Contains(strings.ToLower(other.term), strings.ToLower(m.term)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			highlights = append(highlights, m.term)
+		}
+	}
+	return highlights
+}
+
+// chunkContext 块前后各 n 个相邻句子，只取同一文档的句子，到文档开头或结尾为止
+func (s *DocumentService) chunkContext(ctx context.Context, chunk *mysql.DocumentChunk, n int) (before, after string, err error) {
+	if n <= 0 {
+		return "", "", nil
+	}
+	n = min(n, maxContextSentences)
+	from := uint64(1)
+	if chunk.SentenceIDMin > uint64(n) {
+		from = chunk.SentenceIDMin - uint64(n)
+	}
+	var sentences []mysql.DocumentSentence
+	if err := s.db.WithContext(ctx).Table("document_sentence").
+		Where("doc_id = ? AND ((sentence_id >= ? AND sentence_id < ?) OR (sentence_id > ? AND sentence_id <= ?))",
+			chunk.DocID, from, chunk.SentenceIDMin, chunk.SentenceIDMax, chunk.SentenceIDMax+uint64(n)).
+		Order("sentence_id").Find(&sentences).Error; err != nil {
+		return "", "", fmt.Errorf("获取相邻句子失败: %v", err)
+	}
+
+	beforeParts := make([]string, 0, n)
+	afterParts := make([]string, 0, n)
+	for _, sentence := range sentences {
+		if sentence.SentenceID < chunk.SentenceIDMin {
+			beforeParts = append(beforeParts, sentence.Content)
+		} else {
+			afterParts = append(afterParts, sentence.Content)
+		}
+	}
+	return strings.Join(beforeParts, " "), strings.Join(afterParts, " "), nil
+}
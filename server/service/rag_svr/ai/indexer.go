@@ -0,0 +1,614 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"server/framework/config"
+	"server/framework/id_generator"
+	"server/framework/logger"
+	"server/framework/milvus"
+	"server/framework/mysql"
+	"server/framework/redis"
+	"server/service/rag_svr/chunker"
+	"server/service/rag_svr/usage"
+
+	"github.com/yanyiwu/gojieba"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 文档状态
+const (
+	DocumentStatusPending = "pending" // 已保存，等待索引完成
+	DocumentStatusActive  = "active"  // 索引完成，可以检索
+	DocumentStatusFailed  = "failed"  // 索引失败且不再重试
+)
+
+// 索引任务状态
+const (
+	IndexJobPending   = "pending"
+	IndexJobRunning   = "running"
+	IndexJobSucceeded = "succeeded"
+	IndexJobFailed    = "failed"
+)
+
+// 索引任务默认配置
+const (
+	defaultIndexWorkers      = 2
+	defaultIndexBatchSize    = 64
+	defaultIndexMaxAttempts  = 3
+	defaultIndexPollInterval = time.Second
+	defaultIndexLeaseTimeout = 10 * time.Minute
+
+	indexRetryBaseDelay = 10 * time.Second
+	indexRetryMaxDelay  = 10 * time.Minute
+	maxIndexErrorLength = 1000
+
+	indexLockPrefix = "document:index:lock:"
+)
+
+// 不会因重试而成功的错误，任务直接失败
+var (
+	errDocumentGone   = errors.New("文档不存在或已删除")
+	errInvalidChunker = errors.New("切块配置无效")
+)
+
+// indexWakeup 有新任务时唤醒空闲的 worker，不必等到下一次轮询
+var indexWakeup = make(chan struct{}, 1)
+
+type indexerConfig struct {
+	workers      int
+	batchSize    int
+	maxAttempts  int
+	pollInterval time.Duration
+	leaseTimeout time.Duration
+}
+
+func getIndexerConfig() indexerConfig {
+	cfg := indexerConfig{
+		workers:      defaultIndexWorkers,
+		batchSize:    defaultIndexBatchSize,
+		maxAttempts:  defaultIndexMaxAttempts,
+		pollInterval: defaultIndexPollInterval,
+		leaseTimeout: defaultIndexLeaseTimeout,
+	}
+	if config.GlobalConfig == nil {
+		return cfg
+	}
+	indexer := config.GlobalConfig.Indexer
+	if indexer.Workers > 0 {
+		cfg.workers = indexer.Workers
+	}
+	if indexer.BatchSize > 0 {
+		cfg.batchSize = indexer.BatchSize
+	}
+	if indexer.MaxAttempts > 0 {
+		cfg.maxAttempts = indexer.MaxAttempts
+	}
+	if indexer.PollInterval > 0 {
+		cfg.pollInterval = time.Duration(indexer.PollInterval) * time.Millisecond
+	}
+	if indexer.LeaseTimeout > 0 {
+		cfg.leaseTimeout = time.Duration(indexer.LeaseTimeout) * time.Second
+	}
+	return cfg
+}
+
+// enqueueIndexJob 创建索引任务，需要与文档在同一事务中调用
+// 文档已有未开始执行的任务时复用该任务，连续多次更新只索引一次
+func enqueueIndexJob(tx *gorm.DB, docID, userID uint64) error {
+	var queued mysql.DocumentIndexJob
+	result := tx.Table("document_index_job").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("doc_id = ? AND status = ?", docID, IndexJobPending).
+		Order("job_id DESC").
+		Limit(1).
+		Find(&queued)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return tx.Table("document_index_job").Where("job_id = ?", queued.JobID).Updates(map[string]interface{}{
+			"attempts":     0,
+			"max_attempts": getIndexerConfig().maxAttempts,
+			"last_error":   "",
+			"next_run_at":  time.Now(),
+		}).Error
+	}
+
+	job := &mysql.DocumentIndexJob{
+		DocID:       docID,
+		UserID:      userID,
+		Status:      IndexJobPending,
+		MaxAttempts: uint32(getIndexerConfig().maxAttempts),
+		NextRunAt:   time.Now(),
+	}
+	return tx.Table("document_index_job").Create(job).Error
+}
+
+// notifyIndexWorkers 唤醒一个空闲的 worker
+func notifyIndexWorkers() {
+	select {
+	case indexWakeup <- struct{}{}:
+	default:
+	}
+}
+
+// StartIndexWorkers 启动后台索引 worker，返回的 stop 函数停止领取新任务并等待 worker 退出，
+// 执行中被中断的任务放回队列，不计入尝试次数
+func StartIndexWorkers() (stop func()) {
+	cfg := getIndexerConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	hostname, _ := os.Hostname()
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.workers; i++ {
+		wg.Add(1)
+		worker := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), i)
+		go func() {
+			defer wg.Done()
+			runIndexWorker(ctx, worker, cfg)
+		}()
+	}
+	logger.Infof("索引 worker 已启动: workers=%d, batch_size=%d, max_attempts=%d", cfg.workers, cfg.batchSize, cfg.maxAttempts)
+
+	return func() {
+		cancel()
+		wg.Wait()
+		logger.Infof("索引 worker 已停止")
+	}
+}
+
+func runIndexWorker(ctx context.Context, worker string, cfg indexerConfig) {
+	for ctx.Err() == nil {
+		job, err := claimIndexJob(ctx, worker, cfg.leaseTimeout)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf("领取索引任务失败: worker=%s, err=%v", worker, err)
+		}
+		if job != nil {
+			runIndexJob(ctx, job, cfg)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-indexWakeup:
+		case <-time.After(cfg.pollInterval):
+		}
+	}
+}
+
+// claimIndexJob 领取一个到期的待执行任务，或租约已过期（执行实例中断）的运行中任务
+// 使用 SKIP LOCKED 避免多个 worker 和实例领取同一个任务
+func claimIndexJob(ctx context.Context, worker string, leaseTimeout time.Duration) (*mysql.DocumentIndexJob, error) {
+	var job mysql.DocumentIndexJob
+	now := time.Now()
+	err := mysql.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("document_index_job").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND next_run_at <= ?) OR (status = ? AND updated_at < ?)",
+				IndexJobPending, now, IndexJobRunning, now.Add(-leaseTimeout)).
+			Order("job_id").
+			Limit(1).
+			Find(&job)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		job.Status = IndexJobRunning
+		job.Attempts++
+		job.Worker = worker
+		job.StartedAt = &now
+		return tx.Table("document_index_job").Where("job_id = ?", job.JobID).Updates(map[string]interface{}{
+			"status":     job.Status,
+			"attempts":   job.Attempts,
+			"worker":     job.Worker,
+			"started_at": now,
+		}).Error
+	})
+	if err != nil || job.JobID == 0 {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// runIndexJob 执行索引任务并记录结果
+func runIndexJob(ctx context.Context, job *mysql.DocumentIndexJob, cfg indexerConfig) {
+	// 同一文档的任务不能同时执行，否则会与同一批旧块比对
+	if redis.GetClient() != nil {
+		lockKey := fmt.Sprintf("%s%d", indexLockPrefix, job.DocID)
+		locked, err := redis.SetNX(ctx, lockKey, job.Worker, cfg.leaseTimeout)
+		if err == nil && !locked {
+			releaseIndexJob(job, indexRetryBaseDelay)
+			logger.Infof("文档正在索引，任务延后执行: job_id=%d, doc_id=%d", job.JobID, job.DocID)
+			return
+		}
+		if err == nil {
+			defer redis.Del(context.Background(), lockKey)
+		}
+	}
+
+	logger.Infof("开始索引文档: job_id=%d, doc_id=%d, attempt=%d/%d", job.JobID, job.DocID, job.Attempts, job.MaxAttempts)
+	start := time.Now()
+
+	var err error
+	if job.Attempts > job.MaxAttempts {
+		// 租约过期被重新领取的任务也可能已经用完尝试次数
+		err = fmt.Errorf("任务多次中断，已达到最大尝试次数")
+	} else {
+		err = indexDocumentChunks(usage.WithScope(ctx, job.UserID, 0), job, cfg.batchSize)
+	}
+
+	switch {
+	case err == nil:
+		finishIndexJob(job)
+		logger.Infof("文档索引完成: job_id=%d, doc_id=%d, chunks=%d, 耗时=%v", job.JobID, job.DocID, job.TotalChunks, time.Since(start))
+	case ctx.Err() != nil:
+		releaseIndexJob(job, 0)
+		logger.Warnf("服务停止，索引任务放回队列: job_id=%d, doc_id=%d", job.JobID, job.DocID)
+	default:
+		failIndexJob(job, err)
+	}
+}
+
+// indexDocumentChunks 切块后与文档已有的块按内容比对：内容不变的块沿用原向量，只更新对应的段落和句子；
+// 新增的块分批向量化写入 MySQL 和 Milvus，每批完成后更新进度；全部写入后切换文档的当前版本，再删除不再出现的块
+func indexDocumentChunks(ctx context.Context, job *mysql.DocumentIndexJob, batchSize int) error {
+	db := mysql.GetDB().WithContext(ctx)
+
+	var doc mysql.Document
+	if err := db.Table("document").First(&doc, job.DocID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errDocumentGone
+		}
+		return fmt.Errorf("获取文档失败: %v", err)
+	}
+
+	docChunker, chunkerConfig, err := documentChunker(&doc)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidChunker, err)
+	}
+
+	// 先取版本号再读段落，段落在此期间被再次更新时由下一个任务更正
+	var version uint32
+	if err := db.Table("document_version").Where("doc_id = ?", job.DocID).
+		Select("COALESCE(MAX(version), 0)").Row().Scan(&version); err != nil {
+		return fmt.Errorf("获取文档版本失败: %v", err)
+	}
+	paragraphs, err := loadChunkerParagraphs(ctx, job.DocID)
+	if err != nil {
+		return err
+	}
+	chunks := docChunker.Chunk(paragraphs)
+
+	// 之前中断或失败的尝试已写入的块也按内容沿用，重试结果保持一致
+	var existing []mysql.DocumentChunk
+	if err := db.Table("document_chunk").Select("chunk_id", "paragraph_id", "sentence_id_min", "sentence_id_max", "content", "version").
+		Where("doc_id = ?", job.DocID).Order("chunk_id").Find(&existing).Error; err != nil {
+		return fmt.Errorf("获取文档块失败: %v", err)
+	}
+	reusable, outdated, err := splitOutdatedChunks(ctx, job.DocID, existing)
+	if err != nil {
+		return err
+	}
+	reused, pending, stale := diffChunks(reusable, chunks)
+	stale = append(stale, outdated...)
+	logger.Infof("文档切块完成: doc_id=%d, version=%d, 切块=%s, chunks=%d, 沿用=%d, 新增=%d, 删除=%d",
+		job.DocID, version, chunkerConfig, len(chunks), len(reused), len(pending), len(stale))
+
+	job.TotalChunks = uint32(len(chunks))
+	job.ReusedChunks = uint32(len(reused))
+	job.DoneChunks = job.ReusedChunks
+	if err := updateIndexProgress(ctx, job); err != nil {
+		return err
+	}
+
+	jieba := gojieba.NewJieba()
+	defer jieba.Free()
+	for start := 0; start < len(pending); start += batchSize {
+		batch := pending[start:min(start+batchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for i, chunk := range batch {
+			texts[i] = chunk.Content
+		}
+		vectors, err := BatchGetEmbedding(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("生成块向量失败: %v", err)
+		}
+
+		rows := make([]mysql.DocumentChunk, 0, len(batch))
+		ids := make([]int64, 0, len(batch))
+		fields := make([]map[string]interface{}, 0, len(batch))
+		for i, chunk := range batch {
+			chunkID := id_generator.GetInstance().GetDocumentChunkID()
+			if chunkID == 0 {
+				return fmt.Errorf("获取块ID失败")
+			}
+			keywordsJSON, _ := json.Marshal(jieba.Extract(chunk.Content, 5))
+			embeddingBytes, err := json.Marshal(vectors[i])
+			if err != nil {
+				return fmt.Errorf("序列化向量失败: %v", err)
+			}
+			rows = append(rows, mysql.DocumentChunk{
+				ChunkID:       chunkID,
+				DocID:         job.DocID,
+				ParagraphID:   chunk.ParagraphID,
+				SentenceIDMin: chunk.SentenceIDMin,
+				SentenceIDMax: chunk.SentenceIDMax,
+				Content:       chunk.Content,
+				Version:       version,
+				Keywords:      string(keywordsJSON),
+				Embedding:     embeddingBytes,
+			})
+			ids = append(ids, int64(chunkID))
+			fields = append(fields, documentVectorFields(&doc))
+		}
+
+		// 向量写入 Milvus 失败时不保留 MySQL 中的块，重试时重新生成
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Table("document_chunk").Create(&rows).Error; err != nil {
+				return fmt.Errorf("创建块失败: %v", err)
+			}
+			if err := milvus.BatchInsertVectorsWithFields(ctx, milvus.DocumentCollectionName, ids, vectors, fields); err != nil {
+				return fmt.Errorf("存储向量到 Milvus 失败: %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		job.DoneChunks += uint32(len(batch))
+		if err := updateIndexProgress(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	// 新的块全部写入后，沿用的块归入新版本并切换文档的当前版本，此前检索的仍是旧版本
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range reused {
+			if err := tx.Table("document_chunk").Where("chunk_id = ?", row.ChunkID).Updates(map[string]interface{}{
+				"paragraph_id":    row.ParagraphID,
+				"sentence_id_min": row.SentenceIDMin,
+				"sentence_id_max": row.SentenceIDMax,
+				"version":         version,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Table("document").Where("doc_id = ?", job.DocID).Update("version", version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("切换文档版本失败: %v", err)
+	}
+
+	return deleteChunks(ctx, stale)
+}
+
+// splitOutdatedChunks 找出由其他向量化模型生成的块，这些块不能沿用，需要重新向量化
+// 没有版本记录的早期块视为当前模型生成
+func splitOutdatedChunks(ctx context.Context, docID uint64, existing []mysql.DocumentChunk) ([]mysql.DocumentChunk, []int64, error) {
+	var versions []mysql.DocumentVersion
+	if err := mysql.GetDB().WithContext(ctx).Table("document_version").Select("version", "embedding_model").
+		Where("doc_id = ?", docID).Find(&versions).Error; err != nil {
+		return nil, nil, fmt.Errorf("获取文档版本失败: %v", err)
+	}
+	models := make(map[uint32]string, len(versions))
+	for _, version := range versions {
+		models[version.Version] = version.EmbeddingModel
+	}
+
+	current := embeddingModelName()
+	reusable := make([]mysql.DocumentChunk, 0, len(existing))
+	outdated := make([]int64, 0)
+	for _, row := range existing {
+		if model, ok := models[row.Version]; ok && model != "" && model != current {
+			outdated = append(outdated, int64(row.ChunkID))
+			continue
+		}
+		reusable = append(reusable, row)
+	}
+	return reusable, outdated, nil
+}
+
+// diffChunks 按内容将新切出的块与已有的块对应，内容相同的多个块按出现顺序对应
+// 返回更新了位置的沿用块、需要向量化的新块和不再出现的旧块ID；没有记录内容的早期块不沿用
+func diffChunks(existing []mysql.DocumentChunk, chunks []chunker.Chunk) (reused []mysql.DocumentChunk, pending []chunker.Chunk, stale []int64) {
+	candidates := make(map[string][]int, len(existing))
+	for i, row := range existing {
+		if row.Content != "" {
+			candidates[row.Content] = append(candidates[row.Content], i)
+		}
+	}
+
+	matched := make([]bool, len(existing))
+	for _, chunk := range chunks {
+		indexes := candidates[chunk.Content]
+		if len(indexes) == 0 {
+			pending = append(pending, chunk)
+			continue
+		}
+		candidates[chunk.Content] = indexes[1:]
+		matched[indexes[0]] = true
+
+		row := existing[indexes[0]]
+		row.ParagraphID = chunk.ParagraphID
+		row.SentenceIDMin = chunk.SentenceIDMin
+		row.SentenceIDMax = chunk.SentenceIDMax
+		reused = append(reused, row)
+	}
+	for i, row := range existing {
+		if !matched[i] {
+			stale = append(stale, int64(row.ChunkID))
+		}
+	}
+	return reused, pending, stale
+}
+
+// loadChunkerParagraphs 读取文档的段落和句子，按 ID 排序
+func loadChunkerParagraphs(ctx context.Context, docID uint64) ([]chunker.Paragraph, error) {
+	db := mysql.GetDB().WithContext(ctx)
+
+	var paragraphs []mysql.DocumentParagraph
+	if err := db.Table("document_paragraph").Where("doc_id = ?", docID).Order("paragraph_id").Find(&paragraphs).Error; err != nil {
+		return nil, fmt.Errorf("获取文档段落失败: %v", err)
+	}
+	var sentences []mysql.DocumentSentence
+	if err := db.Table("document_sentence").Where("doc_id = ?", docID).Order("sentence_id").Find(&sentences).Error; err != nil {
+		return nil, fmt.Errorf("获取文档句子失败: %v", err)
+	}
+
+	result := make([]chunker.Paragraph, len(paragraphs))
+	index := make(map[uint64]int, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		result[i] = chunker.Paragraph{ID: paragraph.ParagraphID, Content: paragraph.Content}
+		if paragraph.Metadata != "" {
+			_ = json.Unmarshal([]byte(paragraph.Metadata), &result[i].Meta)
+		}
+		index[paragraph.ParagraphID] = i
+	}
+	for _, sentence := range sentences {
+		if i, ok := index[sentence.ParagraphID]; ok {
+			result[i].Sentences = append(result[i].Sentences, chunker.Sentence{ID: sentence.SentenceID, Content: sentence.Content})
+		}
+	}
+	return result, nil
+}
+
+// deleteChunks 从 Milvus 和 MySQL 删除指定的块
+func deleteChunks(ctx context.Context, chunkIDs []int64) error {
+	if len(chunkIDs) == 0 {
+		return nil
+	}
+	if err := milvus.BatchDeleteVectors(ctx, milvus.DocumentCollectionName, chunkIDs); err != nil {
+		return fmt.Errorf("删除旧向量失败: %v", err)
+	}
+	if err := mysql.GetDB().WithContext(ctx).Table("document_chunk").Where("chunk_id IN ?", chunkIDs).Delete(&mysql.DocumentChunk{}).Error; err != nil {
+		return fmt.Errorf("删除旧块失败: %v", err)
+	}
+	return nil
+}
+
+// updateIndexProgress 更新任务进度，同时刷新 updated_at 作为心跳
+func updateIndexProgress(ctx context.Context, job *mysql.DocumentIndexJob) error {
+	err := mysql.GetDB().WithContext(ctx).Table("document_index_job").Where("job_id = ?", job.JobID).Updates(map[string]interface{}{
+		"total_chunks":  job.TotalChunks,
+		"done_chunks":   job.DoneChunks,
+		"reused_chunks": job.ReusedChunks,
+		"updated_at":    time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("更新索引进度失败: %v", err)
+	}
+	return nil
+}
+
+// finishIndexJob 任务成功，文档变为可检索
+func finishIndexJob(job *mysql.DocumentIndexJob) {
+	now := time.Now()
+	err := mysql.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("document_index_job").Where("job_id = ?", job.JobID).Updates(map[string]interface{}{
+			"status":      IndexJobSucceeded,
+			"last_error":  "",
+			"finished_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Table("document").
+			Where("doc_id = ? AND status IN ?", job.DocID, []string{DocumentStatusPending, DocumentStatusFailed}).
+			Update("status", DocumentStatusActive).Error
+	})
+	if err != nil {
+		logger.Errorf("更新索引任务状态失败: job_id=%d, err=%v", job.JobID, err)
+	}
+}
+
+// failIndexJob 记录失败原因，未达到最大尝试次数时按指数退避重新排队，否则标记文档索引失败
+func failIndexJob(job *mysql.DocumentIndexJob, cause error) {
+	lastError := truncateRunes(cause.Error(), maxIndexErrorLength)
+	now := time.Now()
+
+	if errors.Is(cause, errDocumentGone) || errors.Is(cause, errInvalidChunker) || job.Attempts >= job.MaxAttempts {
+		logger.Errorf("文档索引失败: job_id=%d, doc_id=%d, attempts=%d, err=%v", job.JobID, job.DocID, job.Attempts, cause)
+		err := mysql.GetDB().Transaction(func(tx *gorm.DB) error {
+			if err := tx.Table("document_index_job").Where("job_id = ?", job.JobID).Updates(map[string]interface{}{
+				"status":      IndexJobFailed,
+				"last_error":  lastError,
+				"finished_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			return tx.Table("document").
+				Where("doc_id = ? AND status = ?", job.DocID, DocumentStatusPending).
+				Update("status", DocumentStatusFailed).Error
+		})
+		if err != nil {
+			logger.Errorf("更新索引任务状态失败: job_id=%d, err=%v", job.JobID, err)
+		}
+		return
+	}
+
+	delay := indexRetryDelay(job.Attempts)
+	logger.Warnf("文档索引失败，%v 后重试: job_id=%d, doc_id=%d, attempt=%d/%d, err=%v",
+		delay, job.JobID, job.DocID, job.Attempts, job.MaxAttempts, cause)
+	err := mysql.GetDB().Table("document_index_job").Where("job_id = ?", job.JobID).Updates(map[string]interface{}{
+		"status":      IndexJobPending,
+		"last_error":  lastError,
+		"next_run_at": now.Add(delay),
+	}).Error
+	if err != nil {
+		logger.Errorf("更新索引任务状态失败: job_id=%d, err=%v", job.JobID, err)
+	}
+}
+
+// releaseIndexJob 将任务放回队列，delay 后再执行，本次尝试不计数
+func releaseIndexJob(job *mysql.DocumentIndexJob, delay time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := mysql.GetDB().WithContext(ctx).Table("document_index_job").Where("job_id = ?", job.JobID).Updates(map[string]interface{}{
+		"status":      IndexJobPending,
+		"attempts":    gorm.Expr("GREATEST(attempts, 1) - 1"),
+		"next_run_at": time.Now().Add(delay),
+	}).Error
+	if err != nil {
+		logger.Errorf("放回索引任务失败: job_id=%d, err=%v", job.JobID, err)
+	}
+}
+
+// indexRetryDelay 第 n 次失败后的重试间隔
+func indexRetryDelay(attempts uint32) time.Duration {
+	delay := indexRetryBaseDelay
+	for i := uint32(1); i < attempts && delay < indexRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, indexRetryMaxDelay)
+}
+
+// GetDocumentIndexStatus 获取文档及其最近一次索引任务，早于异步索引添加的文档没有任务时返回 nil
+func GetDocumentIndexStatus(ctx context.Context, docID uint64) (*mysql.Document, *mysql.DocumentIndexJob, error) {
+	db := mysql.GetDB().WithContext(ctx)
+
+	var doc mysql.Document
+	if err := db.Table("document").First(&doc, docID).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var jobs []mysql.DocumentIndexJob
+	if err := db.Table("document_index_job").Where("doc_id = ?", docID).Order("job_id DESC").Limit(1).Find(&jobs).Error; err != nil {
+		return nil, nil, fmt.Errorf("获取索引任务失败: %v", err)
+	}
+	if len(jobs) == 0 {
+		return &doc, nil, nil
+	}
+	return &doc, &jobs[0], nil
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"server/framework/config"
+	"server/service/rag_svr/mockllm"
+)
+
+const mockDimension = 64
+
+var mockServer *mockllm.Server
+
+// TestMain 将全局配置指向模拟服务，模型网关、对话模型和向量化模型按该配置只创建一次；
+// 不初始化 MySQL 和 Redis，用量不落库，向量不缓存
+func TestMain(m *testing.M) {
+	mockServer = mockllm.NewServer(mockllm.Options{Dimension: mockDimension, MaxBatchSize: 4})
+	config.GlobalConfig = &config.Config{}
+	mockServer.Configure(config.GlobalConfig)
+
+	code := m.Run()
+	mockServer.Close()
+	os.Exit(code)
+}
+
+func TestChatRoundTrip(t *testing.T) {
+	mockServer.Reset()
+	mockServer.AddRule(mockllm.Rule{Match: "退货", Content: "收到商品 7 天内可以无理由退货。"})
+
+	provider, err := GetLLMProvider()
+	if err != nil {
+		t.Fatalf("创建对话模型失败: %v", err)
+	}
+	messages := []QwenMessage{
+		{Role: "system", Content: "你是客服助手"},
+		{Role: "user", Content: "怎么退货？"},
+	}
+
+	result, err := provider.Chat(context.Background(), messages, nil)
+	if err != nil {
+		t.Fatalf("对话失败: %v", err)
+	}
+	if result.Content != "收到商品 7 天内可以无理由退货。" {
+		t.Errorf("回复不符: %q", result.Content)
+	}
+	if result.Usage.TotalTokens == 0 {
+		t.Errorf("没有返回用量")
+	}
+
+	chunks, errs := provider.StreamChat(context.Background(), messages)
+	var content strings.Builder
+	for chunk := range chunks {
+		content.WriteString(chunk.Delta)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("流式对话失败: %v", err)
+	}
+	if content.String() != result.Content {
+		t.Errorf("流式回复不符: %q", content.String())
+	}
+
+	requests := mockServer.Requests()
+	if len(requests) != 2 || requests[0].Stream || !requests[1].Stream {
+		t.Fatalf("模拟服务收到的请求不符: %+v", requests)
+	}
+	if requests[0].Model != config.GlobalConfig.AI.ChatModel.ModelName {
+		t.Errorf("请求的模型不符: %s", requests[0].Model)
+	}
+}
+
+func TestEmbeddingRoundTrip(t *testing.T) {
+	mockServer.Reset()
+
+	vector, err := GetEmbedding(context.Background(), "退货政策")
+	if err != nil {
+		t.Fatalf("向量化失败: %v", err)
+	}
+	if !reflect.DeepEqual(vector, mockllm.HashEmbedding("退货政策", mockDimension)) {
+		t.Errorf("向量与模拟服务生成的不一致")
+	}
+
+	// 超过单次请求上限时拆分为多个请求，结果按原顺序返回
+	texts := []string{"一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+	vectors, err := BatchGetEmbedding(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("批量向量化失败: %v", err)
+	}
+	if len(vectors) != len(texts) {
+		t.Fatalf("向量数量不符: %d", len(vectors))
+	}
+	for i, text := range texts {
+		if !reflect.DeepEqual(vectors[i], mockllm.HashEmbedding(text, mockDimension)) {
+			t.Errorf("第%d个向量与文本不对应: %s", i, text)
+		}
+	}
+
+	// 单条 1 次，10 条按 4 条拆分为 3 次
+	requests := mockServer.Requests()
+	if len(requests) != 4 {
+		t.Errorf("请求数不符: 期望4, 实际%d", len(requests))
+	}
+	for _, req := range requests {
+		if req.Model != config.GlobalConfig.AI.EmbeddingModel.ModelName {
+			t.Errorf("请求的模型不符: %s", req.Model)
+		}
+	}
+}
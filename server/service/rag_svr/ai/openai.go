@@ -0,0 +1,207 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"server/framework/logger"
+	"server/service/rag_svr/usage"
+)
+
+const openAIChatCompletionsAPI = "/chat/completions"
+
+// OpenAIClient OpenAI 兼容的 /chat/completions 接口，实现 LLMProvider
+type OpenAIClient struct {
+	apiKey   string
+	endpoint string
+	gateway  *ModelGateway
+	config   *ChatModelConfig
+}
+
+type openAIChatRequest struct {
+	Model            string               `json:"model"`
+	Messages         []QwenMessage        `json:"messages"`
+	Temperature      float64              `json:"temperature"`
+	TopP             float64              `json:"top_p,omitempty"`
+	MaxTokens        int                  `json:"max_tokens,omitempty"`
+	FrequencyPenalty float64              `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64              `json:"presence_penalty,omitempty"`
+	Tools            []QwenTool           `json:"tools,omitempty"`
+	Stream           bool                 `json:"stream,omitempty"`
+	StreamOptions    *openAIStreamOptions `json:"stream_options,omitempty"`
+	ResponseFormat   *ResponseFormat      `json:"response_format,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIChatResponse struct {
+	ID      string         `json:"id"`
+	Model   string         `json:"model"`
+	Choices []openAIChoice `json:"choices"`
+	Usage   *openAIUsage   `json:"usage,omitempty"`
+}
+
+type openAIChoice struct {
+	Index        int         `json:"index"`
+	Message      QwenMessage `json:"message"`
+	Delta        QwenMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u *openAIUsage) toQwenUsage() QwenUsage {
+	if u == nil {
+		return QwenUsage{}
+	}
+	return QwenUsage{
+		InputTokens:  u.PromptTokens,
+		OutputTokens: u.CompletionTokens,
+		TotalTokens:  u.TotalTokens,
+	}
+}
+
+func NewOpenAIClient(config *ChatModelConfig) *OpenAIClient {
+	return &OpenAIClient{
+		apiKey:   config.APIKey,
+		endpoint: strings.TrimRight(config.BaseURL, "/") + openAIChatCompletionsAPI,
+		gateway:  GetModelGateway(),
+		config:   config,
+	}
+}
+
+// ModelName 当前使用的模型名称
+func (c *OpenAIClient) ModelName() string {
+	return c.config.ModelName
+}
+
+// newRequest 构建请求
+func (c *OpenAIClient) newRequest(messages []QwenMessage) openAIChatRequest {
+	return openAIChatRequest{
+		Model:            c.config.ModelName,
+		Messages:         messages,
+		Temperature:      c.config.Temperature,
+		TopP:             c.config.TopP,
+		MaxTokens:        c.config.MaxTokens,
+		FrequencyPenalty: c.config.FrequencyPenalty,
+		PresencePenalty:  c.config.PresencePenalty,
+	}
+}
+
+// Chat 非流式对话
+func (c *OpenAIClient) Chat(ctx context.Context, messages []QwenMessage, tools []QwenTool) (*ChatResult, error) {
+	request := c.newRequest(messages)
+	request.Tools = tools
+	request.ResponseFormat = responseFormatFrom(ctx)
+
+	var response openAIChatResponse
+	if err := c.gateway.PostJSON(ctx, EndpointChat, c.endpoint, c.apiKey, request, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("模型返回结果为空: id=%s", response.ID)
+	}
+
+	u := response.Usage.toQwenUsage()
+	recordUsage(ctx, usage.CallTypeChat, c.config.ModelName, u)
+
+	choice := response.Choices[0]
+	return &ChatResult{
+		Content:      choice.Message.Content,
+		ToolCalls:    choice.Message.ToolCalls,
+		FinishReason: choice.FinishReason,
+		Usage:        u,
+		Model:        c.config.ModelName,
+	}, nil
+}
+
+// StreamChat 流式对话
+func (c *OpenAIClient) StreamChat(ctx context.Context, messages []QwenMessage) (<-chan StreamChunk, <-chan error) {
+	responseChan := make(chan StreamChunk)
+	errorChan := make(chan error, 1)
+
+	var closeOnce sync.Once
+	closeChannels := func() {
+		closeOnce.Do(func() {
+			close(responseChan)
+			close(errorChan)
+		})
+	}
+
+	go func() {
+		defer closeChannels()
+
+		request := c.newRequest(messages)
+		request.Stream = true
+		request.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+
+		resp, err := c.gateway.OpenStream(ctx, EndpointChatStream, c.endpoint, c.apiKey, request, nil)
+		if err != nil {
+			logger.Errorf("发起流式请求失败: %v", err)
+			errorChan <- err
+			return
+		}
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				if err == io.EOF {
+					return
+				}
+				logger.Errorf("读取响应失败: %v", err)
+				errorChan <- fmt.Errorf("读取响应失败: %v", err)
+				return
+			}
+
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				return
+			}
+
+			var response openAIChatResponse
+			if err := json.Unmarshal([]byte(data), &response); err != nil {
+				logger.Errorf("解析响应失败: %v, data=%s", err, data)
+				continue
+			}
+
+			// include_usage 时最后一个事件 choices 为空，只携带用量
+			chunk := StreamChunk{}
+			if len(response.Choices) > 0 {
+				chunk.Delta = response.Choices[0].Delta.Content
+			}
+			if response.Usage != nil {
+				u := response.Usage.toQwenUsage()
+				chunk.Usage = &u
+				recordUsage(ctx, usage.CallTypeChat, c.config.ModelName, u)
+			}
+			if chunk.Delta == "" && chunk.Usage == nil {
+				continue
+			}
+
+			select {
+			case responseChan <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return responseChan, errorChan
+}
@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"server/framework/config"
+	"server/service/rag_svr/usage"
+)
+
+// 大模型提供方
+const (
+	ProviderDashScope = "dashscope" // 通义千问原生接口
+	ProviderOpenAI    = "openai"    // OpenAI 兼容的 /chat/completions 接口，如 vLLM、Ollama
+)
+
+// ChatModelConfig 对话模型配置
+type ChatModelConfig struct {
+	APIKey           string
+	Provider         string
+	ModelName        string
+	BaseURL          string
+	Temperature      float64
+	MaxTokens        int
+	TopP             float64
+	FrequencyPenalty float64
+	PresencePenalty  float64
+	MaxToolRounds    int
+}
+
+// ChatResult 非流式对话结果
+type ChatResult struct {
+	Content      string         `json:"content"`
+	ToolCalls    []QwenToolCall `json:"tool_calls,omitempty"`
+	FinishReason string         `json:"finish_reason"`
+	Usage        QwenUsage      `json:"usage"`
+	Model        string         `json:"model"`
+}
+
+// StreamChunk 流式输出分片，结束时的分片携带用量
+type StreamChunk struct {
+	Delta string
+	Usage *QwenUsage
+}
+
+// LLMProvider 大模型提供方接口
+type LLMProvider interface {
+	// Chat 非流式对话，tools 为空时不启用工具调用
+	Chat(ctx context.Context, messages []QwenMessage, tools []QwenTool) (*ChatResult, error)
+	// StreamChat 流式对话，分片为增量文本
+	StreamChat(ctx context.Context, messages []QwenMessage) (<-chan StreamChunk, <-chan error)
+	// ModelName 当前使用的模型名称
+	ModelName() string
+}
+
+var (
+	llmProviderInstance LLMProvider
+	llmProviderErr      error
+	llmProviderOnce     sync.Once
+)
+
+// GetLLMProvider 获取按全局配置创建的大模型提供方，配置了备用模型时按顺序切换
+func GetLLMProvider() (LLMProvider, error) {
+	llmProviderOnce.Do(func() {
+		if config.GlobalConfig == nil {
+			llmProviderErr = fmt.Errorf("全局配置未初始化")
+			return
+		}
+		chatModel := config.GlobalConfig.AI.ChatModel
+		provider, err := newFallbackLLMProvider(&ChatModelConfig{
+			APIKey:           chatModel.APIKey,
+			Provider:         chatModel.Provider,
+			ModelName:        chatModel.ModelName,
+			BaseURL:          chatModel.BaseURL,
+			Temperature:      chatModel.Temperature,
+			MaxTokens:        chatModel.MaxTokens,
+			TopP:             chatModel.TopP,
+			FrequencyPenalty: chatModel.FrequencyPenalty,
+			PresencePenalty:  chatModel.PresencePenalty,
+			MaxToolRounds:    chatModel.MaxToolRounds,
+		}, config.GlobalConfig.AI.Fallback.ChatModels)
+		if err != nil {
+			llmProviderErr = err
+			return
+		}
+		llmProviderInstance = provider
+	})
+	return llmProviderInstance, llmProviderErr
+}
+
+// NewLLMProvider 根据配置创建大模型提供方
+func NewLLMProvider(config *ChatModelConfig) (LLMProvider, error) {
+	switch config.Provider {
+	case ProviderDashScope, "":
+		return NewQwenClient(config), nil
+	case ProviderOpenAI:
+		return NewOpenAIClient(config), nil
+	default:
+		return nil, fmt.Errorf("不支持的模型提供方: %s", config.Provider)
+	}
+}
+
+// completeText 使用全局大模型提供方完成一次单轮对话
+func completeText(ctx context.Context, systemPrompt, text string) (string, error) {
+	provider, err := GetLLMProvider()
+	if err != nil {
+		return "", err
+	}
+	result, err := provider.Chat(ctx, []QwenMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: text},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
+
+// recordUsage 记录一次模型调用的用量，归属用户从 ctx 获取
+func recordUsage(ctx context.Context, callType, model string, u QwenUsage) {
+	usage.Record(ctx, callType, model, u.InputTokens, u.OutputTokens, u.TotalTokens)
+}
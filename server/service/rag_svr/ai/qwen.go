@@ -2,38 +2,28 @@ package ai
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 	"sync"
-	"time"
 
 	"server/framework/logger"
+	"server/service/rag_svr/usage"
 )
 
 const (
-	QwenAPIEndpoint = "https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation"
-	MaxRetries      = 3
-	RetryDelay      = time.Second
+	QwenAPIEndpoint   = "https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation"
+	qwenGenerationAPI = "/services/aigc/text-generation/generation"
 )
 
+// QwenClient 通义千问 DashScope 原生接口，实现 LLMProvider
 type QwenClient struct {
-	apiKey     string
-	httpClient *http.Client
-	config     *struct {
-		APIKey           string
-		Provider         string
-		ModelName        string
-		Temperature      float64
-		MaxTokens        int
-		TopP             float64
-		FrequencyPenalty float64
-		PresencePenalty  float64
-	}
+	apiKey   string
+	endpoint string
+	gateway  *ModelGateway
+	config   *ChatModelConfig
 }
 
 type QwenRequest struct {
@@ -48,19 +38,24 @@ type QwenInput struct {
 }
 
 type QwenMessage struct {
-	Role      string         `json:"role"`
-	Content   string         `json:"content"`
-	Tools     []QwenTool     `json:"tools,omitempty"`
-	ToolCalls []QwenToolCall `json:"tool_calls,omitempty"`
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	Name       string         `json:"name,omitempty"` // tool 消息对应的函数名
+	Tools      []QwenTool     `json:"tools,omitempty"`
+	ToolCalls  []QwenToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
 }
 
 type QwenParameters struct {
-	ResultFormat string  `json:"result_format"`
-	Temperature  float64 `json:"temperature"`
-	TopP         float64 `json:"top_p"`
-	TopK         int     `json:"top_k"`
-	MaxTokens    int     `json:"max_tokens"`
-	Stream       bool    `json:"stream"`
+	ResultFormat      string          `json:"result_format"`
+	Temperature       float64         `json:"temperature"`
+	TopP              float64         `json:"top_p"`
+	TopK              int             `json:"top_k"`
+	MaxTokens         int             `json:"max_tokens"`
+	Stream            bool            `json:"stream"`
+	IncrementalOutput bool            `json:"incremental_output,omitempty"` // 流式时只返回增量文本
+	Tools             []QwenTool      `json:"tools,omitempty"`
+	ResponseFormat    *ResponseFormat `json:"response_format,omitempty"`
 }
 
 type QwenResponse struct {
@@ -73,6 +68,13 @@ type QwenOutput struct {
 	Text         string         `json:"text"`
 	ToolCalls    []QwenToolCall `json:"tool_calls,omitempty"`
 	FinishReason string         `json:"finish_reason"`
+	Choices      []QwenChoice   `json:"choices,omitempty"` // result_format 为 message 时返回
+}
+
+// QwenChoice message 格式的输出
+type QwenChoice struct {
+	FinishReason string      `json:"finish_reason"`
+	Message      QwenMessage `json:"message"`
 }
 
 type QwenUsage struct {
@@ -88,10 +90,10 @@ type QwenFunction struct {
 	Parameters  map[string]interface{} `json:"parameters"`
 }
 
-// QwenFunctionCall 函数调用
+// QwenFunctionCall 函数调用，参数为 JSON 字符串
 type QwenFunctionCall struct {
-	Name      string                 `json:"name"`
-	Arguments map[string]interface{} `json:"arguments"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 // QwenToolCall 工具调用
@@ -114,67 +116,65 @@ type QwenFunctionDefinition struct {
 	Parameters  map[string]interface{} `json:"parameters"`
 }
 
-func NewQwenClient(config *struct {
-	APIKey           string
-	Provider         string
-	ModelName        string
-	Temperature      float64
-	MaxTokens        int
-	TopP             float64
-	FrequencyPenalty float64
-	PresencePenalty  float64
-}) *QwenClient {
+func NewQwenClient(config *ChatModelConfig) *QwenClient {
+	endpoint := QwenAPIEndpoint
+	if config.BaseURL != "" {
+		endpoint = strings.TrimRight(config.BaseURL, "/") + qwenGenerationAPI
+	}
 	return &QwenClient{
-		apiKey: config.APIKey,
-		httpClient: &http.Client{
-			Timeout: time.Duration(30) * time.Second, // 使用默认超时时间
-		},
-		config: config,
+		apiKey:   config.APIKey,
+		endpoint: endpoint,
+		gateway:  GetModelGateway(),
+		config:   config,
 	}
 }
 
-// handleFunctionCall 处理函数调用
-func (c *QwenClient) handleFunctionCall(ctx context.Context, response *QwenResponse) (*QwenMessage, error) {
-	if len(response.Output.ToolCalls) == 0 {
-		return nil, nil
-	}
+// ModelName 当前使用的模型名称
+func (c *QwenClient) ModelName() string {
+	return c.config.ModelName
+}
 
-	toolCall := response.Output.ToolCalls[0]
-	call := &FunctionCall{
-		Name:      toolCall.Function.Name,
-		Arguments: toolCall.Function.Arguments,
+// Chat 实现与 Qwen 大模型的非流式对话
+func (c *QwenClient) Chat(ctx context.Context, messages []QwenMessage, tools []QwenTool) (*ChatResult, error) {
+	request := QwenRequest{
+		Model: c.config.ModelName,
+		Input: QwenInput{
+			Messages: messages,
+		},
+		Parameters: QwenParameters{
+			ResultFormat:   "message",
+			Temperature:    c.config.Temperature,
+			TopP:           c.config.TopP,
+			TopK:           10,
+			MaxTokens:      c.config.MaxTokens,
+			Tools:          tools,
+			ResponseFormat: responseFormatFrom(ctx),
+		},
 	}
 
-	result, err := ExecuteFunctionCall(ctx, call)
-	if err != nil {
-		return nil, fmt.Errorf("执行函数调用失败: %v", err)
+	var response QwenResponse
+	if err := c.gateway.PostJSON(ctx, EndpointChat, c.endpoint, c.apiKey, request, &response); err != nil {
+		return nil, err
 	}
-
-	// 将结果转换为字符串
-	resultJSON, err := json.Marshal(result)
-	if err != nil {
-		return nil, fmt.Errorf("序列化函数调用结果失败: %v", err)
+	if len(response.Output.Choices) == 0 {
+		return nil, fmt.Errorf("模型返回结果为空: request_id=%s", response.RequestID)
 	}
 
-	return &QwenMessage{
-		Role:    "tool",
-		Content: string(resultJSON),
-		ToolCalls: []QwenToolCall{
-			{
-				ID:   toolCall.ID,
-				Type: "function",
-				Function: QwenFunctionCall{
-					Name:      toolCall.Function.Name,
-					Arguments: toolCall.Function.Arguments,
-				},
-			},
-		},
+	recordUsage(ctx, usage.CallTypeChat, c.config.ModelName, response.Usage)
+
+	choice := response.Output.Choices[0]
+	return &ChatResult{
+		Content:      choice.Message.Content,
+		ToolCalls:    choice.Message.ToolCalls,
+		FinishReason: choice.FinishReason,
+		Usage:        response.Usage,
+		Model:        c.config.ModelName,
 	}, nil
 }
 
 // StreamChat 实现与 Qwen 大模型的流式对话
-func (c *QwenClient) StreamChat(ctx context.Context, messages []QwenMessage, context string) (<-chan string, <-chan error) {
-	responseChan := make(chan string)
+func (c *QwenClient) StreamChat(ctx context.Context, messages []QwenMessage) (<-chan StreamChunk, <-chan error) {
+	responseChan := make(chan StreamChunk)
 	errorChan := make(chan error, 1)
 
 	// 使用 sync.Once 确保通道只被关闭一次
@@ -189,15 +189,6 @@ func (c *QwenClient) StreamChat(ctx context.Context, messages []QwenMessage, con
 	go func() {
 		defer closeChannels()
 
-		// 构建系统消息
-		systemMessage := QwenMessage{
-			Role:    "system",
-			Content: context,
-		}
-
-		// 将系统消息添加到消息列表的开头
-		messages = append([]QwenMessage{systemMessage}, messages...)
-
 		// 构建请求
 		request := QwenRequest{
 			Model: c.config.ModelName,
@@ -205,70 +196,33 @@ func (c *QwenClient) StreamChat(ctx context.Context, messages []QwenMessage, con
 				Messages: messages,
 			},
 			Parameters: QwenParameters{
-				ResultFormat: "text",
-				Temperature:  c.config.Temperature,
-				TopP:         c.config.TopP,
-				TopK:         10,
-				MaxTokens:    c.config.MaxTokens,
-				Stream:       true,
+				ResultFormat:      "text",
+				Temperature:       c.config.Temperature,
+				TopP:              c.config.TopP,
+				TopK:              10,
+				MaxTokens:         c.config.MaxTokens,
+				Stream:            true,
+				IncrementalOutput: true,
 			},
 		}
 
-		// 序列化请求
-		reqBody, err := json.Marshal(request)
-		if err != nil {
-			logger.Errorf("序列化请求失败: %v", err)
-			errorChan <- fmt.Errorf("序列化请求失败: %v", err)
-			return
-		}
-
-		// 创建 HTTP 请求
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", QwenAPIEndpoint, bytes.NewBuffer(reqBody))
-		if err != nil {
-			logger.Errorf("创建请求失败: %v", err)
-			errorChan <- fmt.Errorf("创建请求失败: %v", err)
-			return
-		}
-
-		// 设置请求头
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-		httpReq.Header.Set("X-DashScope-SSE", "enable") // 添加 SSE 支持
-
-		// 发送请求
-		resp, err := c.httpClient.Do(httpReq)
+		resp, err := c.gateway.OpenStream(ctx, EndpointChatStream, c.endpoint, c.apiKey, request, map[string]string{
+			"X-DashScope-SSE": "enable", // 添加 SSE 支持
+		})
 		if err != nil {
-			logger.Errorf("发送请求失败: %v", err)
-			errorChan <- fmt.Errorf("发送请求失败: %v", err)
+			logger.Errorf("发起流式请求失败: %v", err)
+			errorChan <- err
 			return
 		}
 		defer resp.Body.Close()
 
-		// 检查响应状态码
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			logger.Errorf("请求失败: status=%d, body=%s", resp.StatusCode, string(body))
-			errorChan <- fmt.Errorf("请求失败: status=%d, body=%s", resp.StatusCode, string(body))
-			return
-		}
-
-		// 处理流式响应
+		// 处理流式响应，每个事件只包含增量文本
 		reader := bufio.NewReader(resp.Body)
-		var lastText string
 
 		for {
 			line, err := reader.ReadString('\n')
 			if err != nil {
 				if err == io.EOF {
-					// 确保最后一个片段被发送
-					if lastText != "" {
-						select {
-						case responseChan <- lastText:
-							logger.Infof("发送最后一个文本片段: %s", lastText)
-						case <-ctx.Done():
-							return
-						}
-					}
 					return
 				}
 				logger.Errorf("读取响应失败: %v", err)
@@ -276,82 +230,42 @@ func (c *QwenClient) StreamChat(ctx context.Context, messages []QwenMessage, con
 				return
 			}
 
-			// 记录原始行
-			logger.Infof("收到原始行: %s", line)
-			line = strings.TrimSpace(line) // 先去除空白字符
-			logger.Infof("处理后的行: %s", line)
-			logger.Infof("原始行是否以 data: 开头: %v", strings.HasPrefix(line, "data:"))
-
 			// 解析 SSE 数据
-			if strings.HasPrefix(line, "data:") {
-				data := strings.TrimPrefix(line, "data:")
-				data = strings.TrimSpace(data) // 去除可能的空白字符
-				logger.Infof("收到原始响应数据: %s", data)
-
-				// 尝试解析为 JSON
-				var response QwenResponse
-				if err := json.Unmarshal([]byte(data), &response); err != nil {
-					logger.Errorf("解析响应失败: %v, data=%s", err, data)
-					continue
-				}
-				logger.Infof("解析后的响应: finish_reason=%s, text=%s", response.Output.FinishReason, response.Output.Text)
-
-				// 发送文本片段
-				if response.Output.Text != "" {
-					lastText = response.Output.Text
-					select {
-					case responseChan <- response.Output.Text:
-						logger.Infof("成功发送响应: %s", response.Output.Text)
-					case <-ctx.Done():
-						return
-					}
-				}
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
 
-				// 检查是否完成
-				if response.Output.FinishReason == "stop" {
-					logger.Infof("收到完成信号，准备发送最后响应")
-					// 发送最后的完整响应
-					select {
-					case responseChan <- response.Output.Text:
-						logger.Infof("发送最后的完整响应: %s", response.Output.Text)
-					case <-ctx.Done():
-						logger.Infof("上下文已取消，退出")
-						return
-					}
-					logger.Infof("响应完成, 原因: %s, 完整响应: %s", response.Output.FinishReason, response.Output.Text)
-					// 等待一小段时间确保最后一个片段被处理
-					time.Sleep(100 * time.Millisecond)
+			var response QwenResponse
+			if err := json.Unmarshal([]byte(data), &response); err != nil {
+				logger.Errorf("解析响应失败: %v, data=%s", err, data)
+				continue
+			}
+
+			chunk := StreamChunk{Delta: response.Output.Text}
+			finished := response.Output.FinishReason == "stop" || response.Output.FinishReason == "length"
+			if finished {
+				chunk.Usage = &response.Usage
+				recordUsage(ctx, usage.CallTypeChat, c.config.ModelName, response.Usage)
+			}
+
+			// 发送文本片段
+			if chunk.Delta != "" || chunk.Usage != nil {
+				select {
+				case responseChan <- chunk:
+				case <-ctx.Done():
 					return
 				}
 			}
+
+			// 检查是否完成
+			if finished {
+				logger.Infof("流式响应完成, request_id=%s, tokens=%d", response.RequestID, response.Usage.TotalTokens)
+				return
+			}
 		}
 	}()
 
 	return responseChan, errorChan
 }
-
-// 修改处理流式响应的部分
-func (c *QwenClient) handleStreamResponse(ctx context.Context, response *QwenResponse, responseChan chan<- string, errorChan chan<- error) {
-	// 处理函数调用
-	if len(response.Output.ToolCalls) > 0 {
-		msg, err := c.handleFunctionCall(ctx, response)
-		if err != nil {
-			errorChan <- err
-			return
-		}
-		if msg != nil {
-			responseChan <- msg.Content
-		}
-		return
-	}
-
-	// 发送文本片段
-	if response.Output.Text != "" {
-		responseChan <- response.Output.Text
-	}
-
-	// 检查是否结束
-	if response.Output.FinishReason == "stop" {
-		close(responseChan)
-	}
-}
@@ -0,0 +1,222 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"server/framework/config"
+	"server/framework/logger"
+	"server/service/rag_svr/usage"
+)
+
+const (
+	// 重排接口路径，拼接在 base_url 之后
+	dashScopeRerankAPI = "/services/rerank/text-rerank/text-rerank"
+	jinaRerankAPI      = "/rerank"
+
+	defaultRerankCandidateFactor = 3
+)
+
+// Reranker 交叉编码器重排模型，对查询和每个候选文本成对打分
+type Reranker interface {
+	// Rerank 返回与 documents 一一对应的相关度分数，分数越高越相关
+	Rerank(ctx context.Context, query string, documents []string) ([]float32, error)
+}
+
+// rerankResult 重排接口返回的一条结果，index 为候选文本在请求中的下标
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float32 `json:"relevance_score"`
+}
+
+// rerankResponse 同时兼容 DashScope（结果在 output 中）和 Jina/Cohere（结果在顶层）的响应
+type rerankResponse struct {
+	Output struct {
+		Results []rerankResult `json:"results"`
+	} `json:"output"`
+	Results []rerankResult `json:"results"`
+	Usage   struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// httpReranker 通过 HTTP 调用的重排模型，支持 DashScope 和 Jina/Cohere 兼容的 /rerank 接口
+type httpReranker struct {
+	provider  string
+	modelName string
+	url       string
+	apiKey    string
+}
+
+var (
+	rerankerInstance Reranker
+	rerankerOnce     sync.Once
+)
+
+// GetReranker 获取按全局配置创建的重排模型，未配置时返回 nil
+func GetReranker() Reranker {
+	rerankerOnce.Do(func() {
+		reranker, err := newHTTPReranker()
+		if err != nil {
+			logger.Errorf("重排模型配置无效，检索结果不重排: %v", err)
+			return
+		}
+		if reranker != nil {
+			logger.Infof("重排模型: %s/%s", reranker.provider, reranker.modelName)
+			rerankerInstance = reranker
+		}
+	})
+	return rerankerInstance
+}
+
+func newHTTPReranker() (*httpReranker, error) {
+	if config.GlobalConfig == nil || config.GlobalConfig.AI.Reranker.Provider == "" {
+		return nil, nil
+	}
+	cfg := config.GlobalConfig.AI.Reranker
+	if cfg.ModelName == "" {
+		return nil, fmt.Errorf("重排模型名称未配置")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("重排模型 Base URL 未配置")
+	}
+
+	r := &httpReranker{
+		provider:  cfg.Provider,
+		modelName: cfg.ModelName,
+		apiKey:    cfg.APIKey,
+	}
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	switch cfg.Provider {
+	case "dashscope":
+		r.url = baseURL + dashScopeRerankAPI
+	case "jina":
+		r.url = baseURL + jinaRerankAPI
+	default:
+		return nil, fmt.Errorf("不支持的重排模型 provider: %s", cfg.Provider)
+	}
+	return r, nil
+}
+
+func (r *httpReranker) Rerank(ctx context.Context, query string, documents []string) ([]float32, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	var reqBody map[string]interface{}
+	if r.provider == "dashscope" {
+		reqBody = map[string]interface{}{
+			"model": r.modelName,
+			"input": map[string]interface{}{
+				"query":     query,
+				"documents": documents,
+			},
+			"parameters": map[string]interface{}{
+				"top_n":            len(documents),
+				"return_documents": false,
+			},
+		}
+	} else {
+		reqBody = map[string]interface{}{
+			"model":            r.modelName,
+			"query":            query,
+			"documents":        documents,
+			"top_n":            len(documents),
+			"return_documents": false,
+		}
+	}
+
+	var result rerankResponse
+	if err := GetModelGateway().PostJSON(ctx, EndpointRerank, r.url, r.apiKey, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	recordUsage(ctx, usage.CallTypeRerank, r.modelName, QwenUsage{
+		InputTokens: result.Usage.TotalTokens,
+		TotalTokens: result.Usage.TotalTokens,
+	})
+
+	results := result.Results
+	if len(results) == 0 {
+		results = result.Output.Results
+	}
+	// 接口按分数排序返回，按 index 放回原位，保证分数与候选一一对应
+	scores := make([]float32, len(documents))
+	scored := make([]bool, len(documents))
+	for _, item := range results {
+		if item.Index < 0 || item.Index >= len(documents) || scored[item.Index] {
+			return nil, &ModelError{
+				Endpoint: EndpointRerank,
+				Kind:     ErrModelResponse,
+				Err:      fmt.Errorf("重排结果下标无效: %d", item.Index),
+			}
+		}
+		scores[item.Index] = item.RelevanceScore
+		scored[item.Index] = true
+	}
+	if len(results) != len(documents) {
+		return nil, &ModelError{
+			Endpoint: EndpointRerank,
+			Kind:     ErrModelResponse,
+			Err:      fmt.Errorf("重排结果数量不匹配: 期望%d, 实际%d", len(documents), len(results)),
+		}
+	}
+	return scores, nil
+}
+
+// rerankCandidateFactor 送入重排的候选数为 top_k 的倍数
+func rerankCandidateFactor() int {
+	if config.GlobalConfig != nil && config.GlobalConfig.AI.Reranker.CandidateFactor > 0 {
+		return config.GlobalConfig.AI.Reranker.CandidateFactor
+	}
+	return defaultRerankCandidateFactor
+}
+
+// rerankMinScore 请求未指定分数下限时使用配置的值
+func rerankMinScore(requested float64) float64 {
+	if requested > 0 {
+		return requested
+	}
+	if config.GlobalConfig != nil {
+		return config.GlobalConfig.AI.Reranker.MinScore
+	}
+	return 0
+}
+
+// RerankChunks 用重排模型为检索结果打分，按重排分数从高到低返回前 topK 个不低于 minScore 的块，
+// 分数写入每个块的 RerankScore；调用前须填好块的 Content
+func RerankChunks(ctx context.Context, reranker Reranker, query string, results []*RetrievedChunk, topK int, minScore float64) ([]*RetrievedChunk, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+	documents := make([]string, len(results))
+	for i, result := range results {
+		documents[i] = result.Chunk.Content
+	}
+	scores, err := reranker.Rerank(ctx, query, documents)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) != len(results) {
+		return nil, fmt.Errorf("重排分数数量不匹配: 期望%d, 实际%d", len(results), len(scores))
+	}
+
+	reranked := make([]*RetrievedChunk, 0, len(results))
+	for i, result := range results {
+		result.RerankScore = scores[i]
+		if float64(scores[i]) >= minScore {
+			reranked = append(reranked, result)
+		}
+	}
+	// 分数相同时保持 RRF 顺序
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].RerankScore > reranked[j].RerankScore
+	})
+	if topK > 0 && len(reranked) > topK {
+		reranked = reranked[:topK]
+	}
+	return reranked, nil
+}
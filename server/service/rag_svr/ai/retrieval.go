@@ -0,0 +1,229 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"server/framework/config"
+	"server/framework/milvus"
+	"server/framework/mysql"
+	"server/service/rag_svr/authz"
+)
+
+// 混合检索默认配置
+const (
+	defaultRetrievalTopK   = 5
+	defaultVectorWeight    = 1.0
+	defaultKeywordWeight   = 1.0
+	defaultRRFK            = 60
+	defaultCandidateFactor = 4
+	minRetrievalCandidates = 20 // top_k 较小时每路检索至少取的候选数
+)
+
+// RetrievalOptions 混合检索参数
+type RetrievalOptions struct {
+	TopK int
+	// 检索范围：只检索该用户能访问的文档，指定了知识库或文档时只检索其中的块
+	UserID        uint64
+	Scope         *authz.Scope // 用户的授权范围，为空时按 UserID 加载
+	CollectionIDs []uint64
+	DocIDs        []uint64
+	// 两路检索在 RRF 融合中的权重，都为 0 时使用配置的默认值，只有一个为 0 时不执行该路检索
+	VectorWeight  float64
+	KeywordWeight float64
+}
+
+// RetrievedChunk 检索到的块
+type RetrievedChunk struct {
+	Chunk        mysql.DocumentChunk
+	Score        float64 // RRF 融合分数
+	VectorRank   int     // 在向量检索结果中的名次，从 1 开始，未命中为 0
+	VectorScore  float32 // 向量相似度
+	KeywordRank  int     // 在关键词检索结果中的名次，从 1 开始，未命中为 0
+	KeywordScore float32 // FULLTEXT 相关度
+	RerankScore  float32 // 重排模型的相关度，未重排为 0
+}
+
+// rankedHit 单路检索的一条结果，按名次排列
+type rankedHit struct {
+	ChunkID uint64
+	Score   float32
+}
+
+type retrievalConfig struct {
+	vectorWeight    float64
+	keywordWeight   float64
+	rrfK            int
+	candidateFactor int
+}
+
+func getRetrievalConfig() retrievalConfig {
+	cfg := retrievalConfig{
+		vectorWeight:    defaultVectorWeight,
+		keywordWeight:   defaultKeywordWeight,
+		rrfK:            defaultRRFK,
+		candidateFactor: defaultCandidateFactor,
+	}
+	if config.GlobalConfig == nil {
+		return cfg
+	}
+	retrieval := config.GlobalConfig.Retrieval
+	if retrieval.VectorWeight > 0 || retrieval.KeywordWeight > 0 {
+		cfg.vectorWeight = retrieval.VectorWeight
+		cfg.keywordWeight = retrieval.KeywordWeight
+	}
+	if retrieval.RRFK > 0 {
+		cfg.rrfK = retrieval.RRFK
+	}
+	if retrieval.CandidateFactor > 0 {
+		cfg.candidateFactor = retrieval.CandidateFactor
+	}
+	return cfg
+}
+
+// HybridSearch 向量检索与关键词检索各取一批候选，按加权 RRF 融合：score = Σ weight / (k + rank)
+// 关键词检索能命中产品编号、名称等向量检索容易漏掉的精确词；只返回检索范围内属于文档当前版本的块
+func HybridSearch(ctx context.Context, query string, opts RetrievalOptions) ([]*RetrievedChunk, error) {
+	cfg := getRetrievalConfig()
+	vectorWeight, keywordWeight := opts.VectorWeight, opts.KeywordWeight
+	if opts.UserID == 0 {
+		return nil, fmt.Errorf("检索必须指定用户")
+	}
+	if vectorWeight < 0 || keywordWeight < 0 {
+		return nil, fmt.Errorf("检索权重不能为负数")
+	}
+	if vectorWeight == 0 && keywordWeight == 0 {
+		vectorWeight, keywordWeight = cfg.vectorWeight, cfg.keywordWeight
+	}
+	if opts.Scope == nil {
+		scope, err := authz.LoadScope(ctx, opts.UserID)
+		if err != nil {
+			return nil, err
+		}
+		opts.Scope = scope
+	}
+	topK := opts.TopK
+	if topK <= 0 {
+		topK = defaultRetrievalTopK
+	}
+	candidates := max(topK*cfg.candidateFactor, minRetrievalCandidates)
+
+	var vectorHits, keywordHits []rankedHit
+	if vectorWeight > 0 {
+		var err error
+		if vectorHits, err = vectorSearch(ctx, query, candidates, opts); err != nil {
+			return nil, err
+		}
+	}
+	if keywordWeight > 0 {
+		var err error
+		if keywordHits, err = keywordSearch(ctx, query, candidates, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	fused := make(map[uint64]*RetrievedChunk, len(vectorHits)+len(keywordHits))
+	results := make([]*RetrievedChunk, 0, len(vectorHits)+len(keywordHits))
+	get := func(chunkID uint64) *RetrievedChunk {
+		if result, ok := fused[chunkID]; ok {
+			return result
+		}
+		result := &RetrievedChunk{Chunk: mysql.DocumentChunk{ChunkID: chunkID}}
+		fused[chunkID] = result
+		results = append(results, result)
+		return result
+	}
+	for i, hit := range vectorHits {
+		result := get(hit.ChunkID)
+		result.VectorRank, result.VectorScore = i+1, hit.Score
+		result.Score += vectorWeight / float64(cfg.rrfK+i+1)
+	}
+	for i, hit := range keywordHits {
+		result := get(hit.ChunkID)
+		result.KeywordRank, result.KeywordScore = i+1, hit.Score
+		result.Score += keywordWeight / float64(cfg.rrfK+i+1)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint64, len(results))
+	for i, result := range results {
+		ids[i] = result.Chunk.ChunkID
+	}
+	var chunks []mysql.DocumentChunk
+	if err := scopeChunks(activeChunks(mysql.GetDB().WithContext(ctx)), opts.Scope, opts.CollectionIDs, opts.DocIDs).
+		Where("document_chunk.chunk_id IN ?", ids).Find(&chunks).Error; err != nil {
+		return nil, fmt.Errorf("获取文档块失败: %v", err)
+	}
+	for _, chunk := range chunks {
+		fused[chunk.ChunkID].Chunk = chunk
+	}
+
+	// 检索期间切换了版本或移出检索范围的块不再返回
+	retrieved := results[:0]
+	for _, result := range results {
+		if result.Chunk.DocID != 0 {
+			retrieved = append(retrieved, result)
+		}
+	}
+	return retrieved, nil
+}
+
+// vectorSearch 在 Milvus 中按授权范围、知识库和文档过滤后检索相似的块，去掉不属于文档当前版本的块后按相似度排列
+func vectorSearch(ctx context.Context, query string, limit int, opts RetrievalOptions) ([]rankedHit, error) {
+	queryEmbedding, err := GetEmbedding(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("生成查询向量失败: %v", err)
+	}
+	expr := documentFilterExpr(opts.Scope, opts.CollectionIDs, opts.DocIDs)
+	ids, scores, _, err := milvus.SearchVector(ctx, milvus.DocumentCollectionName, queryEmbedding, limit, expr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("向量搜索失败: %v", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var activeIDs []uint64
+	if err := scopeChunks(mysql.GetDB().WithContext(ctx).Table("document_chunk").Joins(activeChunkJoin), opts.Scope, opts.CollectionIDs, opts.DocIDs).
+		Where("document_chunk.chunk_id IN ?", ids).Pluck("document_chunk.chunk_id", &activeIDs).Error; err != nil {
+		return nil, fmt.Errorf("获取文档块失败: %v", err)
+	}
+	active := make(map[uint64]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+
+	hits := make([]rankedHit, 0, len(activeIDs))
+	for i, id := range ids {
+		if active[uint64(id)] {
+			hits = append(hits, rankedHit{ChunkID: uint64(id), Score: scores[i]})
+		}
+	}
+	return hits, nil
+}
+
+// keywordSearch 用块内容上的 FULLTEXT 索引（ngram 分词）在检索范围内检索，按相关度排列
+func keywordSearch(ctx context.Context, query string, limit int, opts RetrievalOptions) ([]rankedHit, error) {
+	var hits []rankedHit
+	db := mysql.GetDB().WithContext(ctx).Table("document_chunk").
+		Select("document_chunk.chunk_id, MATCH(document_chunk.content) AGAINST(? IN NATURAL LANGUAGE MODE) AS score", query).
+		Joins(activeChunkJoin)
+	err := scopeChunks(db, opts.Scope, opts.CollectionIDs, opts.DocIDs).
+		Where("MATCH(document_chunk.content) AGAINST(? IN NATURAL LANGUAGE MODE)", query).
+		Order("score DESC").
+		Limit(limit).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, fmt.Errorf("关键词检索失败: %v", err)
+	}
+	return hits, nil
+}
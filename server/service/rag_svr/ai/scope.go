@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+
+	"server/framework/milvus"
+	"server/framework/mysql"
+	"server/service/rag_svr/authz"
+	"server/service/rag_svr/kitex_gen/rag_svr"
+
+	"gorm.io/gorm"
+)
+
+// 文档块向量集合中的标量字段，user_id 为分区键，按用户过滤时只搜索对应分区
+const (
+	vectorFieldUserID       = "user_id"
+	vectorFieldDocID        = "doc_id"
+	vectorFieldCollectionID = "collection_id"
+)
+
+// documentVectorFields 文档块向量随向量写入的标量字段
+func documentVectorFields(doc *mysql.Document) map[string]interface{} {
+	return map[string]interface{}{
+		vectorFieldUserID:       int64(doc.UserID),
+		vectorFieldDocID:        int64(doc.DocID),
+		vectorFieldCollectionID: int64(doc.CollectionID),
+	}
+}
+
+// documentFilterExpr 文档块向量的过滤表达式：只检索用户能访问的块，指定了知识库或文档时只检索其中的块
+func documentFilterExpr(scope *authz.Scope, collectionIDs, docIDs []uint64) string {
+	conditions := []string{scope.VectorExpr(vectorFieldUserID, vectorFieldCollectionID, vectorFieldDocID)}
+	if len(collectionIDs) > 0 {
+		conditions = append(conditions, milvus.InExpr(vectorFieldCollectionID, toInt64s(collectionIDs)))
+	}
+	if len(docIDs) > 0 {
+		conditions = append(conditions, milvus.InExpr(vectorFieldDocID, toInt64s(docIDs)))
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// scopeChunks 在连接了 document 表的块查询上限定用户能访问的文档、知识库和文档，与 documentFilterExpr 的条件一致
+func scopeChunks(db *gorm.DB, scope *authz.Scope, collectionIDs, docIDs []uint64) *gorm.DB {
+	db = scope.FilterDocuments(db)
+	if len(collectionIDs) > 0 {
+		db = db.Where("document.collection_id IN ?", collectionIDs)
+	}
+	if len(docIDs) > 0 {
+		db = db.Where("document.doc_id IN ?", docIDs)
+	}
+	return db
+}
+
+// AuthzFailure 将权限检查的错误转换为响应的错误码和提示，无权访问和角色不足都返回 ERR_PERMISSION_DENIED
+func AuthzFailure(err error, deniedMsg string) (uint32, string) {
+	if errors.Is(err, authz.ErrForbidden) || errors.Is(err, authz.ErrNotFound) || errors.Is(err, ErrCollectionNotFound) {
+		return uint32(rag_svr.ErrCode_ERR_PERMISSION_DENIED), deniedMsg
+	}
+	return uint32(rag_svr.ErrCode_ERR_FAILED), err.Error()
+}
+
+func toInt64s(values []uint64) []int64 {
+	result := make([]int64, len(values))
+	for i, value := range values {
+		result[i] = int64(value)
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/framework/config"
+	"server/framework/logger"
+	"server/framework/mysql"
+	"server/framework/redis"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// system_state 中记录已折叠进摘要的最后一条对话记录的 ID 和创建时间
+	SummarizedRecordKey     = "summarized_record_id"
+	SummarizedRecordTimeKey = "summarized_record_time"
+
+	defaultSummaryTriggerTurns = 6  // 未摘要的对话超出保留轮数达到该值时触发
+	defaultSummaryKeepTurns    = 4  // 保留原文的最近轮数
+	summaryBatchTurns          = 20 // 单次调用模型折叠的最大轮数
+
+	summaryLockPrefix = "session:summary:lock:"
+	summaryLockTTL    = 2 * time.Minute
+)
+
+// SummaryWatermark 已折叠进摘要的最后一条对话记录，对话记录按 (created_at, id) 排序
+// 记录ID由各实例按号段分配，不随时间递增，不能单独用 ID 判断先后
+type SummaryWatermark struct {
+	CreatedAt time.Time
+	ID        uint64
+}
+
+// LoadSummaryWatermark 获取会话的摘要水位，没有摘要时返回零值
+// 只记录了 ID 的旧会话按该记录的创建时间补全
+func LoadSummaryWatermark(ctx context.Context, session *mysql.ChatSession) SummaryWatermark {
+	state, err := session.GetSystemState()
+	if err != nil {
+		return SummaryWatermark{}
+	}
+	id, _ := strconv.ParseUint(state[SummarizedRecordKey], 10, 64)
+	if id == 0 {
+		return SummaryWatermark{}
+	}
+	if createdAt, err := time.Parse(time.RFC3339Nano, state[SummarizedRecordTimeKey]); err == nil {
+		return SummaryWatermark{CreatedAt: createdAt, ID: id}
+	}
+	var record mysql.ChatRecord
+	if err := mysql.GetDB().WithContext(ctx).Table("chat_record").Select("id", "created_at").
+		Where("id = ?", id).Take(&record).Error; err != nil {
+		logger.Errorf("获取摘要水位的对话记录失败: session_id=%d, record_id=%d, err=%v", session.ID, id, err)
+		return SummaryWatermark{}
+	}
+	return SummaryWatermark{CreatedAt: record.CreatedAt, ID: id}
+}
+
+// After 限定为水位之后的对话记录
+func (w SummaryWatermark) After(db *gorm.DB) *gorm.DB {
+	if w.ID == 0 {
+		return db
+	}
+	return db.Where("(created_at > ? OR (created_at = ? AND id > ?))", w.CreatedAt, w.CreatedAt, w.ID)
+}
+
+// SummarizeSession 未摘要的对话达到阈值时，把较早的对话折叠进会话摘要，最近的对话保留原文
+func SummarizeSession(ctx context.Context, sessionID uint64) error {
+	triggerTurns, keepTurns := summaryConfig()
+
+	// 同一会话同时只允许一个摘要任务
+	lockKey := fmt.Sprintf("%s%d", summaryLockPrefix, sessionID)
+	locked, err := redis.SetNX(ctx, lockKey, 1, summaryLockTTL)
+	if err != nil {
+		return fmt.Errorf("获取摘要锁失败: %v", err)
+	}
+	if !locked {
+		return nil
+	}
+	defer redis.Del(ctx, lockKey)
+
+	var session mysql.ChatSession
+	if err := mysql.GetDB().WithContext(ctx).Table("chat_session").First(&session, sessionID).Error; err != nil {
+		return fmt.Errorf("获取会话信息失败: %v", err)
+	}
+	watermark := LoadSummaryWatermark(ctx, &session)
+
+	var records []mysql.ChatRecord
+	if err := watermark.After(mysql.GetDB().WithContext(ctx).Table("chat_record")).
+		Where("session_id = ?", sessionID).
+		Order("created_at ASC, id ASC").
+		Find(&records).Error; err != nil {
+		return fmt.Errorf("获取对话记录失败: %v", err)
+	}
+	if len(records) < triggerTurns+keepTurns {
+		return nil
+	}
+
+	// 分批折叠，避免一次输入过长
+	summary := session.Summary
+	fold := records[:len(records)-keepTurns]
+	for start := 0; start < len(fold); start += summaryBatchTurns {
+		end := min(start+summaryBatchTurns, len(fold))
+		turns := make([]PromptTurn, 0, end-start)
+		for _, record := range fold[start:end] {
+			turns = append(turns, PromptTurn{User: record.Message, Assistant: record.Response})
+		}
+		if summary, err = UpdateSessionSummary(ctx, summary, turns); err != nil {
+			return fmt.Errorf("生成会话摘要失败: %v", err)
+		}
+		watermark = SummaryWatermark{CreatedAt: fold[end-1].CreatedAt, ID: fold[end-1].ID}
+	}
+
+	state, err := session.GetSystemState()
+	if err != nil {
+		state = make(map[string]string)
+	}
+	state[SummarizedRecordKey] = strconv.FormatUint(watermark.ID, 10)
+	state[SummarizedRecordTimeKey] = watermark.CreatedAt.Format(time.RFC3339Nano)
+	if err := session.SetSystemState(state); err != nil {
+		return err
+	}
+	if err := mysql.GetDB().WithContext(ctx).Table("chat_session").
+		Where("id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"summary":      summary,
+			"system_state": session.SystemState,
+		}).Error; err != nil {
+		return fmt.Errorf("保存会话摘要失败: %v", err)
+	}
+
+	logger.Infof("会话摘要已更新: session_id=%d, 折叠%d轮, summarized_record_id=%d", sessionID, len(fold), watermark.ID)
+	return nil
+}
+
+func summaryConfig() (triggerTurns, keepTurns int) {
+	triggerTurns, keepTurns = defaultSummaryTriggerTurns, defaultSummaryKeepTurns
+	if config.GlobalConfig == nil {
+		return
+	}
+	cfg := config.GlobalConfig.AI.Summary
+	if cfg.TriggerTurns > 0 {
+		triggerTurns = cfg.TriggerTurns
+	}
+	if cfg.KeepTurns > 0 {
+		keepTurns = cfg.KeepTurns
+	}
+	return
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"server/framework/logger"
+	"server/framework/mysql"
+	"server/service/rag_svr/usage"
+)
+
+const sessionTitlePrompt = `你是一个会话标题生成助手。请根据用户和助手的第一轮对话，为会话生成一个简短的标题。
+要求：
+1. 概括对话主题，不超过15个字
+2. 不要使用标点符号、引号和表情
+3. 只输出标题本身`
+
+// MaxSessionTitleLength 会话标题的最大字符数
+const MaxSessionTitleLength = 50
+
+// GenerateSessionTitle 会话还没有标题时，根据第一轮对话生成标题
+// 用户已手动设置标题时不会覆盖
+func GenerateSessionTitle(ctx context.Context, sessionID uint64, message, answer string) error {
+	var session mysql.ChatSession
+	if err := mysql.GetDB().WithContext(ctx).Table("chat_session").First(&session, sessionID).Error; err != nil {
+		return fmt.Errorf("获取会话信息失败: %v", err)
+	}
+	if session.Title != "" {
+		return nil
+	}
+
+	text := "用户：" + truncateRunes(message, maxSummaryTurnChars) + "\n助手：" + truncateRunes(answer, maxSummaryTurnChars)
+	title, err := completeText(usage.WithCallType(ctx, usage.CallTypeTitle), sessionTitlePrompt, text)
+	if err != nil {
+		return fmt.Errorf("生成会话标题失败: %v", err)
+	}
+	title = CleanSessionTitle(title)
+	if title == "" {
+		return fmt.Errorf("未获取到会话标题")
+	}
+
+	// 生成期间用户可能已手动命名，只更新仍为空的标题
+	result := mysql.GetDB().WithContext(ctx).Table("chat_session").
+		Where("id = ? AND (title = '' OR title IS NULL)", sessionID).
+		Update("title", title)
+	if result.Error != nil {
+		return fmt.Errorf("保存会话标题失败: %v", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logger.Infof("会话标题已生成: session_id=%d, title=%s", sessionID, title)
+	}
+	return nil
+}
+
+// CleanSessionTitle 去掉标题首尾的空白、引号和句末标点，并限制长度
+func CleanSessionTitle(title string) string {
+	title = strings.TrimSpace(title)
+	// 模型偶尔输出多行，只取第一行
+	if i := strings.IndexAny(title, "\r\n"); i >= 0 {
+		title = title[:i]
+	}
+	title = strings.TrimPrefix(title, "标题：")
+	title = strings.Trim(title, " \t\"'`“”‘’「」《》【】#*")
+	title = strings.TrimRight(title, "。.！!？?，,；;：:")
+	runes := []rune(title)
+	if len(runes) > MaxSessionTitleLength {
+		title = string(runes[:MaxSessionTitleLength])
+	}
+	return strings.TrimSpace(title)
+}
@@ -0,0 +1,315 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"server/framework/logger"
+)
+
+// 结构化输出校验失败后重新请求的最大次数
+const maxStructuredRepairs = 2
+
+// ResponseFormat 模型输出格式，DashScope 和 OpenAI 兼容接口格式一致
+type ResponseFormat struct {
+	Type string `json:"type"` // text 或 json_object
+}
+
+type responseFormatKey struct{}
+
+// withJSONMode 在 ctx 中要求模型输出 JSON 对象，不支持的提供方忽略该设置
+func withJSONMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseFormatKey{}, &ResponseFormat{Type: "json_object"})
+}
+
+// responseFormatFrom 获取 ctx 中指定的输出格式，未指定时为 nil
+func responseFormatFrom(ctx context.Context) *ResponseFormat {
+	format, _ := ctx.Value(responseFormatKey{}).(*ResponseFormat)
+	return format
+}
+
+// CompleteJSON 单轮对话并将输出解析到 out
+// 开启提供方的 JSON 模式，按 out 的类型生成 JSON Schema 写入提示词并校验输出，
+// 输出夹杂说明文字时先尝试提取修复，仍不符合时带上错误原因重新请求
+func CompleteJSON(ctx context.Context, systemPrompt, text string, out interface{}) error {
+	provider, err := GetLLMProvider()
+	if err != nil {
+		return err
+	}
+
+	schema := JSONSchemaOf(out)
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("生成 JSON Schema 失败: %v", err)
+	}
+	messages := []QwenMessage{
+		{Role: "system", Content: systemPrompt + "\n\n请只输出一个符合以下 JSON Schema 的 JSON 对象，不要输出其他内容：\n" + string(schemaJSON)},
+		{Role: "user", Content: text},
+	}
+
+	ctx = withJSONMode(ctx)
+	for attempt := 0; ; attempt++ {
+		result, err := provider.Chat(ctx, messages, nil)
+		if err != nil {
+			return err
+		}
+
+		err = decodeStructured(result.Content, schema, out)
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxStructuredRepairs {
+			return fmt.Errorf("模型输出不符合格式要求: %v", err)
+		}
+
+		logger.Warnf("模型输出不符合格式要求，重新请求(第%d次): %v", attempt+1, err)
+		messages = append(messages,
+			QwenMessage{Role: "assistant", Content: result.Content},
+			QwenMessage{Role: "user", Content: fmt.Sprintf("上面的输出不符合要求：%v。请重新输出，只包含符合 JSON Schema 的 JSON 对象。", err)},
+		)
+	}
+}
+
+// decodeStructured 提取、校验并解析模型输出
+func decodeStructured(content string, schema map[string]interface{}, out interface{}) error {
+	raw, err := extractJSON(content)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return fmt.Errorf("JSON 格式错误: %v", err)
+	}
+	if err := validateSchema(schema, value, "$"); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(raw), out); err != nil {
+		return fmt.Errorf("解析 JSON 失败: %v", err)
+	}
+	return nil
+}
+
+// extractJSON 从模型输出中提取第一个完整的 JSON 对象，兼容 Markdown 代码块和前后说明文字
+func extractJSON(content string) (string, error) {
+	start := strings.Index(content, "{")
+	if start < 0 {
+		return "", fmt.Errorf("输出中没有 JSON 对象")
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(content); i++ {
+		c := content[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return content[start : i+1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("JSON 对象不完整")
+}
+
+// JSONSchemaOf 根据 Go 类型生成 JSON Schema
+// 字段名取自 json 标签，没有 omitempty 的字段为必填；
+// jsonschema 标签可以补充约束，如 `jsonschema:"enum=a|b,minimum=0,maximum=1"`
+func JSONSchemaOf(v interface{}) map[string]interface{} {
+	return schemaOfType(reflect.TypeOf(v))
+}
+
+func schemaOfType(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		required := make([]string, 0)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, omitempty := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			property := schemaOfType(field.Type)
+			applySchemaTag(property, field.Tag.Get("jsonschema"))
+			properties[name] = property
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaOfType(t.Elem()),
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaOfType(t.Elem()),
+		}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// applySchemaTag 解析 jsonschema 标签，支持 enum、minimum、maximum、description
+func applySchemaTag(schema map[string]interface{}, tag string) {
+	if tag == "" {
+		return
+	}
+	for _, item := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "enum":
+			schema["enum"] = strings.Split(value, "|")
+		case "minimum", "maximum":
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				schema[key] = number
+			}
+		case "description":
+			schema["description"] = value
+		}
+	}
+}
+
+// validateSchema 按 JSONSchemaOf 生成的 Schema 校验解析后的 JSON 值
+func validateSchema(schema map[string]interface{}, value interface{}, path string) error {
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s 应为对象", path)
+		}
+		if required, ok := schema["required"].([]string); ok {
+			for _, name := range required {
+				if _, ok := object[name]; !ok {
+					return fmt.Errorf("缺少字段 %s.%s", path, name)
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for name, fieldValue := range object {
+			fieldSchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				fieldSchema = additional
+			}
+			if fieldSchema == nil {
+				continue
+			}
+			if err := validateSchema(fieldSchema, fieldValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s 应为数组", path)
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range array {
+			if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s 应为字符串", path)
+		}
+		if enum, ok := schema["enum"].([]string); ok && !containsString(enum, text) {
+			return fmt.Errorf("%s 的值 %q 不在可选范围 %v 内", path, text, enum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s 应为布尔值", path)
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s 应为数字", path)
+		}
+		if schema["type"] == "integer" && number != float64(int64(number)) {
+			return fmt.Errorf("%s 应为整数", path)
+		}
+		if minimum, ok := schema["minimum"].(float64); ok && number < minimum {
+			return fmt.Errorf("%s 不能小于 %v", path, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && number > maximum {
+			return fmt.Errorf("%s 不能大于 %v", path, maximum)
+		}
+	}
+	return nil
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
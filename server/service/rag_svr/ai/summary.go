@@ -1,114 +1,64 @@
 package ai
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"time"
-)
+	"strings"
 
-// ChatResponse 对话响应
-type ChatResponse struct {
-	Output struct {
-		Text string `json:"text"`
-	} `json:"output"`
-}
+	"server/service/rag_svr/usage"
+)
 
 // GetSummary 获取文本摘要
-func GetSummary(text string) (string, error) {
-	// 从环境变量获取配置
-	apiKey := os.Getenv("DASHSCOPE_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("环境变量 DASHSCOPE_API_KEY 未设置")
+func GetSummary(ctx context.Context, text string) (string, error) {
+	summary, err := completeText(usage.WithCallType(ctx, usage.CallTypeSummary), "你是一个专业的文本摘要助手，请对输入的文本进行简洁的摘要。", text)
+	if err != nil {
+		return "", err
 	}
-	baseURL := os.Getenv("DASHSCOPE_BASE_URL")
-	if baseURL == "" {
-		return "", fmt.Errorf("环境变量 DASHSCOPE_BASE_URL 未设置")
+	if summary == "" {
+		return "", fmt.Errorf("未获取到摘要")
 	}
+	return summary, nil
+}
 
-	// 获取并发令牌
-	requestSemaphore <- struct{}{}
-	defer func() { <-requestSemaphore }()
-
-	var lastErr error
-	for i := 0; i < maxRetries; i++ {
-		if i > 0 {
-			time.Sleep(retryInterval)
-		}
-
-		// 构建请求体
-		reqBody := map[string]interface{}{
-			"input": map[string]interface{}{
-				"messages": []map[string]string{
-					{
-						"role":    "system",
-						"content": "你是一个专业的文本摘要助手，请对输入的文本进行简洁的摘要。",
-					},
-					{
-						"role":    "user",
-						"content": text,
-					},
-				},
-			},
-			"parameters": map[string]interface{}{
-				"temperature": 0.7,
-				"top_p":       0.8,
-			},
-		}
-		jsonData, err := json.Marshal(reqBody)
-		if err != nil {
-			return "", fmt.Errorf("序列化请求失败: %v", err)
-		}
-
-		// 创建请求
-		req, err := http.NewRequest("POST", baseURL+"/services/aigc/text-generation/generation", bytes.NewBuffer(jsonData))
-		if err != nil {
-			return "", fmt.Errorf("创建请求失败: %v", err)
-		}
-
-		// 设置请求头
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-
-		// 发送请求
-		client := &http.Client{Timeout: requestTimeout}
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("发送请求失败: %v", err)
-			continue
-		}
-
-		// 读取响应
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("读取响应失败: %v", err)
-			continue
-		}
-
-		// 检查响应状态码
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-			continue
-		}
+const sessionSummaryPrompt = `你是一个对话摘要助手。请根据已有摘要和新增的对话，输出更新后的完整会话摘要。
+要求：
+1. 保留用户的身份信息、偏好、待办事项和已经达成的结论
+2. 删除寒暄和重复内容
+3. 不超过300字，只输出摘要正文`
 
-		// 解析响应
-		var chatResp ChatResponse
-		if err := json.Unmarshal(body, &chatResp); err != nil {
-			lastErr = fmt.Errorf("解析响应失败: %v", err)
-			continue
-		}
+// maxSummaryTurnChars 折叠进摘要时单条消息保留的最大字符数
+const maxSummaryTurnChars = 500
 
-		if chatResp.Output.Text == "" {
-			lastErr = fmt.Errorf("未获取到摘要")
-			continue
-		}
+// UpdateSessionSummary 将新增的对话折叠进已有的会话摘要
+func UpdateSessionSummary(ctx context.Context, previous string, turns []PromptTurn) (string, error) {
+	var text strings.Builder
+	text.WriteString("已有摘要：\n")
+	if previous == "" {
+		text.WriteString("无\n")
+	} else {
+		text.WriteString(previous + "\n")
+	}
+	text.WriteString("\n新增对话：\n")
+	for _, turn := range turns {
+		text.WriteString("用户：" + truncateRunes(turn.User, maxSummaryTurnChars) + "\n")
+		text.WriteString("助手：" + truncateRunes(turn.Assistant, maxSummaryTurnChars) + "\n")
+	}
 
-		return chatResp.Output.Text, nil
+	summary, err := completeText(usage.WithCallType(ctx, usage.CallTypeSummary), sessionSummaryPrompt, text.String())
+	if err != nil {
+		return "", err
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("未获取到摘要")
 	}
+	return summary, nil
+}
 
-	return "", fmt.Errorf("重试%d次后仍然失败: %v", maxRetries, lastErr)
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n]) + truncatedMark
 }
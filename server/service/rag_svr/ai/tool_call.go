@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"server/framework/logger"
+)
+
+// 默认工具调用最大轮数
+const defaultMaxToolRounds = 5
+
+// ToolCaller 发起对话的用户，工具按该用户的身份和检索范围执行；
+// 模型生成的参数不可信，不能从参数中取用户
+type ToolCaller struct {
+	UserID        uint64
+	SessionID     uint64
+	CollectionIDs []uint64 // 对话请求指定的知识库，为空时检索用户能访问的全部文档
+}
+
+type toolCallerKey struct{}
+
+// WithToolCaller 在 ctx 中记录发起对话的用户
+func WithToolCaller(ctx context.Context, caller ToolCaller) context.Context {
+	return context.WithValue(ctx, toolCallerKey{}, caller)
+}
+
+// toolCallerFrom 获取发起对话的用户，没有记录时返回错误，工具不执行
+func toolCallerFrom(ctx context.Context) (ToolCaller, error) {
+	caller, ok := ctx.Value(toolCallerKey{}).(ToolCaller)
+	if !ok || caller.UserID == 0 {
+		return ToolCaller{}, fmt.Errorf("缺少发起对话的用户")
+	}
+	return caller, nil
+}
+
+// ToolCallTrace 工具调用轨迹，保存到 ChatRecord.FunctionCalls
+type ToolCallTrace struct {
+	Round      int                    `json:"round"`
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	Result     interface{}            `json:"result,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+}
+
+// ChatWithTools 带工具调用的对话，循环执行模型返回的工具调用直到得到最终回复
+// messages 需已包含系统消息，maxRounds 不大于 0 时使用默认值
+func ChatWithTools(ctx context.Context, provider LLMProvider, messages []QwenMessage, maxRounds int) (*ChatResult, []*ToolCallTrace, error) {
+	if maxRounds <= 0 {
+		maxRounds = defaultMaxToolRounds
+	}
+
+	tools := buildQwenTools()
+	traces := make([]*ToolCallTrace, 0)
+	var usage QwenUsage
+
+	for round := 1; ; round++ {
+		// 达到最大轮数后不再提供工具，要求模型直接回答
+		roundTools := tools
+		if round > maxRounds {
+			roundTools = nil
+		}
+
+		result, err := provider.Chat(ctx, messages, roundTools)
+		if err != nil {
+			return nil, traces, err
+		}
+		usage.InputTokens += result.Usage.InputTokens
+		usage.OutputTokens += result.Usage.OutputTokens
+		usage.TotalTokens += result.Usage.TotalTokens
+
+		if len(result.ToolCalls) == 0 || round > maxRounds {
+			result.Usage = usage
+			logger.Infof("工具调用对话完成: 轮数=%d, 调用次数=%d, tokens=%d", round, len(traces), usage.TotalTokens)
+			return result, traces, nil
+		}
+
+		// 记录模型的工具调用请求，再追加每个调用的结果
+		messages = append(messages, QwenMessage{
+			Role:      "assistant",
+			Content:   result.Content,
+			ToolCalls: result.ToolCalls,
+		})
+		roundTraces := executeToolCalls(ctx, round, result.ToolCalls)
+		for i, trace := range roundTraces {
+			messages = append(messages, QwenMessage{
+				Role:       "tool",
+				Name:       trace.Name,
+				Content:    toolResultContent(trace),
+				ToolCallID: result.ToolCalls[i].ID,
+			})
+		}
+		traces = append(traces, roundTraces...)
+	}
+}
+
+// buildQwenTools 将注册的函数转换为工具定义
+func buildQwenTools() []QwenTool {
+	definitions := GetFunctionRegistry().GetFunctionDefinitions()
+	tools := make([]QwenTool, 0, len(definitions))
+	for _, def := range definitions {
+		tools = append(tools, QwenTool{
+			Type: "function",
+			Function: QwenFunctionDefinition{
+				Name:        def.Name,
+				Description: def.Description,
+				Parameters:  def.Parameters,
+			},
+		})
+	}
+	return tools
+}
+
+// executeToolCalls 执行一轮中的所有工具调用
+// 无副作用的函数并发执行，有副作用的函数按原顺序串行执行，结果顺序与调用顺序一致
+func executeToolCalls(ctx context.Context, round int, toolCalls []QwenToolCall) []*ToolCallTrace {
+	traces := make([]*ToolCallTrace, len(toolCalls))
+
+	var wg sync.WaitGroup
+	serial := make([]int, 0)
+	for i, toolCall := range toolCalls {
+		if hasSideEffect(toolCall.Function.Name) {
+			serial = append(serial, i)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, toolCall QwenToolCall) {
+			defer wg.Done()
+			traces[i] = executeToolCall(ctx, round, toolCall)
+		}(i, toolCall)
+	}
+	for _, i := range serial {
+		traces[i] = executeToolCall(ctx, round, toolCalls[i])
+	}
+	wg.Wait()
+
+	return traces
+}
+
+// executeToolCall 执行单个工具调用，错误记录在轨迹中交给模型处理
+func executeToolCall(ctx context.Context, round int, toolCall QwenToolCall) (trace *ToolCallTrace) {
+	trace = &ToolCallTrace{
+		Round: round,
+		ID:    toolCall.ID,
+		Name:  toolCall.Function.Name,
+	}
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("工具调用发生panic: name=%s, panic=%v", trace.Name, r)
+			trace.Error = fmt.Sprintf("函数执行异常: %v", r)
+		}
+		trace.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	args := make(map[string]interface{})
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+			trace.Error = fmt.Sprintf("解析函数参数失败: %v", err)
+			return trace
+		}
+	}
+	trace.Arguments = args
+
+	result, err := ExecuteFunctionCall(ctx, &FunctionCall{
+		Name:      toolCall.Function.Name,
+		Arguments: args,
+	})
+	if err != nil {
+		trace.Error = err.Error()
+		return trace
+	}
+	trace.Result = result
+	return trace
+}
+
+// hasSideEffect 判断函数是否有副作用，未注册的函数按无副作用处理
+func hasSideEffect(name string) bool {
+	handler, ok := GetFunctionRegistry().GetFunction(name)
+	if !ok {
+		return false
+	}
+	if f, ok := handler.(SideEffectFunction); ok {
+		return f.HasSideEffect()
+	}
+	return false
+}
+
+// toolResultContent 生成 tool 消息内容
+func toolResultContent(trace *ToolCallTrace) string {
+	var content interface{} = trace.Result
+	if trace.Error != "" {
+		content = map[string]string{"error": trace.Error}
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Sprintf(`{"error":"序列化函数结果失败: %v"}`, err)
+	}
+	return string(data)
+}
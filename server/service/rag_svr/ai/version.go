@@ -0,0 +1,178 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"server/framework/config"
+	"server/framework/logger"
+	"server/framework/mysql"
+	"server/service/rag_svr/ingest"
+
+	"gorm.io/gorm"
+)
+
+// 每个文档默认保留的历史版本数
+const defaultMaxDocumentVersions = 10
+
+// versionParagraph 版本中保存的段落，恢复版本时按原样重新保存
+type versionParagraph struct {
+	Content string               `json:"content"`
+	Meta    ingest.ParagraphMeta `json:"meta"`
+}
+
+func maxDocumentVersions() int {
+	if config.GlobalConfig != nil && config.GlobalConfig.DocumentVersion.MaxVersions > 0 {
+		return config.GlobalConfig.DocumentVersion.MaxVersions
+	}
+	return defaultMaxDocumentVersions
+}
+
+// createDocumentVersion 以文档当前的段落和切块配置创建新版本，需要在更新文档记录之后、同一事务中调用，
+// 文档记录的行锁保证版本号不重复；超出保留数量的历史版本一并删除，当前可检索的版本不删除
+func createDocumentVersion(tx *gorm.DB, doc *mysql.Document, userID uint64, paragraphs []ingest.Paragraph, restoredFrom uint32) (uint32, error) {
+	snapshot := make([]versionParagraph, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		snapshot[i] = versionParagraph{Content: paragraph.Content, Meta: paragraph.Meta}
+	}
+	contentJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("序列化版本内容失败: %v", err)
+	}
+	hash := sha256.Sum256(contentJSON)
+
+	var latest uint32
+	if err := tx.Table("document_version").Where("doc_id = ?", doc.DocID).
+		Select("COALESCE(MAX(version), 0)").Row().Scan(&latest); err != nil {
+		return 0, fmt.Errorf("获取文档版本失败: %v", err)
+	}
+
+	version := &mysql.DocumentVersion{
+		DocID:          doc.DocID,
+		Version:        latest + 1,
+		UserID:         userID,
+		ContentHash:    hex.EncodeToString(hash[:]),
+		Content:        string(contentJSON),
+		Chunker:        doc.Chunker,
+		EmbeddingModel: embeddingModelName(),
+		ParagraphCount: doc.ParagraphCount,
+		SentenceCount:  doc.SentenceCount,
+		RestoredFrom:   restoredFrom,
+	}
+	if err := tx.Table("document_version").Create(version).Error; err != nil {
+		return 0, fmt.Errorf("创建文档版本失败: %v", err)
+	}
+
+	if keep := uint32(maxDocumentVersions()); version.Version > keep+1 {
+		if err := tx.Table("document_version").
+			Where("doc_id = ? AND version < ? AND version <> ?", doc.DocID, version.Version-keep, doc.Version).
+			Delete(&mysql.DocumentVersion{}).Error; err != nil {
+			return 0, fmt.Errorf("删除历史版本失败: %v", err)
+		}
+	}
+	return version.Version, nil
+}
+
+// activeChunkJoin 只保留属于文档当前版本的块，已删除文档的块也一并排除
+const activeChunkJoin = "JOIN document ON document.doc_id = document_chunk.doc_id AND document.version = document_chunk.version"
+
+// activeChunks 只查询属于文档当前版本的块，新版本索引完成前检索的仍是旧版本
+func activeChunks(db *gorm.DB) *gorm.DB {
+	return db.Table("document_chunk").Joins(activeChunkJoin).Select("document_chunk.*")
+}
+
+// replaceParagraphs 用新的段落替换文档的段落和句子，并更新文档的段落数和句子数
+func replaceParagraphs(tx *gorm.DB, doc *mysql.Document, paragraphs []ingest.Paragraph) error {
+	if err := tx.Table("document_paragraph").Where("doc_id = ?", doc.DocID).Delete(&mysql.DocumentParagraph{}).Error; err != nil {
+		return fmt.Errorf("删除旧段落失败: %v", err)
+	}
+	if err := tx.Table("document_sentence").Where("doc_id = ?", doc.DocID).Delete(&mysql.DocumentSentence{}).Error; err != nil {
+		return fmt.Errorf("删除旧句子失败: %v", err)
+	}
+	paragraphCount, sentenceCount, err := saveParagraphs(tx, doc.DocID, paragraphs)
+	if err != nil {
+		return err
+	}
+	doc.ParagraphCount = paragraphCount
+	doc.SentenceCount = sentenceCount
+	return nil
+}
+
+// currentParagraphs 读取文档当前保存的段落
+func currentParagraphs(ctx context.Context, docID uint64) ([]ingest.Paragraph, error) {
+	loaded, err := loadChunkerParagraphs(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+	paragraphs := make([]ingest.Paragraph, len(loaded))
+	for i, paragraph := range loaded {
+		paragraphs[i] = ingest.Paragraph{Content: paragraph.Content, Meta: paragraph.Meta}
+	}
+	return paragraphs, nil
+}
+
+// ListDocumentVersions 获取文档保留的版本，按版本号从新到旧，不含版本内容
+func (s *DocumentService) ListDocumentVersions(ctx context.Context, docID uint64) ([]mysql.DocumentVersion, error) {
+	var versions []mysql.DocumentVersion
+	if err := s.db.WithContext(ctx).Table("document_version").Omit("content").
+		Where("doc_id = ?", docID).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("获取文档版本失败: %v", err)
+	}
+	return versions, nil
+}
+
+// RestoreDocumentVersion 将文档恢复为指定版本的内容和切块配置，恢复结果作为一个新版本保存并重新索引，
+// 内容不变的块沿用原向量；返回新版本号
+func (s *DocumentService) RestoreDocumentVersion(ctx context.Context, doc *mysql.Document, userID uint64, version uint32) (uint32, error) {
+	var target mysql.DocumentVersion
+	if err := s.db.WithContext(ctx).Table("document_version").
+		Where("doc_id = ? AND version = ?", doc.DocID, version).First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("版本不存在或已删除: %d", version)
+		}
+		return 0, fmt.Errorf("获取文档版本失败: %v", err)
+	}
+
+	var snapshot []versionParagraph
+	if err := json.Unmarshal([]byte(target.Content), &snapshot); err != nil {
+		return 0, fmt.Errorf("解析版本内容失败: %v", err)
+	}
+	paragraphs := make([]ingest.Paragraph, len(snapshot))
+	for i, paragraph := range snapshot {
+		paragraphs[i] = ingest.Paragraph{Content: paragraph.Content, Meta: paragraph.Meta}
+	}
+
+	var newVersion uint32
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := replaceParagraphs(tx, doc, paragraphs); err != nil {
+			return err
+		}
+		if target.Chunker != "" {
+			doc.Chunker = target.Chunker
+		}
+		doc.Status = DocumentStatusPending
+		if err := tx.Table("document").Save(doc).Error; err != nil {
+			return fmt.Errorf("更新文档失败: %v", err)
+		}
+		var err error
+		if newVersion, err = createDocumentVersion(tx, doc, userID, paragraphs, version); err != nil {
+			return err
+		}
+		if err := enqueueIndexJob(tx, doc.DocID, doc.UserID); err != nil {
+			return fmt.Errorf("创建索引任务失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	notifyIndexWorkers()
+	invalidateDocumentCache(ctx, doc.DocID)
+
+	logger.Infof("恢复文档版本成功: docID=%d, 恢复版本=%d, 新版本=%d", doc.DocID, version, newVersion)
+	return newVersion, nil
+}
@@ -0,0 +1,273 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"server/framework/milvus"
+	"server/framework/mysql"
+
+	"gorm.io/gorm"
+)
+
+// Role 用户在知识库或文档上的角色，高的角色包含低的角色的全部权限
+type Role int
+
+const (
+	RoleNone   Role = iota
+	RoleViewer      // 查看、检索、在对话中引用
+	RoleEditor      // 修改内容、标签和版本，向知识库中添加文档
+	RoleOwner       // 删除、移动和共享
+)
+
+// 资源类型，知识库的授权对其中的文档同样生效
+const (
+	ResourceCollection = "collection"
+	ResourceDocument   = "document"
+)
+
+// 授权对象类型
+const (
+	PrincipalUser  = "user"
+	PrincipalGroup = "group"
+)
+
+var roleNames = map[Role]string{
+	RoleViewer: "viewer",
+	RoleEditor: "editor",
+	RoleOwner:  "owner",
+}
+
+var (
+	// ErrForbidden 用户在资源上的角色不足
+	ErrForbidden = errors.New("无权限")
+	// ErrNotFound 资源不存在，用户没有任何角色的资源同样视为不存在
+	ErrNotFound = errors.New("资源不存在")
+)
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return ""
+}
+
+// ParseRole 解析授权时指定的角色
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if roleName == name {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("无效的角色: %s", name)
+}
+
+// principalCondition 授权对象为该用户或其所在的用户组
+const principalCondition = "((principal_type = ? AND principal_id = ?) OR " +
+	"(principal_type = ? AND principal_id IN (SELECT group_id FROM user_group_member WHERE user_id = ?)))"
+
+func principalArgs(userID uint64) []interface{} {
+	return []interface{}{PrincipalUser, userID, PrincipalGroup, userID}
+}
+
+// Scope 用户能访问的知识库和文档，用于在列表和检索中过滤
+// 用户自己创建的文档不在其中，按文档的 user_id 判断
+type Scope struct {
+	UserID      uint64
+	collections map[uint64]Role // 自己创建的和被授权的知识库
+	documents   map[uint64]Role // 被单独授权的文档
+}
+
+// LoadScope 加载用户直接或通过用户组获得的全部授权
+func LoadScope(ctx context.Context, userID uint64) (*Scope, error) {
+	db := mysql.GetDB().WithContext(ctx)
+	scope := &Scope{
+		UserID:      userID,
+		collections: make(map[uint64]Role),
+		documents:   make(map[uint64]Role),
+	}
+
+	var owned []uint64
+	if err := db.Table("knowledge_collection").Where("user_id = ?", userID).Pluck("collection_id", &owned).Error; err != nil {
+		return nil, fmt.Errorf("获取知识库失败: %v", err)
+	}
+	for _, collectionID := range owned {
+		scope.collections[collectionID] = RoleOwner
+	}
+
+	var grants []mysql.AccessGrant
+	if err := db.Table("access_grant").Select("resource_type", "resource_id", "role").
+		Where(principalCondition, principalArgs(userID)...).Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("获取授权失败: %v", err)
+	}
+	for _, grant := range grants {
+		role, err := ParseRole(grant.Role)
+		if err != nil {
+			continue
+		}
+		switch grant.ResourceType {
+		case ResourceCollection:
+			scope.collections[grant.ResourceID] = max(scope.collections[grant.ResourceID], role)
+		case ResourceDocument:
+			scope.documents[grant.ResourceID] = max(scope.documents[grant.ResourceID], role)
+		}
+	}
+	return scope, nil
+}
+
+// CollectionRole 用户在知识库上的角色
+func (s *Scope) CollectionRole(collection *mysql.KnowledgeCollection) Role {
+	if collection.UserID == s.UserID {
+		return RoleOwner
+	}
+	return s.collections[collection.CollectionID]
+}
+
+// DocumentRole 用户在文档上的角色，取文档本身和所属知识库上较高的角色
+func (s *Scope) DocumentRole(doc *mysql.Document) Role {
+	if doc.UserID == s.UserID {
+		return RoleOwner
+	}
+	role := s.documents[doc.DocID]
+	if doc.CollectionID != 0 {
+		role = max(role, s.collections[doc.CollectionID])
+	}
+	return role
+}
+
+// CollectionIDs 用户能访问的知识库
+func (s *Scope) CollectionIDs() []uint64 {
+	return sortedKeys(s.collections)
+}
+
+// DocumentIDs 被单独授权给用户的文档
+func (s *Scope) DocumentIDs() []uint64 {
+	return sortedKeys(s.documents)
+}
+
+// FilterDocuments 在 document 表的查询上限定为用户能访问的文档：自己创建的、所在知识库能访问的和被单独授权的
+func (s *Scope) FilterDocuments(db *gorm.DB) *gorm.DB {
+	conditions := []string{"document.user_id = ?"}
+	args := []interface{}{s.UserID}
+	if collectionIDs := s.CollectionIDs(); len(collectionIDs) > 0 {
+		conditions = append(conditions, "document.collection_id IN ?")
+		args = append(args, collectionIDs)
+	}
+	if docIDs := s.DocumentIDs(); len(docIDs) > 0 {
+		conditions = append(conditions, "document.doc_id IN ?")
+		args = append(args, docIDs)
+	}
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// VectorExpr 与 FilterDocuments 条件一致的 Milvus 过滤表达式，参数为块向量中对应的标量字段名
+func (s *Scope) VectorExpr(userField, collectionField, docField string) string {
+	conditions := []string{fmt.Sprintf("%s == %d", userField, s.UserID)}
+	if collectionIDs := s.CollectionIDs(); len(collectionIDs) > 0 {
+		conditions = append(conditions, milvus.InExpr(collectionField, toInt64s(collectionIDs)))
+	}
+	if docIDs := s.DocumentIDs(); len(docIDs) > 0 {
+		conditions = append(conditions, milvus.InExpr(docField, toInt64s(docIDs)))
+	}
+	return "(" + strings.Join(conditions, " || ") + ")"
+}
+
+// CollectionRole 查询用户在知识库上的角色
+func CollectionRole(ctx context.Context, userID uint64, collection *mysql.KnowledgeCollection) (Role, error) {
+	if collection.UserID == userID {
+		return RoleOwner, nil
+	}
+	return grantedRole(ctx, userID, ResourceCollection, collection.CollectionID)
+}
+
+// DocumentRole 查询用户在文档上的角色，取文档本身和所属知识库上较高的角色
+func DocumentRole(ctx context.Context, userID uint64, doc *mysql.Document) (Role, error) {
+	if doc.UserID == userID {
+		return RoleOwner, nil
+	}
+	role, err := grantedRole(ctx, userID, ResourceDocument, doc.DocID)
+	if err != nil || doc.CollectionID == 0 {
+		return role, err
+	}
+	var collection mysql.KnowledgeCollection
+	if err := mysql.GetDB().WithContext(ctx).Table("knowledge_collection").Select("collection_id", "user_id").
+		Where("collection_id = ?", doc.CollectionID).Take(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return role, nil
+		}
+		return RoleNone, fmt.Errorf("获取知识库失败: %v", err)
+	}
+	collectionRole, err := CollectionRole(ctx, userID, &collection)
+	if err != nil {
+		return RoleNone, err
+	}
+	return max(role, collectionRole), nil
+}
+
+// CheckCollection 检查用户在知识库上的角色不低于 need，没有任何角色时返回 ErrNotFound
+func CheckCollection(ctx context.Context, userID uint64, collection *mysql.KnowledgeCollection, need Role) (Role, error) {
+	role, err := CollectionRole(ctx, userID, collection)
+	if err != nil {
+		return RoleNone, err
+	}
+	return role, checkRole(role, need)
+}
+
+// CheckDocument 检查用户在文档上的角色不低于 need，没有任何角色时返回 ErrNotFound
+func CheckDocument(ctx context.Context, userID uint64, doc *mysql.Document, need Role) (Role, error) {
+	role, err := DocumentRole(ctx, userID, doc)
+	if err != nil {
+		return RoleNone, err
+	}
+	return role, checkRole(role, need)
+}
+
+func checkRole(role, need Role) error {
+	switch {
+	case role == RoleNone:
+		return ErrNotFound
+	case role < need:
+		return ErrForbidden
+	}
+	return nil
+}
+
+// grantedRole 用户直接或通过用户组在资源上获得的最高角色
+func grantedRole(ctx context.Context, userID uint64, resourceType string, resourceID uint64) (Role, error) {
+	var roles []string
+	if err := mysql.GetDB().WithContext(ctx).Table("access_grant").
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Where(principalCondition, principalArgs(userID)...).
+		Pluck("role", &roles).Error; err != nil {
+		return RoleNone, fmt.Errorf("获取授权失败: %v", err)
+	}
+	best := RoleNone
+	for _, name := range roles {
+		if role, err := ParseRole(name); err == nil {
+			best = max(best, role)
+		}
+	}
+	return best, nil
+}
+
+func sortedKeys(m map[uint64]Role) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for key, role := range m {
+		if role > RoleNone {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func toInt64s(values []uint64) []int64 {
+	result := make([]int64, len(values))
+	for i, value := range values {
+		result[i] = int64(value)
+	}
+	return result
+}
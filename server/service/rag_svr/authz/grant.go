@@ -0,0 +1,154 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"server/framework/logger"
+	"server/framework/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// resourceRole 获取资源的创建者和用户在资源上的角色
+func resourceRole(ctx context.Context, userID uint64, resourceType string, resourceID uint64) (uint64, Role, error) {
+	db := mysql.GetDB().WithContext(ctx)
+	switch resourceType {
+	case ResourceDocument:
+		var doc mysql.Document
+		if err := db.Table("document").Select("doc_id", "user_id", "collection_id").
+			Where("doc_id = ?", resourceID).Take(&doc).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, RoleNone, ErrNotFound
+			}
+			return 0, RoleNone, fmt.Errorf("获取文档失败: %v", err)
+		}
+		role, err := DocumentRole(ctx, userID, &doc)
+		return doc.UserID, role, err
+	case ResourceCollection:
+		var collection mysql.KnowledgeCollection
+		if err := db.Table("knowledge_collection").Where("collection_id = ?", resourceID).Take(&collection).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, RoleNone, ErrNotFound
+			}
+			return 0, RoleNone, fmt.Errorf("获取知识库失败: %v", err)
+		}
+		role, err := CollectionRole(ctx, userID, &collection)
+		return collection.UserID, role, err
+	default:
+		return 0, RoleNone, fmt.Errorf("无效的资源类型: %s", resourceType)
+	}
+}
+
+// principalExists 授权对象是否存在
+func principalExists(ctx context.Context, principalType string, principalID uint64) (bool, error) {
+	var table, column string
+	switch principalType {
+	case PrincipalUser:
+		table, column = "user", "id"
+	case PrincipalGroup:
+		table, column = "user_group", "group_id"
+	default:
+		return false, fmt.Errorf("无效的授权对象类型: %s", principalType)
+	}
+	var count int64
+	if err := mysql.GetDB().WithContext(ctx).Table(table).Where(column+" = ?", principalID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("获取授权对象失败: %v", err)
+	}
+	return count > 0, nil
+}
+
+// Grant 把资源的角色授予用户或用户组，已有授权时改为新的角色；只有所有者可以授权
+func Grant(ctx context.Context, actorID uint64, grant *mysql.AccessGrant) error {
+	if _, err := ParseRole(grant.Role); err != nil {
+		return err
+	}
+	ownerID, role, err := resourceRole(ctx, actorID, grant.ResourceType, grant.ResourceID)
+	if err != nil {
+		return err
+	}
+	if err := checkRole(role, RoleOwner); err != nil {
+		return err
+	}
+	exists, err := principalExists(ctx, grant.PrincipalType, grant.PrincipalID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("授权对象不存在: %s/%d", grant.PrincipalType, grant.PrincipalID)
+	}
+	if grant.PrincipalType == PrincipalUser && grant.PrincipalID == ownerID {
+		return fmt.Errorf("创建者已是所有者，不需要授权")
+	}
+
+	grant.GrantedBy = actorID
+	db := mysql.GetDB().WithContext(ctx)
+	if err := db.Table("access_grant").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resource_type"}, {Name: "resource_id"}, {Name: "principal_type"}, {Name: "principal_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "granted_by", "updated_at"}),
+	}).Create(grant).Error; err != nil {
+		return fmt.Errorf("保存授权失败: %v", err)
+	}
+	// 更新已有授权时返回的 ID 不可靠，按唯一键重新读取
+	if err := db.Table("access_grant").
+		Where("resource_type = ? AND resource_id = ? AND principal_type = ? AND principal_id = ?",
+			grant.ResourceType, grant.ResourceID, grant.PrincipalType, grant.PrincipalID).
+		Take(grant).Error; err != nil {
+		return fmt.Errorf("获取授权失败: %v", err)
+	}
+	logger.Infof("授权成功: %s/%d -> %s/%d, role=%s, granted_by=%d",
+		grant.ResourceType, grant.ResourceID, grant.PrincipalType, grant.PrincipalID, grant.Role, actorID)
+	return nil
+}
+
+// Revoke 撤销用户或用户组在资源上的授权；只有所有者可以撤销
+func Revoke(ctx context.Context, actorID uint64, resourceType string, resourceID uint64, principalType string, principalID uint64) error {
+	_, role, err := resourceRole(ctx, actorID, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+	if err := checkRole(role, RoleOwner); err != nil {
+		return err
+	}
+	result := mysql.GetDB().WithContext(ctx).Table("access_grant").
+		Where("resource_type = ? AND resource_id = ? AND principal_type = ? AND principal_id = ?",
+			resourceType, resourceID, principalType, principalID).
+		Delete(&mysql.AccessGrant{})
+	if result.Error != nil {
+		return fmt.Errorf("撤销授权失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("授权不存在")
+	}
+	logger.Infof("撤销授权成功: %s/%d -> %s/%d, revoked_by=%d", resourceType, resourceID, principalType, principalID, actorID)
+	return nil
+}
+
+// ListGrants 获取资源的创建者和全部授权；能访问资源的用户都可以查看
+func ListGrants(ctx context.Context, actorID uint64, resourceType string, resourceID uint64) (uint64, []mysql.AccessGrant, error) {
+	ownerID, role, err := resourceRole(ctx, actorID, resourceType, resourceID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := checkRole(role, RoleViewer); err != nil {
+		return 0, nil, err
+	}
+	var grants []mysql.AccessGrant
+	if err := mysql.GetDB().WithContext(ctx).Table("access_grant").
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("id").Find(&grants).Error; err != nil {
+		return 0, nil, fmt.Errorf("获取授权失败: %v", err)
+	}
+	return ownerID, grants, nil
+}
+
+// DeleteGrants 在事务中删除资源的全部授权，删除资源时调用
+func DeleteGrants(tx *gorm.DB, resourceType string, resourceID uint64) error {
+	if err := tx.Table("access_grant").Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Delete(&mysql.AccessGrant{}).Error; err != nil {
+		return fmt.Errorf("删除授权失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"server/framework/logger"
+	"server/framework/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxGroupNameLength = 100
+
+// checkUsersExist 检查用户都存在
+func checkUsersExist(db *gorm.DB, userIDs []uint64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	var found []uint64
+	if err := db.Table("user").Where("id IN ?", userIDs).Pluck("id", &found).Error; err != nil {
+		return fmt.Errorf("获取用户失败: %v", err)
+	}
+	exists := make(map[uint64]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	for _, id := range userIDs {
+		if !exists[id] {
+			return fmt.Errorf("用户不存在: %d", id)
+		}
+	}
+	return nil
+}
+
+// addMembers 在事务中把用户加入用户组，已在组中的忽略
+func addMembers(tx *gorm.DB, groupID uint64, userIDs []uint64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]mysql.UserGroupMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, mysql.UserGroupMember{GroupID: groupID, UserID: userID})
+	}
+	if err := tx.Table("user_group_member").Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error; err != nil {
+		return fmt.Errorf("添加成员失败: %v", err)
+	}
+	return nil
+}
+
+// ownedGroup 获取用户组并确认由 userID 创建，只有创建者可以管理成员
+func ownedGroup(db *gorm.DB, groupID, userID uint64) (*mysql.UserGroup, error) {
+	var group mysql.UserGroup
+	if err := db.Table("user_group").Where("group_id = ?", groupID).Take(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("获取用户组失败: %v", err)
+	}
+	if group.OwnerID != userID {
+		return nil, ErrForbidden
+	}
+	return &group, nil
+}
+
+// CreateGroup 创建用户组，创建者自动成为成员
+func CreateGroup(ctx context.Context, ownerID uint64, name string, memberIDs []uint64) (*mysql.UserGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("用户组名称不能为空")
+	}
+	if len([]rune(name)) > maxGroupNameLength {
+		return nil, fmt.Errorf("用户组名称不能超过 %d 个字符", maxGroupNameLength)
+	}
+
+	group := &mysql.UserGroup{OwnerID: ownerID, Name: name}
+	err := mysql.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Table("user_group").Where("owner_id = ? AND name = ?", ownerID, name).Count(&count).Error; err != nil {
+			return fmt.Errorf("检查用户组名称失败: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("用户组名称已存在: %s", name)
+		}
+		if err := checkUsersExist(tx, memberIDs); err != nil {
+			return err
+		}
+		if err := tx.Table("user_group").Create(group).Error; err != nil {
+			return fmt.Errorf("创建用户组失败: %v", err)
+		}
+		return addMembers(tx, group.GroupID, append([]uint64{ownerID}, memberIDs...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("创建用户组成功: group_id=%d, owner_id=%d, name=%s", group.GroupID, ownerID, name)
+	return group, nil
+}
+
+// AddGroupMembers 向用户组添加成员
+func AddGroupMembers(ctx context.Context, actorID, groupID uint64, memberIDs []uint64) (*mysql.UserGroup, error) {
+	var group *mysql.UserGroup
+	err := mysql.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if group, err = ownedGroup(tx, groupID, actorID); err != nil {
+			return err
+		}
+		if err := checkUsersExist(tx, memberIDs); err != nil {
+			return err
+		}
+		return addMembers(tx, groupID, memberIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("添加用户组成员成功: group_id=%d, members=%v", groupID, memberIDs)
+	return group, nil
+}
+
+// RemoveGroupMembers 从用户组移除成员，创建者不能被移除
+func RemoveGroupMembers(ctx context.Context, actorID, groupID uint64, memberIDs []uint64) (*mysql.UserGroup, error) {
+	db := mysql.GetDB().WithContext(ctx)
+	group, err := ownedGroup(db, groupID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range memberIDs {
+		if id == group.OwnerID {
+			return nil, fmt.Errorf("不能移除用户组的创建者")
+		}
+	}
+	if len(memberIDs) > 0 {
+		if err := db.Table("user_group_member").Where("group_id = ? AND user_id IN ?", groupID, memberIDs).
+			Delete(&mysql.UserGroupMember{}).Error; err != nil {
+			return nil, fmt.Errorf("移除成员失败: %v", err)
+		}
+	}
+	logger.Infof("移除用户组成员成功: group_id=%d, members=%v", groupID, memberIDs)
+	return group, nil
+}
+
+// ListGroups 获取用户所在的用户组
+func ListGroups(ctx context.Context, userID uint64) ([]mysql.UserGroup, error) {
+	var groups []mysql.UserGroup
+	if err := mysql.GetDB().WithContext(ctx).Table("user_group").
+		Where("group_id IN (?)", mysql.GetDB().Table("user_group_member").Select("group_id").Where("user_id = ?", userID)).
+		Order("group_id").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("获取用户组失败: %v", err)
+	}
+	return groups, nil
+}
+
+// GroupMembers 获取用户组的成员
+func GroupMembers(ctx context.Context, groupIDs []uint64) (map[uint64][]uint64, error) {
+	result := make(map[uint64][]uint64, len(groupIDs))
+	if len(groupIDs) == 0 {
+		return result, nil
+	}
+	var members []mysql.UserGroupMember
+	if err := mysql.GetDB().WithContext(ctx).Table("user_group_member").Where("group_id IN ?", groupIDs).
+		Order("group_id, user_id").Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("获取用户组成员失败: %v", err)
+	}
+	for _, member := range members {
+		result[member.GroupID] = append(result[member.GroupID], member.UserID)
+	}
+	return result, nil
+}
@@ -0,0 +1,252 @@
+// Package chunker 文档切块
+//
+// 切块在句子之上进行，每个块记录覆盖的句子范围，便于检索结果回溯原文；
+// 块的内容取自段落原文，保留标点
+package chunker
+
+import (
+	"fmt"
+	"strings"
+
+	"server/service/rag_svr/ingest"
+)
+
+// 切块策略
+const (
+	StrategySentenceWindow = "sentence_window" // 段落内按句子滑动窗口
+	StrategyFixedToken     = "fixed_token"     // 按 token 数打包句子，相邻块重叠
+	StrategyMarkdown       = "markdown"        // 按标题分节，节内按 token 数打包，块前附标题路径
+	StrategyRecursive      = "recursive"       // 按分隔符层级递归切分字符
+)
+
+// DefaultStrategy 未配置时使用的策略，与早期版本的切块方式一致
+const DefaultStrategy = StrategySentenceWindow
+
+// 各策略的默认参数
+const (
+	defaultWindowSize     = 3
+	defaultStep           = 2
+	defaultTokenChunkSize = 256
+	defaultTokenOverlap   = 32
+	defaultCharChunkSize  = 500
+	defaultCharOverlap    = 50
+)
+
+// DefaultSeparators recursive 策略默认的分隔符，从段落到句子再到短语，都不存在时按字符切分
+var DefaultSeparators = []string{"\n\n", "\n", "。", "！", "？", "；", ". ", "! ", "? ", "; ", "，", ", ", " "}
+
+// Config 切块策略及参数，记录在文档上用于重新索引
+type Config struct {
+	Strategy   string   `json:"strategy"`
+	WindowSize int      `json:"window_size,omitempty"` // sentence_window：每块的句子数
+	Step       int      `json:"step,omitempty"`        // sentence_window：窗口步长
+	ChunkSize  int      `json:"chunk_size,omitempty"`  // fixed_token、markdown 为 token 数，recursive 为字符数
+	Overlap    int      `json:"overlap,omitempty"`     // 相邻块重叠的 token 数或字符数
+	Separators []string `json:"separators,omitempty"`  // recursive：分隔符，按顺序尝试
+}
+
+// Resolve 补全默认参数并校验，清除策略用不到的参数
+// 参数为 0 时使用默认值，overlap 小于 0 表示不重叠；默认的 overlap 不超过 chunk_size 的四分之一
+func Resolve(cfg Config) (Config, error) {
+	if cfg.Strategy == "" {
+		cfg.Strategy = DefaultStrategy
+	}
+
+	resolved := Config{Strategy: cfg.Strategy}
+	switch cfg.Strategy {
+	case StrategySentenceWindow:
+		resolved.WindowSize = orDefault(cfg.WindowSize, defaultWindowSize)
+		resolved.Step = orDefault(cfg.Step, defaultStep)
+	case StrategyFixedToken, StrategyMarkdown:
+		resolved.ChunkSize = orDefault(cfg.ChunkSize, defaultTokenChunkSize)
+		resolved.Overlap = overlapOrDefault(cfg.Overlap, defaultTokenOverlap, resolved.ChunkSize)
+	case StrategyRecursive:
+		resolved.ChunkSize = orDefault(cfg.ChunkSize, defaultCharChunkSize)
+		resolved.Overlap = overlapOrDefault(cfg.Overlap, defaultCharOverlap, resolved.ChunkSize)
+		resolved.Separators = cfg.Separators
+		if len(resolved.Separators) == 0 {
+			resolved.Separators = DefaultSeparators
+		}
+	default:
+		return Config{}, fmt.Errorf("不支持的切块策略: %s", cfg.Strategy)
+	}
+	if resolved.Overlap < 0 {
+		resolved.Overlap = 0
+	}
+
+	if err := resolved.validate(); err != nil {
+		return Config{}, err
+	}
+	return resolved, nil
+}
+
+func (c Config) validate() error {
+	switch c.Strategy {
+	case StrategySentenceWindow:
+		if c.WindowSize <= 0 || c.Step <= 0 {
+			return fmt.Errorf("window_size 和 step 必须大于 0")
+		}
+		if c.Step > c.WindowSize {
+			return fmt.Errorf("step 不能大于 window_size，否则会遗漏句子")
+		}
+	case StrategyFixedToken, StrategyMarkdown, StrategyRecursive:
+		if c.ChunkSize <= 0 {
+			return fmt.Errorf("chunk_size 必须大于 0")
+		}
+		if c.Overlap < 0 || c.Overlap >= c.ChunkSize {
+			return fmt.Errorf("overlap 必须小于 chunk_size")
+		}
+	default:
+		return fmt.Errorf("不支持的切块策略: %s", c.Strategy)
+	}
+	return nil
+}
+
+// String 用于日志
+func (c Config) String() string {
+	switch c.Strategy {
+	case StrategySentenceWindow:
+		return fmt.Sprintf("%s(window_size=%d, step=%d)", c.Strategy, c.WindowSize, c.Step)
+	default:
+		return fmt.Sprintf("%s(chunk_size=%d, overlap=%d)", c.Strategy, c.ChunkSize, c.Overlap)
+	}
+}
+
+// Tokenizer token 计数器
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// Sentence 段落中的一个句子，ID 在文档内递增
+type Sentence struct {
+	ID      uint64
+	Content string
+}
+
+// Paragraph 段落及其句子，句子按 ID 排序
+type Paragraph struct {
+	ID        uint64
+	Content   string
+	Meta      ingest.ParagraphMeta
+	Sentences []Sentence
+}
+
+// Chunk 一个块，句子范围可以跨段落，ParagraphID 为第一个句子所在的段落
+type Chunk struct {
+	ParagraphID   uint64
+	SentenceIDMin uint64
+	SentenceIDMax uint64
+	Content       string
+}
+
+// Chunker 切块器
+type Chunker interface {
+	Chunk(paragraphs []Paragraph) []Chunk
+}
+
+// New 按已补全的配置创建切块器，参数按原值使用，保证同一配置的切块结果一致
+func New(cfg Config, tokenizer Tokenizer) (Chunker, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Strategy {
+	case StrategySentenceWindow:
+		return &sentenceWindow{size: cfg.WindowSize, step: cfg.Step}, nil
+	case StrategyFixedToken:
+		return &fixedToken{size: cfg.ChunkSize, overlap: cfg.Overlap, tokenizer: tokenizer}, nil
+	case StrategyMarkdown:
+		return &markdown{size: cfg.ChunkSize, overlap: cfg.Overlap, tokenizer: tokenizer}, nil
+	default:
+		return &recursive{size: cfg.ChunkSize, overlap: cfg.Overlap, separators: cfg.Separators}, nil
+	}
+}
+
+func orDefault(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// overlapOrDefault 未指定 overlap 时使用默认值，指定了较小的 chunk_size 时按其四分之一收窄
+func overlapOrDefault(value, def, chunkSize int) int {
+	if value == 0 {
+		return min(def, chunkSize/4)
+	}
+	return value
+}
+
+// unit 切块的最小单位：一个句子及其后到下一个句子前的标点和空白
+type unit struct {
+	paragraph int // 段落下标
+	sentence  uint64
+	start     int // 在段落原文中的字节偏移
+	end       int
+	text      string
+}
+
+// splitUnits 在段落原文中定位句子，得到首尾相接、覆盖整个段落的单元
+// 句子由段落原文切分而来，正常都能定位到；定位不到时使用句子本身的内容
+func splitUnits(index int, paragraph Paragraph) []unit {
+	units := make([]unit, 0, len(paragraph.Sentences))
+	content := paragraph.Content
+	pos := 0
+	found := true
+	for _, sentence := range paragraph.Sentences {
+		start := pos
+		if i := strings.Index(content[pos:], sentence.Content); i >= 0 && found {
+			start = pos + i
+			pos = start + len(sentence.Content)
+		} else {
+			found = false
+		}
+		units = append(units, unit{paragraph: index, sentence: sentence.ID, start: start, text: sentence.Content + " "})
+	}
+	if !found {
+		return units
+	}
+
+	// 每个单元延伸到下一个句子开始，第一个单元从段落开始
+	for i := range units {
+		if i == 0 {
+			units[i].start = 0
+		}
+		units[i].end = len(content)
+		if i+1 < len(units) {
+			units[i].end = units[i+1].start
+		}
+		units[i].text = content[units[i].start:units[i].end]
+	}
+	return units
+}
+
+// documentUnits 按文档顺序返回所有段落的单元
+func documentUnits(paragraphs []Paragraph) []unit {
+	units := make([]unit, 0)
+	for i, paragraph := range paragraphs {
+		units = append(units, splitUnits(i, paragraph)...)
+	}
+	return units
+}
+
+// joinUnits 拼接连续的单元，同一段落内还原原文，跨段落换行
+func joinUnits(units []unit) string {
+	var b strings.Builder
+	for i, u := range units {
+		if i > 0 && u.paragraph != units[i-1].paragraph {
+			b.WriteString("\n")
+		}
+		b.WriteString(u.text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// newChunk 由连续的单元生成块
+func newChunk(paragraphs []Paragraph, units []unit, content string) Chunk {
+	return Chunk{
+		ParagraphID:   paragraphs[units[0].paragraph].ID,
+		SentenceIDMin: units[0].sentence,
+		SentenceIDMax: units[len(units)-1].sentence,
+		Content:       content,
+	}
+}
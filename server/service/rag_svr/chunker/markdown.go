@@ -0,0 +1,93 @@
+package chunker
+
+import (
+	"strings"
+
+	"server/service/rag_svr/ingest"
+)
+
+// 标题路径的分隔符
+const headingSeparator = " > "
+
+// markdown 按标题分节，块不跨节，节内按 token 数打包，每块前附标题路径
+// 标题来自解析时的段落结构信息，Markdown、HTML、DOCX 都适用，没有标题的文档整篇为一节
+type markdown struct {
+	size      int
+	overlap   int
+	tokenizer Tokenizer
+}
+
+// section 标题路径相同的连续段落
+type section struct {
+	headings []string
+	body     []unit // 正文单元
+	heading  []unit // 标题段落本身的单元
+}
+
+func (m *markdown) Chunk(paragraphs []Paragraph) []Chunk {
+	sections := splitSections(paragraphs)
+
+	chunks := make([]Chunk, 0)
+	for i, sec := range sections {
+		prefix := strings.Join(sec.headings, headingSeparator)
+		if len(sec.body) == 0 {
+			// 只有标题的节：标题出现在下一节的路径中时跳过，否则单独成块
+			if len(sec.heading) == 0 || prefix == "" || (i+1 < len(sections) && hasPrefix(sections[i+1].headings, sec.headings)) {
+				continue
+			}
+			chunks = append(chunks, newChunk(paragraphs, sec.heading, prefix))
+			continue
+		}
+
+		// 标题路径占用块的部分预算，至少为正文保留一半
+		budget := m.size
+		if prefix != "" {
+			budget = max(m.size-countTokens(m.tokenizer, prefix), m.size/2, 1)
+		}
+		packUnits(sec.body, budget, min(m.overlap, budget-1), m.tokenizer, func(units []unit, content string) {
+			if prefix != "" {
+				content = prefix + "\n" + content
+			}
+			chunks = append(chunks, newChunk(paragraphs, units, content))
+		})
+	}
+	return chunks
+}
+
+// splitSections 按标题路径分节，标题段落开始新的一节
+func splitSections(paragraphs []Paragraph) []section {
+	sections := make([]section, 0)
+	for i, paragraph := range paragraphs {
+		isHeading := paragraph.Meta.Type == ingest.BlockHeading
+		last := len(sections) - 1
+		if last < 0 || isHeading || !equalPath(sections[last].headings, paragraph.Meta.Headings) {
+			sections = append(sections, section{headings: paragraph.Meta.Headings})
+			last++
+		}
+
+		units := splitUnits(i, paragraph)
+		if isHeading {
+			sections[last].heading = append(sections[last].heading, units...)
+		} else {
+			sections[last].body = append(sections[last].body, units...)
+		}
+	}
+	return sections
+}
+
+func equalPath(a, b []string) bool {
+	return len(a) == len(b) && hasPrefix(a, b)
+}
+
+// hasPrefix path 是否以 prefix 开头
+func hasPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package chunker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 全文中段落之间的分隔
+const paragraphSeparator = "\n\n"
+
+// recursive 递归字符切分：段落以空行连接为全文，按分隔符顺序切分，
+// 片段仍超过 size 个字符时用下一个分隔符继续切分，再将相邻片段合并到 size 以内，
+// 相邻块重叠不超过 overlap 个字符；块覆盖的句子由块在原文中的位置确定
+type recursive struct {
+	size       int
+	overlap    int
+	separators []string
+}
+
+// span 全文中的字节区间 [start, end)
+type span struct {
+	start int
+	end   int
+}
+
+func (r *recursive) Chunk(paragraphs []Paragraph) []Chunk {
+	var b strings.Builder
+	units := make([]unit, 0)
+	for i, paragraph := range paragraphs {
+		if i > 0 {
+			b.WriteString(paragraphSeparator)
+		}
+		base := b.Len()
+		b.WriteString(paragraph.Content)
+		for _, u := range splitUnits(i, paragraph) {
+			u.start += base
+			u.end = max(u.end+base, u.start+1) // 定位失败的单元按一个字节计
+			units = append(units, u)
+		}
+	}
+	text := b.String()
+
+	chunks := make([]Chunk, 0)
+	for _, s := range r.split(text, span{0, len(text)}, r.separators) {
+		s = trimSpan(text, s)
+		if s.start >= s.end {
+			continue
+		}
+		// 第一个结束位置在块开始之后的单元起，到开始位置不早于块结束的单元为止
+		first := sort.Search(len(units), func(i int) bool { return units[i].end > s.start })
+		last := first
+		for last < len(units) && units[last].start < s.end {
+			last++
+		}
+		if first == last {
+			continue
+		}
+		chunks = append(chunks, newChunk(paragraphs, units[first:last], text[s.start:s.end]))
+	}
+	return chunks
+}
+
+// split 用第一个出现在文本中的分隔符切分，过长的片段用后续分隔符递归切分
+func (r *recursive) split(text string, s span, separators []string) []span {
+	separator, rest := "", []string(nil)
+	for i, candidate := range separators {
+		if candidate != "" && strings.Contains(text[s.start:s.end], candidate) {
+			separator, rest = candidate, separators[i+1:]
+			break
+		}
+	}
+
+	spans := make([]span, 0)
+	good := make([]span, 0)
+	for _, piece := range cut(text, s, separator) {
+		if runeLen(text, piece) <= r.size {
+			good = append(good, piece)
+			continue
+		}
+		if len(good) > 0 {
+			spans = append(spans, r.merge(text, good)...)
+			good = good[:0]
+		}
+		spans = append(spans, r.split(text, piece, rest)...)
+	}
+	if len(good) > 0 {
+		spans = append(spans, r.merge(text, good)...)
+	}
+	return spans
+}
+
+// merge 合并相邻的片段，每块不超过 size 个字符，下一块保留上一块末尾不超过 overlap 个字符的片段
+func (r *recursive) merge(text string, pieces []span) []span {
+	merged := make([]span, 0)
+	current := make([]span, 0)
+	total := 0
+	for _, piece := range pieces {
+		length := runeLen(text, piece)
+		if total+length > r.size && len(current) > 0 {
+			merged = append(merged, span{current[0].start, current[len(current)-1].end})
+			for len(current) > 0 && (total > r.overlap || total+length > r.size) {
+				total -= runeLen(text, current[0])
+				current = current[1:]
+			}
+		}
+		current = append(current, piece)
+		total += length
+	}
+	if len(current) > 0 {
+		merged = append(merged, span{current[0].start, current[len(current)-1].end})
+	}
+	return merged
+}
+
+// cut 按分隔符切分，分隔符保留在前一个片段末尾，分隔符为空时按字符切分
+func cut(text string, s span, separator string) []span {
+	pieces := make([]span, 0)
+	if separator == "" {
+		for pos := s.start; pos < s.end; {
+			_, width := utf8.DecodeRuneInString(text[pos:s.end])
+			pieces = append(pieces, span{pos, pos + width})
+			pos += width
+		}
+		return pieces
+	}
+
+	pos := s.start
+	for pos < s.end {
+		i := strings.Index(text[pos:s.end], separator)
+		if i < 0 {
+			break
+		}
+		pieces = append(pieces, span{pos, pos + i + len(separator)})
+		pos += i + len(separator)
+	}
+	if pos < s.end {
+		pieces = append(pieces, span{pos, s.end})
+	}
+	return pieces
+}
+
+// trimSpan 去掉区间首尾的空白
+func trimSpan(text string, s span) span {
+	for s.start < s.end {
+		r, width := utf8.DecodeRuneInString(text[s.start:s.end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s.start += width
+	}
+	for s.end > s.start {
+		r, width := utf8.DecodeLastRuneInString(text[s.start:s.end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s.end -= width
+	}
+	return s
+}
+
+func runeLen(text string, s span) int {
+	return utf8.RuneCountInString(text[s.start:s.end])
+}
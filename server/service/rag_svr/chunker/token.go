@@ -0,0 +1,87 @@
+package chunker
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// fixedToken 按文档顺序打包句子，每块不超过 size 个 token，可以跨段落；
+// 下一块从上一块末尾不超过 overlap 个 token 的句子开始
+type fixedToken struct {
+	size      int
+	overlap   int
+	tokenizer Tokenizer
+}
+
+func (t *fixedToken) Chunk(paragraphs []Paragraph) []Chunk {
+	chunks := make([]Chunk, 0)
+	packUnits(documentUnits(paragraphs), t.size, t.overlap, t.tokenizer, func(units []unit, content string) {
+		chunks = append(chunks, newChunk(paragraphs, units, content))
+	})
+	return chunks
+}
+
+// packUnits 将连续的单元按 token 数打包，emit 按顺序接收每块的单元和内容
+// 单个句子超过 size 时单独成块，内容按 token 数拆成多块
+func packUnits(units []unit, size, overlap int, tokenizer Tokenizer, emit func(units []unit, content string)) {
+	tokens := make([]int, len(units))
+	for i, u := range units {
+		tokens[i] = countTokens(tokenizer, u.text)
+	}
+
+	for start := 0; start < len(units); {
+		end, total := start, 0
+		for end < len(units) && (end == start || total+tokens[end] <= size) {
+			total += tokens[end]
+			end++
+		}
+
+		if end-start == 1 && tokens[start] > size {
+			for _, piece := range splitByTokens(units[start].text, size, tokenizer) {
+				emit(units[start:end], piece)
+			}
+		} else {
+			emit(units[start:end], joinUnits(units[start:end]))
+		}
+		if end == len(units) {
+			break
+		}
+
+		// 回退不超过 overlap 个 token 的句子作为下一块的开头，且须放得下下一个句子，至少前进一个句子
+		next, overlapped := end, 0
+		for next-1 > start && overlapped+tokens[next-1] <= overlap && overlapped+tokens[next-1]+tokens[end] <= size {
+			overlapped += tokens[next-1]
+			next--
+		}
+		start = next
+	}
+}
+
+// splitByTokens 将过长的文本按字符拆成不超过 size 个 token 的片段
+func splitByTokens(text string, size int, tokenizer Tokenizer) []string {
+	pieces := make([]string, 0)
+	start := 0
+	for start < len(text) {
+		end := start
+		for end < len(text) {
+			_, width := utf8.DecodeRuneInString(text[end:])
+			if end > start && countTokens(tokenizer, text[start:end+width]) > size {
+				break
+			}
+			end += width
+		}
+		if piece := strings.TrimSpace(text[start:end]); piece != "" {
+			pieces = append(pieces, piece)
+		}
+		start = end
+	}
+	return pieces
+}
+
+// countTokens 未指定 tokenizer 时按字符数计
+func countTokens(tokenizer Tokenizer, text string) int {
+	if tokenizer == nil {
+		return utf8.RuneCountInString(text)
+	}
+	return tokenizer.CountTokens(text)
+}
@@ -0,0 +1,22 @@
+package chunker
+
+// sentenceWindow 段落内按句子滑动窗口，最后不足一个窗口的句子也生成一块
+type sentenceWindow struct {
+	size int
+	step int
+}
+
+func (w *sentenceWindow) Chunk(paragraphs []Paragraph) []Chunk {
+	chunks := make([]Chunk, 0)
+	for i, paragraph := range paragraphs {
+		units := splitUnits(i, paragraph)
+		for j := 0; j < len(units); j += w.step {
+			last := min(j+w.size, len(units))
+			chunks = append(chunks, newChunk(paragraphs, units[j:last], joinUnits(units[j:last])))
+			if last == len(units) {
+				break // 最后一块
+			}
+		}
+	}
+	return chunks
+}
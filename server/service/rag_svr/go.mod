@@ -3,6 +3,7 @@ module server/service/rag_svr
 go 1.23.5
 
 require (
+	github.com/bytedance/gopkg v0.1.2
 	github.com/cloudwego/kitex v0.13.1
 	github.com/cloudwego/prutal v0.1.0
 	github.com/neurosnap/sentences v1.1.2
@@ -14,7 +15,6 @@ require (
 )
 
 require (
-	github.com/bytedance/gopkg v0.1.2 // indirect
 	github.com/bytedance/sonic v1.13.2 // indirect
 	github.com/bytedance/sonic/loader v0.2.4 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
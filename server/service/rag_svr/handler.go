@@ -4,24 +4,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"server/framework/config"
 	"server/framework/id_generator"
 	"server/framework/logger"
 	"server/framework/milvus"
+	"server/framework/mongodb"
 	"server/framework/mysql"
 	"server/service/rag_svr/ai"
+	"server/service/rag_svr/authz"
+	"server/service/rag_svr/chunker"
 	rag_svr "server/service/rag_svr/kitex_gen/rag_svr"
 	"server/service/rag_svr/memory"
+	"server/service/rag_svr/usage"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 // RagServiceImpl implements the last service interface defined in the IDL.
 type RagServiceImpl struct {
-	qwenClient *ai.QwenClient
+	llm           ai.LLMProvider
+	promptBuilder *ai.PromptBuilder
 }
 
 // Test implements the RagServiceImpl interface.
@@ -221,20 +230,75 @@ func (s *RagServiceImpl) EndSession(ctx context.Context, req *rag_svr.EndSession
 	}, nil
 }
 
+// UpdateSession implements the RagServiceImpl interface.
+func (s *RagServiceImpl) UpdateSession(ctx context.Context, req *rag_svr.UpdateSessionReq) (resp *rag_svr.UpdateSessionRsp, err error) {
+	logger.Infof("更新会话请求: session_id=%d, user_id=%d", req.SessionId, req.UserId)
+
+	title := strings.TrimSpace(req.Title)
+	if req.SessionId == 0 || req.UserId == 0 || title == "" {
+		return &rag_svr.UpdateSessionRsp{
+			Code: 1,
+			Msg:  "会话ID、用户ID和标题不能为空",
+		}, nil
+	}
+	if len([]rune(title)) > ai.MaxSessionTitleLength {
+		return &rag_svr.UpdateSessionRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("标题不能超过%d个字符", ai.MaxSessionTitleLength),
+		}, nil
+	}
+
+	var session mysql.ChatSession
+	if err := mysql.GetDB().Table("chat_session").Model(&mysql.ChatSession{}).First(&session, req.SessionId).Error; err != nil {
+		logger.Errorf("获取会话信息失败: %v", err)
+		return &rag_svr.UpdateSessionRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取会话信息失败: %v", err),
+		}, nil
+	}
+	if session.UserID != req.UserId {
+		logger.Errorf("用户无权限修改该会话: user_id=%d, session_user_id=%d", req.UserId, session.UserID)
+		return &rag_svr.UpdateSessionRsp{
+			Code: 1,
+			Msg:  "无权限访问该会话",
+		}, nil
+	}
+
+	if err := mysql.GetDB().Table("chat_session").Model(&session).Update("title", title).Error; err != nil {
+		logger.Errorf("更新会话失败: %v", err)
+		return &rag_svr.UpdateSessionRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("更新会话失败: %v", err),
+		}, nil
+	}
+
+	logger.Infof("会话已更新: session_id=%d, title=%s", req.SessionId, title)
+
+	return &rag_svr.UpdateSessionRsp{
+		Code: 0,
+		Msg:  "success",
+		SessionInfo: &rag_svr.SessionInfo{
+			SessionId:  session.ID,
+			UserId:     session.UserID,
+			Title:      session.Title,
+			Summary:    session.Summary,
+			Status:     session.Status,
+			CreateTime: uint64(session.CreatedAt.Unix()),
+			UpdateTime: uint64(session.UpdatedAt.Unix()),
+		},
+	}, nil
+}
+
 // extractAndStoreMemories 提取并存储重要信息
 func (s *RagServiceImpl) extractAndStoreMemories(ctx context.Context, userID, sessionID uint64, message, response string) error {
 	memoryManager := memory.GetInstance()
 
-	// 分析用户消息和AI回复
-	intent, err := ai.AnalyzeIntent(message)
-	if err != nil {
-		return fmt.Errorf("分析意图失败: %v", err)
-	}
-
-	sentiment, err := ai.AnalyzeSentiment(message)
+	// 一次调用同时分析用户消息的意图和情感
+	analysis, err := ai.AnalyzeMessage(ctx, message)
 	if err != nil {
-		return fmt.Errorf("分析情感失败: %v", err)
+		return fmt.Errorf("分析消息失败: %v", err)
 	}
+	intent, sentiment := &analysis.Intent, &analysis.Sentiment
 
 	// 根据意图类型提取不同类型的记忆
 	switch intent.Intent {
@@ -354,7 +418,12 @@ func (s *RagServiceImpl) extractAndStoreMemories(ctx context.Context, userID, se
 
 // AddDocument 实现添加文档
 func (s *RagServiceImpl) AddDocument(ctx context.Context, req *rag_svr.AddDocumentReq) (resp *rag_svr.AddDocumentRsp, err error) {
-	logger.Infof("添加文档请求: user_id=%d, title=%s", req.UserId, req.Title)
+	logger.Infof("添加文档请求: user_id=%d, title=%s, mime_type=%s, file_name=%s, collection=%s", req.UserId, req.Title, req.MimeType, req.FileName, req.Collection)
+
+	// 上传文件未指定标题时使用文件名
+	if req.Title == "" {
+		req.Title = titleFromFileName(req.FileName)
+	}
 
 	// 参数校验
 	if req.UserId == 0 || req.Title == "" {
@@ -364,12 +433,23 @@ func (s *RagServiceImpl) AddDocument(ctx context.Context, req *rag_svr.AddDocume
 		}, nil
 	}
 
+	// 文档向量化计入用户配额
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.AddDocumentRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithScope(ctx, req.UserId, 0)
+
 	docID, err := ai.GetDocumentServiceInstance().AddDocument(ctx, req)
 	if err != nil {
 		logger.Errorf("添加文档失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "无权限向该知识库添加文档")
 		return &rag_svr.AddDocumentRsp{
-			Code: 1,
-			Msg:  err.Error(),
+			Code: code,
+			Msg:  msg,
 		}, nil
 	}
 
@@ -381,6 +461,394 @@ func (s *RagServiceImpl) AddDocument(ctx context.Context, req *rag_svr.AddDocume
 	}, nil
 }
 
+// AddDocumentFile 从 GridFS 中已上传的文件添加文档
+func (s *RagServiceImpl) AddDocumentFile(ctx context.Context, req *rag_svr.AddDocumentFileReq) (resp *rag_svr.AddDocumentFileRsp, err error) {
+	logger.Infof("添加文件文档请求: user_id=%d, file_id=%s, file_name=%s, mime_type=%s", req.UserId, req.FileId, req.FileName, req.MimeType)
+
+	if req.UserId == 0 || req.FileId == "" {
+		return &rag_svr.AddDocumentFileRsp{
+			Code: 1,
+			Msg:  "用户ID和文件ID不能为空",
+		}, nil
+	}
+	fileID, err := primitive.ObjectIDFromHex(req.FileId)
+	if err != nil {
+		return &rag_svr.AddDocumentFileRsp{
+			Code: 1,
+			Msg:  "文件ID格式错误",
+		}, nil
+	}
+
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.AddDocumentFileRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithScope(ctx, req.UserId, 0)
+
+	file, data, err := mongodb.DownloadFile(ctx, mongodb.DocumentBucket, fileID)
+	if err != nil {
+		logger.Errorf("读取上传文件失败: file_id=%s, err=%v", req.FileId, err)
+		return &rag_svr.AddDocumentFileRsp{
+			Code: 1,
+			Msg:  "读取上传文件失败",
+		}, nil
+	}
+	// 只能使用自己上传的文件
+	var fileMeta struct {
+		UserID uint64 `bson:"user_id"`
+	}
+	if err := bson.Unmarshal(file.Metadata, &fileMeta); err != nil || fileMeta.UserID != req.UserId {
+		logger.Errorf("无权使用该文件: file_id=%s, user_id=%d, owner=%d", req.FileId, req.UserId, fileMeta.UserID)
+		return &rag_svr.AddDocumentFileRsp{
+			Code: 1,
+			Msg:  "无权使用该文件",
+		}, nil
+	}
+
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = file.Name
+	}
+	title := req.Title
+	if title == "" {
+		title = titleFromFileName(fileName)
+	}
+
+	docID, err := ai.GetDocumentServiceInstance().AddDocument(ctx, &rag_svr.AddDocumentReq{
+		SeqId:        req.SeqId,
+		UserId:       req.UserId,
+		Title:        title,
+		Metadata:     req.Metadata,
+		MimeType:     req.MimeType,
+		Data:         data,
+		FileName:     fileName,
+		Collection:   req.Collection,
+		Chunker:      req.Chunker,
+		CollectionId: req.CollectionId,
+		Tags:         req.Tags,
+	})
+	if err != nil {
+		logger.Errorf("添加文档失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "无权限向该知识库添加文档")
+		return &rag_svr.AddDocumentFileRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+
+	// 在文件元数据中记录对应的文档，便于按文档查找原始文件
+	if err := mongodb.SetFileMetadata(ctx, mongodb.DocumentBucket, fileID, "doc_id", docID); err != nil {
+		logger.Warnf("记录文件对应的文档失败: file_id=%s, doc_id=%d, err=%v", req.FileId, docID, err)
+	}
+
+	logger.Infof("文件文档添加成功: doc_id=%d, file_id=%s", docID, req.FileId)
+	return &rag_svr.AddDocumentFileRsp{
+		Code:  0,
+		Msg:   "success",
+		DocId: docID,
+	}, nil
+}
+
+// UpdateDocument 更新文档，内容变化时只为变化的块重新生成向量
+func (s *RagServiceImpl) UpdateDocument(ctx context.Context, req *rag_svr.UpdateDocumentReq) (resp *rag_svr.UpdateDocumentRsp, err error) {
+	logger.Infof("更新文档请求: doc_id=%d, user_id=%d, mime_type=%s, file_name=%s", req.DocId, req.UserId, req.MimeType, req.FileName)
+
+	if req.DocId == 0 || req.UserId == 0 {
+		return &rag_svr.UpdateDocumentRsp{
+			Code: 1,
+			Msg:  "文档ID和用户ID不能为空",
+		}, nil
+	}
+
+	var doc mysql.Document
+	if err := mysql.GetDB().WithContext(ctx).Table("document").First(&doc, req.DocId).Error; err != nil {
+		logger.Errorf("获取文档信息失败: %v", err)
+		return &rag_svr.UpdateDocumentRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
+		}, nil
+	}
+	if _, err := authz.CheckDocument(ctx, req.UserId, &doc, authz.RoleEditor); err != nil {
+		logger.Errorf("用户无权限更新该文档: user_id=%d, doc_id=%d, err=%v", req.UserId, doc.DocID, err)
+		code, msg := ai.AuthzFailure(err, "无权限更新该文档")
+		return &rag_svr.UpdateDocumentRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+
+	// 重新向量化计入用户配额
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.UpdateDocumentRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithScope(ctx, req.UserId, 0)
+
+	result, err := ai.GetDocumentServiceInstance().UpdateDocument(ctx, &doc, req)
+	if err != nil {
+		logger.Errorf("更新文档失败: %v", err)
+		return &rag_svr.UpdateDocumentRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+
+	return &rag_svr.UpdateDocumentRsp{
+		Code:                0,
+		Msg:                 "success",
+		DocId:               doc.DocID,
+		ParagraphsAdded:     result.ParagraphsAdded,
+		ParagraphsRemoved:   result.ParagraphsRemoved,
+		ParagraphsUnchanged: result.ParagraphsUnchanged,
+		Reindexing:          result.Reindexing,
+		Version:             result.Version,
+	}, nil
+}
+
+// ListDocumentVersions 获取文档保留的版本
+func (s *RagServiceImpl) ListDocumentVersions(ctx context.Context, req *rag_svr.ListDocumentVersionsReq) (resp *rag_svr.ListDocumentVersionsRsp, err error) {
+	logger.Infof("获取文档版本请求: doc_id=%d, user_id=%d", req.DocId, req.UserId)
+
+	if req.DocId == 0 || req.UserId == 0 {
+		return &rag_svr.ListDocumentVersionsRsp{
+			Code: 1,
+			Msg:  "文档ID和用户ID不能为空",
+		}, nil
+	}
+
+	var doc mysql.Document
+	if err := mysql.GetDB().WithContext(ctx).Table("document").First(&doc, req.DocId).Error; err != nil {
+		logger.Errorf("获取文档信息失败: %v", err)
+		return &rag_svr.ListDocumentVersionsRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
+		}, nil
+	}
+	if _, err := authz.CheckDocument(ctx, req.UserId, &doc, authz.RoleViewer); err != nil {
+		code, msg := ai.AuthzFailure(err, "无权限访问该文档")
+		return &rag_svr.ListDocumentVersionsRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+
+	versions, err := ai.GetDocumentServiceInstance().ListDocumentVersions(ctx, doc.DocID)
+	if err != nil {
+		logger.Errorf("获取文档版本失败: %v", err)
+		return &rag_svr.ListDocumentVersionsRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+
+	versionList := make([]*rag_svr.DocumentVersion, 0, len(versions))
+	for _, version := range versions {
+		versionList = append(versionList, &rag_svr.DocumentVersion{
+			Version:        version.Version,
+			ContentHash:    version.ContentHash,
+			Chunker:        toChunkerConfig(version.Chunker),
+			EmbeddingModel: version.EmbeddingModel,
+			ParagraphCount: version.ParagraphCount,
+			SentenceCount:  version.SentenceCount,
+			RestoredFrom:   version.RestoredFrom,
+			UserId:         version.UserID,
+			CreateTime:     uint64(version.CreatedAt.Unix()),
+			Active:         version.Version == doc.Version,
+		})
+	}
+
+	return &rag_svr.ListDocumentVersionsRsp{
+		Code:          0,
+		Msg:           "success",
+		DocId:         doc.DocID,
+		ActiveVersion: doc.Version,
+		Versions:      versionList,
+	}, nil
+}
+
+// RestoreDocumentVersion 将文档恢复为指定版本，恢复结果作为新版本重新索引
+func (s *RagServiceImpl) RestoreDocumentVersion(ctx context.Context, req *rag_svr.RestoreDocumentVersionReq) (resp *rag_svr.RestoreDocumentVersionRsp, err error) {
+	logger.Infof("恢复文档版本请求: doc_id=%d, user_id=%d, version=%d", req.DocId, req.UserId, req.Version)
+
+	if req.DocId == 0 || req.UserId == 0 || req.Version == 0 {
+		return &rag_svr.RestoreDocumentVersionRsp{
+			Code: 1,
+			Msg:  "文档ID、用户ID和版本号不能为空",
+		}, nil
+	}
+
+	var doc mysql.Document
+	if err := mysql.GetDB().WithContext(ctx).Table("document").First(&doc, req.DocId).Error; err != nil {
+		logger.Errorf("获取文档信息失败: %v", err)
+		return &rag_svr.RestoreDocumentVersionRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
+		}, nil
+	}
+	if _, err := authz.CheckDocument(ctx, req.UserId, &doc, authz.RoleEditor); err != nil {
+		logger.Errorf("用户无权限恢复该文档: user_id=%d, doc_id=%d, err=%v", req.UserId, doc.DocID, err)
+		code, msg := ai.AuthzFailure(err, "无权限恢复该文档")
+		return &rag_svr.RestoreDocumentVersionRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+
+	// 重新向量化计入用户配额
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.RestoreDocumentVersionRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithScope(ctx, req.UserId, 0)
+
+	version, err := ai.GetDocumentServiceInstance().RestoreDocumentVersion(ctx, &doc, req.UserId, req.Version)
+	if err != nil {
+		logger.Errorf("恢复文档版本失败: %v", err)
+		return &rag_svr.RestoreDocumentVersionRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+
+	return &rag_svr.RestoreDocumentVersionRsp{
+		Code:    0,
+		Msg:     "success",
+		DocId:   doc.DocID,
+		Version: version,
+	}, nil
+}
+
+// GetDocumentStatus 获取文档状态和索引进度
+func (s *RagServiceImpl) GetDocumentStatus(ctx context.Context, req *rag_svr.GetDocumentStatusReq) (resp *rag_svr.GetDocumentStatusRsp, err error) {
+	logger.Infof("获取文档状态请求: doc_id=%d, user_id=%d", req.DocId, req.UserId)
+
+	if req.DocId == 0 || req.UserId == 0 {
+		return &rag_svr.GetDocumentStatusRsp{
+			Code: 1,
+			Msg:  "文档ID和用户ID不能为空",
+		}, nil
+	}
+
+	doc, job, err := ai.GetDocumentIndexStatus(ctx, req.DocId)
+	if err != nil {
+		logger.Errorf("获取文档状态失败: %v", err)
+		return &rag_svr.GetDocumentStatusRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取文档状态失败: %v", err),
+		}, nil
+	}
+	if _, err := authz.CheckDocument(ctx, req.UserId, doc, authz.RoleViewer); err != nil {
+		code, msg := ai.AuthzFailure(err, "无权限访问该文档")
+		return &rag_svr.GetDocumentStatusRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+
+	resp = &rag_svr.GetDocumentStatusRsp{
+		Code:   0,
+		Msg:    "success",
+		DocId:  doc.DocID,
+		Status: doc.Status,
+	}
+	if job != nil {
+		resp.Job = &rag_svr.DocumentIndexJob{
+			JobId:        job.JobID,
+			Status:       job.Status,
+			TotalChunks:  job.TotalChunks,
+			DoneChunks:   job.DoneChunks,
+			ReusedChunks: job.ReusedChunks,
+			Attempts:     job.Attempts,
+			MaxAttempts:  job.MaxAttempts,
+			LastError:    job.LastError,
+			CreateTime:   uint64(job.CreatedAt.Unix()),
+			NextRunTime:  uint64(job.NextRunAt.Unix()),
+		}
+		if job.StartedAt != nil {
+			resp.Job.StartTime = uint64(job.StartedAt.Unix())
+		}
+		if job.FinishedAt != nil {
+			resp.Job.FinishTime = uint64(job.FinishedAt.Unix())
+		}
+	}
+	return resp, nil
+}
+
+// titleFromFileName 使用去掉扩展名的文件名作为文档标题
+func titleFromFileName(fileName string) string {
+	base := filepath.Base(fileName)
+	if base == "." || base == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// toChunkerConfig 将文档记录的切块配置转换为响应结构，未记录时返回 nil
+func toChunkerConfig(raw string) *rag_svr.ChunkerConfig {
+	if raw == "" {
+		return nil
+	}
+	var cfg chunker.Config
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil
+	}
+	return &rag_svr.ChunkerConfig{
+		Strategy:   cfg.Strategy,
+		WindowSize: int32(cfg.WindowSize),
+		Step:       int32(cfg.Step),
+		ChunkSize:  int32(cfg.ChunkSize),
+		Overlap:    int32(cfg.Overlap),
+		Separators: cfg.Separators,
+	}
+}
+
+// toKnowledgeCollection 将知识库记录转换为响应结构
+func toKnowledgeCollection(collection *mysql.KnowledgeCollection, documentCount int64, role authz.Role) *rag_svr.KnowledgeCollection {
+	return &rag_svr.KnowledgeCollection{
+		CollectionId:  collection.CollectionID,
+		UserId:        collection.UserID,
+		Name:          collection.Name,
+		Description:   collection.Description,
+		DocumentCount: documentCount,
+		CreateTime:    uint64(collection.CreatedAt.Unix()),
+		UpdateTime:    uint64(collection.UpdatedAt.Unix()),
+		Role:          role.String(),
+	}
+}
+
+func toAccessGrant(grant *mysql.AccessGrant) *rag_svr.AccessGrant {
+	return &rag_svr.AccessGrant{
+		Id:            grant.ID,
+		ResourceType:  grant.ResourceType,
+		ResourceId:    grant.ResourceID,
+		PrincipalType: grant.PrincipalType,
+		PrincipalId:   grant.PrincipalID,
+		Role:          grant.Role,
+		GrantedBy:     grant.GrantedBy,
+		CreateTime:    uint64(grant.CreatedAt.Unix()),
+		UpdateTime:    uint64(grant.UpdatedAt.Unix()),
+	}
+}
+
+func toUserGroup(group *mysql.UserGroup, memberIDs []uint64) *rag_svr.UserGroup {
+	return &rag_svr.UserGroup{
+		GroupId:    group.GroupID,
+		OwnerId:    group.OwnerID,
+		Name:       group.Name,
+		MemberIds:  memberIDs,
+		CreateTime: uint64(group.CreatedAt.Unix()),
+	}
+}
+
 // filterEmptyStrings 过滤空字符串
 func filterEmptyStrings(strs []string) []string {
 	var result []string
@@ -395,30 +863,25 @@ func filterEmptyStrings(strs []string) []string {
 // SearchDocument 实现搜索文档
 func (s *RagServiceImpl) SearchDocument(ctx context.Context, req *rag_svr.SearchDocumentReq) (resp *rag_svr.SearchDocumentRsp, err error) {
 	logger.Infof("搜索文档请求: user_id=%d, query=%s, top_k=%d", req.UserId, req.Query, req.TopK)
-	return ai.GetDocumentServiceInstance().SearchDocument(ctx, req)
-}
 
-// calculateRelevanceScore 计算相关性分数
-func calculateRelevanceScore(chunk mysql.DocumentChunk, queryKeywords []string) float32 {
-	// 解析块关键词
-	var chunkKeywords []string
-	if err := json.Unmarshal([]byte(chunk.Keywords), &chunkKeywords); err != nil {
-		return 0
-	}
-
-	// 计算关键词匹配度
-	matchedKeywords := 0
-	for _, qk := range queryKeywords {
-		for _, ck := range chunkKeywords {
-			if qk == ck {
-				matchedKeywords++
-				break
-			}
-		}
+	// 只检索用户自己的文档，必须指定用户
+	if req.UserId == 0 || req.Query == "" {
+		return &rag_svr.SearchDocumentRsp{
+			Code: 1,
+			Msg:  "用户ID和查询内容不能为空",
+		}, nil
+	}
+
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.SearchDocumentRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
 	}
+	ctx = usage.WithScope(ctx, req.UserId, 0)
 
-	// 计算分数 (关键词匹配度 / 查询关键词总数)
-	return float32(matchedKeywords) / float32(len(queryKeywords))
+	return ai.GetDocumentServiceInstance().SearchDocument(ctx, req)
 }
 
 // GetSessionList implements the RagServiceImpl interface.
@@ -560,11 +1023,42 @@ func (s *RagServiceImpl) CleanInactiveSessions(ctx context.Context, req *rag_svr
 
 // ListDocument 实现搜索文档
 func (s *RagServiceImpl) ListDocument(ctx context.Context, req *rag_svr.ListDocumentReq) (resp *rag_svr.ListDocumentRsp, err error) {
-	logger.Infof("获取文档列表请求: user_id=%d, page=%d, page_size=%d", req.UserId, req.Page, req.PageSize)
+	logger.Infof("获取文档列表请求: user_id=%d, collection_id=%d, collection_ids=%v, tags=%v, page=%d, page_size=%d",
+		req.UserId, req.CollectionId, req.CollectionIds, req.Tags, req.Page, req.PageSize)
 
-	// 获取用户的文档列表
+	if req.UserId == 0 {
+		return &rag_svr.ListDocumentRsp{
+			Code: 1,
+			Msg:  "用户ID不能为空",
+		}, nil
+	}
+
+	// 获取用户创建的和被共享的文档列表
+	scope, err := authz.LoadScope(ctx, req.UserId)
+	if err != nil {
+		logger.Errorf("加载授权范围失败: %v", err)
+		return &rag_svr.ListDocumentRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
 	var documents []mysql.Document
-	query := mysql.GetDB().Table("document").Model(&mysql.Document{})
+	filter := &ai.DocumentFilter{
+		Scope:         scope,
+		CollectionIDs: req.CollectionIds,
+		Tags:          req.Tags,
+		Metadata:      req.MetadataFilters,
+	}
+	if req.CollectionId != 0 {
+		filter.CollectionIDs = append(filter.CollectionIDs, req.CollectionId)
+	}
+	query, err := ai.FilterDocuments(mysql.GetDB().Table("document").Model(&mysql.Document{}), filter)
+	if err != nil {
+		return &rag_svr.ListDocumentRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
 
 	// 获取总数
 	var total int64
@@ -591,17 +1085,32 @@ func (s *RagServiceImpl) ListDocument(ctx context.Context, req *rag_svr.ListDocu
 
 	logger.Infof("获取文档列表成功: 总数=%d, 当前页=%d", total, len(documents))
 
+	docIDs := make([]uint64, len(documents))
+	for i, doc := range documents {
+		docIDs[i] = doc.DocID
+	}
+	tags, err := ai.LoadDocumentTags(ctx, docIDs)
+	if err != nil {
+		logger.Errorf("获取文档标签失败: %v", err)
+	}
+
 	// 构建响应
 	docList := make([]*rag_svr.Document, 0, len(documents))
-	for _, doc := range documents {
+	for i, doc := range documents {
 		docList = append(docList, &rag_svr.Document{
-			DocId:      doc.DocID,
-			UserId:     doc.UserID,
-			Title:      doc.Title,
-			Content:    "", // 文档内容不返回
-			Metadata:   doc.Metadata,
-			CreateTime: uint64(doc.CreatedAt.Unix()),
-			UpdateTime: uint64(doc.UpdatedAt.Unix()),
+			DocId:        doc.DocID,
+			UserId:       doc.UserID,
+			Title:        doc.Title,
+			Content:      "", // 文档内容不返回
+			Metadata:     doc.Metadata,
+			CreateTime:   uint64(doc.CreatedAt.Unix()),
+			UpdateTime:   uint64(doc.UpdatedAt.Unix()),
+			Status:       doc.Status,
+			Chunker:      toChunkerConfig(doc.Chunker),
+			Version:      doc.Version,
+			CollectionId: doc.CollectionID,
+			Tags:         tags[doc.DocID],
+			Role:         scope.DocumentRole(&documents[i]).String(),
 		})
 	}
 
@@ -722,38 +1231,468 @@ func (s *RagServiceImpl) DeleteDocument(ctx context.Context, req *rag_svr.Delete
 	return ai.GetDocumentServiceInstance().DeleteDocument(ctx, req)
 }
 
-// AddChatRecord 实现添加聊天记录
-func (s *RagServiceImpl) AddChatRecord(ctx context.Context, req *rag_svr.AddChatRecordReq) (resp *rag_svr.AddChatRecordRsp, err error) {
-	logger.Infof("添加聊天记录请求: session_id=%d, user_id=%d", req.SessionId, req.UserId)
+// MoveDocument 把文档移到另一个知识库
+func (s *RagServiceImpl) MoveDocument(ctx context.Context, req *rag_svr.MoveDocumentReq) (resp *rag_svr.MoveDocumentRsp, err error) {
+	logger.Infof("移动文档请求: doc_id=%d, user_id=%d, collection_id=%d", req.DocId, req.UserId, req.CollectionId)
 
-	chatRecordID := id_generator.GetInstance().GetChatRecordID()
-	if chatRecordID == 0 {
-		logger.Errorf("获取聊天记录ID失败")
-		return &rag_svr.AddChatRecordRsp{
+	if req.DocId == 0 || req.UserId == 0 {
+		return &rag_svr.MoveDocumentRsp{
 			Code: 1,
-			Msg:  "获取聊天记录ID失败",
+			Msg:  "文档ID和用户ID不能为空",
 		}, nil
 	}
 
-	// 创建聊天记录
-	chatRecord := &mysql.ChatRecord{
-		ID:            chatRecordID,
-		SessionID:     req.SessionId,
-		UserID:        req.UserId,
-		Message:       req.Message,
-		Response:      req.Response,
-		MessageType:   req.MessageType,
-		Status:        "completed",
-		Context:       req.Context,
-		FunctionCalls: req.FunctionCalls,
-		Metadata:      req.Metadata,
+	var doc mysql.Document
+	if err := mysql.GetDB().Table("document").First(&doc, req.DocId).Error; err != nil {
+		logger.Errorf("获取文档信息失败: %v", err)
+		return &rag_svr.MoveDocumentRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
+		}, nil
+	}
+	// 只有文档的所有者可以移动，且须是目标知识库的编辑者
+	if _, err := authz.CheckDocument(ctx, req.UserId, &doc, authz.RoleOwner); err != nil {
+		logger.Errorf("用户无权限移动该文档: user_id=%d, doc_id=%d, err=%v", req.UserId, doc.DocID, err)
+		code, msg := ai.AuthzFailure(err, "无权限修改该文档")
+		return &rag_svr.MoveDocumentRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
 	}
 
-	if err := mysql.GetDB().Table("chat_record").Create(chatRecord).Error; err != nil {
-		logger.Errorf("创建聊天记录失败: %v", err)
-		return &rag_svr.AddChatRecordRsp{
-			Code: 1,
-			Msg:  fmt.Sprintf("创建聊天记录失败: %v", err),
+	if err := ai.GetDocumentServiceInstance().MoveDocument(ctx, &doc, req.UserId, req.CollectionId); err != nil {
+		logger.Errorf("移动文档失败: doc_id=%d, err=%v", req.DocId, err)
+		code, msg := ai.AuthzFailure(err, "无权限向该知识库添加文档")
+		return &rag_svr.MoveDocumentRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	return &rag_svr.MoveDocumentRsp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
+
+// SetDocumentTags 替换文档的全部标签
+func (s *RagServiceImpl) SetDocumentTags(ctx context.Context, req *rag_svr.SetDocumentTagsReq) (resp *rag_svr.SetDocumentTagsRsp, err error) {
+	logger.Infof("设置文档标签请求: doc_id=%d, user_id=%d, tags=%v", req.DocId, req.UserId, req.Tags)
+
+	if req.DocId == 0 || req.UserId == 0 {
+		return &rag_svr.SetDocumentTagsRsp{
+			Code: 1,
+			Msg:  "文档ID和用户ID不能为空",
+		}, nil
+	}
+
+	var doc mysql.Document
+	if err := mysql.GetDB().Table("document").First(&doc, req.DocId).Error; err != nil {
+		logger.Errorf("获取文档信息失败: %v", err)
+		return &rag_svr.SetDocumentTagsRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取文档信息失败: %v", err),
+		}, nil
+	}
+	if _, err := authz.CheckDocument(ctx, req.UserId, &doc, authz.RoleEditor); err != nil {
+		logger.Errorf("用户无权限修改该文档: user_id=%d, doc_id=%d, err=%v", req.UserId, doc.DocID, err)
+		code, msg := ai.AuthzFailure(err, "无权限修改该文档")
+		return &rag_svr.SetDocumentTagsRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+
+	tags, err := ai.GetDocumentServiceInstance().SetDocumentTags(ctx, &doc, req.Tags)
+	if err != nil {
+		logger.Errorf("设置文档标签失败: doc_id=%d, err=%v", req.DocId, err)
+		return &rag_svr.SetDocumentTagsRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	return &rag_svr.SetDocumentTagsRsp{
+		Code: 0,
+		Msg:  "success",
+		Tags: tags,
+	}, nil
+}
+
+// CreateCollection 创建知识库
+func (s *RagServiceImpl) CreateCollection(ctx context.Context, req *rag_svr.CreateCollectionReq) (resp *rag_svr.CreateCollectionRsp, err error) {
+	logger.Infof("创建知识库请求: user_id=%d, name=%s", req.UserId, req.Name)
+
+	if req.UserId == 0 {
+		return &rag_svr.CreateCollectionRsp{
+			Code: 1,
+			Msg:  "用户ID不能为空",
+		}, nil
+	}
+
+	collection, err := ai.GetDocumentServiceInstance().CreateCollection(ctx, req.UserId, req.Name, req.Description)
+	if err != nil {
+		logger.Errorf("创建知识库失败: %v", err)
+		return &rag_svr.CreateCollectionRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	return &rag_svr.CreateCollectionRsp{
+		Code:       0,
+		Msg:        "success",
+		Collection: toKnowledgeCollection(collection, 0, authz.RoleOwner),
+	}, nil
+}
+
+// ListCollections 获取用户创建的和被共享的知识库列表
+func (s *RagServiceImpl) ListCollections(ctx context.Context, req *rag_svr.ListCollectionsReq) (resp *rag_svr.ListCollectionsRsp, err error) {
+	logger.Infof("获取知识库列表请求: user_id=%d", req.UserId)
+
+	if req.UserId == 0 {
+		return &rag_svr.ListCollectionsRsp{
+			Code: 1,
+			Msg:  "用户ID不能为空",
+		}, nil
+	}
+
+	scope, err := authz.LoadScope(ctx, req.UserId)
+	if err != nil {
+		logger.Errorf("加载授权范围失败: %v", err)
+		return &rag_svr.ListCollectionsRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	collections, counts, err := ai.GetDocumentServiceInstance().ListCollections(ctx, scope)
+	if err != nil {
+		logger.Errorf("获取知识库列表失败: %v", err)
+		return &rag_svr.ListCollectionsRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	list := make([]*rag_svr.KnowledgeCollection, 0, len(collections))
+	for i := range collections {
+		list = append(list, toKnowledgeCollection(&collections[i], counts[collections[i].CollectionID], scope.CollectionRole(&collections[i])))
+	}
+	return &rag_svr.ListCollectionsRsp{
+		Code:        0,
+		Msg:         "success",
+		Collections: list,
+	}, nil
+}
+
+// UpdateCollection 修改知识库名称和描述
+func (s *RagServiceImpl) UpdateCollection(ctx context.Context, req *rag_svr.UpdateCollectionReq) (resp *rag_svr.UpdateCollectionRsp, err error) {
+	logger.Infof("修改知识库请求: collection_id=%d, user_id=%d, name=%s", req.CollectionId, req.UserId, req.Name)
+
+	if req.CollectionId == 0 || req.UserId == 0 {
+		return &rag_svr.UpdateCollectionRsp{
+			Code: 1,
+			Msg:  "知识库ID和用户ID不能为空",
+		}, nil
+	}
+
+	service := ai.GetDocumentServiceInstance()
+	collection, role, err := service.UpdateCollection(ctx, req.CollectionId, req.UserId, req.Name, req.Description)
+	if err != nil {
+		logger.Errorf("修改知识库失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "无权限修改该知识库")
+		return &rag_svr.UpdateCollectionRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	count, err := service.CountCollectionDocuments(ctx, req.CollectionId)
+	if err != nil {
+		logger.Errorf("统计知识库文档数失败: %v", err)
+	}
+	return &rag_svr.UpdateCollectionRsp{
+		Code:       0,
+		Msg:        "success",
+		Collection: toKnowledgeCollection(collection, count, role),
+	}, nil
+}
+
+// DeleteCollection 删除知识库，知识库中还有文档时不能删除
+func (s *RagServiceImpl) DeleteCollection(ctx context.Context, req *rag_svr.DeleteCollectionReq) (resp *rag_svr.DeleteCollectionRsp, err error) {
+	logger.Infof("删除知识库请求: collection_id=%d, user_id=%d", req.CollectionId, req.UserId)
+
+	if req.CollectionId == 0 || req.UserId == 0 {
+		return &rag_svr.DeleteCollectionRsp{
+			Code: 1,
+			Msg:  "知识库ID和用户ID不能为空",
+		}, nil
+	}
+
+	if err := ai.GetDocumentServiceInstance().DeleteCollection(ctx, req.CollectionId, req.UserId); err != nil {
+		logger.Errorf("删除知识库失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "无权限删除该知识库")
+		return &rag_svr.DeleteCollectionRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	return &rag_svr.DeleteCollectionRsp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
+
+// GrantAccess 把知识库或文档的角色授予用户或用户组
+func (s *RagServiceImpl) GrantAccess(ctx context.Context, req *rag_svr.GrantAccessReq) (resp *rag_svr.GrantAccessRsp, err error) {
+	logger.Infof("授权请求: user_id=%d, resource=%s/%d, principal=%s/%d, role=%s",
+		req.UserId, req.ResourceType, req.ResourceId, req.PrincipalType, req.PrincipalId, req.Role)
+
+	if req.UserId == 0 || req.ResourceId == 0 || req.PrincipalId == 0 {
+		return &rag_svr.GrantAccessRsp{
+			Code: 1,
+			Msg:  "用户ID、资源ID和授权对象ID不能为空",
+		}, nil
+	}
+
+	grant := &mysql.AccessGrant{
+		ResourceType:  req.ResourceType,
+		ResourceID:    req.ResourceId,
+		PrincipalType: req.PrincipalType,
+		PrincipalID:   req.PrincipalId,
+		Role:          req.Role,
+	}
+	if err := authz.Grant(ctx, req.UserId, grant); err != nil {
+		logger.Errorf("授权失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "只有所有者可以共享")
+		return &rag_svr.GrantAccessRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	return &rag_svr.GrantAccessRsp{
+		Code:  0,
+		Msg:   "success",
+		Grant: toAccessGrant(grant),
+	}, nil
+}
+
+// RevokeAccess 撤销用户或用户组在知识库或文档上的授权
+func (s *RagServiceImpl) RevokeAccess(ctx context.Context, req *rag_svr.RevokeAccessReq) (resp *rag_svr.RevokeAccessRsp, err error) {
+	logger.Infof("撤销授权请求: user_id=%d, resource=%s/%d, principal=%s/%d",
+		req.UserId, req.ResourceType, req.ResourceId, req.PrincipalType, req.PrincipalId)
+
+	if req.UserId == 0 || req.ResourceId == 0 || req.PrincipalId == 0 {
+		return &rag_svr.RevokeAccessRsp{
+			Code: 1,
+			Msg:  "用户ID、资源ID和授权对象ID不能为空",
+		}, nil
+	}
+
+	if err := authz.Revoke(ctx, req.UserId, req.ResourceType, req.ResourceId, req.PrincipalType, req.PrincipalId); err != nil {
+		logger.Errorf("撤销授权失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "只有所有者可以撤销授权")
+		return &rag_svr.RevokeAccessRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	return &rag_svr.RevokeAccessRsp{
+		Code: 0,
+		Msg:  "success",
+	}, nil
+}
+
+// ListAccess 获取知识库或文档的授权列表
+func (s *RagServiceImpl) ListAccess(ctx context.Context, req *rag_svr.ListAccessReq) (resp *rag_svr.ListAccessRsp, err error) {
+	logger.Infof("获取授权列表请求: user_id=%d, resource=%s/%d", req.UserId, req.ResourceType, req.ResourceId)
+
+	if req.UserId == 0 || req.ResourceId == 0 {
+		return &rag_svr.ListAccessRsp{
+			Code: 1,
+			Msg:  "用户ID和资源ID不能为空",
+		}, nil
+	}
+
+	ownerID, grants, err := authz.ListGrants(ctx, req.UserId, req.ResourceType, req.ResourceId)
+	if err != nil {
+		logger.Errorf("获取授权列表失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "无权限访问该资源")
+		return &rag_svr.ListAccessRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	list := make([]*rag_svr.AccessGrant, 0, len(grants))
+	for i := range grants {
+		list = append(list, toAccessGrant(&grants[i]))
+	}
+	return &rag_svr.ListAccessRsp{
+		Code:    0,
+		Msg:     "success",
+		OwnerId: ownerID,
+		Grants:  list,
+	}, nil
+}
+
+// CreateGroup 创建用户组
+func (s *RagServiceImpl) CreateGroup(ctx context.Context, req *rag_svr.CreateGroupReq) (resp *rag_svr.CreateGroupRsp, err error) {
+	logger.Infof("创建用户组请求: user_id=%d, name=%s, members=%v", req.UserId, req.Name, req.MemberIds)
+
+	if req.UserId == 0 {
+		return &rag_svr.CreateGroupRsp{
+			Code: 1,
+			Msg:  "用户ID不能为空",
+		}, nil
+	}
+
+	group, err := authz.CreateGroup(ctx, req.UserId, req.Name, req.MemberIds)
+	if err != nil {
+		logger.Errorf("创建用户组失败: %v", err)
+		return &rag_svr.CreateGroupRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	return &rag_svr.CreateGroupRsp{
+		Code:  0,
+		Msg:   "success",
+		Group: s.loadUserGroup(ctx, group),
+	}, nil
+}
+
+// AddGroupMembers 向用户组添加成员
+func (s *RagServiceImpl) AddGroupMembers(ctx context.Context, req *rag_svr.AddGroupMembersReq) (resp *rag_svr.AddGroupMembersRsp, err error) {
+	logger.Infof("添加用户组成员请求: user_id=%d, group_id=%d, members=%v", req.UserId, req.GroupId, req.MemberIds)
+
+	if req.UserId == 0 || req.GroupId == 0 {
+		return &rag_svr.AddGroupMembersRsp{
+			Code: 1,
+			Msg:  "用户ID和用户组ID不能为空",
+		}, nil
+	}
+
+	group, err := authz.AddGroupMembers(ctx, req.UserId, req.GroupId, req.MemberIds)
+	if err != nil {
+		logger.Errorf("添加用户组成员失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "只有创建者可以管理用户组")
+		return &rag_svr.AddGroupMembersRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	return &rag_svr.AddGroupMembersRsp{
+		Code:  0,
+		Msg:   "success",
+		Group: s.loadUserGroup(ctx, group),
+	}, nil
+}
+
+// RemoveGroupMembers 从用户组移除成员
+func (s *RagServiceImpl) RemoveGroupMembers(ctx context.Context, req *rag_svr.RemoveGroupMembersReq) (resp *rag_svr.RemoveGroupMembersRsp, err error) {
+	logger.Infof("移除用户组成员请求: user_id=%d, group_id=%d, members=%v", req.UserId, req.GroupId, req.MemberIds)
+
+	if req.UserId == 0 || req.GroupId == 0 {
+		return &rag_svr.RemoveGroupMembersRsp{
+			Code: 1,
+			Msg:  "用户ID和用户组ID不能为空",
+		}, nil
+	}
+
+	group, err := authz.RemoveGroupMembers(ctx, req.UserId, req.GroupId, req.MemberIds)
+	if err != nil {
+		logger.Errorf("移除用户组成员失败: %v", err)
+		code, msg := ai.AuthzFailure(err, "只有创建者可以管理用户组")
+		return &rag_svr.RemoveGroupMembersRsp{
+			Code: code,
+			Msg:  msg,
+		}, nil
+	}
+	return &rag_svr.RemoveGroupMembersRsp{
+		Code:  0,
+		Msg:   "success",
+		Group: s.loadUserGroup(ctx, group),
+	}, nil
+}
+
+// ListGroups 获取用户所在的用户组
+func (s *RagServiceImpl) ListGroups(ctx context.Context, req *rag_svr.ListGroupsReq) (resp *rag_svr.ListGroupsRsp, err error) {
+	logger.Infof("获取用户组列表请求: user_id=%d", req.UserId)
+
+	if req.UserId == 0 {
+		return &rag_svr.ListGroupsRsp{
+			Code: 1,
+			Msg:  "用户ID不能为空",
+		}, nil
+	}
+
+	groups, err := authz.ListGroups(ctx, req.UserId)
+	if err != nil {
+		logger.Errorf("获取用户组列表失败: %v", err)
+		return &rag_svr.ListGroupsRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	groupIDs := make([]uint64, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.GroupID
+	}
+	members, err := authz.GroupMembers(ctx, groupIDs)
+	if err != nil {
+		logger.Errorf("获取用户组成员失败: %v", err)
+		return &rag_svr.ListGroupsRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+	list := make([]*rag_svr.UserGroup, 0, len(groups))
+	for i := range groups {
+		list = append(list, toUserGroup(&groups[i], members[groups[i].GroupID]))
+	}
+	return &rag_svr.ListGroupsRsp{
+		Code:   0,
+		Msg:    "success",
+		Groups: list,
+	}, nil
+}
+
+// loadUserGroup 附带成员列表返回用户组，获取成员失败时只记录日志
+func (s *RagServiceImpl) loadUserGroup(ctx context.Context, group *mysql.UserGroup) *rag_svr.UserGroup {
+	members, err := authz.GroupMembers(ctx, []uint64{group.GroupID})
+	if err != nil {
+		logger.Errorf("获取用户组成员失败: group_id=%d, err=%v", group.GroupID, err)
+	}
+	return toUserGroup(group, members[group.GroupID])
+}
+
+// AddChatRecord 实现添加聊天记录
+func (s *RagServiceImpl) AddChatRecord(ctx context.Context, req *rag_svr.AddChatRecordReq) (resp *rag_svr.AddChatRecordRsp, err error) {
+	logger.Infof("添加聊天记录请求: session_id=%d, user_id=%d", req.SessionId, req.UserId)
+
+	chatRecordID := id_generator.GetInstance().GetChatRecordID()
+	if chatRecordID == 0 {
+		logger.Errorf("获取聊天记录ID失败")
+		return &rag_svr.AddChatRecordRsp{
+			Code: 1,
+			Msg:  "获取聊天记录ID失败",
+		}, nil
+	}
+
+	// 创建聊天记录
+	chatRecord := &mysql.ChatRecord{
+		ID:            chatRecordID,
+		SessionID:     req.SessionId,
+		UserID:        req.UserId,
+		Message:       req.Message,
+		Response:      req.Response,
+		MessageType:   req.MessageType,
+		Status:        "completed",
+		Context:       req.Context,
+		FunctionCalls: req.FunctionCalls,
+		Metadata:      req.Metadata,
+	}
+
+	if err := mysql.GetDB().Table("chat_record").Create(chatRecord).Error; err != nil {
+		logger.Errorf("创建聊天记录失败: %v", err)
+		return &rag_svr.AddChatRecordRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("创建聊天记录失败: %v", err),
 		}, nil
 	}
 
@@ -824,10 +1763,496 @@ func (s *RagServiceImpl) GetChatRecords(ctx context.Context, req *rag_svr.GetCha
 	}, nil
 }
 
+const (
+	// 对话默认参数
+	defaultChatHistoryLimit = 10
+	defaultChatMemoryLimit  = 5
+	defaultChatDocTopK      = 3
+)
+
+// Chat 实现一次完整的 RAG 对话
+func (s *RagServiceImpl) Chat(ctx context.Context, req *rag_svr.ChatReq) (resp *rag_svr.ChatRsp, err error) {
+	logger.Infof("对话请求: session_id=%d, user_id=%d", req.SessionId, req.UserId)
+
+	session, err := s.checkChatReq(req)
+	if err != nil {
+		logger.Errorf("对话请求校验失败: %v", err)
+		return &rag_svr.ChatRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+
+	// 检查配额，本次对话的模型用量归属到该用户和会话
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.ChatRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithScope(ctx, req.UserId, req.SessionId)
+	// 工具按发起对话的用户执行，不使用模型参数中的用户
+	ctx = ai.WithToolCaller(ctx, ai.ToolCaller{UserID: req.UserId, SessionID: req.SessionId, CollectionIDs: req.CollectionIds})
+
+	// 组装提示词和引用
+	messages, citations, err := s.buildChatMessages(ctx, req, session, true)
+	if err != nil {
+		logger.Errorf("构建对话上下文失败: %v", err)
+		return &rag_svr.ChatRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("构建对话上下文失败: %v", err),
+		}, nil
+	}
+
+	// 调用大模型，执行模型请求的工具调用
+	result, traces, err := ai.ChatWithTools(ctx, s.llm, messages, config.GlobalConfig.AI.ChatModel.MaxToolRounds)
+	if err != nil {
+		logger.Errorf("调用大模型失败: %v", err)
+		return &rag_svr.ChatRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用大模型失败: %v", err),
+		}, nil
+	}
+	answer := result.Content
+
+	// 持久化本轮对话
+	chatID, err := s.saveChatTurn(ctx, req.SessionId, req.UserId, req.Message, answer, citations, traces, result.Usage)
+	if err != nil {
+		logger.Errorf("保存对话记录失败: %v", err)
+		return &rag_svr.ChatRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("保存对话记录失败: %v", err),
+		}, nil
+	}
+
+	logger.Infof("对话完成: chat_id=%d, 引用数=%d, 工具调用数=%d, tokens=%d", chatID, len(citations), len(traces), result.Usage.TotalTokens)
+
+	return &rag_svr.ChatRsp{
+		Code:      0,
+		Msg:       "success",
+		ChatId:    chatID,
+		Response:  answer,
+		Citations: citations,
+	}, nil
+}
+
+// StreamChat 实现流式 RAG 对话
+func (s *RagServiceImpl) StreamChat(req *rag_svr.ChatReq, stream rag_svr.RagService_StreamChatServer) (err error) {
+	logger.Infof("流式对话请求: session_id=%d, user_id=%d", req.SessionId, req.UserId)
+
+	// 客户端断开时 stream 的 ctx 会被取消，上游请求随之取消
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	session, err := s.checkChatReq(req)
+	if err != nil {
+		logger.Errorf("对话请求校验失败: %v", err)
+		return stream.Send(&rag_svr.StreamChatRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		})
+	}
+
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return stream.Send(&rag_svr.StreamChatRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		})
+	}
+	ctx = usage.WithScope(ctx, req.UserId, req.SessionId)
+	ctx = ai.WithToolCaller(ctx, ai.ToolCaller{UserID: req.UserId, SessionID: req.SessionId, CollectionIDs: req.CollectionIds})
+
+	messages, citations, err := s.buildChatMessages(ctx, req, session, false)
+	if err != nil {
+		logger.Errorf("构建对话上下文失败: %v", err)
+		return stream.Send(&rag_svr.StreamChatRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("构建对话上下文失败: %v", err),
+		})
+	}
+
+	responseChan, errorChan := s.llm.StreamChat(ctx, messages)
+
+	var answer strings.Builder
+	var tokenUsage ai.QwenUsage
+	for chunk := range responseChan {
+		if chunk.Usage != nil {
+			tokenUsage = *chunk.Usage
+		}
+		if chunk.Delta == "" {
+			continue
+		}
+		answer.WriteString(chunk.Delta)
+		if err := stream.Send(&rag_svr.StreamChatRsp{
+			Code:  0,
+			Msg:   "success",
+			Delta: chunk.Delta,
+		}); err != nil {
+			logger.Errorf("发送流式分片失败，客户端可能已断开: %v", err)
+			return err
+		}
+	}
+
+	if err := <-errorChan; err != nil {
+		logger.Errorf("流式调用大模型失败: %v", err)
+		return stream.Send(&rag_svr.StreamChatRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("调用大模型失败: %v", err),
+		})
+	}
+	if ctx.Err() != nil {
+		logger.Infof("流式对话被取消: session_id=%d, 已生成%d字节", req.SessionId, answer.Len())
+		return ctx.Err()
+	}
+
+	// 生成结束后保存完整回复
+	chatID, err := s.saveChatTurn(ctx, req.SessionId, req.UserId, req.Message, answer.String(), citations, nil, tokenUsage)
+	if err != nil {
+		logger.Errorf("保存对话记录失败: %v", err)
+		return stream.Send(&rag_svr.StreamChatRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("保存对话记录失败: %v", err),
+		})
+	}
+
+	logger.Infof("流式对话完成: chat_id=%d, 引用数=%d", chatID, len(citations))
+
+	return stream.Send(&rag_svr.StreamChatRsp{
+		Code:      0,
+		Msg:       "success",
+		Finished:  true,
+		ChatId:    chatID,
+		Citations: citations,
+	})
+}
+
+// checkChatReq 校验对话参数和会话归属，返回会话
+func (s *RagServiceImpl) checkChatReq(req *rag_svr.ChatReq) (*mysql.ChatSession, error) {
+	if req.SessionId == 0 || req.UserId == 0 || strings.TrimSpace(req.Message) == "" {
+		return nil, fmt.Errorf("会话ID、用户ID和消息不能为空")
+	}
+
+	var session mysql.ChatSession
+	if err := mysql.GetDB().Table("chat_session").Model(&mysql.ChatSession{}).First(&session, req.SessionId).Error; err != nil {
+		return nil, fmt.Errorf("获取会话信息失败: %v", err)
+	}
+	if session.UserID != req.UserId {
+		return nil, fmt.Errorf("无权限访问该会话")
+	}
+	return &session, nil
+}
+
+// buildChatMessages 加载摘要和历史、检索记忆和文档，生成发送给模型的消息列表
+func (s *RagServiceImpl) buildChatMessages(ctx context.Context, req *rag_svr.ChatReq, session *mysql.ChatSession, withTools bool) ([]ai.QwenMessage, []*rag_svr.Citation, error) {
+	historyLimit := int(req.HistoryLimit)
+	if historyLimit <= 0 {
+		historyLimit = defaultChatHistoryLimit
+	}
+	memoryLimit := req.MemoryLimit
+	if memoryLimit <= 0 {
+		memoryLimit = defaultChatMemoryLimit
+	}
+	docTopK := req.DocTopK
+	if docTopK <= 0 {
+		docTopK = defaultChatDocTopK
+	}
+
+	// 加载最近的对话记录，已折叠进摘要的记录由摘要代替
+	var records []mysql.ChatRecord
+	watermark := ai.LoadSummaryWatermark(ctx, session)
+	if err := watermark.After(mysql.GetDB().Table("chat_record").Model(&mysql.ChatRecord{})).
+		Where("session_id = ?", req.SessionId).
+		Order("created_at DESC, id DESC").
+		Limit(historyLimit).
+		Find(&records).Error; err != nil {
+		return nil, nil, fmt.Errorf("获取对话记录失败: %v", err)
+	}
+
+	history := make([]ai.PromptTurn, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		history = append(history, ai.PromptTurn{
+			User:      records[i].Message,
+			Assistant: records[i].Response,
+		})
+	}
+
+	input := &ai.PromptInput{
+		SystemPrompt: ai.SystemPrompt,
+		Summary:      session.Summary,
+		History:      history,
+		UserID:       req.UserId,
+		SessionID:    req.SessionId,
+		Message:      req.Message,
+		Now:          time.Now(),
+	}
+	if withTools {
+		input.ReserveTokens = s.promptBuilder.EstimateToolTokens()
+	}
+
+	// 检索相关记忆，失败时不影响对话
+	var memories []*rag_svr.Memory
+	memRsp, err := s.SearchMemories(ctx, &rag_svr.SearchMemoriesReq{
+		SeqId:  req.SeqId,
+		UserId: req.UserId,
+		Query:  req.Message,
+		Limit:  memoryLimit,
+	})
+	if err != nil || memRsp.Code != 0 {
+		logger.Errorf("检索记忆失败: err=%v, rsp=%v", err, memRsp)
+	} else {
+		memories = memRsp.Memories
+		for _, mem := range memories {
+			input.Memories = append(input.Memories, ai.KnowledgeItem{
+				Label:   "记忆/" + mem.MemoryType,
+				Content: mem.Content,
+			})
+		}
+	}
+
+	// 检索相关文档块，失败时不影响对话
+	var documents []*rag_svr.Document
+	var scores []float32
+	var hits []*rag_svr.SearchHit
+	docRsp, err := ai.GetDocumentServiceInstance().SearchDocument(ctx, &rag_svr.SearchDocumentReq{
+		SeqId:         req.SeqId,
+		UserId:        req.UserId,
+		Query:         req.Message,
+		TopK:          docTopK,
+		CollectionIds: req.CollectionIds,
+	})
+	if err != nil || docRsp.Code != 0 {
+		logger.Errorf("检索文档失败: err=%v, rsp=%v", err, docRsp)
+	} else {
+		documents, scores, hits = docRsp.Documents, docRsp.Scores, docRsp.Hits
+		for _, doc := range documents {
+			input.Documents = append(input.Documents, ai.KnowledgeItem{
+				Label:   "文档《" + doc.Title + "》",
+				Content: doc.Content,
+			})
+		}
+	}
+
+	// 按上下文窗口预算组装，引用只包含实际注入的知识，编号与提示词一致
+	prompt := s.promptBuilder.Build(input)
+	citations := make([]*rag_svr.Citation, 0, len(prompt.UsedMemories)+len(prompt.UsedDocuments))
+	for _, i := range prompt.UsedMemories {
+		citations = append(citations, &rag_svr.Citation{
+			SourceType: "memory",
+			SourceId:   memories[i].MemoryId,
+			Content:    memories[i].Content,
+		})
+	}
+	for _, i := range prompt.UsedDocuments {
+		var score float32
+		if i < len(scores) {
+			score = scores[i]
+		}
+		citation := &rag_svr.Citation{
+			SourceType: "document",
+			SourceId:   documents[i].DocId,
+			Title:      documents[i].Title,
+			Content:    strings.TrimSpace(documents[i].Content),
+			Score:      score,
+		}
+		if i < len(hits) {
+			citation.ParagraphId = hits[i].ParagraphId
+			citation.SentenceIdMin = hits[i].SentenceIdMin
+			citation.SentenceIdMax = hits[i].SentenceIdMax
+		}
+		citations = append(citations, citation)
+	}
+
+	if prompt.DroppedTurns > 0 {
+		logger.Infof("历史对话超出预算: session_id=%d, 保留%d轮, 丢弃%d轮", req.SessionId, prompt.HistoryTurns, prompt.DroppedTurns)
+	}
+	logger.Debugf("提示词构建完成: session_id=%d, 估算tokens=%d, 引用数=%d", req.SessionId, prompt.EstimatedTokens, len(citations))
+
+	return prompt.Messages, citations, nil
+}
+
+// saveChatTurn 保存一轮对话，并异步提取记忆
+func (s *RagServiceImpl) saveChatTurn(ctx context.Context, sessionID, userID uint64, message, answer string, citations []*rag_svr.Citation, traces []*ai.ToolCallTrace, tokenUsage ai.QwenUsage) (uint64, error) {
+	citationsJSON, err := json.Marshal(citations)
+	if err != nil {
+		return 0, fmt.Errorf("序列化引用失败: %v", err)
+	}
+	functionCalls := "[]"
+	if len(traces) > 0 {
+		tracesJSON, err := json.Marshal(traces)
+		if err != nil {
+			return 0, fmt.Errorf("序列化工具调用轨迹失败: %v", err)
+		}
+		functionCalls = string(tracesJSON)
+	}
+	usageJSON, err := json.Marshal(tokenUsage)
+	if err != nil {
+		return 0, fmt.Errorf("序列化用量失败: %v", err)
+	}
+
+	recordRsp, err := s.AddChatRecord(ctx, &rag_svr.AddChatRecordReq{
+		SessionId:     sessionID,
+		UserId:        userID,
+		Message:       message,
+		Response:      answer,
+		MessageType:   "text",
+		Context:       string(citationsJSON),
+		FunctionCalls: functionCalls,
+		Metadata:      fmt.Sprintf(`{"usage":%s}`, usageJSON),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if recordRsp.Code != 0 {
+		return 0, fmt.Errorf("%s", recordRsp.Msg)
+	}
+
+	// 更新会话活跃时间
+	if err := mysql.GetDB().Table("chat_session").Model(&mysql.ChatSession{}).
+		Where("id = ?", sessionID).
+		Update("last_active_time", time.Now()).Error; err != nil {
+		logger.Errorf("更新会话活跃时间失败: %v", err)
+	}
+
+	// 生成标题、提取记忆和更新会话摘要不阻塞响应
+	go func() {
+		ctx := usage.WithScope(context.Background(), userID, sessionID)
+		if err := ai.GenerateSessionTitle(ctx, sessionID, message, answer); err != nil {
+			logger.Errorf("生成会话标题失败: session_id=%d, err=%v", sessionID, err)
+		}
+		if err := s.extractAndStoreMemories(ctx, userID, sessionID, message, answer); err != nil {
+			logger.Errorf("提取记忆失败: %v", err)
+		}
+		if err := ai.SummarizeSession(ctx, sessionID); err != nil {
+			logger.Errorf("更新会话摘要失败: session_id=%d, err=%v", sessionID, err)
+		}
+	}()
+
+	return recordRsp.ChatId, nil
+}
+
+// GetUsage 查询用户的模型用量和配额
+func (s *RagServiceImpl) GetUsage(ctx context.Context, req *rag_svr.GetUsageReq) (resp *rag_svr.GetUsageRsp, err error) {
+	logger.Infof("查询用量请求: user_id=%d, start_date=%s, end_date=%s", req.UserId, req.StartDate, req.EndDate)
+
+	if req.UserId == 0 {
+		return &rag_svr.GetUsageRsp{
+			Code: 1,
+			Msg:  "用户ID不能为空",
+		}, nil
+	}
+
+	// 默认查询本月
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	if req.StartDate != "" {
+		if start, err = time.ParseInLocation("2006-01-02", req.StartDate, now.Location()); err != nil {
+			return &rag_svr.GetUsageRsp{
+				Code: 1,
+				Msg:  fmt.Sprintf("开始日期格式错误: %v", err),
+			}, nil
+		}
+	}
+	if req.EndDate != "" {
+		if end, err = time.ParseInLocation("2006-01-02", req.EndDate, now.Location()); err != nil {
+			return &rag_svr.GetUsageRsp{
+				Code: 1,
+				Msg:  fmt.Sprintf("结束日期格式错误: %v", err),
+			}, nil
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+
+	daily, monthly, err := usage.GetCounters(ctx, req.UserId)
+	if err != nil {
+		logger.Errorf("获取用量计数失败: %v", err)
+		return &rag_svr.GetUsageRsp{
+			Code: 1,
+			Msg:  fmt.Sprintf("获取用量计数失败: %v", err),
+		}, nil
+	}
+
+	stats, err := usage.Summarize(ctx, req.UserId, start, end)
+	if err != nil {
+		logger.Errorf("汇总用量失败: %v", err)
+		return &rag_svr.GetUsageRsp{
+			Code: 1,
+			Msg:  err.Error(),
+		}, nil
+	}
+
+	usageStats := make([]*rag_svr.ModelUsageStat, 0, len(stats))
+	for _, stat := range stats {
+		usageStats = append(usageStats, &rag_svr.ModelUsageStat{
+			Model:        stat.Model,
+			CallType:     stat.CallType,
+			InputTokens:  stat.InputTokens,
+			OutputTokens: stat.OutputTokens,
+			TotalTokens:  stat.TotalTokens,
+			CallCount:    stat.CallCount,
+		})
+	}
+
+	dailyQuota, monthlyQuota := usage.Quota()
+	return &rag_svr.GetUsageRsp{
+		Code:          0,
+		Msg:           "success",
+		DailyTokens:   daily,
+		DailyQuota:    dailyQuota,
+		MonthlyTokens: monthly,
+		MonthlyQuota:  monthlyQuota,
+		Stats:         usageStats,
+	}, nil
+}
+
+// GetModelStatus implements the RagServiceImpl interface.
+func (s *RagServiceImpl) GetModelStatus(ctx context.Context, req *rag_svr.GetModelStatusReq) (resp *rag_svr.GetModelStatusRsp, err error) {
+	status := ai.GetModelStatus()
+	return &rag_svr.GetModelStatusRsp{
+		Code:            0,
+		Msg:             "success",
+		ChatModels:      toModelBreakerStats(status.Chat),
+		EmbeddingModels: toModelBreakerStats(status.Embedding),
+	}, nil
+}
+
+func toModelBreakerStats(stats []ai.BreakerStats) []*rag_svr.ModelBreakerStat {
+	result := make([]*rag_svr.ModelBreakerStat, 0, len(stats))
+	for _, stat := range stats {
+		var openedAt uint64
+		if !stat.OpenedAt.IsZero() {
+			openedAt = uint64(stat.OpenedAt.Unix())
+		}
+		result = append(result, &rag_svr.ModelBreakerStat{
+			Name:                stat.Name,
+			State:               stat.State,
+			ConsecutiveFailures: int32(stat.ConsecutiveFailures),
+			OpenedAt:            openedAt,
+			Requests:            stat.Requests,
+			Failures:            stat.Failures,
+			Rejected:            stat.Rejected,
+			Fallbacks:           stat.Fallbacks,
+		})
+	}
+	return result
+}
+
 // AddMemory implements the RagServiceImpl interface.
 func (s *RagServiceImpl) AddMemory(ctx context.Context, req *rag_svr.AddMemoryReq) (resp *rag_svr.AddMemoryRsp, err error) {
 	logger.Infof("添加记忆请求: user_id=%d, memory_type=%s", req.UserId, req.MemoryType)
 
+	// 记忆向量化计入用户配额
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.AddMemoryRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithScope(ctx, req.UserId, req.SessionId)
+
 	// 获取记忆管理器实例
 	memoryManager := memory.GetInstance()
 
@@ -941,6 +2366,16 @@ func (s *RagServiceImpl) GetMemory(ctx context.Context, req *rag_svr.GetMemoryRe
 func (s *RagServiceImpl) SearchMemories(ctx context.Context, req *rag_svr.SearchMemoriesReq) (resp *rag_svr.SearchMemoriesRsp, err error) {
 	logger.Infof("搜索记忆请求: query=%s, limit=%d", req.Query, req.Limit)
 
+	// 查询向量化计入用户配额，对话中检索记忆时保留对话的会话归属
+	if err := usage.CheckQuota(ctx, req.UserId); err != nil {
+		logger.Warnf("用户配额不足: user_id=%d, err=%v", req.UserId, err)
+		return &rag_svr.SearchMemoriesRsp{
+			Code: uint32(rag_svr.ErrCode_ERR_QUOTA_EXCEEDED),
+			Msg:  err.Error(),
+		}, nil
+	}
+	ctx = usage.WithUserScope(ctx, req.UserId)
+
 	// 调用记忆管理器搜索记忆
 	memories, err := memory.GetInstance().SearchMemories(ctx, req.Query, int(req.Limit))
 	if err != nil {
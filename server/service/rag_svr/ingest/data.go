@@ -0,0 +1,165 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// extractCSV 解析 CSV，第一行作为列名，之后每行一段
+func extractCSV(data []byte) ([]Paragraph, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	// 分号或制表符分隔的文件按首行判断
+	if firstLine, _, _ := strings.Cut(string(data), "\n"); !strings.Contains(firstLine, ",") {
+		if strings.Contains(firstLine, "\t") {
+			reader.Comma = '\t'
+		} else if strings.Contains(firstLine, ";") {
+			reader.Comma = ';'
+		}
+	}
+
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	paragraphs := make([]Paragraph, 0)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %v", row+1, err)
+		}
+		paragraphs = append(paragraphs, Paragraph{
+			Content: rowContent(columns, record),
+			Meta: ParagraphMeta{
+				Type:    BlockTableRow,
+				Row:     row,
+				Columns: columns,
+			},
+		})
+	}
+	return paragraphs, nil
+}
+
+// extractJSON 解析 JSON
+// 顶层（或唯一的数组字段）是数组时每个元素一段，否则每个顶层字段一段；
+// 对象展开为 "路径: 值" 的行，NDJSON 按行解析
+func extractJSON(data []byte) ([]Paragraph, error) {
+	values, err := decodeJSONValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	paragraphs := make([]Paragraph, 0)
+	add := func(path string, value interface{}) {
+		paragraphs = append(paragraphs, Paragraph{
+			Content: strings.Join(flattenJSON("", value), "\n"),
+			Meta:    ParagraphMeta{Type: BlockRecord, Path: path},
+		})
+	}
+
+	for i, value := range values {
+		root := "$"
+		if len(values) > 1 {
+			root = fmt.Sprintf("$[%d]", i)
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			for j, item := range v {
+				add(fmt.Sprintf("%s[%d]", root, j), item)
+			}
+		case map[string]interface{}:
+			if len(values) > 1 {
+				add(root, v)
+				continue
+			}
+			for _, key := range sortedKeys(v) {
+				if items, ok := v[key].([]interface{}); ok {
+					for j, item := range items {
+						add(fmt.Sprintf("%s.%s[%d]", root, key, j), item)
+					}
+					continue
+				}
+				add(root+"."+key, map[string]interface{}{key: v[key]})
+			}
+		default:
+			add(root, v)
+		}
+	}
+	return paragraphs, nil
+}
+
+// decodeJSONValues 解码一个或多个连续的 JSON 值，兼容 NDJSON
+func decodeJSONValues(data []byte) ([]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	values := make([]interface{}, 0, 1)
+	for {
+		var value interface{}
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// flattenJSON 将 JSON 值展开为 "路径: 值" 的行，字段按名称排序保证结果稳定
+func flattenJSON(prefix string, value interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		lines := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			lines = append(lines, flattenJSON(path, v[key])...)
+		}
+		return lines
+	case []interface{}:
+		lines := make([]string, 0, len(v))
+		for i, item := range v {
+			lines = append(lines, flattenJSON(prefix+"["+strconv.Itoa(i)+"]", item)...)
+		}
+		return lines
+	case nil:
+		return nil
+	default:
+		if prefix == "" {
+			return []string{fmt.Sprint(v)}
+		}
+		return []string{prefix + ": " + fmt.Sprint(v)}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
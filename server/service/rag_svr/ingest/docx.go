@@ -0,0 +1,237 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOCX 正文所在的文件
+const docxDocumentPath = "word/document.xml"
+
+// extractDOCX 解析 DOCX 正文
+// 标题样式（Heading1-9 / 标题1-9）作为标题路径，列表段落标记为 list，表格每行一段
+func extractDOCX(data []byte) ([]Paragraph, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的 DOCX 文件: %v", err)
+	}
+
+	var document *zip.File
+	for _, f := range reader.File {
+		if f.Name == docxDocumentPath {
+			document = f
+			break
+		}
+	}
+	if document == nil {
+		return nil, fmt.Errorf("DOCX 中缺少 %s", docxDocumentPath)
+	}
+
+	rc, err := document.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return parseDocxXML(rc)
+}
+
+type docxParser struct {
+	headings   headingStack
+	paragraphs []Paragraph
+
+	// 当前段落
+	text    strings.Builder
+	style   string
+	isList  bool
+	inRun   bool
+	inText  bool
+	tblDeep int
+
+	// 当前表格（只处理最外层表格，嵌套表格的文字并入单元格）
+	columns []string
+	cells   []string
+	cell    *strings.Builder
+	row     int
+}
+
+func parseDocxXML(r io.Reader) ([]Paragraph, error) {
+	decoder := xml.NewDecoder(r)
+	p := &docxParser{}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %v", docxDocumentPath, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			p.start(t)
+		case xml.EndElement:
+			p.end(t.Name.Local)
+		case xml.CharData:
+			if p.inText {
+				p.text.Write(t)
+			}
+		}
+	}
+
+	return p.paragraphs, nil
+}
+
+func (p *docxParser) start(t xml.StartElement) {
+	switch t.Name.Local {
+	case "tbl":
+		p.tblDeep++
+		if p.tblDeep == 1 {
+			p.columns = nil
+			p.row = 0
+		}
+	case "tr":
+		if p.tblDeep == 1 {
+			p.cells = nil
+		}
+	case "tc":
+		if p.tblDeep == 1 {
+			p.cell = &strings.Builder{}
+		}
+	case "p":
+		p.text.Reset()
+		p.style = ""
+		p.isList = false
+	case "pStyle":
+		p.style = attr(t, "val")
+	case "numPr":
+		p.isList = true
+	case "t":
+		p.inText = true
+	case "tab":
+		p.text.WriteString("\t")
+	case "br", "cr":
+		p.text.WriteString("\n")
+	}
+}
+
+func (p *docxParser) end(name string) {
+	switch name {
+	case "t":
+		p.inText = false
+	case "p":
+		p.endParagraph()
+	case "tc":
+		if p.tblDeep == 1 && p.cell != nil {
+			p.cells = append(p.cells, strings.TrimSpace(p.cell.String()))
+			p.cell = nil
+		}
+	case "tr":
+		if p.tblDeep == 1 {
+			p.endRow()
+		}
+	case "tbl":
+		p.tblDeep--
+	}
+}
+
+func (p *docxParser) endParagraph() {
+	text := p.text.String()
+	p.text.Reset()
+
+	// 表格内的段落并入单元格
+	if p.cell != nil {
+		if p.cell.Len() > 0 {
+			p.cell.WriteString(" ")
+		}
+		p.cell.WriteString(strings.TrimSpace(text))
+		return
+	}
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	if level := headingLevel(p.style); level > 0 {
+		text = strings.Join(strings.Fields(text), " ")
+		p.headings.push(level, text)
+		p.paragraphs = append(p.paragraphs, Paragraph{
+			Content: text,
+			Meta: ParagraphMeta{
+				Type:     BlockHeading,
+				Headings: p.headings.path(),
+				Level:    level,
+			},
+		})
+		return
+	}
+
+	blockType := BlockText
+	if p.isList || strings.HasPrefix(strings.ToLower(p.style), "list") {
+		blockType = BlockList
+	}
+	// 连续的列表项合并为一段
+	if last := len(p.paragraphs) - 1; blockType == BlockList && last >= 0 && p.paragraphs[last].Meta.Type == BlockList {
+		p.paragraphs[last].Content += "\n" + text
+		return
+	}
+	p.paragraphs = append(p.paragraphs, Paragraph{
+		Content: text,
+		Meta: ParagraphMeta{
+			Type:     blockType,
+			Headings: p.headings.path(),
+		},
+	})
+}
+
+// endRow 表格第一行作为列名
+func (p *docxParser) endRow() {
+	if len(p.cells) == 0 {
+		return
+	}
+	if p.columns == nil {
+		p.columns = p.cells
+		return
+	}
+	p.row++
+	p.paragraphs = append(p.paragraphs, Paragraph{
+		Content: rowContent(p.columns, p.cells),
+		Meta: ParagraphMeta{
+			Type:     BlockTableRow,
+			Headings: p.headings.path(),
+			Row:      p.row,
+			Columns:  p.columns,
+		},
+	})
+	p.cells = nil
+}
+
+// headingLevel 从段落样式解析标题级别，Title 视为一级标题
+func headingLevel(style string) int {
+	lower := strings.ToLower(style)
+	if lower == "title" {
+		return 1
+	}
+	for _, prefix := range []string{"heading", "标题"} {
+		if strings.HasPrefix(lower, prefix) {
+			if level, err := strconv.Atoi(strings.TrimSpace(lower[len(prefix):])); err == nil && level >= 1 && level <= 9 {
+				return level
+			}
+		}
+	}
+	return 0
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
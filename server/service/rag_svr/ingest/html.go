@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// 按块级元素分段，其余元素的文字并入所在的块
+var htmlBlocks = map[string]string{
+	"p": BlockText, "div": BlockText, "section": BlockText, "article": BlockText,
+	"blockquote": BlockText, "header": BlockText, "footer": BlockText, "main": BlockText,
+	"li": BlockList, "dt": BlockText, "dd": BlockText,
+	"pre": BlockCode,
+}
+
+// 内容不参与索引的元素
+var htmlSkipped = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"head": true, "nav": true, "svg": true, "iframe": true,
+}
+
+// htmlParser 基于 encoding/xml 的宽松模式解析 HTML
+type htmlParser struct {
+	headings   headingStack
+	paragraphs []Paragraph
+	text       strings.Builder
+	blockType  string
+	skipDepth  int
+	heading    int
+
+	// 表格状态
+	inTable bool
+	columns []string
+	cells   []string
+	cell    *strings.Builder
+	isHead  bool
+	row     int
+}
+
+// extractHTML 解析 HTML，h1-h6 作为标题路径，表格每行一段
+func extractHTML(data []byte) ([]Paragraph, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	p := &htmlParser{blockType: BlockText}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 宽松模式下仍可能遇到无法恢复的错误，保留已解析的内容
+			if len(p.paragraphs) > 0 || p.text.Len() > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			p.start(strings.ToLower(t.Name.Local))
+		case xml.EndElement:
+			p.end(strings.ToLower(t.Name.Local))
+		case xml.CharData:
+			if p.skipDepth > 0 {
+				continue
+			}
+			if p.cell != nil {
+				p.cell.Write(t)
+			} else {
+				p.text.Write(t)
+			}
+		}
+	}
+	p.flush()
+
+	return p.paragraphs, nil
+}
+
+func (p *htmlParser) start(name string) {
+	if htmlSkipped[name] {
+		p.skipDepth++
+		return
+	}
+	if p.skipDepth > 0 {
+		return
+	}
+
+	switch {
+	case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+		p.flush()
+		p.heading = int(name[1] - '0')
+	case name == "table":
+		p.flush()
+		p.inTable = true
+		p.columns = nil
+		p.row = 0
+	case name == "tr" && p.inTable:
+		p.cells = nil
+		p.isHead = false
+	case (name == "td" || name == "th") && p.inTable:
+		p.cell = &strings.Builder{}
+		if name == "th" {
+			p.isHead = true
+		}
+	case name == "br":
+		if p.cell != nil {
+			p.cell.WriteString(" ")
+		} else {
+			p.text.WriteString("\n")
+		}
+	default:
+		if blockType, ok := htmlBlocks[name]; ok && p.cell == nil {
+			p.flush()
+			p.blockType = blockType
+		}
+	}
+}
+
+func (p *htmlParser) end(name string) {
+	if htmlSkipped[name] {
+		if p.skipDepth > 0 {
+			p.skipDepth--
+		}
+		return
+	}
+	if p.skipDepth > 0 {
+		return
+	}
+
+	switch {
+	case p.heading > 0 && len(name) == 2 && name[0] == 'h':
+		text := strings.Join(strings.Fields(p.text.String()), " ")
+		p.text.Reset()
+		if text != "" {
+			p.headings.push(p.heading, text)
+			p.paragraphs = append(p.paragraphs, Paragraph{
+				Content: text,
+				Meta: ParagraphMeta{
+					Type:     BlockHeading,
+					Headings: p.headings.path(),
+					Level:    p.heading,
+				},
+			})
+		}
+		p.heading = 0
+	case (name == "td" || name == "th") && p.cell != nil:
+		p.cells = append(p.cells, strings.Join(strings.Fields(p.cell.String()), " "))
+		p.cell = nil
+	case name == "tr" && p.inTable:
+		p.endRow()
+	case name == "table":
+		p.inTable = false
+		p.columns = nil
+	default:
+		if _, ok := htmlBlocks[name]; ok && p.cell == nil {
+			p.flush()
+		}
+	}
+}
+
+// endRow 第一行全部为 th 或表格没有表头时，第一行作为列名
+func (p *htmlParser) endRow() {
+	if len(p.cells) == 0 {
+		return
+	}
+	if p.columns == nil && p.isHead {
+		p.columns = p.cells
+		return
+	}
+	p.row++
+	p.paragraphs = append(p.paragraphs, Paragraph{
+		Content: rowContent(p.columns, p.cells),
+		Meta: ParagraphMeta{
+			Type:     BlockTableRow,
+			Headings: p.headings.path(),
+			Row:      p.row,
+			Columns:  p.columns,
+		},
+	})
+	p.cells = nil
+}
+
+func (p *htmlParser) flush() {
+	text := p.text.String()
+	p.text.Reset()
+	if p.blockType != BlockCode {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.Fields(line), " ")
+		}
+		text = strings.Join(lines, "\n")
+	}
+	// 连续的列表项合并为一段
+	if last := len(p.paragraphs) - 1; p.blockType == BlockList && last >= 0 && p.paragraphs[last].Meta.Type == BlockList && strings.TrimSpace(text) != "" {
+		p.paragraphs[last].Content += "\n" + text
+	} else if strings.TrimSpace(text) != "" {
+		p.paragraphs = append(p.paragraphs, Paragraph{
+			Content: text,
+			Meta: ParagraphMeta{
+				Type:     p.blockType,
+				Headings: p.headings.path(),
+			},
+		})
+	}
+	p.blockType = BlockText
+}
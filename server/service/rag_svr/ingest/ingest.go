@@ -0,0 +1,322 @@
+// Package ingest 文档解析，将各种格式的上传内容转换为规范化的段落
+package ingest
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 支持的 MIME 类型
+const (
+	MIMEText     = "text/plain"
+	MIMEMarkdown = "text/markdown"
+	MIMEHTML     = "text/html"
+	MIMEPDF      = "application/pdf"
+	MIMEDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMECSV      = "text/csv"
+	MIMEJSON     = "application/json"
+)
+
+// 段落类型
+const (
+	BlockText     = "text"      // 正文
+	BlockHeading  = "heading"   // 标题
+	BlockList     = "list"      // 列表
+	BlockCode     = "code"      // 代码
+	BlockTableRow = "table_row" // 表格行
+	BlockRecord   = "record"    // JSON 记录
+)
+
+// 单个段落的最大字符数，超出时按句子拆分
+const maxParagraphRunes = 1500
+
+// ParagraphMeta 段落的结构信息，保存到 document_paragraph.metadata
+type ParagraphMeta struct {
+	Type     string   `json:"type"`               // 段落类型
+	Headings []string `json:"headings,omitempty"` // 所在的标题路径，从一级标题开始
+	Level    int      `json:"level,omitempty"`    // 标题级别，仅标题段落
+	Page     int      `json:"page,omitempty"`     // 页码，从 1 开始，仅 PDF
+	Row      int      `json:"row,omitempty"`      // 表格行号，从 1 开始，不含表头
+	Columns  []string `json:"columns,omitempty"`  // 表头，仅表格行
+	Path     string   `json:"path,omitempty"`     // JSON 路径，仅 JSON 记录
+}
+
+// Paragraph 解析后的段落
+type Paragraph struct {
+	Content string
+	Meta    ParagraphMeta
+}
+
+// Extractor 文档解析器
+type Extractor interface {
+	Extract(data []byte) ([]Paragraph, error)
+}
+
+// ExtractorFunc 函数形式的解析器
+type ExtractorFunc func(data []byte) ([]Paragraph, error)
+
+// Extract 实现 Extractor
+func (f ExtractorFunc) Extract(data []byte) ([]Paragraph, error) {
+	return f(data)
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = map[string]Extractor{
+		MIMEText:     ExtractorFunc(extractText),
+		MIMEMarkdown: ExtractorFunc(extractMarkdown),
+		MIMEHTML:     ExtractorFunc(extractHTML),
+		MIMEPDF:      ExtractorFunc(extractPDF),
+		MIMEDOCX:     ExtractorFunc(extractDOCX),
+		MIMECSV:      ExtractorFunc(extractCSV),
+		MIMEJSON:     ExtractorFunc(extractJSON),
+	}
+
+	// 常见的别名
+	mimeAliases = map[string]string{
+		"text/x-markdown":          MIMEMarkdown,
+		"application/xhtml+xml":    MIMEHTML,
+		"application/csv":          MIMECSV,
+		"text/json":                MIMEJSON,
+		"application/x-ndjson":     MIMEJSON,
+		"application/octet-stream": "",
+	}
+
+	// 按文件扩展名识别
+	extensionTypes = map[string]string{
+		".txt":      MIMEText,
+		".text":     MIMEText,
+		".md":       MIMEMarkdown,
+		".markdown": MIMEMarkdown,
+		".html":     MIMEHTML,
+		".htm":      MIMEHTML,
+		".pdf":      MIMEPDF,
+		".docx":     MIMEDOCX,
+		".csv":      MIMECSV,
+		".json":     MIMEJSON,
+	}
+)
+
+// Register 注册或替换某个 MIME 类型的解析器
+func Register(mimeType string, extractor Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	extractors[NormalizeMIME(mimeType)] = extractor
+}
+
+// Supported 判断是否支持该 MIME 类型
+func Supported(mimeType string) bool {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	_, ok := extractors[NormalizeMIME(mimeType)]
+	return ok
+}
+
+// NormalizeMIME 去掉参数并转换别名，如 "text/markdown; charset=utf-8" 转换为 "text/markdown"
+func NormalizeMIME(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(mimeType))
+	}
+	if alias, ok := mimeAliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+// DetectMIME 识别内容类型，优先使用声明的类型，其次是文件扩展名，最后根据内容判断
+func DetectMIME(declared, fileName string, data []byte) string {
+	if mimeType := NormalizeMIME(declared); mimeType != "" {
+		return mimeType
+	}
+	if mimeType, ok := extensionTypes[strings.ToLower(filepath.Ext(fileName))]; ok {
+		return mimeType
+	}
+	if len(data) >= 4 && string(data[:4]) == "%PDF" {
+		return MIMEPDF
+	}
+	// DOCX 是 zip 包，只能靠扩展名识别
+	mimeType := NormalizeMIME(http.DetectContentType(data))
+	if mimeType == MIMEText && looksLikeMarkdown(data) {
+		return MIMEMarkdown
+	}
+	return mimeType
+}
+
+// CheckContent 校验文件内容与类型是否一致，拒绝扩展名或声明的类型与实际内容不符的文件
+func CheckContent(mimeType string, data []byte) error {
+	switch NormalizeMIME(mimeType) {
+	case MIMEPDF:
+		if !bytes.HasPrefix(data, []byte("%PDF")) {
+			return fmt.Errorf("文件内容不是 PDF")
+		}
+	case MIMEDOCX:
+		if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+			return fmt.Errorf("文件内容不是 DOCX")
+		}
+	default:
+		// 文本类格式不能是二进制内容
+		head := data
+		if len(head) > 512 {
+			head = head[:512]
+		}
+		if bytes.IndexByte(head, 0) >= 0 || !strings.HasPrefix(http.DetectContentType(head), "text/") {
+			return fmt.Errorf("文件内容不是文本")
+		}
+	}
+	return nil
+}
+
+var markdownHint = regexp.MustCompile(`(?m)^(#{1,6} |\s*[-*] |\|.*\|\s*$|` + "```" + `)`)
+
+func looksLikeMarkdown(data []byte) bool {
+	return markdownHint.Match(data)
+}
+
+// Extract 按 MIME 类型解析内容，返回规范化后的段落
+func Extract(mimeType string, data []byte) ([]Paragraph, error) {
+	mimeType = NormalizeMIME(mimeType)
+	if mimeType == "" {
+		mimeType = MIMEText
+	}
+
+	extractorsMu.RLock()
+	extractor, ok := extractors[mimeType]
+	extractorsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("不支持的文档类型: %s", mimeType)
+	}
+
+	// 文本类格式要求 UTF-8
+	if strings.HasPrefix(mimeType, "text/") || mimeType == MIMEJSON {
+		data = trimBOM(data)
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("文档不是有效的 UTF-8 编码")
+		}
+	}
+
+	paragraphs, err := extractor.Extract(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析文档失败: %v", err)
+	}
+	paragraphs = normalize(paragraphs)
+	if len(paragraphs) == 0 {
+		return nil, fmt.Errorf("文档中没有可索引的内容")
+	}
+	return paragraphs, nil
+}
+
+func trimBOM(data []byte) []byte {
+	if len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF {
+		return data[3:]
+	}
+	return data
+}
+
+var (
+	spaceRun   = regexp.MustCompile(`[ \t\x{00a0}\x{3000}]+`)
+	newlineRun = regexp.MustCompile(`\n{3,}`)
+)
+
+// normalize 统一空白、去掉空段落，过长的段落按句子拆分并保留结构信息
+func normalize(paragraphs []Paragraph) []Paragraph {
+	result := make([]Paragraph, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		content := strings.ReplaceAll(p.Content, "\r\n", "\n")
+		content = strings.ReplaceAll(content, "\r", "\n")
+		if p.Meta.Type != BlockCode {
+			content = spaceRun.ReplaceAllString(content, " ")
+		}
+		content = newlineRun.ReplaceAllString(content, "\n\n")
+		content = strings.TrimSpace(content)
+		if content == "" {
+			continue
+		}
+		for _, part := range splitLong(content, maxParagraphRunes) {
+			result = append(result, Paragraph{Content: part, Meta: p.Meta})
+		}
+	}
+	return result
+}
+
+// splitLong 将过长的文本在句末标点或换行处拆分，每段不超过 limit 个字符
+func splitLong(content string, limit int) []string {
+	runes := []rune(content)
+	if len(runes) <= limit {
+		return []string{content}
+	}
+
+	parts := make([]string, 0, len(runes)/limit+1)
+	for len(runes) > limit {
+		cut := limit
+		for i := limit - 1; i > limit/2; i-- {
+			if strings.ContainsRune("。！？.!?\n；;", runes[i]) {
+				cut = i + 1
+				break
+			}
+		}
+		if part := strings.TrimSpace(string(runes[:cut])); part != "" {
+			parts = append(parts, part)
+		}
+		runes = runes[cut:]
+	}
+	if part := strings.TrimSpace(string(runes)); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// headingStack 维护当前所在的标题路径
+type headingStack []struct {
+	level int
+	text  string
+}
+
+// push 进入一个新标题，弹出同级及更低级的标题
+func (s *headingStack) push(level int, text string) {
+	for len(*s) > 0 && (*s)[len(*s)-1].level >= level {
+		*s = (*s)[:len(*s)-1]
+	}
+	*s = append(*s, struct {
+		level int
+		text  string
+	}{level, text})
+}
+
+// path 当前标题路径的副本
+func (s headingStack) path() []string {
+	if len(s) == 0 {
+		return nil
+	}
+	path := make([]string, len(s))
+	for i, h := range s {
+		path[i] = h.text
+	}
+	return path
+}
+
+// rowContent 将表格行格式化为 "列名: 值" 的形式，便于检索时保留列含义
+func rowContent(columns, cells []string) string {
+	parts := make([]string, 0, len(cells))
+	for i, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		if i < len(columns) && strings.TrimSpace(columns[i]) != "" {
+			parts = append(parts, strings.TrimSpace(columns[i])+": "+cell)
+		} else {
+			parts = append(parts, cell)
+		}
+	}
+	return strings.Join(parts, "；")
+}
@@ -0,0 +1,1085 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// 只读取 PDF 的文本层：解析对象（含对象流）、按页面树顺序解释内容流中的文本操作符，
+// 通过字体的 ToUnicode CMap 还原文字。不支持加密文档，扫描件没有文本层无法提取。
+
+// PDF 对象类型
+type (
+	pdfName   string
+	pdfString []byte
+	pdfArray  []interface{}
+	pdfDict   map[pdfName]interface{}
+	pdfDelim  string // [ ] << >> { }
+	pdfOp     string // 内容流操作符和其他关键字
+	pdfRef    struct{ num, gen int }
+	pdfStream struct {
+		dict pdfDict
+		data []byte
+	}
+)
+
+// 解析的安全上限
+const (
+	pdfMaxResolveDepth = 32
+	pdfMaxFormDepth    = 3
+	pdfMaxNesting      = 64
+	// FlateDecode 解压后的大小上限，防止少量压缩数据展开成巨大的内容耗尽内存
+	pdfMaxStreamSize  = 64 << 20  // 单个流
+	pdfMaxDecodedSize = 256 << 20 // 整个文档累计
+)
+
+var errPDFTooLarge = errors.New("PDF 解压后的内容过大")
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// extractPDF 按页提取 PDF 文本，段落记录页码
+func extractPDF(data []byte) ([]Paragraph, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\f\r "), []byte("%PDF")) {
+		return nil, fmt.Errorf("不是有效的 PDF 文件")
+	}
+
+	doc := parsePDF(data)
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	if doc.encrypted {
+		return nil, fmt.Errorf("不支持加密的 PDF")
+	}
+
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("PDF 中没有找到页面")
+	}
+
+	paragraphs := make([]Paragraph, 0)
+	for i, page := range pages {
+		lines := doc.pageLines(page)
+		for _, text := range groupLines(lines) {
+			paragraphs = append(paragraphs, Paragraph{
+				Content: text,
+				Meta:    ParagraphMeta{Type: BlockText, Page: i + 1},
+			})
+		}
+		if doc.err != nil {
+			return nil, doc.err
+		}
+	}
+	if len(paragraphs) == 0 {
+		return nil, fmt.Errorf("PDF 中没有文本层，可能是扫描件")
+	}
+	return paragraphs, nil
+}
+
+// pdfDocument 解析后的 PDF 对象表
+type pdfDocument struct {
+	objects   map[int]interface{}
+	fonts     map[pdfRef]*pdfFont
+	encrypted bool
+	decoded   int   // 已解压的字节数，计入 pdfMaxDecodedSize
+	err       error // 超出解压上限时记录，整个文档不再处理
+}
+
+// parsePDF 顺序扫描 "N G obj" 解析所有对象，不依赖可能损坏的 xref 表，后出现的对象覆盖先出现的（增量更新）
+func parsePDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{
+		objects: make(map[int]interface{}),
+		fonts:   make(map[pdfRef]*pdfFont),
+	}
+
+	end := 0
+	for _, loc := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		// 跳过流数据内部偶然出现的匹配
+		if loc[0] < end {
+			continue
+		}
+		if loc[0] > 0 && !isPDFSpace(data[loc[0]-1]) && !isPDFDelim(data[loc[0]-1]) {
+			continue
+		}
+		num, _ := strconv.Atoi(string(data[loc[2]:loc[3]]))
+
+		lexer := &pdfLexer{data: data, pos: loc[1]}
+		obj, err := lexer.object()
+		if err != nil {
+			continue
+		}
+		if dict, ok := obj.(pdfDict); ok {
+			if stream, ok := lexer.stream(dict); ok {
+				obj = stream
+			}
+		}
+		doc.objects[num] = obj
+		end = lexer.pos
+	}
+
+	doc.loadObjectStreams()
+	doc.encrypted = bytes.Contains(data, []byte("/Encrypt"))
+	return doc
+}
+
+// loadObjectStreams 展开对象流（PDF 1.5+），已直接定义的对象不覆盖
+func (d *pdfDocument) loadObjectStreams() {
+	nums := make([]int, 0)
+	for num, obj := range d.objects {
+		if stream, ok := obj.(*pdfStream); ok && stream.dict["Type"] == pdfName("ObjStm") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+
+	for _, num := range nums {
+		stream := d.objects[num].(*pdfStream)
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := d.resolve(stream.dict["N"]).(float64)
+		first, _ := d.resolve(stream.dict["First"]).(float64)
+
+		header := &pdfLexer{data: data}
+		for i := 0; i < int(n); i++ {
+			objNum, err1 := header.next()
+			offset, err2 := header.next()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			objNumF, ok1 := objNum.(float64)
+			offsetF, ok2 := offset.(float64)
+			if !ok1 || !ok2 {
+				break
+			}
+			if _, exists := d.objects[int(objNumF)]; exists {
+				continue
+			}
+			pos := int(first) + int(offsetF)
+			if pos < 0 || pos >= len(data) {
+				continue
+			}
+			lexer := &pdfLexer{data: data, pos: pos}
+			if obj, err := lexer.object(); err == nil {
+				d.objects[int(objNumF)] = obj
+			}
+		}
+	}
+}
+
+// resolve 解引用间接对象
+func (d *pdfDocument) resolve(v interface{}) interface{} {
+	for i := 0; i < pdfMaxResolveDepth; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.num]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v interface{}) pdfDict {
+	switch t := d.resolve(v).(type) {
+	case pdfDict:
+		return t
+	case *pdfStream:
+		return t.dict
+	}
+	return nil
+}
+
+// decodeStream 按 Filter 解码流数据
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	filters := make([]pdfName, 0)
+	switch f := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = append(filters, f)
+	case pdfArray:
+		for _, item := range f {
+			if name, ok := d.resolve(item).(pdfName); ok {
+				filters = append(filters, name)
+			}
+		}
+	}
+
+	data := stream.data
+	for _, filter := range filters {
+		var err error
+		switch filter {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data, min(pdfMaxStreamSize, pdfMaxDecodedSize-d.decoded))
+			if errors.Is(err, errPDFTooLarge) {
+				d.err = err
+				return nil, err
+			}
+			if err == nil {
+				d.decoded += len(data)
+			}
+		case "ASCIIHexDecode", "AHx":
+			data, err = decodeASCIIHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("不支持的 PDF 压缩方式: %s", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate 解压 FlateDecode 数据，解压后超过 limit 字节时返回 errPDFTooLarge
+func inflate(data []byte, limit int) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	out, err := io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+	if len(out) > limit {
+		return nil, fmt.Errorf("%w: 单个流最多 %d MB，整个文档最多 %d MB", errPDFTooLarge, pdfMaxStreamSize>>20, pdfMaxDecodedSize>>20)
+	}
+	// 部分文件的流末尾被截断，保留已解压的内容
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	clean := make([]byte, 0, len(data))
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isPDFSpace(c) {
+			clean = append(clean, c)
+		}
+	}
+	if len(clean)%2 == 1 {
+		clean = append(clean, '0')
+	}
+	return hex.DecodeString(string(clean))
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, len(data))
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+// pdfPage 页面及其（可能继承自父节点的）资源
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages 按页面树顺序返回页面，找不到目录时按对象编号顺序返回所有页面对象
+func (d *pdfDocument) pages() []pdfPage {
+	var catalog pdfDict
+	catalogNum := -1
+	for num, obj := range d.objects {
+		if dict := d.dict(obj); dict != nil && dict["Type"] == pdfName("Catalog") && num > catalogNum {
+			catalog, catalogNum = dict, num
+		}
+	}
+
+	pages := make([]pdfPage, 0)
+	if catalog != nil {
+		visited := make(map[int]bool)
+		d.walkPages(catalog["Pages"], nil, visited, &pages, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	nums := make([]int, 0)
+	for num, obj := range d.objects {
+		if dict := d.dict(obj); dict != nil && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		dict := d.dict(d.objects[num])
+		pages = append(pages, pdfPage{dict: dict, resources: d.dict(dict["Resources"])})
+	}
+	return pages
+}
+
+func (d *pdfDocument) walkPages(node interface{}, inherited pdfDict, visited map[int]bool, pages *[]pdfPage, depth int) {
+	if depth > pdfMaxNesting {
+		return
+	}
+	if ref, ok := node.(pdfRef); ok {
+		if visited[ref.num] {
+			return
+		}
+		visited[ref.num] = true
+	}
+	dict := d.dict(node)
+	if dict == nil {
+		return
+	}
+
+	resources := inherited
+	if own := d.dict(dict["Resources"]); own != nil {
+		resources = own
+	}
+	if kids, ok := d.resolve(dict["Kids"]).(pdfArray); ok && dict["Type"] != pdfName("Page") {
+		for _, kid := range kids {
+			d.walkPages(kid, resources, visited, pages, depth+1)
+		}
+		return
+	}
+	*pages = append(*pages, pdfPage{dict: dict, resources: resources})
+}
+
+// pdfLine 一行文字及其纵坐标
+type pdfLine struct {
+	y    float64
+	text string
+}
+
+// pageLines 解释页面内容流，返回按出现顺序排列的文本行
+func (d *pdfDocument) pageLines(page pdfPage) []pdfLine {
+	content := make([]byte, 0)
+	switch c := d.resolve(page.dict["Contents"]).(type) {
+	case *pdfStream:
+		if data, err := d.decodeStream(c); err == nil {
+			content = data
+		}
+	case pdfArray:
+		for _, item := range c {
+			if stream, ok := d.resolve(item).(*pdfStream); ok {
+				if data, err := d.decodeStream(stream); err == nil {
+					content = append(content, data...)
+					content = append(content, '\n')
+				}
+			}
+		}
+	}
+
+	state := &pdfTextState{scale: 1}
+	d.runContent(content, page.resources, state, 0)
+	state.newLine(state.y)
+	return state.lines
+}
+
+// pdfTextState 文本操作符的解释状态，只跟踪换行所需的纵坐标
+type pdfTextState struct {
+	font    *pdfFont
+	y       float64
+	scale   float64
+	leading float64
+	current strings.Builder
+	lines   []pdfLine
+}
+
+func (s *pdfTextState) newLine(y float64) {
+	if text := strings.TrimSpace(s.current.String()); text != "" {
+		s.lines = append(s.lines, pdfLine{y: s.y, text: text})
+	}
+	s.current.Reset()
+	s.y = y
+}
+
+func (s *pdfTextState) show(str pdfString) {
+	if s.font == nil {
+		s.current.WriteString(decodeSimple(str))
+		return
+	}
+	s.current.WriteString(s.font.decode(str))
+}
+
+func (s *pdfTextState) space() {
+	text := s.current.String()
+	if text != "" && !strings.HasSuffix(text, " ") {
+		s.current.WriteString(" ")
+	}
+}
+
+// runContent 解释内容流，Form XObject 递归处理
+func (d *pdfDocument) runContent(content []byte, resources pdfDict, state *pdfTextState, depth int) {
+	fonts := d.dict(resources["Font"])
+	xobjects := d.dict(resources["XObject"])
+
+	lexer := &pdfLexer{data: content, noRefs: true}
+	operands := make([]interface{}, 0, 8)
+	number := func(i int) float64 {
+		if i < len(operands) {
+			if f, ok := operands[i].(float64); ok {
+				return f
+			}
+		}
+		return 0
+	}
+
+	for {
+		obj, err := lexer.object()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(pdfOp)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "BT":
+			state.newLine(0)
+			state.scale = 1
+		case "Tf":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					state.font = d.font(fonts[name])
+				}
+			}
+		case "TL":
+			state.leading = number(0)
+		case "Td", "TD":
+			ty := number(1)
+			if op == "TD" {
+				state.leading = -ty
+			}
+			if ty != 0 {
+				state.newLine(state.y + ty*state.scale)
+			} else if number(0) != 0 {
+				state.space()
+			}
+		case "Tm":
+			state.scale = number(3)
+			if state.scale == 0 {
+				state.scale = 1
+			}
+			if y := number(5); y != state.y {
+				state.newLine(y)
+			} else {
+				state.space()
+			}
+		case "T*":
+			state.newLine(state.y - state.leading*state.scale)
+		case "Tj":
+			if len(operands) >= 1 {
+				if str, ok := operands[len(operands)-1].(pdfString); ok {
+					state.show(str)
+				}
+			}
+		case "'", "\"":
+			state.newLine(state.y - state.leading*state.scale)
+			if len(operands) >= 1 {
+				if str, ok := operands[len(operands)-1].(pdfString); ok {
+					state.show(str)
+				}
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				if array, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, item := range array {
+						switch v := item.(type) {
+						case pdfString:
+							state.show(v)
+						case float64:
+							// 较大的负偏移表示词间距
+							if v < -250 {
+								state.space()
+							}
+						}
+					}
+				}
+			}
+		case "BI":
+			lexer.skipInlineImage()
+		case "Do":
+			if depth < pdfMaxFormDepth && len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					d.runForm(xobjects[name], resources, state, depth)
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+func (d *pdfDocument) runForm(ref interface{}, resources pdfDict, state *pdfTextState, depth int) {
+	form, ok := d.resolve(ref).(*pdfStream)
+	if !ok || form.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := d.decodeStream(form)
+	if err != nil {
+		return
+	}
+	if own := d.dict(form.dict["Resources"]); own != nil {
+		resources = own
+	}
+	d.runContent(data, resources, state, depth+1)
+}
+
+// groupLines 将行合并为段落：行距明显大于正文行距或坐标回退（换栏）时分段
+func groupLines(lines []pdfLine) []string {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	gaps := make([]float64, 0, len(lines))
+	for i := 1; i < len(lines); i++ {
+		if gap := lines[i-1].y - lines[i].y; gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	threshold := 0.0
+	if len(gaps) > 0 {
+		sort.Float64s(gaps)
+		threshold = gaps[len(gaps)/2]*1.5 + 0.5
+	}
+
+	paragraphs := make([]string, 0)
+	current := lines[0].text
+	for i := 1; i < len(lines); i++ {
+		gap := lines[i-1].y - lines[i].y
+		if gap <= 0 || gap > threshold {
+			paragraphs = append(paragraphs, current)
+			current = lines[i].text
+			continue
+		}
+		current = joinLines(current, lines[i].text)
+	}
+	return append(paragraphs, current)
+}
+
+// joinLines 拼接同一段落中的相邻行，中文直接拼接，英文断词连字符去掉
+func joinLines(prev, next string) string {
+	last, _ := lastRune(prev)
+	first := []rune(next)[0]
+	if last == '-' && unicode.IsLower(first) {
+		return strings.TrimSuffix(prev, "-") + next
+	}
+	if isWide(last) || isWide(first) {
+		return prev + next
+	}
+	return prev + " " + next
+}
+
+func lastRune(s string) (rune, bool) {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, false
+	}
+	return runes[len(runes)-1], true
+}
+
+func isWide(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// pdfFont 字体的编码信息
+type pdfFont struct {
+	toUnicode map[string]string
+	codeLen   int  // 编码字节数，复合字体为 2
+	composite bool // Type0 字体，没有 ToUnicode 时无法还原文字
+}
+
+// font 加载字体的 ToUnicode CMap，按引用缓存
+func (d *pdfDocument) font(v interface{}) *pdfFont {
+	ref, isRef := v.(pdfRef)
+	if isRef {
+		if font, ok := d.fonts[ref]; ok {
+			return font
+		}
+	}
+
+	font := &pdfFont{codeLen: 1}
+	if dict := d.dict(v); dict != nil {
+		if dict["Subtype"] == pdfName("Type0") {
+			font.composite = true
+			font.codeLen = 2
+		}
+		if stream, ok := d.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+			if data, err := d.decodeStream(stream); err == nil {
+				font.toUnicode, font.codeLen = parseCMap(data, font.codeLen)
+			}
+		}
+	}
+
+	if isRef {
+		d.fonts[ref] = font
+	}
+	return font
+}
+
+func (f *pdfFont) decode(s pdfString) string {
+	if f.toUnicode == nil {
+		if f.composite {
+			return ""
+		}
+		return decodeSimple(s)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		n := f.codeLen
+		if i+n > len(s) {
+			n = len(s) - i
+		}
+		if text, ok := f.toUnicode[string(s[i:i+n])]; ok {
+			b.WriteString(text)
+		} else if !f.composite && s[i] >= 0x20 && s[i] < 0x7F {
+			b.WriteByte(s[i])
+		}
+		i += n
+	}
+	return b.String()
+}
+
+// decodeSimple 没有 ToUnicode 的简单字体按 Latin-1 解码，忽略控制字符
+func decodeSimple(s pdfString) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= 0x20 && c != 0x7F && (c < 0x80 || c >= 0xA0) {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// parseCMap 解析 ToUnicode CMap 的 bfchar 和 bfrange，返回映射和编码字节数
+func parseCMap(data []byte, defaultLen int) (map[string]string, int) {
+	mapping := make(map[string]string)
+	codeLen := 0
+	lexer := &pdfLexer{data: data, noRefs: true}
+
+	tokens := make([]interface{}, 0)
+	for {
+		obj, err := lexer.object()
+		if err != nil {
+			break
+		}
+		tokens = append(tokens, obj)
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		op, ok := tokens[i].(pdfOp)
+		if !ok {
+			continue
+		}
+		switch op {
+		case "begincodespacerange":
+			if i+1 < len(tokens) {
+				if lo, ok := tokens[i+1].(pdfString); ok && codeLen == 0 {
+					codeLen = len(lo)
+				}
+			}
+		case "beginbfchar":
+			for i++; i+1 < len(tokens) && tokens[i] != pdfOp("endbfchar"); i += 2 {
+				src, ok1 := tokens[i].(pdfString)
+				dst, ok2 := tokens[i+1].(pdfString)
+				if ok1 && ok2 {
+					mapping[string(src)] = utf16BE(dst)
+				}
+			}
+		case "beginbfrange":
+			for i++; i+2 < len(tokens) && tokens[i] != pdfOp("endbfrange"); i += 3 {
+				lo, ok1 := tokens[i].(pdfString)
+				hi, ok2 := tokens[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 {
+					continue
+				}
+				start, end := codeValue(lo), codeValue(hi)
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+				switch dst := tokens[i+2].(type) {
+				case pdfString:
+					base := []rune(utf16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for code := start; code <= end; code++ {
+						text := append([]rune(nil), base...)
+						text[len(text)-1] += rune(code - start)
+						mapping[string(codeBytes(code, len(lo)))] = string(text)
+					}
+				case pdfArray:
+					for j, item := range dst {
+						if text, ok := item.(pdfString); ok && start+j <= end {
+							mapping[string(codeBytes(start+j, len(lo)))] = utf16BE(text)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if codeLen == 0 {
+		codeLen = defaultLen
+		for src := range mapping {
+			codeLen = len(src)
+			break
+		}
+	}
+	return mapping, codeLen
+}
+
+func codeValue(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func codeBytes(v, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
+
+func utf16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfLexer PDF 词法和语法解析
+type pdfLexer struct {
+	data   []byte
+	pos    int
+	noRefs bool // 内容流中没有间接引用，不做 "N G R" 的预读
+}
+
+func isPDFSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelim(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// next 读取下一个记号
+func (l *pdfLexer) next() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfName(decodeName(l.data[start:l.pos])), nil
+	case c == '(':
+		return l.literalString(), nil
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfDelim("<<"), nil
+		}
+		return l.hexString(), nil
+	case c == '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+		}
+		return pdfDelim(">>"), nil
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return pdfDelim(string(c)), nil
+	case c == ')':
+		l.pos++
+		return l.next()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		start := l.pos
+		for l.pos < len(l.data) && strings.IndexByte("+-.0123456789", l.data[l.pos]) >= 0 {
+			l.pos++
+		}
+		f, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+		if err != nil {
+			return 0.0, nil
+		}
+		return f, nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	switch word := string(l.data[start:l.pos]); word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return pdfOp(word), nil
+	}
+}
+
+// object 读取一个完整的对象，数组和字典递归解析
+func (l *pdfLexer) object() (interface{}, error) {
+	return l.objectDepth(0)
+}
+
+func (l *pdfLexer) objectDepth(depth int) (interface{}, error) {
+	if depth > pdfMaxNesting {
+		return nil, fmt.Errorf("PDF 对象嵌套过深")
+	}
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case pdfDelim:
+		switch t {
+		case "[":
+			array := make(pdfArray, 0)
+			for {
+				l.skipSpace()
+				if l.pos < len(l.data) && l.data[l.pos] == ']' {
+					l.pos++
+					return array, nil
+				}
+				item, err := l.objectDepth(depth + 1)
+				if err != nil {
+					return array, err
+				}
+				array = append(array, item)
+			}
+		case "<<":
+			dict := make(pdfDict)
+			for {
+				key, err := l.next()
+				if err != nil {
+					return dict, err
+				}
+				if key == pdfDelim(">>") {
+					return dict, nil
+				}
+				name, ok := key.(pdfName)
+				if !ok {
+					continue
+				}
+				value, err := l.objectDepth(depth + 1)
+				if err != nil {
+					return dict, err
+				}
+				dict[name] = value
+			}
+		}
+	case float64:
+		if l.noRefs {
+			return t, nil
+		}
+		// 预读 "N G R"
+		save := l.pos
+		if gen, err := l.next(); err == nil {
+			if genF, ok := gen.(float64); ok {
+				if r, err := l.next(); err == nil && r == pdfOp("R") {
+					return pdfRef{num: int(t), gen: int(genF)}, nil
+				}
+			}
+		}
+		l.pos = save
+	}
+	return tok, nil
+}
+
+// stream 字典之后如果是 stream 关键字则读取流数据
+func (l *pdfLexer) stream(dict pdfDict) (*pdfStream, bool) {
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return nil, false
+	}
+	l.pos += len("stream")
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+
+	// 优先使用直接给出的 Length，校验不通过时查找 endstream
+	if length, ok := dict["Length"].(float64); ok {
+		end := start + int(length)
+		if end <= len(l.data) && bytes.HasPrefix(bytes.TrimLeft(l.data[end:], "\r\n "), []byte("endstream")) {
+			l.pos = end
+			return &pdfStream{dict: dict, data: l.data[start:end]}, true
+		}
+	}
+	i := bytes.Index(l.data[start:], []byte("endstream"))
+	if i < 0 {
+		l.pos = len(l.data)
+		return &pdfStream{dict: dict, data: l.data[start:]}, true
+	}
+	end := start + i
+	l.pos = end + len("endstream")
+	data := bytes.TrimSuffix(l.data[start:end], []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+	return &pdfStream{dict: dict, data: data}, true
+}
+
+// skipInlineImage 跳过内联图片 BI ... ID <数据> EI
+func (l *pdfLexer) skipInlineImage() {
+	i := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if i < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += i + 2
+	for l.pos+2 < len(l.data) {
+		if isPDFSpace(l.data[l.pos]) && l.data[l.pos+1] == 'E' && l.data[l.pos+2] == 'I' &&
+			(l.pos+3 == len(l.data) || isPDFSpace(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // (
+	out := make([]byte, 0)
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; k++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++ // <
+	start := l.pos
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		l.pos++
+	}
+	raw := l.data[start:l.pos]
+	if l.pos < len(l.data) {
+		l.pos++
+	}
+	decoded, _ := decodeASCIIHex(raw)
+	return decoded
+}
+
+// decodeName 处理名称中的 #xx 转义
+func decodeName(raw []byte) string {
+	if bytes.IndexByte(raw, '#') < 0 {
+		return string(raw)
+	}
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, raw[i])
+	}
+	return string(out)
+}
@@ -0,0 +1,179 @@
+package ingest
+
+import (
+	"regexp"
+	"strings"
+)
+
+var blankLines = regexp.MustCompile(`\n\s*\n`)
+
+// extractText 纯文本按空行分段
+func extractText(data []byte) ([]Paragraph, error) {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	blocks := blankLines.Split(content, -1)
+
+	paragraphs := make([]Paragraph, 0, len(blocks))
+	for _, block := range blocks {
+		paragraphs = append(paragraphs, Paragraph{
+			Content: block,
+			Meta:    ParagraphMeta{Type: BlockText},
+		})
+	}
+	return paragraphs, nil
+}
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListItem  = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)
+	mdFence     = regexp.MustCompile("^\\s*(```|~~~)")
+	mdTableSep  = regexp.MustCompile(`^\s*\|?\s*:?-{2,}:?\s*(\|\s*:?-{2,}:?\s*)*\|?\s*$`)
+	mdSetextH1  = regexp.MustCompile(`^=+\s*$`)
+	mdSetextH2  = regexp.MustCompile(`^-+\s*$`)
+	mdInlineFmt = regexp.MustCompile("(\\*\\*|__|`)")
+	mdLink      = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+)
+
+// markdownParser 按行解析 Markdown，保留标题路径、列表、代码块和表格行
+type markdownParser struct {
+	headings   headingStack
+	paragraphs []Paragraph
+	block      []string
+	blockType  string
+}
+
+// extractMarkdown 解析 Markdown
+// 标题单独成段并记录级别，其余段落记录所在标题路径；表格每行一段，格式为 "列名: 值"
+func extractMarkdown(data []byte) ([]Paragraph, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	p := &markdownParser{}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// 代码块原样保留
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			p.flush()
+			code := make([]string, 0)
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]); i++ {
+				code = append(code, lines[i])
+			}
+			p.add(BlockCode, strings.Join(code, "\n"))
+			continue
+		}
+
+		if trimmed == "" {
+			p.flush()
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(trimmed); m != nil {
+			p.flush()
+			p.heading(len(m[1]), m[2])
+			continue
+		}
+
+		// Setext 风格标题：文本下一行是 === 或 ---
+		if i+1 < len(lines) && len(p.block) == 0 && !strings.HasPrefix(trimmed, "|") {
+			next := strings.TrimSpace(lines[i+1])
+			if next != "" && (mdSetextH1.MatchString(next) || mdSetextH2.MatchString(next)) {
+				level := 1
+				if mdSetextH2.MatchString(next) {
+					level = 2
+				}
+				p.heading(level, trimmed)
+				i++
+				continue
+			}
+		}
+
+		// 表格：表头行后紧跟分隔行
+		if strings.Contains(trimmed, "|") && i+1 < len(lines) && mdTableSep.MatchString(lines[i+1]) {
+			p.flush()
+			columns := splitTableRow(trimmed)
+			row := 0
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				row++
+				p.paragraphs = append(p.paragraphs, Paragraph{
+					Content: cleanInline(rowContent(columns, splitTableRow(lines[i]))),
+					Meta: ParagraphMeta{
+						Type:     BlockTableRow,
+						Headings: p.headings.path(),
+						Row:      row,
+						Columns:  columns,
+					},
+				})
+			}
+			i--
+			continue
+		}
+
+		lineType := BlockText
+		if mdListItem.MatchString(line) {
+			lineType = BlockList
+		}
+		// 列表和正文之间切换时分段
+		if len(p.block) > 0 && p.blockType != lineType && !(p.blockType == BlockList && strings.HasPrefix(line, " ")) {
+			p.flush()
+		}
+		if len(p.block) == 0 {
+			p.blockType = lineType
+		}
+		p.block = append(p.block, strings.TrimRight(line, " \t"))
+	}
+	p.flush()
+
+	return p.paragraphs, nil
+}
+
+func (p *markdownParser) heading(level int, text string) {
+	text = cleanInline(text)
+	p.headings.push(level, text)
+	p.paragraphs = append(p.paragraphs, Paragraph{
+		Content: text,
+		Meta: ParagraphMeta{
+			Type:     BlockHeading,
+			Headings: p.headings.path(),
+			Level:    level,
+		},
+	})
+}
+
+func (p *markdownParser) add(blockType, content string) {
+	if blockType != BlockCode {
+		content = cleanInline(content)
+	}
+	p.paragraphs = append(p.paragraphs, Paragraph{
+		Content: content,
+		Meta: ParagraphMeta{
+			Type:     blockType,
+			Headings: p.headings.path(),
+		},
+	})
+}
+
+func (p *markdownParser) flush() {
+	if len(p.block) == 0 {
+		return
+	}
+	p.add(p.blockType, strings.Join(p.block, "\n"))
+	p.block = nil
+}
+
+// splitTableRow 拆分 Markdown 表格行，去掉首尾的竖线
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// cleanInline 去掉加粗、行内代码等标记，链接和图片只保留文字
+func cleanInline(text string) string {
+	text = mdLink.ReplaceAllString(text, "$1")
+	return mdInlineFmt.ReplaceAllString(text, "")
+}
@@ -4,10 +4,45 @@ package rag_svr
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/cloudwego/kitex/pkg/streaming"
 	"github.com/cloudwego/prutal"
 )
 
+// 错误码，对应各响应的 code 字段
+type ErrCode int32
+
+const (
+	ErrCode_ERR_SUCCESS           ErrCode = 0    // 成功
+	ErrCode_ERR_FAILED            ErrCode = 1    // 通用错误
+	ErrCode_ERR_QUOTA_EXCEEDED    ErrCode = 1001 // token 配额已用完
+	ErrCode_ERR_PERMISSION_DENIED ErrCode = 1002 // 无权访问或修改该文档、知识库
+)
+
+// Enum value maps for ErrCode.
+var ErrCode_name = map[int32]string{
+	0:    "ERR_SUCCESS",
+	1:    "ERR_FAILED",
+	1001: "ERR_QUOTA_EXCEEDED",
+	1002: "ERR_PERMISSION_DENIED",
+}
+
+var ErrCode_value = map[string]int32{
+	"ERR_SUCCESS":           0,
+	"ERR_FAILED":            1,
+	"ERR_QUOTA_EXCEEDED":    1001,
+	"ERR_PERMISSION_DENIED": 1002,
+}
+
+func (x ErrCode) String() string {
+	s, ok := ErrCode_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+
 // 基础响应
 type BaseRsp struct {
 	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
@@ -542,1232 +577,4133 @@ func (x *EndSessionRsp) GetMsg() string {
 	return ""
 }
 
-// 知识文档
-type Document struct {
-	DocId      uint64 `protobuf:"varint,1,opt,name=doc_id" json:"doc_id,omitempty"`
-	UserId     uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
-	Title      string `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"`
-	Content    string `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
-	Metadata   string `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
-	CreateTime uint64 `protobuf:"varint,6,opt,name=create_time" json:"create_time,omitempty"`
-	UpdateTime uint64 `protobuf:"varint,7,opt,name=update_time" json:"update_time,omitempty"`
+// 更新会话请求，目前支持修改标题
+type UpdateSessionReq struct {
+	SeqId     uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	SessionId uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	UserId    uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Title     string `protobuf:"bytes,4,opt,name=title" json:"title,omitempty"` // 会话标题
 }
 
-func (x *Document) Reset() { *x = Document{} }
+func (x *UpdateSessionReq) Reset() { *x = UpdateSessionReq{} }
 
-func (x *Document) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *UpdateSessionReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *Document) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *UpdateSessionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *Document) GetDocId() uint64 {
+func (x *UpdateSessionReq) GetSeqId() uint32 {
 	if x != nil {
-		return x.DocId
+		return x.SeqId
 	}
 	return 0
 }
 
-func (x *Document) GetUserId() uint64 {
+func (x *UpdateSessionReq) GetSessionId() uint64 {
 	if x != nil {
-		return x.UserId
+		return x.SessionId
 	}
 	return 0
 }
 
-func (x *Document) GetTitle() string {
+func (x *UpdateSessionReq) GetUserId() uint64 {
 	if x != nil {
-		return x.Title
+		return x.UserId
 	}
-	return ""
+	return 0
 }
 
-func (x *Document) GetContent() string {
+func (x *UpdateSessionReq) GetTitle() string {
 	if x != nil {
-		return x.Content
+		return x.Title
 	}
 	return ""
 }
 
-func (x *Document) GetMetadata() string {
+type UpdateSessionRsp struct {
+	Code        uint32       `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg         string       `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	SessionInfo *SessionInfo `protobuf:"bytes,3,opt,name=session_info" json:"session_info,omitempty"`
+}
+
+func (x *UpdateSessionRsp) Reset() { *x = UpdateSessionRsp{} }
+
+func (x *UpdateSessionRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *UpdateSessionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *UpdateSessionRsp) GetCode() uint32 {
 	if x != nil {
-		return x.Metadata
+		return x.Code
 	}
-	return ""
+	return 0
 }
 
-func (x *Document) GetCreateTime() uint64 {
+func (x *UpdateSessionRsp) GetMsg() string {
 	if x != nil {
-		return x.CreateTime
+		return x.Msg
 	}
-	return 0
+	return ""
 }
 
-func (x *Document) GetUpdateTime() uint64 {
+func (x *UpdateSessionRsp) GetSessionInfo() *SessionInfo {
 	if x != nil {
-		return x.UpdateTime
+		return x.SessionInfo
 	}
-	return 0
+	return nil
 }
 
-type AddDocumentReq struct {
-	SeqId    uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	UserId   uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
-	Title    string `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"`
-	Content  string `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
-	Metadata string `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
+// 知识文档
+type Document struct {
+	DocId        uint64         `protobuf:"varint,1,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId       uint64         `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Title        string         `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"`
+	Content      string         `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
+	Metadata     string         `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
+	CreateTime   uint64         `protobuf:"varint,6,opt,name=create_time" json:"create_time,omitempty"`
+	UpdateTime   uint64         `protobuf:"varint,7,opt,name=update_time" json:"update_time,omitempty"`
+	Status       string         `protobuf:"bytes,8,opt,name=status" json:"status,omitempty"`                 // pending/active/failed
+	Chunker      *ChunkerConfig `protobuf:"bytes,9,opt,name=chunker" json:"chunker,omitempty"`               // 索引时使用的切块配置
+	Version      uint32         `protobuf:"varint,10,opt,name=version" json:"version,omitempty"`             // 当前可检索的版本，新版本索引完成后切换
+	CollectionId uint64         `protobuf:"varint,11,opt,name=collection_id" json:"collection_id,omitempty"` // 所属知识库ID，0 表示未归入知识库
+	Tags         []string       `protobuf:"bytes,12,rep,name=tags" json:"tags,omitempty"`
+	Role         string         `protobuf:"bytes,13,opt,name=role" json:"role,omitempty"` // 请求用户在文档上的角色：owner/editor/viewer
 }
 
-func (x *AddDocumentReq) Reset() { *x = AddDocumentReq{} }
+func (x *Document) Reset() { *x = Document{} }
 
-func (x *AddDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *Document) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *AddDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *Document) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *AddDocumentReq) GetSeqId() uint32 {
+func (x *Document) GetDocId() uint64 {
 	if x != nil {
-		return x.SeqId
+		return x.DocId
 	}
 	return 0
 }
 
-func (x *AddDocumentReq) GetUserId() uint64 {
+func (x *Document) GetUserId() uint64 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-func (x *AddDocumentReq) GetTitle() string {
+func (x *Document) GetTitle() string {
 	if x != nil {
 		return x.Title
 	}
 	return ""
 }
 
-func (x *AddDocumentReq) GetContent() string {
+func (x *Document) GetContent() string {
 	if x != nil {
 		return x.Content
 	}
 	return ""
 }
 
-func (x *AddDocumentReq) GetMetadata() string {
+func (x *Document) GetMetadata() string {
 	if x != nil {
 		return x.Metadata
 	}
 	return ""
 }
 
-type AddDocumentRsp struct {
-	Code  uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg   string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	DocId uint64 `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
+func (x *Document) GetCreateTime() uint64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
 }
 
-func (x *AddDocumentRsp) Reset() { *x = AddDocumentRsp{} }
-
-func (x *AddDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *AddDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *AddDocumentRsp) GetCode() uint32 {
+func (x *Document) GetUpdateTime() uint64 {
 	if x != nil {
-		return x.Code
+		return x.UpdateTime
 	}
 	return 0
 }
 
-func (x *AddDocumentRsp) GetMsg() string {
+func (x *Document) GetStatus() string {
 	if x != nil {
-		return x.Msg
+		return x.Status
 	}
 	return ""
 }
 
-func (x *AddDocumentRsp) GetDocId() uint64 {
+func (x *Document) GetChunker() *ChunkerConfig {
 	if x != nil {
-		return x.DocId
+		return x.Chunker
 	}
-	return 0
+	return nil
 }
 
-type ListDocumentReq struct {
-	UserId   uint64 `protobuf:"varint,1,opt,name=user_id" json:"user_id,omitempty"`
-	Page     int32  `protobuf:"varint,2,opt,name=page" json:"page,omitempty"`
-	PageSize int32  `protobuf:"varint,3,opt,name=page_size" json:"page_size,omitempty"`
+func (x *Document) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
 }
 
-func (x *ListDocumentReq) Reset() { *x = ListDocumentReq{} }
-
-func (x *ListDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *ListDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *ListDocumentReq) GetUserId() uint64 {
+func (x *Document) GetCollectionId() uint64 {
 	if x != nil {
-		return x.UserId
+		return x.CollectionId
 	}
 	return 0
 }
 
-func (x *ListDocumentReq) GetPage() int32 {
+func (x *Document) GetTags() []string {
 	if x != nil {
-		return x.Page
+		return x.Tags
 	}
-	return 0
+	return nil
 }
 
-func (x *ListDocumentReq) GetPageSize() int32 {
+func (x *Document) GetRole() string {
 	if x != nil {
-		return x.PageSize
+		return x.Role
 	}
-	return 0
+	return ""
 }
 
-type ListDocumentRsp struct {
-	Code      uint32      `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg       string      `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	Total     int64       `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`
-	Page      int32       `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`
-	PageSize  int32       `protobuf:"varint,5,opt,name=page_size" json:"page_size,omitempty"`
-	Documents []*Document `protobuf:"bytes,6,rep,name=documents" json:"documents,omitempty"`
+// 文档切块配置，strategy 为空时使用知识库或全局配置，参数为 0 时使用策略的默认值
+type ChunkerConfig struct {
+	Strategy   string   `protobuf:"bytes,1,opt,name=strategy" json:"strategy,omitempty"`        // sentence_window / fixed_token / markdown / recursive
+	WindowSize int32    `protobuf:"varint,2,opt,name=window_size" json:"window_size,omitempty"` // sentence_window：每块的句子数
+	Step       int32    `protobuf:"varint,3,opt,name=step" json:"step,omitempty"`               // sentence_window：窗口步长
+	ChunkSize  int32    `protobuf:"varint,4,opt,name=chunk_size" json:"chunk_size,omitempty"`   // fixed_token、markdown 为 token 数，recursive 为字符数
+	Overlap    int32    `protobuf:"varint,5,opt,name=overlap" json:"overlap,omitempty"`         // 相邻块重叠的 token 数或字符数，小于 0 表示不重叠
+	Separators []string `protobuf:"bytes,6,rep,name=separators" json:"separators,omitempty"`    // recursive：分隔符，按顺序尝试
 }
 
-func (x *ListDocumentRsp) Reset() { *x = ListDocumentRsp{} }
+func (x *ChunkerConfig) Reset() { *x = ChunkerConfig{} }
 
-func (x *ListDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *ChunkerConfig) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *ListDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *ChunkerConfig) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *ListDocumentRsp) GetCode() uint32 {
+func (x *ChunkerConfig) GetStrategy() string {
 	if x != nil {
-		return x.Code
+		return x.Strategy
 	}
-	return 0
+	return ""
 }
 
-func (x *ListDocumentRsp) GetMsg() string {
+func (x *ChunkerConfig) GetWindowSize() int32 {
 	if x != nil {
-		return x.Msg
+		return x.WindowSize
 	}
-	return ""
+	return 0
 }
 
-func (x *ListDocumentRsp) GetTotal() int64 {
+func (x *ChunkerConfig) GetStep() int32 {
 	if x != nil {
-		return x.Total
+		return x.Step
 	}
 	return 0
 }
 
-func (x *ListDocumentRsp) GetPage() int32 {
+func (x *ChunkerConfig) GetChunkSize() int32 {
 	if x != nil {
-		return x.Page
+		return x.ChunkSize
 	}
 	return 0
 }
 
-func (x *ListDocumentRsp) GetPageSize() int32 {
+func (x *ChunkerConfig) GetOverlap() int32 {
 	if x != nil {
-		return x.PageSize
+		return x.Overlap
 	}
 	return 0
 }
 
-func (x *ListDocumentRsp) GetDocuments() []*Document {
+func (x *ChunkerConfig) GetSeparators() []string {
 	if x != nil {
-		return x.Documents
+		return x.Separators
 	}
 	return nil
 }
 
-type SearchDocumentReq struct {
-	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	UserId uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
-	Query  string `protobuf:"bytes,3,opt,name=query" json:"query,omitempty"`
-	TopK   int32  `protobuf:"varint,4,opt,name=top_k" json:"top_k,omitempty"`
+type AddDocumentReq struct {
+	SeqId        uint32         `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId       uint64         `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Title        string         `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"`
+	Content      string         `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"` // 文本内容，data 为空时使用
+	Metadata     string         `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
+	MimeType     string         `protobuf:"bytes,6,opt,name=mime_type" json:"mime_type,omitempty"`           // 内容类型，如 text/markdown、application/pdf，为空时根据文件名和内容识别
+	Data         []byte         `protobuf:"bytes,7,opt,name=data" json:"data,omitempty"`                     // 原始文件内容，用于 PDF、DOCX 等二进制格式
+	FileName     string         `protobuf:"bytes,8,opt,name=file_name" json:"file_name,omitempty"`           // 原始文件名，用于识别内容类型
+	Collection   string         `protobuf:"bytes,9,opt,name=collection" json:"collection,omitempty"`         // 知识库名称，用于选择切块配置
+	Chunker      *ChunkerConfig `protobuf:"bytes,10,opt,name=chunker" json:"chunker,omitempty"`              // 单独指定切块配置，优先于知识库和全局配置
+	CollectionId uint64         `protobuf:"varint,11,opt,name=collection_id" json:"collection_id,omitempty"` // 所属知识库ID，0 表示不归入知识库，检索时可按知识库过滤
+	Tags         []string       `protobuf:"bytes,12,rep,name=tags" json:"tags,omitempty"`                    // 文档标签，检索和列表可按标签过滤
 }
 
-func (x *SearchDocumentReq) Reset() { *x = SearchDocumentReq{} }
+func (x *AddDocumentReq) Reset() { *x = AddDocumentReq{} }
 
-func (x *SearchDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *AddDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *SearchDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *AddDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *SearchDocumentReq) GetSeqId() uint32 {
+func (x *AddDocumentReq) GetSeqId() uint32 {
 	if x != nil {
 		return x.SeqId
 	}
 	return 0
 }
 
-func (x *SearchDocumentReq) GetUserId() uint64 {
+func (x *AddDocumentReq) GetUserId() uint64 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-func (x *SearchDocumentReq) GetQuery() string {
+func (x *AddDocumentReq) GetTitle() string {
 	if x != nil {
-		return x.Query
+		return x.Title
 	}
 	return ""
 }
 
-func (x *SearchDocumentReq) GetTopK() int32 {
+func (x *AddDocumentReq) GetContent() string {
 	if x != nil {
-		return x.TopK
+		return x.Content
 	}
-	return 0
+	return ""
 }
 
-type SearchDocumentRsp struct {
-	Code      uint32      `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg       string      `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	Documents []*Document `protobuf:"bytes,3,rep,name=documents" json:"documents,omitempty"`
-	Scores    []float32   `protobuf:"fixed32,4,rep,packed,name=scores" json:"scores,omitempty"`
+func (x *AddDocumentReq) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
 }
 
-func (x *SearchDocumentRsp) Reset() { *x = SearchDocumentRsp{} }
-
-func (x *SearchDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *AddDocumentReq) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
 
-func (x *SearchDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *AddDocumentReq) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
 
-func (x *SearchDocumentRsp) GetCode() uint32 {
+func (x *AddDocumentReq) GetFileName() string {
 	if x != nil {
-		return x.Code
+		return x.FileName
 	}
-	return 0
+	return ""
 }
 
-func (x *SearchDocumentRsp) GetMsg() string {
+func (x *AddDocumentReq) GetCollection() string {
 	if x != nil {
-		return x.Msg
+		return x.Collection
 	}
 	return ""
 }
 
-func (x *SearchDocumentRsp) GetDocuments() []*Document {
+func (x *AddDocumentReq) GetChunker() *ChunkerConfig {
 	if x != nil {
-		return x.Documents
+		return x.Chunker
 	}
 	return nil
 }
 
-func (x *SearchDocumentRsp) GetScores() []float32 {
+func (x *AddDocumentReq) GetCollectionId() uint64 {
 	if x != nil {
-		return x.Scores
+		return x.CollectionId
+	}
+	return 0
+}
+
+func (x *AddDocumentReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
 	}
 	return nil
 }
 
-// 会话管理
-type GetSessionListReq struct {
-	UserId    uint64 `protobuf:"varint,1,opt,name=user_id" json:"user_id,omitempty"`
-	Status    string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`         // 可选，会话状态过滤
-	StartTime string `protobuf:"bytes,3,opt,name=start_time" json:"start_time,omitempty"` // 可选，开始时间
-	EndTime   string `protobuf:"bytes,4,opt,name=end_time" json:"end_time,omitempty"`     // 可选，结束时间
-	Page      int32  `protobuf:"varint,5,opt,name=page" json:"page,omitempty"`            // 页码，从1开始
-	PageSize  int32  `protobuf:"varint,6,opt,name=page_size" json:"page_size,omitempty"`  // 每页大小
+type AddDocumentRsp struct {
+	Code  uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg   string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DocId uint64 `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
 }
 
-func (x *GetSessionListReq) Reset() { *x = GetSessionListReq{} }
+func (x *AddDocumentRsp) Reset() { *x = AddDocumentRsp{} }
 
-func (x *GetSessionListReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *AddDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *GetSessionListReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *AddDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *GetSessionListReq) GetUserId() uint64 {
+func (x *AddDocumentRsp) GetCode() uint32 {
 	if x != nil {
-		return x.UserId
+		return x.Code
 	}
 	return 0
 }
 
-func (x *GetSessionListReq) GetStatus() string {
+func (x *AddDocumentRsp) GetMsg() string {
 	if x != nil {
-		return x.Status
+		return x.Msg
 	}
 	return ""
 }
 
-func (x *GetSessionListReq) GetStartTime() string {
+func (x *AddDocumentRsp) GetDocId() uint64 {
 	if x != nil {
-		return x.StartTime
+		return x.DocId
 	}
-	return ""
+	return 0
 }
 
-func (x *GetSessionListReq) GetEndTime() string {
-	if x != nil {
-		return x.EndTime
-	}
-	return ""
+// 从已上传的文件添加文档，文件内容存放在 MongoDB GridFS 中
+type AddDocumentFileReq struct {
+	SeqId        uint32         `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId       uint64         `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Title        string         `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"` // 为空时使用文件名
+	Metadata     string         `protobuf:"bytes,4,opt,name=metadata" json:"metadata,omitempty"`
+	FileId       string         `protobuf:"bytes,5,opt,name=file_id" json:"file_id,omitempty"` // GridFS 文件ID
+	FileName     string         `protobuf:"bytes,6,opt,name=file_name" json:"file_name,omitempty"`
+	MimeType     string         `protobuf:"bytes,7,opt,name=mime_type" json:"mime_type,omitempty"` // 上传时识别的内容类型
+	Collection   string         `protobuf:"bytes,8,opt,name=collection" json:"collection,omitempty"`
+	Chunker      *ChunkerConfig `protobuf:"bytes,9,opt,name=chunker" json:"chunker,omitempty"`
+	CollectionId uint64         `protobuf:"varint,10,opt,name=collection_id" json:"collection_id,omitempty"` // 所属知识库ID，0 表示不归入知识库
+	Tags         []string       `protobuf:"bytes,11,rep,name=tags" json:"tags,omitempty"`
 }
 
-func (x *GetSessionListReq) GetPage() int32 {
+func (x *AddDocumentFileReq) Reset() { *x = AddDocumentFileReq{} }
+
+func (x *AddDocumentFileReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddDocumentFileReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddDocumentFileReq) GetSeqId() uint32 {
 	if x != nil {
-		return x.Page
+		return x.SeqId
 	}
 	return 0
 }
 
-func (x *GetSessionListReq) GetPageSize() int32 {
+func (x *AddDocumentFileReq) GetUserId() uint64 {
 	if x != nil {
-		return x.PageSize
+		return x.UserId
 	}
 	return 0
 }
 
-type GetSessionListRsp struct {
-	Code        uint32         `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg         string         `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	Total       int64          `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`              // 总数
-	Page        int32          `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`                // 当前页码
-	PageSize    int32          `protobuf:"varint,5,opt,name=page_size" json:"page_size,omitempty"`      // 每页大小
-	SessionList []*SessionInfo `protobuf:"bytes,6,rep,name=session_list" json:"session_list,omitempty"` // 会话列表
-}
-
-func (x *GetSessionListRsp) Reset() { *x = GetSessionListRsp{} }
-
-func (x *GetSessionListRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *GetSessionListRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *GetSessionListRsp) GetCode() uint32 {
+func (x *AddDocumentFileReq) GetTitle() string {
 	if x != nil {
-		return x.Code
+		return x.Title
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSessionListRsp) GetMsg() string {
+func (x *AddDocumentFileReq) GetMetadata() string {
 	if x != nil {
-		return x.Msg
+		return x.Metadata
 	}
 	return ""
 }
 
-func (x *GetSessionListRsp) GetTotal() int64 {
+func (x *AddDocumentFileReq) GetFileId() string {
 	if x != nil {
-		return x.Total
+		return x.FileId
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSessionListRsp) GetPage() int32 {
+func (x *AddDocumentFileReq) GetFileName() string {
 	if x != nil {
-		return x.Page
+		return x.FileName
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSessionListRsp) GetPageSize() int32 {
+func (x *AddDocumentFileReq) GetMimeType() string {
 	if x != nil {
-		return x.PageSize
+		return x.MimeType
 	}
-	return 0
+	return ""
 }
 
-func (x *GetSessionListRsp) GetSessionList() []*SessionInfo {
+func (x *AddDocumentFileReq) GetCollection() string {
 	if x != nil {
-		return x.SessionList
+		return x.Collection
 	}
-	return nil
-}
-
-// 获取会话详情
-type GetSessionReq struct {
-	SeqId     uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	SessionId uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
-	UserId    uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	return ""
 }
 
-func (x *GetSessionReq) Reset() { *x = GetSessionReq{} }
-
-func (x *GetSessionReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *GetSessionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *GetSessionReq) GetSeqId() uint32 {
+func (x *AddDocumentFileReq) GetChunker() *ChunkerConfig {
 	if x != nil {
-		return x.SeqId
+		return x.Chunker
 	}
-	return 0
+	return nil
 }
 
-func (x *GetSessionReq) GetSessionId() uint64 {
+func (x *AddDocumentFileReq) GetCollectionId() uint64 {
 	if x != nil {
-		return x.SessionId
+		return x.CollectionId
 	}
 	return 0
 }
 
-func (x *GetSessionReq) GetUserId() uint64 {
+func (x *AddDocumentFileReq) GetTags() []string {
 	if x != nil {
-		return x.UserId
+		return x.Tags
 	}
-	return 0
+	return nil
 }
 
-type GetSessionRsp struct {
-	Code        uint32       `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg         string       `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	SessionInfo *SessionInfo `protobuf:"bytes,3,opt,name=session_info" json:"session_info,omitempty"`
+type AddDocumentFileRsp struct {
+	Code  uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg   string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DocId uint64 `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
 }
 
-func (x *GetSessionRsp) Reset() { *x = GetSessionRsp{} }
+func (x *AddDocumentFileRsp) Reset() { *x = AddDocumentFileRsp{} }
 
-func (x *GetSessionRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *AddDocumentFileRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *GetSessionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *AddDocumentFileRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *GetSessionRsp) GetCode() uint32 {
+func (x *AddDocumentFileRsp) GetCode() uint32 {
 	if x != nil {
 		return x.Code
 	}
 	return 0
 }
 
-func (x *GetSessionRsp) GetMsg() string {
+func (x *AddDocumentFileRsp) GetMsg() string {
 	if x != nil {
 		return x.Msg
 	}
 	return ""
 }
 
-func (x *GetSessionRsp) GetSessionInfo() *SessionInfo {
+func (x *AddDocumentFileRsp) GetDocId() uint64 {
 	if x != nil {
-		return x.SessionInfo
+		return x.DocId
 	}
-	return nil
+	return 0
 }
 
-// 删除文档
-type DeleteDocumentReq struct {
-	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	DocId  uint64 `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
-	UserId uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+// 更新文档，未指定的字段保持不变
+type UpdateDocumentReq struct {
+	SeqId    uint32         `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId    uint64         `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId   uint64         `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Title    string         `protobuf:"bytes,4,opt,name=title" json:"title,omitempty"`
+	Metadata string         `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
+	Content  string         `protobuf:"bytes,6,opt,name=content" json:"content,omitempty"` // 新的文本内容，与 data 都为空时不更新内容
+	MimeType string         `protobuf:"bytes,7,opt,name=mime_type" json:"mime_type,omitempty"`
+	Data     []byte         `protobuf:"bytes,8,opt,name=data" json:"data,omitempty"` // 新的原始文件内容
+	FileName string         `protobuf:"bytes,9,opt,name=file_name" json:"file_name,omitempty"`
+	Chunker  *ChunkerConfig `protobuf:"bytes,10,opt,name=chunker" json:"chunker,omitempty"` // 新的切块配置，变化时重新切块
 }
 
-func (x *DeleteDocumentReq) Reset() { *x = DeleteDocumentReq{} }
+func (x *UpdateDocumentReq) Reset() { *x = UpdateDocumentReq{} }
 
-func (x *DeleteDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *UpdateDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *DeleteDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *UpdateDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *DeleteDocumentReq) GetSeqId() uint32 {
+func (x *UpdateDocumentReq) GetSeqId() uint32 {
 	if x != nil {
 		return x.SeqId
 	}
 	return 0
 }
 
-func (x *DeleteDocumentReq) GetDocId() uint64 {
+func (x *UpdateDocumentReq) GetDocId() uint64 {
 	if x != nil {
 		return x.DocId
 	}
 	return 0
 }
 
-func (x *DeleteDocumentReq) GetUserId() uint64 {
+func (x *UpdateDocumentReq) GetUserId() uint64 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-type DeleteDocumentRsp struct {
-	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+func (x *UpdateDocumentReq) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
 }
 
-func (x *DeleteDocumentRsp) Reset() { *x = DeleteDocumentRsp{} }
-
-func (x *DeleteDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *DeleteDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *DeleteDocumentRsp) GetCode() uint32 {
+func (x *UpdateDocumentReq) GetMetadata() string {
 	if x != nil {
-		return x.Code
+		return x.Metadata
 	}
-	return 0
+	return ""
 }
 
-func (x *DeleteDocumentRsp) GetMsg() string {
+func (x *UpdateDocumentReq) GetContent() string {
 	if x != nil {
-		return x.Msg
+		return x.Content
 	}
 	return ""
 }
 
-type CleanInactiveSessionsReq struct {
-	InactiveDays int32 `protobuf:"varint,1,opt,name=inactive_days" json:"inactive_days,omitempty"` // 不活跃天数
+func (x *UpdateDocumentReq) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
 }
 
-func (x *CleanInactiveSessionsReq) Reset() { *x = CleanInactiveSessionsReq{} }
-
-func (x *CleanInactiveSessionsReq) Marshal(in []byte) ([]byte, error) {
-	return prutal.MarshalAppend(in, x)
+func (x *UpdateDocumentReq) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
 }
 
-func (x *CleanInactiveSessionsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *UpdateDocumentReq) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
 
-func (x *CleanInactiveSessionsReq) GetInactiveDays() int32 {
+func (x *UpdateDocumentReq) GetChunker() *ChunkerConfig {
 	if x != nil {
-		return x.InactiveDays
+		return x.Chunker
 	}
-	return 0
+	return nil
 }
 
-type CleanInactiveSessionsRsp struct {
-	Code         uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg          string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	CleanedCount int64  `protobuf:"varint,3,opt,name=cleaned_count" json:"cleaned_count,omitempty"` // 清理的会话数量
+type UpdateDocumentRsp struct {
+	Code                uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg                 string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DocId               uint64 `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
+	ParagraphsAdded     uint32 `protobuf:"varint,4,opt,name=paragraphs_added" json:"paragraphs_added,omitempty"`
+	ParagraphsRemoved   uint32 `protobuf:"varint,5,opt,name=paragraphs_removed" json:"paragraphs_removed,omitempty"`
+	ParagraphsUnchanged uint32 `protobuf:"varint,6,opt,name=paragraphs_unchanged" json:"paragraphs_unchanged,omitempty"`
+	Reindexing          bool   `protobuf:"varint,7,opt,name=reindexing" json:"reindexing,omitempty"` // 是否已创建索引任务，只有内容变化的块重新生成向量
+	Version             uint32 `protobuf:"varint,8,opt,name=version" json:"version,omitempty"`       // 内容或切块配置变化时生成的新版本
 }
 
-func (x *CleanInactiveSessionsRsp) Reset() { *x = CleanInactiveSessionsRsp{} }
+func (x *UpdateDocumentRsp) Reset() { *x = UpdateDocumentRsp{} }
 
-func (x *CleanInactiveSessionsRsp) Marshal(in []byte) ([]byte, error) {
-	return prutal.MarshalAppend(in, x)
-}
+func (x *UpdateDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *CleanInactiveSessionsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *UpdateDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *CleanInactiveSessionsRsp) GetCode() uint32 {
+func (x *UpdateDocumentRsp) GetCode() uint32 {
 	if x != nil {
 		return x.Code
 	}
 	return 0
 }
 
-func (x *CleanInactiveSessionsRsp) GetMsg() string {
+func (x *UpdateDocumentRsp) GetMsg() string {
 	if x != nil {
 		return x.Msg
 	}
 	return ""
 }
 
-func (x *CleanInactiveSessionsRsp) GetCleanedCount() int64 {
+func (x *UpdateDocumentRsp) GetDocId() uint64 {
 	if x != nil {
-		return x.CleanedCount
+		return x.DocId
 	}
 	return 0
 }
 
-// 记忆管理
-type Memory struct {
-	MemoryId    uint64  `protobuf:"varint,1,opt,name=memory_id" json:"memory_id,omitempty"`
-	SessionId   uint64  `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
-	UserId      uint64  `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
-	Content     string  `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
-	MemoryType  string  `protobuf:"bytes,5,opt,name=memory_type" json:"memory_type,omitempty"`
-	Importance  float64 `protobuf:"fixed64,6,opt,name=importance" json:"importance,omitempty"`
-	Metadata    string  `protobuf:"bytes,7,opt,name=metadata" json:"metadata,omitempty"`
-	CreateTime  uint64  `protobuf:"varint,8,opt,name=create_time" json:"create_time,omitempty"`
-	UpdateTime  uint64  `protobuf:"varint,9,opt,name=update_time" json:"update_time,omitempty"`
-	ExpireTime  uint64  `protobuf:"varint,10,opt,name=expire_time" json:"expire_time,omitempty"`
-	AccessCount int32   `protobuf:"varint,11,opt,name=access_count" json:"access_count,omitempty"`
+func (x *UpdateDocumentRsp) GetParagraphsAdded() uint32 {
+	if x != nil {
+		return x.ParagraphsAdded
+	}
+	return 0
 }
 
-func (x *Memory) Reset() { *x = Memory{} }
-
-func (x *Memory) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *Memory) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *UpdateDocumentRsp) GetParagraphsRemoved() uint32 {
+	if x != nil {
+		return x.ParagraphsRemoved
+	}
+	return 0
+}
 
-func (x *Memory) GetMemoryId() uint64 {
+func (x *UpdateDocumentRsp) GetParagraphsUnchanged() uint32 {
 	if x != nil {
-		return x.MemoryId
+		return x.ParagraphsUnchanged
 	}
 	return 0
 }
 
-func (x *Memory) GetSessionId() uint64 {
+func (x *UpdateDocumentRsp) GetReindexing() bool {
 	if x != nil {
-		return x.SessionId
+		return x.Reindexing
+	}
+	return false
+}
+
+func (x *UpdateDocumentRsp) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
 	}
 	return 0
 }
 
-func (x *Memory) GetUserId() uint64 {
+// 文档版本，每次内容或切块配置变化生成一个，创建后不再修改
+type DocumentVersion struct {
+	Version        uint32         `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	ContentHash    string         `protobuf:"bytes,2,opt,name=content_hash" json:"content_hash,omitempty"` // 段落内容的 SHA-256
+	Chunker        *ChunkerConfig `protobuf:"bytes,3,opt,name=chunker" json:"chunker,omitempty"`
+	EmbeddingModel string         `protobuf:"bytes,4,opt,name=embedding_model" json:"embedding_model,omitempty"`
+	ParagraphCount uint32         `protobuf:"varint,5,opt,name=paragraph_count" json:"paragraph_count,omitempty"`
+	SentenceCount  uint32         `protobuf:"varint,6,opt,name=sentence_count" json:"sentence_count,omitempty"`
+	RestoredFrom   uint32         `protobuf:"varint,7,opt,name=restored_from" json:"restored_from,omitempty"` // 从哪个版本恢复，0 表示编辑产生
+	UserId         uint64         `protobuf:"varint,8,opt,name=user_id" json:"user_id,omitempty"`             // 创建版本的用户
+	CreateTime     uint64         `protobuf:"varint,9,opt,name=create_time" json:"create_time,omitempty"`
+	Active         bool           `protobuf:"varint,10,opt,name=active" json:"active,omitempty"` // 是否为当前可检索的版本
+}
+
+func (x *DocumentVersion) Reset() { *x = DocumentVersion{} }
+
+func (x *DocumentVersion) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DocumentVersion) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DocumentVersion) GetVersion() uint32 {
 	if x != nil {
-		return x.UserId
+		return x.Version
 	}
 	return 0
 }
 
-func (x *Memory) GetContent() string {
+func (x *DocumentVersion) GetContentHash() string {
 	if x != nil {
-		return x.Content
+		return x.ContentHash
 	}
 	return ""
 }
 
-func (x *Memory) GetMemoryType() string {
+func (x *DocumentVersion) GetChunker() *ChunkerConfig {
 	if x != nil {
-		return x.MemoryType
+		return x.Chunker
+	}
+	return nil
+}
+
+func (x *DocumentVersion) GetEmbeddingModel() string {
+	if x != nil {
+		return x.EmbeddingModel
 	}
 	return ""
 }
 
-func (x *Memory) GetImportance() float64 {
+func (x *DocumentVersion) GetParagraphCount() uint32 {
 	if x != nil {
-		return x.Importance
+		return x.ParagraphCount
 	}
 	return 0
 }
 
-func (x *Memory) GetMetadata() string {
+func (x *DocumentVersion) GetSentenceCount() uint32 {
 	if x != nil {
-		return x.Metadata
+		return x.SentenceCount
 	}
-	return ""
+	return 0
 }
 
-func (x *Memory) GetCreateTime() uint64 {
+func (x *DocumentVersion) GetRestoredFrom() uint32 {
 	if x != nil {
-		return x.CreateTime
+		return x.RestoredFrom
 	}
 	return 0
 }
 
-func (x *Memory) GetUpdateTime() uint64 {
+func (x *DocumentVersion) GetUserId() uint64 {
 	if x != nil {
-		return x.UpdateTime
+		return x.UserId
 	}
 	return 0
 }
 
-func (x *Memory) GetExpireTime() uint64 {
+func (x *DocumentVersion) GetCreateTime() uint64 {
 	if x != nil {
-		return x.ExpireTime
+		return x.CreateTime
 	}
 	return 0
 }
 
-func (x *Memory) GetAccessCount() int32 {
+func (x *DocumentVersion) GetActive() bool {
 	if x != nil {
-		return x.AccessCount
+		return x.Active
 	}
-	return 0
+	return false
 }
 
-type AddMemoryReq struct {
-	SeqId      uint32  `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	SessionId  uint64  `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
-	UserId     uint64  `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
-	Content    string  `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
-	MemoryType string  `protobuf:"bytes,5,opt,name=memory_type" json:"memory_type,omitempty"`
-	Importance float64 `protobuf:"fixed64,6,opt,name=importance" json:"importance,omitempty"`
-	Metadata   string  `protobuf:"bytes,7,opt,name=metadata" json:"metadata,omitempty"`
+type ListDocumentVersionsReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId  uint64 `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
 }
 
-func (x *AddMemoryReq) Reset() { *x = AddMemoryReq{} }
+func (x *ListDocumentVersionsReq) Reset() { *x = ListDocumentVersionsReq{} }
 
-func (x *AddMemoryReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *ListDocumentVersionsReq) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
 
-func (x *AddMemoryReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *ListDocumentVersionsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *AddMemoryReq) GetSeqId() uint32 {
+func (x *ListDocumentVersionsReq) GetSeqId() uint32 {
 	if x != nil {
 		return x.SeqId
 	}
 	return 0
 }
 
-func (x *AddMemoryReq) GetSessionId() uint64 {
+func (x *ListDocumentVersionsReq) GetDocId() uint64 {
 	if x != nil {
-		return x.SessionId
+		return x.DocId
 	}
 	return 0
 }
 
-func (x *AddMemoryReq) GetUserId() uint64 {
+func (x *ListDocumentVersionsReq) GetUserId() uint64 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-func (x *AddMemoryReq) GetContent() string {
+type ListDocumentVersionsRsp struct {
+	Code          uint32             `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg           string             `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DocId         uint64             `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
+	ActiveVersion uint32             `protobuf:"varint,4,opt,name=active_version" json:"active_version,omitempty"`
+	Versions      []*DocumentVersion `protobuf:"bytes,5,rep,name=versions" json:"versions,omitempty"` // 按版本号从新到旧
+}
+
+func (x *ListDocumentVersionsRsp) Reset() { *x = ListDocumentVersionsRsp{} }
+
+func (x *ListDocumentVersionsRsp) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *ListDocumentVersionsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListDocumentVersionsRsp) GetCode() uint32 {
 	if x != nil {
-		return x.Content
+		return x.Code
 	}
-	return ""
+	return 0
 }
 
-func (x *AddMemoryReq) GetMemoryType() string {
+func (x *ListDocumentVersionsRsp) GetMsg() string {
 	if x != nil {
-		return x.MemoryType
+		return x.Msg
 	}
 	return ""
 }
 
-func (x *AddMemoryReq) GetImportance() float64 {
+func (x *ListDocumentVersionsRsp) GetDocId() uint64 {
 	if x != nil {
-		return x.Importance
+		return x.DocId
 	}
 	return 0
 }
 
-func (x *AddMemoryReq) GetMetadata() string {
+func (x *ListDocumentVersionsRsp) GetActiveVersion() uint32 {
 	if x != nil {
-		return x.Metadata
+		return x.ActiveVersion
 	}
-	return ""
+	return 0
 }
 
-type AddMemoryRsp struct {
+func (x *ListDocumentVersionsRsp) GetVersions() []*DocumentVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// 恢复文档版本，恢复结果作为一个新版本保存并重新索引
+type RestoreDocumentVersionReq struct {
+	SeqId   uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId   uint64 `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId  uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Version uint32 `protobuf:"varint,4,opt,name=version" json:"version,omitempty"` // 要恢复的版本
+}
+
+func (x *RestoreDocumentVersionReq) Reset() { *x = RestoreDocumentVersionReq{} }
+
+func (x *RestoreDocumentVersionReq) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *RestoreDocumentVersionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *RestoreDocumentVersionReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *RestoreDocumentVersionReq) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *RestoreDocumentVersionReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RestoreDocumentVersionReq) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type RestoreDocumentVersionRsp struct {
+	Code    uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg     string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DocId   uint64 `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
+	Version uint32 `protobuf:"varint,4,opt,name=version" json:"version,omitempty"` // 新版本号
+}
+
+func (x *RestoreDocumentVersionRsp) Reset() { *x = RestoreDocumentVersionRsp{} }
+
+func (x *RestoreDocumentVersionRsp) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *RestoreDocumentVersionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *RestoreDocumentVersionRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RestoreDocumentVersionRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *RestoreDocumentVersionRsp) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *RestoreDocumentVersionRsp) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// 文档索引任务
+type DocumentIndexJob struct {
+	JobId        uint64 `protobuf:"varint,1,opt,name=job_id" json:"job_id,omitempty"`
+	Status       string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"` // pending/running/succeeded/failed
+	TotalChunks  uint32 `protobuf:"varint,3,opt,name=total_chunks" json:"total_chunks,omitempty"`
+	DoneChunks   uint32 `protobuf:"varint,4,opt,name=done_chunks" json:"done_chunks,omitempty"`
+	Attempts     uint32 `protobuf:"varint,5,opt,name=attempts" json:"attempts,omitempty"`
+	MaxAttempts  uint32 `protobuf:"varint,6,opt,name=max_attempts" json:"max_attempts,omitempty"`
+	LastError    string `protobuf:"bytes,7,opt,name=last_error" json:"last_error,omitempty"`
+	CreateTime   uint64 `protobuf:"varint,8,opt,name=create_time" json:"create_time,omitempty"`
+	StartTime    uint64 `protobuf:"varint,9,opt,name=start_time" json:"start_time,omitempty"`    // 最近一次开始执行的时间，未执行为 0
+	FinishTime   uint64 `protobuf:"varint,10,opt,name=finish_time" json:"finish_time,omitempty"` // 成功或最终失败的时间，未结束为 0
+	NextRunTime  uint64 `protobuf:"varint,11,opt,name=next_run_time" json:"next_run_time,omitempty"`
+	ReusedChunks uint32 `protobuf:"varint,12,opt,name=reused_chunks" json:"reused_chunks,omitempty"` // 内容未变、沿用原向量的块数
+}
+
+func (x *DocumentIndexJob) Reset() { *x = DocumentIndexJob{} }
+
+func (x *DocumentIndexJob) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DocumentIndexJob) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DocumentIndexJob) GetJobId() uint64 {
+	if x != nil {
+		return x.JobId
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DocumentIndexJob) GetTotalChunks() uint32 {
+	if x != nil {
+		return x.TotalChunks
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetDoneChunks() uint32 {
+	if x != nil {
+		return x.DoneChunks
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetMaxAttempts() uint32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DocumentIndexJob) GetCreateTime() uint64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetStartTime() uint64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetFinishTime() uint64 {
+	if x != nil {
+		return x.FinishTime
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetNextRunTime() uint64 {
+	if x != nil {
+		return x.NextRunTime
+	}
+	return 0
+}
+
+func (x *DocumentIndexJob) GetReusedChunks() uint32 {
+	if x != nil {
+		return x.ReusedChunks
+	}
+	return 0
+}
+
+type GetDocumentStatusReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId  uint64 `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *GetDocumentStatusReq) Reset() { *x = GetDocumentStatusReq{} }
+
+func (x *GetDocumentStatusReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetDocumentStatusReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetDocumentStatusReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *GetDocumentStatusReq) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *GetDocumentStatusReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetDocumentStatusRsp struct {
+	Code   uint32            `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg    string            `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DocId  uint64            `protobuf:"varint,3,opt,name=doc_id" json:"doc_id,omitempty"`
+	Status string            `protobuf:"bytes,4,opt,name=status" json:"status,omitempty"` // 文档状态
+	Job    *DocumentIndexJob `protobuf:"bytes,5,opt,name=job" json:"job,omitempty"`       // 最近一次索引任务，没有时为空
+}
+
+func (x *GetDocumentStatusRsp) Reset() { *x = GetDocumentStatusRsp{} }
+
+func (x *GetDocumentStatusRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetDocumentStatusRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetDocumentStatusRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GetDocumentStatusRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GetDocumentStatusRsp) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *GetDocumentStatusRsp) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetDocumentStatusRsp) GetJob() *DocumentIndexJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type ListDocumentReq struct {
+	UserId          uint64            `protobuf:"varint,1,opt,name=user_id" json:"user_id,omitempty"` // 列出该用户创建的和被共享给该用户的文档
+	Page            int32             `protobuf:"varint,2,opt,name=page" json:"page,omitempty"`
+	PageSize        int32             `protobuf:"varint,3,opt,name=page_size" json:"page_size,omitempty"`
+	CollectionId    uint64            `protobuf:"varint,4,opt,name=collection_id" json:"collection_id,omitempty"`                                                                                // 只列出该知识库的文档，0 表示不限制
+	CollectionIds   []uint64          `protobuf:"varint,5,rep,packed,name=collection_ids" json:"collection_ids,omitempty"`                                                                       // 只列出这些知识库的文档，与 collection_id 合并
+	Tags            []string          `protobuf:"bytes,6,rep,name=tags" json:"tags,omitempty"`                                                                                                   // 只列出带有全部这些标签的文档
+	MetadataFilters map[string]string `protobuf:"bytes,7,rep,name=metadata_filters" json:"metadata_filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 元数据过滤，键为 metadata 中的字段路径（如 dept 或 source.type），值须相等
+}
+
+func (x *ListDocumentReq) Reset() { *x = ListDocumentReq{} }
+
+func (x *ListDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListDocumentReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListDocumentReq) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDocumentReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDocumentReq) GetCollectionId() uint64 {
+	if x != nil {
+		return x.CollectionId
+	}
+	return 0
+}
+
+func (x *ListDocumentReq) GetCollectionIds() []uint64 {
+	if x != nil {
+		return x.CollectionIds
+	}
+	return nil
+}
+
+func (x *ListDocumentReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListDocumentReq) GetMetadataFilters() map[string]string {
+	if x != nil {
+		return x.MetadataFilters
+	}
+	return nil
+}
+
+type ListDocumentRsp struct {
+	Code      uint32      `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg       string      `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Total     int64       `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`
+	Page      int32       `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`
+	PageSize  int32       `protobuf:"varint,5,opt,name=page_size" json:"page_size,omitempty"`
+	Documents []*Document `protobuf:"bytes,6,rep,name=documents" json:"documents,omitempty"`
+}
+
+func (x *ListDocumentRsp) Reset() { *x = ListDocumentRsp{} }
+
+func (x *ListDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListDocumentRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ListDocumentRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ListDocumentRsp) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListDocumentRsp) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDocumentRsp) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDocumentRsp) GetDocuments() []*Document {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+type SearchDocumentReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Query  string `protobuf:"bytes,3,opt,name=query" json:"query,omitempty"`
+	TopK   int32  `protobuf:"varint,4,opt,name=top_k" json:"top_k,omitempty"`
+
+	// 向量检索与关键词检索在 RRF 融合中的权重，都为 0 时使用配置的默认值，只有一个为 0 时不执行该路检索
+	VectorWeight  float32 `protobuf:"fixed32,5,opt,name=vector_weight" json:"vector_weight,omitempty"`
+	KeywordWeight float32 `protobuf:"fixed32,6,opt,name=keyword_weight" json:"keyword_weight,omitempty"`
+
+	// 重排分数下限，低于该值的结果不返回，为 0 时使用配置的值；未配置重排模型时不生效
+	MinScore float32 `protobuf:"fixed32,7,opt,name=min_score" json:"min_score,omitempty"`
+
+	// 每个结果前后附带的相邻句子数，0 表示不附带，最多 5 句
+	ContextSentences uint32 `protobuf:"varint,8,opt,name=context_sentences" json:"context_sentences,omitempty"`
+
+	// 检索范围，只检索 user_id 创建的和被共享的文档；指定了知识库或文档时只检索其中的块
+	CollectionIds []uint64 `protobuf:"varint,9,rep,packed,name=collection_ids" json:"collection_ids,omitempty"`
+	DocIds        []uint64 `protobuf:"varint,10,rep,packed,name=doc_ids" json:"doc_ids,omitempty"`
+
+	// 只检索带有全部这些标签、且元数据字段与 metadata_filters 相等的文档
+	Tags            []string          `protobuf:"bytes,11,rep,name=tags" json:"tags,omitempty"`
+	MetadataFilters map[string]string `protobuf:"bytes,12,rep,name=metadata_filters" json:"metadata_filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (x *SearchDocumentReq) Reset() { *x = SearchDocumentReq{} }
+
+func (x *SearchDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SearchDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SearchDocumentReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchDocumentReq) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetVectorWeight() float32 {
+	if x != nil {
+		return x.VectorWeight
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetKeywordWeight() float32 {
+	if x != nil {
+		return x.KeywordWeight
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetMinScore() float32 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetContextSentences() uint32 {
+	if x != nil {
+		return x.ContextSentences
+	}
+	return 0
+}
+
+func (x *SearchDocumentReq) GetCollectionIds() []uint64 {
+	if x != nil {
+		return x.CollectionIds
+	}
+	return nil
+}
+
+func (x *SearchDocumentReq) GetDocIds() []uint64 {
+	if x != nil {
+		return x.DocIds
+	}
+	return nil
+}
+
+func (x *SearchDocumentReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SearchDocumentReq) GetMetadataFilters() map[string]string {
+	if x != nil {
+		return x.MetadataFilters
+	}
+	return nil
+}
+
+// 检索命中的块，用于精确引用原文
+type SearchHit struct {
+	DocId         uint64   `protobuf:"varint,1,opt,name=doc_id" json:"doc_id,omitempty"`
+	ChunkId       uint64   `protobuf:"varint,2,opt,name=chunk_id" json:"chunk_id,omitempty"`
+	ParagraphId   uint64   `protobuf:"varint,3,opt,name=paragraph_id" json:"paragraph_id,omitempty"`       // 块起始句子所在的段落
+	SentenceIdMin uint64   `protobuf:"varint,4,opt,name=sentence_id_min" json:"sentence_id_min,omitempty"` // 块覆盖的句子范围，文档内从 1 开始编号
+	SentenceIdMax uint64   `protobuf:"varint,5,opt,name=sentence_id_max" json:"sentence_id_max,omitempty"`
+	Content       string   `protobuf:"bytes,6,opt,name=content" json:"content,omitempty"`               // 块的完整文本
+	Highlights    []string `protobuf:"bytes,7,rep,name=highlights" json:"highlights,omitempty"`         // 文本中出现的查询词（jieba 分词），按出现位置排列
+	ContextBefore string   `protobuf:"bytes,8,opt,name=context_before" json:"context_before,omitempty"` // 块之前的相邻句子，按 context_sentences 附带
+	ContextAfter  string   `protobuf:"bytes,9,opt,name=context_after" json:"context_after,omitempty"`   // 块之后的相邻句子
+}
+
+func (x *SearchHit) Reset() { *x = SearchHit{} }
+
+func (x *SearchHit) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SearchHit) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SearchHit) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *SearchHit) GetChunkId() uint64 {
+	if x != nil {
+		return x.ChunkId
+	}
+	return 0
+}
+
+func (x *SearchHit) GetParagraphId() uint64 {
+	if x != nil {
+		return x.ParagraphId
+	}
+	return 0
+}
+
+func (x *SearchHit) GetSentenceIdMin() uint64 {
+	if x != nil {
+		return x.SentenceIdMin
+	}
+	return 0
+}
+
+func (x *SearchHit) GetSentenceIdMax() uint64 {
+	if x != nil {
+		return x.SentenceIdMax
+	}
+	return 0
+}
+
+func (x *SearchHit) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SearchHit) GetHighlights() []string {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+func (x *SearchHit) GetContextBefore() string {
+	if x != nil {
+		return x.ContextBefore
+	}
+	return ""
+}
+
+func (x *SearchHit) GetContextAfter() string {
+	if x != nil {
+		return x.ContextAfter
+	}
+	return ""
+}
+
+type SearchDocumentRsp struct {
+	Code      uint32       `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg       string       `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Documents []*Document  `protobuf:"bytes,3,rep,name=documents" json:"documents,omitempty"`    // content 为命中块的完整文本
+	Scores    []float32    `protobuf:"fixed32,4,rep,packed,name=scores" json:"scores,omitempty"` // 与 documents 一一对应，配置了重排模型时为重排分数，否则为 RRF 融合分数
+	Hits      []*SearchHit `protobuf:"bytes,5,rep,name=hits" json:"hits,omitempty"`              // 与 documents 一一对应
+}
+
+func (x *SearchDocumentRsp) Reset() { *x = SearchDocumentRsp{} }
+
+func (x *SearchDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SearchDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SearchDocumentRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SearchDocumentRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *SearchDocumentRsp) GetDocuments() []*Document {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+func (x *SearchDocumentRsp) GetScores() []float32 {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *SearchDocumentRsp) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+// 会话管理
+type GetSessionListReq struct {
+	UserId    uint64 `protobuf:"varint,1,opt,name=user_id" json:"user_id,omitempty"`
+	Status    string `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`         // 可选，会话状态过滤
+	StartTime string `protobuf:"bytes,3,opt,name=start_time" json:"start_time,omitempty"` // 可选，开始时间
+	EndTime   string `protobuf:"bytes,4,opt,name=end_time" json:"end_time,omitempty"`     // 可选，结束时间
+	Page      int32  `protobuf:"varint,5,opt,name=page" json:"page,omitempty"`            // 页码，从1开始
+	PageSize  int32  `protobuf:"varint,6,opt,name=page_size" json:"page_size,omitempty"`  // 每页大小
+}
+
+func (x *GetSessionListReq) Reset() { *x = GetSessionListReq{} }
+
+func (x *GetSessionListReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetSessionListReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetSessionListReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetSessionListReq) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetSessionListReq) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *GetSessionListReq) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *GetSessionListReq) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetSessionListReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetSessionListRsp struct {
+	Code        uint32         `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg         string         `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Total       int64          `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`              // 总数
+	Page        int32          `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`                // 当前页码
+	PageSize    int32          `protobuf:"varint,5,opt,name=page_size" json:"page_size,omitempty"`      // 每页大小
+	SessionList []*SessionInfo `protobuf:"bytes,6,rep,name=session_list" json:"session_list,omitempty"` // 会话列表
+}
+
+func (x *GetSessionListRsp) Reset() { *x = GetSessionListRsp{} }
+
+func (x *GetSessionListRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetSessionListRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetSessionListRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GetSessionListRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GetSessionListRsp) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetSessionListRsp) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetSessionListRsp) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetSessionListRsp) GetSessionList() []*SessionInfo {
+	if x != nil {
+		return x.SessionList
+	}
+	return nil
+}
+
+// 获取会话详情
+type GetSessionReq struct {
+	SeqId     uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	SessionId uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	UserId    uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *GetSessionReq) Reset() { *x = GetSessionReq{} }
+
+func (x *GetSessionReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetSessionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetSessionReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *GetSessionReq) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *GetSessionReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetSessionRsp struct {
+	Code        uint32       `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg         string       `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	SessionInfo *SessionInfo `protobuf:"bytes,3,opt,name=session_info" json:"session_info,omitempty"`
+}
+
+func (x *GetSessionRsp) Reset() { *x = GetSessionRsp{} }
+
+func (x *GetSessionRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetSessionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetSessionRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GetSessionRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GetSessionRsp) GetSessionInfo() *SessionInfo {
+	if x != nil {
+		return x.SessionInfo
+	}
+	return nil
+}
+
+// 删除文档
+type DeleteDocumentReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId  uint64 `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *DeleteDocumentReq) Reset() { *x = DeleteDocumentReq{} }
+
+func (x *DeleteDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DeleteDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DeleteDocumentReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *DeleteDocumentReq) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *DeleteDocumentReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type DeleteDocumentRsp struct {
+	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (x *DeleteDocumentRsp) Reset() { *x = DeleteDocumentRsp{} }
+
+func (x *DeleteDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DeleteDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DeleteDocumentRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *DeleteDocumentRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 知识库，文档按知识库分组，检索和对话可以限定在选定的知识库中
+type KnowledgeCollection struct {
+	CollectionId  uint64 `protobuf:"varint,1,opt,name=collection_id" json:"collection_id,omitempty"`
+	UserId        uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Name          string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Description   string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+	DocumentCount int64  `protobuf:"varint,5,opt,name=document_count" json:"document_count,omitempty"`
+	CreateTime    uint64 `protobuf:"varint,6,opt,name=create_time" json:"create_time,omitempty"`
+	UpdateTime    uint64 `protobuf:"varint,7,opt,name=update_time" json:"update_time,omitempty"`
+	Role          string `protobuf:"bytes,8,opt,name=role" json:"role,omitempty"` // 请求用户在知识库上的角色：owner/editor/viewer
+}
+
+func (x *KnowledgeCollection) Reset() { *x = KnowledgeCollection{} }
+
+func (x *KnowledgeCollection) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *KnowledgeCollection) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *KnowledgeCollection) GetCollectionId() uint64 {
+	if x != nil {
+		return x.CollectionId
+	}
+	return 0
+}
+
+func (x *KnowledgeCollection) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *KnowledgeCollection) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KnowledgeCollection) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *KnowledgeCollection) GetDocumentCount() int64 {
+	if x != nil {
+		return x.DocumentCount
+	}
+	return 0
+}
+
+func (x *KnowledgeCollection) GetCreateTime() uint64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *KnowledgeCollection) GetUpdateTime() uint64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+func (x *KnowledgeCollection) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type CreateCollectionReq struct {
+	SeqId       uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId      uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"` // 同一用户下唯一
+	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+}
+
+func (x *CreateCollectionReq) Reset() { *x = CreateCollectionReq{} }
+
+func (x *CreateCollectionReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *CreateCollectionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *CreateCollectionReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *CreateCollectionReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateCollectionReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCollectionReq) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type CreateCollectionRsp struct {
+	Code       uint32               `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg        string               `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Collection *KnowledgeCollection `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+}
+
+func (x *CreateCollectionRsp) Reset() { *x = CreateCollectionRsp{} }
+
+func (x *CreateCollectionRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *CreateCollectionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *CreateCollectionRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CreateCollectionRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *CreateCollectionRsp) GetCollection() *KnowledgeCollection {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+type ListCollectionsReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *ListCollectionsReq) Reset() { *x = ListCollectionsReq{} }
+
+func (x *ListCollectionsReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListCollectionsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListCollectionsReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *ListCollectionsReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type ListCollectionsRsp struct {
+	Code        uint32                 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg         string                 `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Collections []*KnowledgeCollection `protobuf:"bytes,3,rep,name=collections" json:"collections,omitempty"` // 按创建时间排列
+}
+
+func (x *ListCollectionsRsp) Reset() { *x = ListCollectionsRsp{} }
+
+func (x *ListCollectionsRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListCollectionsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListCollectionsRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ListCollectionsRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ListCollectionsRsp) GetCollections() []*KnowledgeCollection {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+type UpdateCollectionReq struct {
+	SeqId        uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	CollectionId uint64 `protobuf:"varint,2,opt,name=collection_id" json:"collection_id,omitempty"`
+	UserId       uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Name         string `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"` // 为空时不修改
+	Description  string `protobuf:"bytes,5,opt,name=description" json:"description,omitempty"`
+}
+
+func (x *UpdateCollectionReq) Reset() { *x = UpdateCollectionReq{} }
+
+func (x *UpdateCollectionReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *UpdateCollectionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *UpdateCollectionReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *UpdateCollectionReq) GetCollectionId() uint64 {
+	if x != nil {
+		return x.CollectionId
+	}
+	return 0
+}
+
+func (x *UpdateCollectionReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateCollectionReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCollectionReq) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type UpdateCollectionRsp struct {
+	Code       uint32               `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg        string               `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Collection *KnowledgeCollection `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+}
+
+func (x *UpdateCollectionRsp) Reset() { *x = UpdateCollectionRsp{} }
+
+func (x *UpdateCollectionRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *UpdateCollectionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *UpdateCollectionRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *UpdateCollectionRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *UpdateCollectionRsp) GetCollection() *KnowledgeCollection {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+// 删除知识库，知识库中还有文档时不能删除
+type DeleteCollectionReq struct {
+	SeqId        uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	CollectionId uint64 `protobuf:"varint,2,opt,name=collection_id" json:"collection_id,omitempty"`
+	UserId       uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *DeleteCollectionReq) Reset() { *x = DeleteCollectionReq{} }
+
+func (x *DeleteCollectionReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DeleteCollectionReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DeleteCollectionReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *DeleteCollectionReq) GetCollectionId() uint64 {
+	if x != nil {
+		return x.CollectionId
+	}
+	return 0
+}
+
+func (x *DeleteCollectionReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type DeleteCollectionRsp struct {
+	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (x *DeleteCollectionRsp) Reset() { *x = DeleteCollectionRsp{} }
+
+func (x *DeleteCollectionRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DeleteCollectionRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DeleteCollectionRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *DeleteCollectionRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 把文档移到另一个知识库，collection_id 为 0 表示移出知识库
+type MoveDocumentReq struct {
+	SeqId        uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId        uint64 `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId       uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	CollectionId uint64 `protobuf:"varint,4,opt,name=collection_id" json:"collection_id,omitempty"`
+}
+
+func (x *MoveDocumentReq) Reset() { *x = MoveDocumentReq{} }
+
+func (x *MoveDocumentReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *MoveDocumentReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *MoveDocumentReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *MoveDocumentReq) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *MoveDocumentReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MoveDocumentReq) GetCollectionId() uint64 {
+	if x != nil {
+		return x.CollectionId
+	}
+	return 0
+}
+
+type MoveDocumentRsp struct {
+	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (x *MoveDocumentRsp) Reset() { *x = MoveDocumentRsp{} }
+
+func (x *MoveDocumentRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *MoveDocumentRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *MoveDocumentRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *MoveDocumentRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 替换文档的全部标签，tags 为空时清除标签
+type SetDocumentTagsReq struct {
+	SeqId  uint32   `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	DocId  uint64   `protobuf:"varint,2,opt,name=doc_id" json:"doc_id,omitempty"`
+	UserId uint64   `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Tags   []string `protobuf:"bytes,4,rep,name=tags" json:"tags,omitempty"`
+}
+
+func (x *SetDocumentTagsReq) Reset() { *x = SetDocumentTagsReq{} }
+
+func (x *SetDocumentTagsReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SetDocumentTagsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SetDocumentTagsReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *SetDocumentTagsReq) GetDocId() uint64 {
+	if x != nil {
+		return x.DocId
+	}
+	return 0
+}
+
+func (x *SetDocumentTagsReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SetDocumentTagsReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SetDocumentTagsRsp struct {
+	Code uint32   `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg  string   `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Tags []string `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"` // 规范化后的标签
+}
+
+func (x *SetDocumentTagsRsp) Reset() { *x = SetDocumentTagsRsp{} }
+
+func (x *SetDocumentTagsRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SetDocumentTagsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SetDocumentTagsRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SetDocumentTagsRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *SetDocumentTagsRsp) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// 共享与权限：知识库或文档的角色可以授予用户或用户组，知识库上的角色对其中的文档同样生效
+// viewer 可以查看和检索，editor 还可以修改内容、标签和向知识库添加文档，owner 还可以删除、移动和共享
+type AccessGrant struct {
+	Id            uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	ResourceType  string `protobuf:"bytes,2,opt,name=resource_type" json:"resource_type,omitempty"` // collection/document
+	ResourceId    uint64 `protobuf:"varint,3,opt,name=resource_id" json:"resource_id,omitempty"`
+	PrincipalType string `protobuf:"bytes,4,opt,name=principal_type" json:"principal_type,omitempty"` // user/group
+	PrincipalId   uint64 `protobuf:"varint,5,opt,name=principal_id" json:"principal_id,omitempty"`
+	Role          string `protobuf:"bytes,6,opt,name=role" json:"role,omitempty"` // owner/editor/viewer
+	GrantedBy     uint64 `protobuf:"varint,7,opt,name=granted_by" json:"granted_by,omitempty"`
+	CreateTime    uint64 `protobuf:"varint,8,opt,name=create_time" json:"create_time,omitempty"`
+	UpdateTime    uint64 `protobuf:"varint,9,opt,name=update_time" json:"update_time,omitempty"`
+}
+
+func (x *AccessGrant) Reset() { *x = AccessGrant{} }
+
+func (x *AccessGrant) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AccessGrant) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AccessGrant) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AccessGrant) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AccessGrant) GetResourceId() uint64 {
+	if x != nil {
+		return x.ResourceId
+	}
+	return 0
+}
+
+func (x *AccessGrant) GetPrincipalType() string {
+	if x != nil {
+		return x.PrincipalType
+	}
+	return ""
+}
+
+func (x *AccessGrant) GetPrincipalId() uint64 {
+	if x != nil {
+		return x.PrincipalId
+	}
+	return 0
+}
+
+func (x *AccessGrant) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AccessGrant) GetGrantedBy() uint64 {
+	if x != nil {
+		return x.GrantedBy
+	}
+	return 0
+}
+
+func (x *AccessGrant) GetCreateTime() uint64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *AccessGrant) GetUpdateTime() uint64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+// 授予角色，已有授权时改为新的角色，只有所有者可以授权
+type GrantAccessReq struct {
+	SeqId         uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId        uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	ResourceType  string `protobuf:"bytes,3,opt,name=resource_type" json:"resource_type,omitempty"`
+	ResourceId    uint64 `protobuf:"varint,4,opt,name=resource_id" json:"resource_id,omitempty"`
+	PrincipalType string `protobuf:"bytes,5,opt,name=principal_type" json:"principal_type,omitempty"`
+	PrincipalId   uint64 `protobuf:"varint,6,opt,name=principal_id" json:"principal_id,omitempty"`
+	Role          string `protobuf:"bytes,7,opt,name=role" json:"role,omitempty"`
+}
+
+func (x *GrantAccessReq) Reset() { *x = GrantAccessReq{} }
+
+func (x *GrantAccessReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GrantAccessReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GrantAccessReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *GrantAccessReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GrantAccessReq) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *GrantAccessReq) GetResourceId() uint64 {
+	if x != nil {
+		return x.ResourceId
+	}
+	return 0
+}
+
+func (x *GrantAccessReq) GetPrincipalType() string {
+	if x != nil {
+		return x.PrincipalType
+	}
+	return ""
+}
+
+func (x *GrantAccessReq) GetPrincipalId() uint64 {
+	if x != nil {
+		return x.PrincipalId
+	}
+	return 0
+}
+
+func (x *GrantAccessReq) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type GrantAccessRsp struct {
+	Code  uint32       `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg   string       `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Grant *AccessGrant `protobuf:"bytes,3,opt,name=grant" json:"grant,omitempty"`
+}
+
+func (x *GrantAccessRsp) Reset() { *x = GrantAccessRsp{} }
+
+func (x *GrantAccessRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GrantAccessRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GrantAccessRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GrantAccessRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GrantAccessRsp) GetGrant() *AccessGrant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+type RevokeAccessReq struct {
+	SeqId         uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId        uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	ResourceType  string `protobuf:"bytes,3,opt,name=resource_type" json:"resource_type,omitempty"`
+	ResourceId    uint64 `protobuf:"varint,4,opt,name=resource_id" json:"resource_id,omitempty"`
+	PrincipalType string `protobuf:"bytes,5,opt,name=principal_type" json:"principal_type,omitempty"`
+	PrincipalId   uint64 `protobuf:"varint,6,opt,name=principal_id" json:"principal_id,omitempty"`
+}
+
+func (x *RevokeAccessReq) Reset() { *x = RevokeAccessReq{} }
+
+func (x *RevokeAccessReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *RevokeAccessReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *RevokeAccessReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *RevokeAccessReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RevokeAccessReq) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *RevokeAccessReq) GetResourceId() uint64 {
+	if x != nil {
+		return x.ResourceId
+	}
+	return 0
+}
+
+func (x *RevokeAccessReq) GetPrincipalType() string {
+	if x != nil {
+		return x.PrincipalType
+	}
+	return ""
+}
+
+func (x *RevokeAccessReq) GetPrincipalId() uint64 {
+	if x != nil {
+		return x.PrincipalId
+	}
+	return 0
+}
+
+type RevokeAccessRsp struct {
+	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (x *RevokeAccessRsp) Reset() { *x = RevokeAccessRsp{} }
+
+func (x *RevokeAccessRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *RevokeAccessRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *RevokeAccessRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RevokeAccessRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+type ListAccessReq struct {
+	SeqId        uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId       uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	ResourceType string `protobuf:"bytes,3,opt,name=resource_type" json:"resource_type,omitempty"`
+	ResourceId   uint64 `protobuf:"varint,4,opt,name=resource_id" json:"resource_id,omitempty"`
+}
+
+func (x *ListAccessReq) Reset() { *x = ListAccessReq{} }
+
+func (x *ListAccessReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListAccessReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListAccessReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *ListAccessReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListAccessReq) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *ListAccessReq) GetResourceId() uint64 {
+	if x != nil {
+		return x.ResourceId
+	}
+	return 0
+}
+
+type ListAccessRsp struct {
+	Code    uint32         `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg     string         `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	OwnerId uint64         `protobuf:"varint,3,opt,name=owner_id" json:"owner_id,omitempty"` // 资源的创建者，始终为所有者
+	Grants  []*AccessGrant `protobuf:"bytes,4,rep,name=grants" json:"grants,omitempty"`
+}
+
+func (x *ListAccessRsp) Reset() { *x = ListAccessRsp{} }
+
+func (x *ListAccessRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListAccessRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListAccessRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ListAccessRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ListAccessRsp) GetOwnerId() uint64 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+func (x *ListAccessRsp) GetGrants() []*AccessGrant {
+	if x != nil {
+		return x.Grants
+	}
+	return nil
+}
+
+// 用户组，作为授权对象时组内成员获得相同的角色
+type UserGroup struct {
+	GroupId    uint64   `protobuf:"varint,1,opt,name=group_id" json:"group_id,omitempty"`
+	OwnerId    uint64   `protobuf:"varint,2,opt,name=owner_id" json:"owner_id,omitempty"`
+	Name       string   `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	MemberIds  []uint64 `protobuf:"varint,4,rep,packed,name=member_ids" json:"member_ids,omitempty"`
+	CreateTime uint64   `protobuf:"varint,5,opt,name=create_time" json:"create_time,omitempty"`
+}
+
+func (x *UserGroup) Reset() { *x = UserGroup{} }
+
+func (x *UserGroup) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *UserGroup) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *UserGroup) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *UserGroup) GetOwnerId() uint64 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+func (x *UserGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserGroup) GetMemberIds() []uint64 {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+func (x *UserGroup) GetCreateTime() uint64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+type CreateGroupReq struct {
+	SeqId     uint32   `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId    uint64   `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Name      string   `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`                     // 同一创建者下唯一
+	MemberIds []uint64 `protobuf:"varint,4,rep,packed,name=member_ids" json:"member_ids,omitempty"` // 创建者自动加入
+}
+
+func (x *CreateGroupReq) Reset() { *x = CreateGroupReq{} }
+
+func (x *CreateGroupReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *CreateGroupReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *CreateGroupReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *CreateGroupReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateGroupReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateGroupReq) GetMemberIds() []uint64 {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+type CreateGroupRsp struct {
+	Code  uint32     `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg   string     `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Group *UserGroup `protobuf:"bytes,3,opt,name=group" json:"group,omitempty"`
+}
+
+func (x *CreateGroupRsp) Reset() { *x = CreateGroupRsp{} }
+
+func (x *CreateGroupRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *CreateGroupRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *CreateGroupRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CreateGroupRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *CreateGroupRsp) GetGroup() *UserGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+// 管理成员，只有创建者可以操作
+type AddGroupMembersReq struct {
+	SeqId     uint32   `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId    uint64   `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	GroupId   uint64   `protobuf:"varint,3,opt,name=group_id" json:"group_id,omitempty"`
+	MemberIds []uint64 `protobuf:"varint,4,rep,packed,name=member_ids" json:"member_ids,omitempty"`
+}
+
+func (x *AddGroupMembersReq) Reset() { *x = AddGroupMembersReq{} }
+
+func (x *AddGroupMembersReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddGroupMembersReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddGroupMembersReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *AddGroupMembersReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddGroupMembersReq) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *AddGroupMembersReq) GetMemberIds() []uint64 {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+type AddGroupMembersRsp struct {
+	Code  uint32     `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg   string     `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Group *UserGroup `protobuf:"bytes,3,opt,name=group" json:"group,omitempty"`
+}
+
+func (x *AddGroupMembersRsp) Reset() { *x = AddGroupMembersRsp{} }
+
+func (x *AddGroupMembersRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddGroupMembersRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddGroupMembersRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AddGroupMembersRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *AddGroupMembersRsp) GetGroup() *UserGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type RemoveGroupMembersReq struct {
+	SeqId     uint32   `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId    uint64   `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	GroupId   uint64   `protobuf:"varint,3,opt,name=group_id" json:"group_id,omitempty"`
+	MemberIds []uint64 `protobuf:"varint,4,rep,packed,name=member_ids" json:"member_ids,omitempty"`
+}
+
+func (x *RemoveGroupMembersReq) Reset() { *x = RemoveGroupMembersReq{} }
+
+func (x *RemoveGroupMembersReq) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *RemoveGroupMembersReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *RemoveGroupMembersReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *RemoveGroupMembersReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RemoveGroupMembersReq) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *RemoveGroupMembersReq) GetMemberIds() []uint64 {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+type RemoveGroupMembersRsp struct {
+	Code  uint32     `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg   string     `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Group *UserGroup `protobuf:"bytes,3,opt,name=group" json:"group,omitempty"`
+}
+
+func (x *RemoveGroupMembersRsp) Reset() { *x = RemoveGroupMembersRsp{} }
+
+func (x *RemoveGroupMembersRsp) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *RemoveGroupMembersRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *RemoveGroupMembersRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RemoveGroupMembersRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *RemoveGroupMembersRsp) GetGroup() *UserGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+// 获取用户所在的用户组
+type ListGroupsReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *ListGroupsReq) Reset() { *x = ListGroupsReq{} }
+
+func (x *ListGroupsReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListGroupsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListGroupsReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *ListGroupsReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type ListGroupsRsp struct {
+	Code   uint32       `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg    string       `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Groups []*UserGroup `protobuf:"bytes,3,rep,name=groups" json:"groups,omitempty"`
+}
+
+func (x *ListGroupsRsp) Reset() { *x = ListGroupsRsp{} }
+
+func (x *ListGroupsRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ListGroupsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ListGroupsRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ListGroupsRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ListGroupsRsp) GetGroups() []*UserGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type CleanInactiveSessionsReq struct {
+	InactiveDays int32 `protobuf:"varint,1,opt,name=inactive_days" json:"inactive_days,omitempty"` // 不活跃天数
+}
+
+func (x *CleanInactiveSessionsReq) Reset() { *x = CleanInactiveSessionsReq{} }
+
+func (x *CleanInactiveSessionsReq) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *CleanInactiveSessionsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *CleanInactiveSessionsReq) GetInactiveDays() int32 {
+	if x != nil {
+		return x.InactiveDays
+	}
+	return 0
+}
+
+type CleanInactiveSessionsRsp struct {
+	Code         uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg          string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	CleanedCount int64  `protobuf:"varint,3,opt,name=cleaned_count" json:"cleaned_count,omitempty"` // 清理的会话数量
+}
+
+func (x *CleanInactiveSessionsRsp) Reset() { *x = CleanInactiveSessionsRsp{} }
+
+func (x *CleanInactiveSessionsRsp) Marshal(in []byte) ([]byte, error) {
+	return prutal.MarshalAppend(in, x)
+}
+
+func (x *CleanInactiveSessionsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *CleanInactiveSessionsRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CleanInactiveSessionsRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *CleanInactiveSessionsRsp) GetCleanedCount() int64 {
+	if x != nil {
+		return x.CleanedCount
+	}
+	return 0
+}
+
+// 记忆管理
+type Memory struct {
+	MemoryId    uint64  `protobuf:"varint,1,opt,name=memory_id" json:"memory_id,omitempty"`
+	SessionId   uint64  `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	UserId      uint64  `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Content     string  `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
+	MemoryType  string  `protobuf:"bytes,5,opt,name=memory_type" json:"memory_type,omitempty"`
+	Importance  float64 `protobuf:"fixed64,6,opt,name=importance" json:"importance,omitempty"`
+	Metadata    string  `protobuf:"bytes,7,opt,name=metadata" json:"metadata,omitempty"`
+	CreateTime  uint64  `protobuf:"varint,8,opt,name=create_time" json:"create_time,omitempty"`
+	UpdateTime  uint64  `protobuf:"varint,9,opt,name=update_time" json:"update_time,omitempty"`
+	ExpireTime  uint64  `protobuf:"varint,10,opt,name=expire_time" json:"expire_time,omitempty"`
+	AccessCount int32   `protobuf:"varint,11,opt,name=access_count" json:"access_count,omitempty"`
+}
+
+func (x *Memory) Reset() { *x = Memory{} }
+
+func (x *Memory) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *Memory) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *Memory) GetMemoryId() uint64 {
+	if x != nil {
+		return x.MemoryId
+	}
+	return 0
+}
+
+func (x *Memory) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *Memory) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Memory) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Memory) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *Memory) GetImportance() float64 {
+	if x != nil {
+		return x.Importance
+	}
+	return 0
+}
+
+func (x *Memory) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+func (x *Memory) GetCreateTime() uint64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *Memory) GetUpdateTime() uint64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+func (x *Memory) GetExpireTime() uint64 {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return 0
+}
+
+func (x *Memory) GetAccessCount() int32 {
+	if x != nil {
+		return x.AccessCount
+	}
+	return 0
+}
+
+type AddMemoryReq struct {
+	SeqId      uint32  `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	SessionId  uint64  `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	UserId     uint64  `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Content    string  `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
+	MemoryType string  `protobuf:"bytes,5,opt,name=memory_type" json:"memory_type,omitempty"`
+	Importance float64 `protobuf:"fixed64,6,opt,name=importance" json:"importance,omitempty"`
+	Metadata   string  `protobuf:"bytes,7,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (x *AddMemoryReq) Reset() { *x = AddMemoryReq{} }
+
+func (x *AddMemoryReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddMemoryReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddMemoryReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *AddMemoryReq) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *AddMemoryReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddMemoryReq) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *AddMemoryReq) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *AddMemoryReq) GetImportance() float64 {
+	if x != nil {
+		return x.Importance
+	}
+	return 0
+}
+
+func (x *AddMemoryReq) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+type AddMemoryRsp struct {
 	Code     uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
 	Msg      string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
 	MemoryId uint64 `protobuf:"varint,3,opt,name=memory_id" json:"memory_id,omitempty"`
 }
 
-func (x *AddMemoryRsp) Reset() { *x = AddMemoryRsp{} }
+func (x *AddMemoryRsp) Reset() { *x = AddMemoryRsp{} }
+
+func (x *AddMemoryRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddMemoryRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddMemoryRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AddMemoryRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *AddMemoryRsp) GetMemoryId() uint64 {
+	if x != nil {
+		return x.MemoryId
+	}
+	return 0
+}
+
+type GetMemoryReq struct {
+	SeqId    uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	MemoryId uint64 `protobuf:"varint,2,opt,name=memory_id" json:"memory_id,omitempty"`
+	UserId   uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+}
+
+func (x *GetMemoryReq) Reset() { *x = GetMemoryReq{} }
+
+func (x *GetMemoryReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetMemoryReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetMemoryReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *GetMemoryReq) GetMemoryId() uint64 {
+	if x != nil {
+		return x.MemoryId
+	}
+	return 0
+}
+
+func (x *GetMemoryReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetMemoryRsp struct {
+	Code   uint32  `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg    string  `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Memory *Memory `protobuf:"bytes,3,opt,name=memory" json:"memory,omitempty"`
+}
+
+func (x *GetMemoryRsp) Reset() { *x = GetMemoryRsp{} }
+
+func (x *GetMemoryRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetMemoryRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetMemoryRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GetMemoryRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GetMemoryRsp) GetMemory() *Memory {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+type SearchMemoriesReq struct {
+	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	Query  string `protobuf:"bytes,3,opt,name=query" json:"query,omitempty"`
+	Limit  int32  `protobuf:"varint,4,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (x *SearchMemoriesReq) Reset() { *x = SearchMemoriesReq{} }
+
+func (x *SearchMemoriesReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SearchMemoriesReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SearchMemoriesReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *SearchMemoriesReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SearchMemoriesReq) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchMemoriesReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchMemoriesRsp struct {
+	Code     uint32    `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg      string    `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Memories []*Memory `protobuf:"bytes,3,rep,name=memories" json:"memories,omitempty"`
+}
+
+func (x *SearchMemoriesRsp) Reset() { *x = SearchMemoriesRsp{} }
+
+func (x *SearchMemoriesRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *SearchMemoriesRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *SearchMemoriesRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SearchMemoriesRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *SearchMemoriesRsp) GetMemories() []*Memory {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+type DeleteMemoryReq struct {
+	SeqId    uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	MemoryId uint64 `protobuf:"varint,2,opt,name=memory_id" json:"memory_id,omitempty"`
+	UserId   uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Reason   string `protobuf:"bytes,4,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (x *DeleteMemoryReq) Reset() { *x = DeleteMemoryReq{} }
+
+func (x *DeleteMemoryReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DeleteMemoryReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DeleteMemoryReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *DeleteMemoryReq) GetMemoryId() uint64 {
+	if x != nil {
+		return x.MemoryId
+	}
+	return 0
+}
+
+func (x *DeleteMemoryReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteMemoryReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DeleteMemoryRsp struct {
+	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (x *DeleteMemoryRsp) Reset() { *x = DeleteMemoryRsp{} }
+
+func (x *DeleteMemoryRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *DeleteMemoryRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *DeleteMemoryRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *DeleteMemoryRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// 聊天记录管理
+type AddChatRecordReq struct {
+	SeqId         uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	SessionId     uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	UserId        uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Message       string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+	Response      string `protobuf:"bytes,5,opt,name=response" json:"response,omitempty"`
+	MessageType   string `protobuf:"bytes,6,opt,name=message_type" json:"message_type,omitempty"`
+	Context       string `protobuf:"bytes,7,opt,name=context" json:"context,omitempty"`
+	FunctionCalls string `protobuf:"bytes,8,opt,name=function_calls" json:"function_calls,omitempty"`
+	Metadata      string `protobuf:"bytes,9,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (x *AddChatRecordReq) Reset() { *x = AddChatRecordReq{} }
+
+func (x *AddChatRecordReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddChatRecordReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddChatRecordReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *AddChatRecordReq) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
 
-func (x *AddMemoryRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *AddChatRecordReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
 
-func (x *AddMemoryRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *AddChatRecordReq) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
 
-func (x *AddMemoryRsp) GetCode() uint32 {
+func (x *AddChatRecordReq) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *AddChatRecordReq) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *AddChatRecordReq) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *AddChatRecordReq) GetFunctionCalls() string {
+	if x != nil {
+		return x.FunctionCalls
+	}
+	return ""
+}
+
+func (x *AddChatRecordReq) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+type AddChatRecordRsp struct {
+	Code   uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg    string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	ChatId uint64 `protobuf:"varint,3,opt,name=chat_id" json:"chat_id,omitempty"`
+}
+
+func (x *AddChatRecordRsp) Reset() { *x = AddChatRecordRsp{} }
+
+func (x *AddChatRecordRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *AddChatRecordRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *AddChatRecordRsp) GetCode() uint32 {
 	if x != nil {
 		return x.Code
 	}
 	return 0
 }
 
-func (x *AddMemoryRsp) GetMsg() string {
+func (x *AddChatRecordRsp) GetMsg() string {
 	if x != nil {
 		return x.Msg
 	}
 	return ""
 }
 
-func (x *AddMemoryRsp) GetMemoryId() uint64 {
+func (x *AddChatRecordRsp) GetChatId() uint64 {
 	if x != nil {
-		return x.MemoryId
+		return x.ChatId
 	}
 	return 0
 }
 
-type GetMemoryReq struct {
-	SeqId    uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	MemoryId uint64 `protobuf:"varint,2,opt,name=memory_id" json:"memory_id,omitempty"`
-	UserId   uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+type GetChatRecordsReq struct {
+	SeqId     uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	SessionId uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	Page      int32  `protobuf:"varint,3,opt,name=page" json:"page,omitempty"`
+	PageSize  int32  `protobuf:"varint,4,opt,name=page_size" json:"page_size,omitempty"`
 }
 
-func (x *GetMemoryReq) Reset() { *x = GetMemoryReq{} }
+func (x *GetChatRecordsReq) Reset() { *x = GetChatRecordsReq{} }
 
-func (x *GetMemoryReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *GetChatRecordsReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *GetMemoryReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *GetChatRecordsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *GetMemoryReq) GetSeqId() uint32 {
+func (x *GetChatRecordsReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *GetChatRecordsReq) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *GetChatRecordsReq) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetChatRecordsReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetChatRecordsRsp struct {
+	Code     uint32        `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg      string        `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Total    int64         `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`
+	Page     int32         `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`
+	PageSize int32         `protobuf:"varint,5,opt,name=page_size" json:"page_size,omitempty"`
+	Records  []*ChatRecord `protobuf:"bytes,6,rep,name=records" json:"records,omitempty"`
+}
+
+func (x *GetChatRecordsRsp) Reset() { *x = GetChatRecordsRsp{} }
+
+func (x *GetChatRecordsRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetChatRecordsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetChatRecordsRsp) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *GetChatRecordsRsp) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *GetChatRecordsRsp) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetChatRecordsRsp) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetChatRecordsRsp) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetChatRecordsRsp) GetRecords() []*ChatRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// 对话
+type Citation struct {
+	SourceType string  `protobuf:"bytes,1,opt,name=source_type" json:"source_type,omitempty"` // memory, document
+	SourceId   uint64  `protobuf:"varint,2,opt,name=source_id" json:"source_id,omitempty"`    // memory_id 或 doc_id
+	Title      string  `protobuf:"bytes,3,opt,name=title" json:"title,omitempty"`
+	Content    string  `protobuf:"bytes,4,opt,name=content" json:"content,omitempty"`
+	Score      float32 `protobuf:"fixed32,5,opt,name=score" json:"score,omitempty"`
+
+	// 文档引用的块位置，可据此定位原文段落和句子
+	ParagraphId   uint64 `protobuf:"varint,6,opt,name=paragraph_id" json:"paragraph_id,omitempty"`
+	SentenceIdMin uint64 `protobuf:"varint,7,opt,name=sentence_id_min" json:"sentence_id_min,omitempty"`
+	SentenceIdMax uint64 `protobuf:"varint,8,opt,name=sentence_id_max" json:"sentence_id_max,omitempty"`
+}
+
+func (x *Citation) Reset() { *x = Citation{} }
+
+func (x *Citation) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *Citation) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *Citation) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *Citation) GetSourceId() uint64 {
+	if x != nil {
+		return x.SourceId
+	}
+	return 0
+}
+
+func (x *Citation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Citation) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Citation) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Citation) GetParagraphId() uint64 {
+	if x != nil {
+		return x.ParagraphId
+	}
+	return 0
+}
+
+func (x *Citation) GetSentenceIdMin() uint64 {
+	if x != nil {
+		return x.SentenceIdMin
+	}
+	return 0
+}
+
+func (x *Citation) GetSentenceIdMax() uint64 {
+	if x != nil {
+		return x.SentenceIdMax
+	}
+	return 0
+}
+
+type ChatReq struct {
+	SeqId         uint32   `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	SessionId     uint64   `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
+	UserId        uint64   `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
+	Message       string   `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+	HistoryLimit  int32    `protobuf:"varint,5,opt,name=history_limit" json:"history_limit,omitempty"`          // 可选，加载的历史对话条数
+	MemoryLimit   int32    `protobuf:"varint,6,opt,name=memory_limit" json:"memory_limit,omitempty"`            // 可选，检索的记忆条数
+	DocTopK       int32    `protobuf:"varint,7,opt,name=doc_top_k" json:"doc_top_k,omitempty"`                  // 可选，检索的文档块数
+	CollectionIds []uint64 `protobuf:"varint,8,rep,packed,name=collection_ids" json:"collection_ids,omitempty"` // 可选，只从这些知识库中检索文档
+}
+
+func (x *ChatReq) Reset() { *x = ChatReq{} }
+
+func (x *ChatReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ChatReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ChatReq) GetSeqId() uint32 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *ChatReq) GetSessionId() uint64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *ChatReq) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ChatReq) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ChatReq) GetHistoryLimit() int32 {
 	if x != nil {
-		return x.SeqId
+		return x.HistoryLimit
 	}
 	return 0
 }
 
-func (x *GetMemoryReq) GetMemoryId() uint64 {
+func (x *ChatReq) GetMemoryLimit() int32 {
 	if x != nil {
-		return x.MemoryId
+		return x.MemoryLimit
 	}
 	return 0
 }
 
-func (x *GetMemoryReq) GetUserId() uint64 {
+func (x *ChatReq) GetDocTopK() int32 {
 	if x != nil {
-		return x.UserId
+		return x.DocTopK
 	}
 	return 0
 }
 
-type GetMemoryRsp struct {
-	Code   uint32  `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg    string  `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	Memory *Memory `protobuf:"bytes,3,opt,name=memory" json:"memory,omitempty"`
+func (x *ChatReq) GetCollectionIds() []uint64 {
+	if x != nil {
+		return x.CollectionIds
+	}
+	return nil
 }
 
-func (x *GetMemoryRsp) Reset() { *x = GetMemoryRsp{} }
+type ChatRsp struct {
+	Code      uint32      `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg       string      `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	ChatId    uint64      `protobuf:"varint,3,opt,name=chat_id" json:"chat_id,omitempty"`
+	Response  string      `protobuf:"bytes,4,opt,name=response" json:"response,omitempty"`
+	Citations []*Citation `protobuf:"bytes,5,rep,name=citations" json:"citations,omitempty"`
+}
 
-func (x *GetMemoryRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *ChatRsp) Reset() { *x = ChatRsp{} }
 
-func (x *GetMemoryRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *ChatRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *GetMemoryRsp) GetCode() uint32 {
+func (x *ChatRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ChatRsp) GetCode() uint32 {
 	if x != nil {
 		return x.Code
 	}
 	return 0
 }
 
-func (x *GetMemoryRsp) GetMsg() string {
+func (x *ChatRsp) GetMsg() string {
 	if x != nil {
 		return x.Msg
 	}
 	return ""
 }
 
-func (x *GetMemoryRsp) GetMemory() *Memory {
+func (x *ChatRsp) GetChatId() uint64 {
 	if x != nil {
-		return x.Memory
+		return x.ChatId
+	}
+	return 0
+}
+
+func (x *ChatRsp) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *ChatRsp) GetCitations() []*Citation {
+	if x != nil {
+		return x.Citations
 	}
 	return nil
 }
 
-type SearchMemoriesReq struct {
-	SeqId  uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	UserId uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
-	Query  string `protobuf:"bytes,3,opt,name=query" json:"query,omitempty"`
-	Limit  int32  `protobuf:"varint,4,opt,name=limit" json:"limit,omitempty"`
+// 流式对话分片
+type StreamChatRsp struct {
+	Code      uint32      `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg       string      `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	Delta     string      `protobuf:"bytes,3,opt,name=delta" json:"delta,omitempty"`         // 本次新增的文本
+	Finished  bool        `protobuf:"varint,4,opt,name=finished" json:"finished,omitempty"`  // 是否为最后一个分片
+	ChatId    uint64      `protobuf:"varint,5,opt,name=chat_id" json:"chat_id,omitempty"`    // 结束时返回
+	Citations []*Citation `protobuf:"bytes,6,rep,name=citations" json:"citations,omitempty"` // 结束时返回
 }
 
-func (x *SearchMemoriesReq) Reset() { *x = SearchMemoriesReq{} }
+func (x *StreamChatRsp) Reset() { *x = StreamChatRsp{} }
 
-func (x *SearchMemoriesReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *StreamChatRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *SearchMemoriesReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *StreamChatRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *SearchMemoriesReq) GetSeqId() uint32 {
+func (x *StreamChatRsp) GetCode() uint32 {
 	if x != nil {
-		return x.SeqId
+		return x.Code
 	}
 	return 0
 }
 
-func (x *SearchMemoriesReq) GetUserId() uint64 {
+func (x *StreamChatRsp) GetMsg() string {
 	if x != nil {
-		return x.UserId
+		return x.Msg
 	}
-	return 0
+	return ""
 }
 
-func (x *SearchMemoriesReq) GetQuery() string {
+func (x *StreamChatRsp) GetDelta() string {
 	if x != nil {
-		return x.Query
+		return x.Delta
 	}
 	return ""
 }
 
-func (x *SearchMemoriesReq) GetLimit() int32 {
+func (x *StreamChatRsp) GetFinished() bool {
 	if x != nil {
-		return x.Limit
+		return x.Finished
 	}
-	return 0
-}
-
-type SearchMemoriesRsp struct {
-	Code     uint32    `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg      string    `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	Memories []*Memory `protobuf:"bytes,3,rep,name=memories" json:"memories,omitempty"`
+	return false
 }
 
-func (x *SearchMemoriesRsp) Reset() { *x = SearchMemoriesRsp{} }
-
-func (x *SearchMemoriesRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *SearchMemoriesRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *SearchMemoriesRsp) GetCode() uint32 {
+func (x *StreamChatRsp) GetChatId() uint64 {
 	if x != nil {
-		return x.Code
+		return x.ChatId
 	}
 	return 0
 }
 
-func (x *SearchMemoriesRsp) GetMsg() string {
-	if x != nil {
-		return x.Msg
-	}
-	return ""
-}
-
-func (x *SearchMemoriesRsp) GetMemories() []*Memory {
+func (x *StreamChatRsp) GetCitations() []*Citation {
 	if x != nil {
-		return x.Memories
+		return x.Citations
 	}
 	return nil
 }
 
-type DeleteMemoryReq struct {
-	SeqId    uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	MemoryId uint64 `protobuf:"varint,2,opt,name=memory_id" json:"memory_id,omitempty"`
-	UserId   uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
-	Reason   string `protobuf:"bytes,4,opt,name=reason" json:"reason,omitempty"`
+// 用量统计
+type ModelUsageStat struct {
+	Model        string `protobuf:"bytes,1,opt,name=model" json:"model,omitempty"`
+	CallType     string `protobuf:"bytes,2,opt,name=call_type" json:"call_type,omitempty"` // chat/intent/sentiment/analysis/summary/title/embedding
+	InputTokens  int64  `protobuf:"varint,3,opt,name=input_tokens" json:"input_tokens,omitempty"`
+	OutputTokens int64  `protobuf:"varint,4,opt,name=output_tokens" json:"output_tokens,omitempty"`
+	TotalTokens  int64  `protobuf:"varint,5,opt,name=total_tokens" json:"total_tokens,omitempty"`
+	CallCount    int64  `protobuf:"varint,6,opt,name=call_count" json:"call_count,omitempty"`
 }
 
-func (x *DeleteMemoryReq) Reset() { *x = DeleteMemoryReq{} }
+func (x *ModelUsageStat) Reset() { *x = ModelUsageStat{} }
 
-func (x *DeleteMemoryReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *ModelUsageStat) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *DeleteMemoryReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *ModelUsageStat) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *DeleteMemoryReq) GetSeqId() uint32 {
+func (x *ModelUsageStat) GetModel() string {
 	if x != nil {
-		return x.SeqId
+		return x.Model
 	}
-	return 0
+	return ""
 }
 
-func (x *DeleteMemoryReq) GetMemoryId() uint64 {
+func (x *ModelUsageStat) GetCallType() string {
 	if x != nil {
-		return x.MemoryId
+		return x.CallType
 	}
-	return 0
+	return ""
 }
 
-func (x *DeleteMemoryReq) GetUserId() uint64 {
+func (x *ModelUsageStat) GetInputTokens() int64 {
 	if x != nil {
-		return x.UserId
+		return x.InputTokens
 	}
 	return 0
 }
 
-func (x *DeleteMemoryReq) GetReason() string {
+func (x *ModelUsageStat) GetOutputTokens() int64 {
 	if x != nil {
-		return x.Reason
+		return x.OutputTokens
 	}
-	return ""
-}
-
-type DeleteMemoryRsp struct {
-	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg  string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	return 0
 }
 
-func (x *DeleteMemoryRsp) Reset() { *x = DeleteMemoryRsp{} }
-
-func (x *DeleteMemoryRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *DeleteMemoryRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *DeleteMemoryRsp) GetCode() uint32 {
+func (x *ModelUsageStat) GetTotalTokens() int64 {
 	if x != nil {
-		return x.Code
+		return x.TotalTokens
 	}
 	return 0
 }
 
-func (x *DeleteMemoryRsp) GetMsg() string {
+func (x *ModelUsageStat) GetCallCount() int64 {
 	if x != nil {
-		return x.Msg
+		return x.CallCount
 	}
-	return ""
+	return 0
 }
 
-// 聊天记录管理
-type AddChatRecordReq struct {
-	SeqId         uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	SessionId     uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
-	UserId        uint64 `protobuf:"varint,3,opt,name=user_id" json:"user_id,omitempty"`
-	Message       string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
-	Response      string `protobuf:"bytes,5,opt,name=response" json:"response,omitempty"`
-	MessageType   string `protobuf:"bytes,6,opt,name=message_type" json:"message_type,omitempty"`
-	Context       string `protobuf:"bytes,7,opt,name=context" json:"context,omitempty"`
-	FunctionCalls string `protobuf:"bytes,8,opt,name=function_calls" json:"function_calls,omitempty"`
-	Metadata      string `protobuf:"bytes,9,opt,name=metadata" json:"metadata,omitempty"`
+type GetUsageReq struct {
+	SeqId     uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
+	UserId    uint64 `protobuf:"varint,2,opt,name=user_id" json:"user_id,omitempty"`
+	StartDate string `protobuf:"bytes,3,opt,name=start_date" json:"start_date,omitempty"` // 可选，格式 2006-01-02，默认本月1日
+	EndDate   string `protobuf:"bytes,4,opt,name=end_date" json:"end_date,omitempty"`     // 可选，格式 2006-01-02（包含当天），默认今天
 }
 
-func (x *AddChatRecordReq) Reset() { *x = AddChatRecordReq{} }
+func (x *GetUsageReq) Reset() { *x = GetUsageReq{} }
 
-func (x *AddChatRecordReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *GetUsageReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *AddChatRecordReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *GetUsageReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *AddChatRecordReq) GetSeqId() uint32 {
+func (x *GetUsageReq) GetSeqId() uint32 {
 	if x != nil {
 		return x.SeqId
 	}
 	return 0
 }
 
-func (x *AddChatRecordReq) GetSessionId() uint64 {
+func (x *GetUsageReq) GetUserId() uint64 {
 	if x != nil {
-		return x.SessionId
+		return x.UserId
 	}
 	return 0
 }
 
-func (x *AddChatRecordReq) GetUserId() uint64 {
+func (x *GetUsageReq) GetStartDate() string {
 	if x != nil {
-		return x.UserId
+		return x.StartDate
 	}
-	return 0
+	return ""
 }
 
-func (x *AddChatRecordReq) GetMessage() string {
+func (x *GetUsageReq) GetEndDate() string {
 	if x != nil {
-		return x.Message
+		return x.EndDate
 	}
 	return ""
 }
 
-func (x *AddChatRecordReq) GetResponse() string {
+type GetUsageRsp struct {
+	Code          uint32            `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg           string            `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	DailyTokens   int64             `protobuf:"varint,3,opt,name=daily_tokens" json:"daily_tokens,omitempty"`     // 今日已使用
+	DailyQuota    int64             `protobuf:"varint,4,opt,name=daily_quota" json:"daily_quota,omitempty"`       // 每日配额，0 表示不限制
+	MonthlyTokens int64             `protobuf:"varint,5,opt,name=monthly_tokens" json:"monthly_tokens,omitempty"` // 本月已使用
+	MonthlyQuota  int64             `protobuf:"varint,6,opt,name=monthly_quota" json:"monthly_quota,omitempty"`   // 每月配额，0 表示不限制
+	Stats         []*ModelUsageStat `protobuf:"bytes,7,rep,name=stats" json:"stats,omitempty"`                    // 查询时间段内按模型和调用类型汇总
+}
+
+func (x *GetUsageRsp) Reset() { *x = GetUsageRsp{} }
+
+func (x *GetUsageRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetUsageRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetUsageRsp) GetCode() uint32 {
 	if x != nil {
-		return x.Response
+		return x.Code
 	}
-	return ""
+	return 0
 }
 
-func (x *AddChatRecordReq) GetMessageType() string {
+func (x *GetUsageRsp) GetMsg() string {
 	if x != nil {
-		return x.MessageType
+		return x.Msg
 	}
 	return ""
 }
 
-func (x *AddChatRecordReq) GetContext() string {
+func (x *GetUsageRsp) GetDailyTokens() int64 {
 	if x != nil {
-		return x.Context
+		return x.DailyTokens
 	}
-	return ""
+	return 0
 }
 
-func (x *AddChatRecordReq) GetFunctionCalls() string {
+func (x *GetUsageRsp) GetDailyQuota() int64 {
 	if x != nil {
-		return x.FunctionCalls
+		return x.DailyQuota
 	}
-	return ""
+	return 0
 }
 
-func (x *AddChatRecordReq) GetMetadata() string {
+func (x *GetUsageRsp) GetMonthlyTokens() int64 {
 	if x != nil {
-		return x.Metadata
+		return x.MonthlyTokens
 	}
-	return ""
+	return 0
 }
 
-type AddChatRecordRsp struct {
-	Code   uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg    string `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	ChatId uint64 `protobuf:"varint,3,opt,name=chat_id" json:"chat_id,omitempty"`
+func (x *GetUsageRsp) GetMonthlyQuota() int64 {
+	if x != nil {
+		return x.MonthlyQuota
+	}
+	return 0
 }
 
-func (x *AddChatRecordRsp) Reset() { *x = AddChatRecordRsp{} }
+func (x *GetUsageRsp) GetStats() []*ModelUsageStat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
 
-func (x *AddChatRecordRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+// 模型状态
+type ModelBreakerStat struct {
+	Name                string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`                                  // 模型名称
+	State               string `protobuf:"bytes,2,opt,name=state" json:"state,omitempty"`                                // 熔断状态：closed/open/half_open
+	ConsecutiveFailures int32  `protobuf:"varint,3,opt,name=consecutive_failures" json:"consecutive_failures,omitempty"` // 连续失败次数
+	OpenedAt            uint64 `protobuf:"varint,4,opt,name=opened_at" json:"opened_at,omitempty"`                       // 最近一次熔断时间，未熔断过为 0
+	Requests            int64  `protobuf:"varint,5,opt,name=requests" json:"requests,omitempty"`                         // 放行的请求数
+	Failures            int64  `protobuf:"varint,6,opt,name=failures" json:"failures,omitempty"`                         // 失败的请求数
+	Rejected            int64  `protobuf:"varint,7,opt,name=rejected" json:"rejected,omitempty"`                         // 因熔断拒绝的请求数
+	Fallbacks           int64  `protobuf:"varint,8,opt,name=fallbacks" json:"fallbacks,omitempty"`                       // 切换到下一个模型的次数
+}
 
-func (x *AddChatRecordRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *ModelBreakerStat) Reset() { *x = ModelBreakerStat{} }
 
-func (x *AddChatRecordRsp) GetCode() uint32 {
+func (x *ModelBreakerStat) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *ModelBreakerStat) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *ModelBreakerStat) GetName() string {
 	if x != nil {
-		return x.Code
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *AddChatRecordRsp) GetMsg() string {
+func (x *ModelBreakerStat) GetState() string {
 	if x != nil {
-		return x.Msg
+		return x.State
 	}
 	return ""
 }
 
-func (x *AddChatRecordRsp) GetChatId() uint64 {
+func (x *ModelBreakerStat) GetConsecutiveFailures() int32 {
 	if x != nil {
-		return x.ChatId
+		return x.ConsecutiveFailures
 	}
 	return 0
 }
 
-type GetChatRecordsReq struct {
-	SeqId     uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
-	SessionId uint64 `protobuf:"varint,2,opt,name=session_id" json:"session_id,omitempty"`
-	Page      int32  `protobuf:"varint,3,opt,name=page" json:"page,omitempty"`
-	PageSize  int32  `protobuf:"varint,4,opt,name=page_size" json:"page_size,omitempty"`
+func (x *ModelBreakerStat) GetOpenedAt() uint64 {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return 0
 }
 
-func (x *GetChatRecordsReq) Reset() { *x = GetChatRecordsReq{} }
-
-func (x *GetChatRecordsReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
-
-func (x *GetChatRecordsReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
-
-func (x *GetChatRecordsReq) GetSeqId() uint32 {
+func (x *ModelBreakerStat) GetRequests() int64 {
 	if x != nil {
-		return x.SeqId
+		return x.Requests
 	}
 	return 0
 }
 
-func (x *GetChatRecordsReq) GetSessionId() uint64 {
+func (x *ModelBreakerStat) GetFailures() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.Failures
 	}
 	return 0
 }
 
-func (x *GetChatRecordsReq) GetPage() int32 {
+func (x *ModelBreakerStat) GetRejected() int64 {
 	if x != nil {
-		return x.Page
+		return x.Rejected
 	}
 	return 0
 }
 
-func (x *GetChatRecordsReq) GetPageSize() int32 {
+func (x *ModelBreakerStat) GetFallbacks() int64 {
 	if x != nil {
-		return x.PageSize
+		return x.Fallbacks
 	}
 	return 0
 }
 
-type GetChatRecordsRsp struct {
-	Code     uint32        `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
-	Msg      string        `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
-	Total    int64         `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`
-	Page     int32         `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`
-	PageSize int32         `protobuf:"varint,5,opt,name=page_size" json:"page_size,omitempty"`
-	Records  []*ChatRecord `protobuf:"bytes,6,rep,name=records" json:"records,omitempty"`
+type GetModelStatusReq struct {
+	SeqId uint32 `protobuf:"varint,1,opt,name=seq_id" json:"seq_id,omitempty"`
 }
 
-func (x *GetChatRecordsRsp) Reset() { *x = GetChatRecordsRsp{} }
+func (x *GetModelStatusReq) Reset() { *x = GetModelStatusReq{} }
 
-func (x *GetChatRecordsRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+func (x *GetModelStatusReq) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
 
-func (x *GetChatRecordsRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+func (x *GetModelStatusReq) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
 
-func (x *GetChatRecordsRsp) GetCode() uint32 {
+func (x *GetModelStatusReq) GetSeqId() uint32 {
 	if x != nil {
-		return x.Code
+		return x.SeqId
 	}
 	return 0
 }
 
-func (x *GetChatRecordsRsp) GetMsg() string {
-	if x != nil {
-		return x.Msg
-	}
-	return ""
+type GetModelStatusRsp struct {
+	Code            uint32              `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Msg             string              `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+	ChatModels      []*ModelBreakerStat `protobuf:"bytes,3,rep,name=chat_models" json:"chat_models,omitempty"`           // 对话模型，按切换顺序
+	EmbeddingModels []*ModelBreakerStat `protobuf:"bytes,4,rep,name=embedding_models" json:"embedding_models,omitempty"` // 向量化模型，按切换顺序
 }
 
-func (x *GetChatRecordsRsp) GetTotal() int64 {
+func (x *GetModelStatusRsp) Reset() { *x = GetModelStatusRsp{} }
+
+func (x *GetModelStatusRsp) Marshal(in []byte) ([]byte, error) { return prutal.MarshalAppend(in, x) }
+
+func (x *GetModelStatusRsp) Unmarshal(in []byte) error { return prutal.Unmarshal(in, x) }
+
+func (x *GetModelStatusRsp) GetCode() uint32 {
 	if x != nil {
-		return x.Total
+		return x.Code
 	}
 	return 0
 }
 
-func (x *GetChatRecordsRsp) GetPage() int32 {
+func (x *GetModelStatusRsp) GetMsg() string {
 	if x != nil {
-		return x.Page
+		return x.Msg
 	}
-	return 0
+	return ""
 }
 
-func (x *GetChatRecordsRsp) GetPageSize() int32 {
+func (x *GetModelStatusRsp) GetChatModels() []*ModelBreakerStat {
 	if x != nil {
-		return x.PageSize
+		return x.ChatModels
 	}
-	return 0
+	return nil
 }
 
-func (x *GetChatRecordsRsp) GetRecords() []*ChatRecord {
+func (x *GetModelStatusRsp) GetEmbeddingModels() []*ModelBreakerStat {
 	if x != nil {
-		return x.Records
+		return x.EmbeddingModels
 	}
 	return nil
 }
@@ -2191,19 +5127,47 @@ type RagService interface {
 	CreateSession(ctx context.Context, req *CreateSessionReq) (res *CreateSessionRsp, err error)
 	GetSession(ctx context.Context, req *GetSessionReq) (res *GetSessionRsp, err error)
 	EndSession(ctx context.Context, req *EndSessionReq) (res *EndSessionRsp, err error)
+	UpdateSession(ctx context.Context, req *UpdateSessionReq) (res *UpdateSessionRsp, err error)
 	GetSessionList(ctx context.Context, req *GetSessionListReq) (res *GetSessionListRsp, err error)
 	CleanInactiveSessions(ctx context.Context, req *CleanInactiveSessionsReq) (res *CleanInactiveSessionsRsp, err error)
 	AddDocument(ctx context.Context, req *AddDocumentReq) (res *AddDocumentRsp, err error)
+	AddDocumentFile(ctx context.Context, req *AddDocumentFileReq) (res *AddDocumentFileRsp, err error)
+	UpdateDocument(ctx context.Context, req *UpdateDocumentReq) (res *UpdateDocumentRsp, err error)
+	ListDocumentVersions(ctx context.Context, req *ListDocumentVersionsReq) (res *ListDocumentVersionsRsp, err error)
+	RestoreDocumentVersion(ctx context.Context, req *RestoreDocumentVersionReq) (res *RestoreDocumentVersionRsp, err error)
+	GetDocumentStatus(ctx context.Context, req *GetDocumentStatusReq) (res *GetDocumentStatusRsp, err error)
 	DeleteDocument(ctx context.Context, req *DeleteDocumentReq) (res *DeleteDocumentRsp, err error)
 	SearchDocument(ctx context.Context, req *SearchDocumentReq) (res *SearchDocumentRsp, err error)
 	ListDocument(ctx context.Context, req *ListDocumentReq) (res *ListDocumentRsp, err error)
+	MoveDocument(ctx context.Context, req *MoveDocumentReq) (res *MoveDocumentRsp, err error)
+	SetDocumentTags(ctx context.Context, req *SetDocumentTagsReq) (res *SetDocumentTagsRsp, err error)
+	CreateCollection(ctx context.Context, req *CreateCollectionReq) (res *CreateCollectionRsp, err error)
+	ListCollections(ctx context.Context, req *ListCollectionsReq) (res *ListCollectionsRsp, err error)
+	UpdateCollection(ctx context.Context, req *UpdateCollectionReq) (res *UpdateCollectionRsp, err error)
+	DeleteCollection(ctx context.Context, req *DeleteCollectionReq) (res *DeleteCollectionRsp, err error)
+	GrantAccess(ctx context.Context, req *GrantAccessReq) (res *GrantAccessRsp, err error)
+	RevokeAccess(ctx context.Context, req *RevokeAccessReq) (res *RevokeAccessRsp, err error)
+	ListAccess(ctx context.Context, req *ListAccessReq) (res *ListAccessRsp, err error)
+	CreateGroup(ctx context.Context, req *CreateGroupReq) (res *CreateGroupRsp, err error)
+	AddGroupMembers(ctx context.Context, req *AddGroupMembersReq) (res *AddGroupMembersRsp, err error)
+	RemoveGroupMembers(ctx context.Context, req *RemoveGroupMembersReq) (res *RemoveGroupMembersRsp, err error)
+	ListGroups(ctx context.Context, req *ListGroupsReq) (res *ListGroupsRsp, err error)
 	AddMemory(ctx context.Context, req *AddMemoryReq) (res *AddMemoryRsp, err error)
 	GetMemory(ctx context.Context, req *GetMemoryReq) (res *GetMemoryRsp, err error)
 	SearchMemories(ctx context.Context, req *SearchMemoriesReq) (res *SearchMemoriesRsp, err error)
 	DeleteMemory(ctx context.Context, req *DeleteMemoryReq) (res *DeleteMemoryRsp, err error)
 	AddChatRecord(ctx context.Context, req *AddChatRecordReq) (res *AddChatRecordRsp, err error)
 	GetChatRecords(ctx context.Context, req *GetChatRecordsReq) (res *GetChatRecordsRsp, err error)
+	Chat(ctx context.Context, req *ChatReq) (res *ChatRsp, err error)
+	StreamChat(req *ChatReq, stream RagService_StreamChatServer) (err error)
+	GetUsage(ctx context.Context, req *GetUsageReq) (res *GetUsageRsp, err error)
+	GetModelStatus(ctx context.Context, req *GetModelStatusReq) (res *GetModelStatusRsp, err error)
 	GetWeather(ctx context.Context, req *GetWeatherReq) (res *GetWeatherRsp, err error)
 	GetHourlyWeather(ctx context.Context, req *GetHourlyWeatherReq) (res *GetHourlyWeatherRsp, err error)
 	GetDailyWeather(ctx context.Context, req *GetDailyWeatherReq) (res *GetDailyWeatherRsp, err error)
 }
+
+type RagService_StreamChatServer interface {
+	streaming.Stream
+	Send(*StreamChatRsp) error
+}
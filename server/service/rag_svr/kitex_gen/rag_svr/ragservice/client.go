@@ -6,6 +6,10 @@ import (
 	"context"
 	client "github.com/cloudwego/kitex/client"
 	callopt "github.com/cloudwego/kitex/client/callopt"
+	streamcall "github.com/cloudwego/kitex/client/callopt/streamcall"
+	streamclient "github.com/cloudwego/kitex/client/streamclient"
+	streaming "github.com/cloudwego/kitex/pkg/streaming"
+	transport "github.com/cloudwego/kitex/transport"
 	rag_svr "server/service/rag_svr/kitex_gen/rag_svr"
 )
 
@@ -17,28 +21,63 @@ type Client interface {
 	CreateSession(ctx context.Context, Req *rag_svr.CreateSessionReq, callOptions ...callopt.Option) (r *rag_svr.CreateSessionRsp, err error)
 	GetSession(ctx context.Context, Req *rag_svr.GetSessionReq, callOptions ...callopt.Option) (r *rag_svr.GetSessionRsp, err error)
 	EndSession(ctx context.Context, Req *rag_svr.EndSessionReq, callOptions ...callopt.Option) (r *rag_svr.EndSessionRsp, err error)
+	UpdateSession(ctx context.Context, Req *rag_svr.UpdateSessionReq, callOptions ...callopt.Option) (r *rag_svr.UpdateSessionRsp, err error)
 	GetSessionList(ctx context.Context, Req *rag_svr.GetSessionListReq, callOptions ...callopt.Option) (r *rag_svr.GetSessionListRsp, err error)
 	CleanInactiveSessions(ctx context.Context, Req *rag_svr.CleanInactiveSessionsReq, callOptions ...callopt.Option) (r *rag_svr.CleanInactiveSessionsRsp, err error)
 	AddDocument(ctx context.Context, Req *rag_svr.AddDocumentReq, callOptions ...callopt.Option) (r *rag_svr.AddDocumentRsp, err error)
+	AddDocumentFile(ctx context.Context, Req *rag_svr.AddDocumentFileReq, callOptions ...callopt.Option) (r *rag_svr.AddDocumentFileRsp, err error)
+	UpdateDocument(ctx context.Context, Req *rag_svr.UpdateDocumentReq, callOptions ...callopt.Option) (r *rag_svr.UpdateDocumentRsp, err error)
+	ListDocumentVersions(ctx context.Context, Req *rag_svr.ListDocumentVersionsReq, callOptions ...callopt.Option) (r *rag_svr.ListDocumentVersionsRsp, err error)
+	RestoreDocumentVersion(ctx context.Context, Req *rag_svr.RestoreDocumentVersionReq, callOptions ...callopt.Option) (r *rag_svr.RestoreDocumentVersionRsp, err error)
+	GetDocumentStatus(ctx context.Context, Req *rag_svr.GetDocumentStatusReq, callOptions ...callopt.Option) (r *rag_svr.GetDocumentStatusRsp, err error)
 	DeleteDocument(ctx context.Context, Req *rag_svr.DeleteDocumentReq, callOptions ...callopt.Option) (r *rag_svr.DeleteDocumentRsp, err error)
 	SearchDocument(ctx context.Context, Req *rag_svr.SearchDocumentReq, callOptions ...callopt.Option) (r *rag_svr.SearchDocumentRsp, err error)
 	ListDocument(ctx context.Context, Req *rag_svr.ListDocumentReq, callOptions ...callopt.Option) (r *rag_svr.ListDocumentRsp, err error)
+	MoveDocument(ctx context.Context, Req *rag_svr.MoveDocumentReq, callOptions ...callopt.Option) (r *rag_svr.MoveDocumentRsp, err error)
+	SetDocumentTags(ctx context.Context, Req *rag_svr.SetDocumentTagsReq, callOptions ...callopt.Option) (r *rag_svr.SetDocumentTagsRsp, err error)
+	CreateCollection(ctx context.Context, Req *rag_svr.CreateCollectionReq, callOptions ...callopt.Option) (r *rag_svr.CreateCollectionRsp, err error)
+	ListCollections(ctx context.Context, Req *rag_svr.ListCollectionsReq, callOptions ...callopt.Option) (r *rag_svr.ListCollectionsRsp, err error)
+	UpdateCollection(ctx context.Context, Req *rag_svr.UpdateCollectionReq, callOptions ...callopt.Option) (r *rag_svr.UpdateCollectionRsp, err error)
+	DeleteCollection(ctx context.Context, Req *rag_svr.DeleteCollectionReq, callOptions ...callopt.Option) (r *rag_svr.DeleteCollectionRsp, err error)
+	GrantAccess(ctx context.Context, Req *rag_svr.GrantAccessReq, callOptions ...callopt.Option) (r *rag_svr.GrantAccessRsp, err error)
+	RevokeAccess(ctx context.Context, Req *rag_svr.RevokeAccessReq, callOptions ...callopt.Option) (r *rag_svr.RevokeAccessRsp, err error)
+	ListAccess(ctx context.Context, Req *rag_svr.ListAccessReq, callOptions ...callopt.Option) (r *rag_svr.ListAccessRsp, err error)
+	CreateGroup(ctx context.Context, Req *rag_svr.CreateGroupReq, callOptions ...callopt.Option) (r *rag_svr.CreateGroupRsp, err error)
+	AddGroupMembers(ctx context.Context, Req *rag_svr.AddGroupMembersReq, callOptions ...callopt.Option) (r *rag_svr.AddGroupMembersRsp, err error)
+	RemoveGroupMembers(ctx context.Context, Req *rag_svr.RemoveGroupMembersReq, callOptions ...callopt.Option) (r *rag_svr.RemoveGroupMembersRsp, err error)
+	ListGroups(ctx context.Context, Req *rag_svr.ListGroupsReq, callOptions ...callopt.Option) (r *rag_svr.ListGroupsRsp, err error)
 	AddMemory(ctx context.Context, Req *rag_svr.AddMemoryReq, callOptions ...callopt.Option) (r *rag_svr.AddMemoryRsp, err error)
 	GetMemory(ctx context.Context, Req *rag_svr.GetMemoryReq, callOptions ...callopt.Option) (r *rag_svr.GetMemoryRsp, err error)
 	SearchMemories(ctx context.Context, Req *rag_svr.SearchMemoriesReq, callOptions ...callopt.Option) (r *rag_svr.SearchMemoriesRsp, err error)
 	DeleteMemory(ctx context.Context, Req *rag_svr.DeleteMemoryReq, callOptions ...callopt.Option) (r *rag_svr.DeleteMemoryRsp, err error)
 	AddChatRecord(ctx context.Context, Req *rag_svr.AddChatRecordReq, callOptions ...callopt.Option) (r *rag_svr.AddChatRecordRsp, err error)
 	GetChatRecords(ctx context.Context, Req *rag_svr.GetChatRecordsReq, callOptions ...callopt.Option) (r *rag_svr.GetChatRecordsRsp, err error)
+	Chat(ctx context.Context, Req *rag_svr.ChatReq, callOptions ...callopt.Option) (r *rag_svr.ChatRsp, err error)
+	StreamChat(ctx context.Context, Req *rag_svr.ChatReq, callOptions ...callopt.Option) (stream RagService_StreamChatClient, err error)
+	GetUsage(ctx context.Context, Req *rag_svr.GetUsageReq, callOptions ...callopt.Option) (r *rag_svr.GetUsageRsp, err error)
+	GetModelStatus(ctx context.Context, Req *rag_svr.GetModelStatusReq, callOptions ...callopt.Option) (r *rag_svr.GetModelStatusRsp, err error)
 	GetWeather(ctx context.Context, Req *rag_svr.GetWeatherReq, callOptions ...callopt.Option) (r *rag_svr.GetWeatherRsp, err error)
 	GetHourlyWeather(ctx context.Context, Req *rag_svr.GetHourlyWeatherReq, callOptions ...callopt.Option) (r *rag_svr.GetHourlyWeatherRsp, err error)
 	GetDailyWeather(ctx context.Context, Req *rag_svr.GetDailyWeatherReq, callOptions ...callopt.Option) (r *rag_svr.GetDailyWeatherRsp, err error)
 }
 
+// StreamClient is designed to provide Interface for Streaming APIs.
+type StreamClient interface {
+	StreamChat(ctx context.Context, Req *rag_svr.ChatReq, callOptions ...streamcall.Option) (stream RagService_StreamChatClient, err error)
+}
+
+type RagService_StreamChatClient interface {
+	streaming.Stream
+	Recv() (*rag_svr.StreamChatRsp, error)
+}
+
 // NewClient creates a client for the service defined in IDL.
 func NewClient(destService string, opts ...client.Option) (Client, error) {
 	var options []client.Option
 	options = append(options, client.WithDestService(destService))
 
+	options = append(options, client.WithTransportProtocol(transport.GRPC))
+
 	options = append(options, opts...)
 
 	kc, err := client.NewClient(serviceInfo(), options...)
@@ -93,6 +132,11 @@ func (p *kRagServiceClient) EndSession(ctx context.Context, Req *rag_svr.EndSess
 	return p.kClient.EndSession(ctx, Req)
 }
 
+func (p *kRagServiceClient) UpdateSession(ctx context.Context, Req *rag_svr.UpdateSessionReq, callOptions ...callopt.Option) (r *rag_svr.UpdateSessionRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.UpdateSession(ctx, Req)
+}
+
 func (p *kRagServiceClient) GetSessionList(ctx context.Context, Req *rag_svr.GetSessionListReq, callOptions ...callopt.Option) (r *rag_svr.GetSessionListRsp, err error) {
 	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
 	return p.kClient.GetSessionList(ctx, Req)
@@ -108,6 +152,31 @@ func (p *kRagServiceClient) AddDocument(ctx context.Context, Req *rag_svr.AddDoc
 	return p.kClient.AddDocument(ctx, Req)
 }
 
+func (p *kRagServiceClient) AddDocumentFile(ctx context.Context, Req *rag_svr.AddDocumentFileReq, callOptions ...callopt.Option) (r *rag_svr.AddDocumentFileRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.AddDocumentFile(ctx, Req)
+}
+
+func (p *kRagServiceClient) UpdateDocument(ctx context.Context, Req *rag_svr.UpdateDocumentReq, callOptions ...callopt.Option) (r *rag_svr.UpdateDocumentRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.UpdateDocument(ctx, Req)
+}
+
+func (p *kRagServiceClient) ListDocumentVersions(ctx context.Context, Req *rag_svr.ListDocumentVersionsReq, callOptions ...callopt.Option) (r *rag_svr.ListDocumentVersionsRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.ListDocumentVersions(ctx, Req)
+}
+
+func (p *kRagServiceClient) RestoreDocumentVersion(ctx context.Context, Req *rag_svr.RestoreDocumentVersionReq, callOptions ...callopt.Option) (r *rag_svr.RestoreDocumentVersionRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.RestoreDocumentVersion(ctx, Req)
+}
+
+func (p *kRagServiceClient) GetDocumentStatus(ctx context.Context, Req *rag_svr.GetDocumentStatusReq, callOptions ...callopt.Option) (r *rag_svr.GetDocumentStatusRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GetDocumentStatus(ctx, Req)
+}
+
 func (p *kRagServiceClient) DeleteDocument(ctx context.Context, Req *rag_svr.DeleteDocumentReq, callOptions ...callopt.Option) (r *rag_svr.DeleteDocumentRsp, err error) {
 	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
 	return p.kClient.DeleteDocument(ctx, Req)
@@ -123,6 +192,71 @@ func (p *kRagServiceClient) ListDocument(ctx context.Context, Req *rag_svr.ListD
 	return p.kClient.ListDocument(ctx, Req)
 }
 
+func (p *kRagServiceClient) MoveDocument(ctx context.Context, Req *rag_svr.MoveDocumentReq, callOptions ...callopt.Option) (r *rag_svr.MoveDocumentRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.MoveDocument(ctx, Req)
+}
+
+func (p *kRagServiceClient) SetDocumentTags(ctx context.Context, Req *rag_svr.SetDocumentTagsReq, callOptions ...callopt.Option) (r *rag_svr.SetDocumentTagsRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.SetDocumentTags(ctx, Req)
+}
+
+func (p *kRagServiceClient) CreateCollection(ctx context.Context, Req *rag_svr.CreateCollectionReq, callOptions ...callopt.Option) (r *rag_svr.CreateCollectionRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.CreateCollection(ctx, Req)
+}
+
+func (p *kRagServiceClient) ListCollections(ctx context.Context, Req *rag_svr.ListCollectionsReq, callOptions ...callopt.Option) (r *rag_svr.ListCollectionsRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.ListCollections(ctx, Req)
+}
+
+func (p *kRagServiceClient) UpdateCollection(ctx context.Context, Req *rag_svr.UpdateCollectionReq, callOptions ...callopt.Option) (r *rag_svr.UpdateCollectionRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.UpdateCollection(ctx, Req)
+}
+
+func (p *kRagServiceClient) DeleteCollection(ctx context.Context, Req *rag_svr.DeleteCollectionReq, callOptions ...callopt.Option) (r *rag_svr.DeleteCollectionRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.DeleteCollection(ctx, Req)
+}
+
+func (p *kRagServiceClient) GrantAccess(ctx context.Context, Req *rag_svr.GrantAccessReq, callOptions ...callopt.Option) (r *rag_svr.GrantAccessRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GrantAccess(ctx, Req)
+}
+
+func (p *kRagServiceClient) RevokeAccess(ctx context.Context, Req *rag_svr.RevokeAccessReq, callOptions ...callopt.Option) (r *rag_svr.RevokeAccessRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.RevokeAccess(ctx, Req)
+}
+
+func (p *kRagServiceClient) ListAccess(ctx context.Context, Req *rag_svr.ListAccessReq, callOptions ...callopt.Option) (r *rag_svr.ListAccessRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.ListAccess(ctx, Req)
+}
+
+func (p *kRagServiceClient) CreateGroup(ctx context.Context, Req *rag_svr.CreateGroupReq, callOptions ...callopt.Option) (r *rag_svr.CreateGroupRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.CreateGroup(ctx, Req)
+}
+
+func (p *kRagServiceClient) AddGroupMembers(ctx context.Context, Req *rag_svr.AddGroupMembersReq, callOptions ...callopt.Option) (r *rag_svr.AddGroupMembersRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.AddGroupMembers(ctx, Req)
+}
+
+func (p *kRagServiceClient) RemoveGroupMembers(ctx context.Context, Req *rag_svr.RemoveGroupMembersReq, callOptions ...callopt.Option) (r *rag_svr.RemoveGroupMembersRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.RemoveGroupMembers(ctx, Req)
+}
+
+func (p *kRagServiceClient) ListGroups(ctx context.Context, Req *rag_svr.ListGroupsReq, callOptions ...callopt.Option) (r *rag_svr.ListGroupsRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.ListGroups(ctx, Req)
+}
+
 func (p *kRagServiceClient) AddMemory(ctx context.Context, Req *rag_svr.AddMemoryReq, callOptions ...callopt.Option) (r *rag_svr.AddMemoryRsp, err error) {
 	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
 	return p.kClient.AddMemory(ctx, Req)
@@ -153,6 +287,26 @@ func (p *kRagServiceClient) GetChatRecords(ctx context.Context, Req *rag_svr.Get
 	return p.kClient.GetChatRecords(ctx, Req)
 }
 
+func (p *kRagServiceClient) Chat(ctx context.Context, Req *rag_svr.ChatReq, callOptions ...callopt.Option) (r *rag_svr.ChatRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Chat(ctx, Req)
+}
+
+func (p *kRagServiceClient) StreamChat(ctx context.Context, Req *rag_svr.ChatReq, callOptions ...callopt.Option) (stream RagService_StreamChatClient, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.StreamChat(ctx, Req)
+}
+
+func (p *kRagServiceClient) GetUsage(ctx context.Context, Req *rag_svr.GetUsageReq, callOptions ...callopt.Option) (r *rag_svr.GetUsageRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GetUsage(ctx, Req)
+}
+
+func (p *kRagServiceClient) GetModelStatus(ctx context.Context, Req *rag_svr.GetModelStatusReq, callOptions ...callopt.Option) (r *rag_svr.GetModelStatusRsp, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GetModelStatus(ctx, Req)
+}
+
 func (p *kRagServiceClient) GetWeather(ctx context.Context, Req *rag_svr.GetWeatherReq, callOptions ...callopt.Option) (r *rag_svr.GetWeatherRsp, err error) {
 	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
 	return p.kClient.GetWeather(ctx, Req)
@@ -167,3 +321,38 @@ func (p *kRagServiceClient) GetDailyWeather(ctx context.Context, Req *rag_svr.Ge
 	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
 	return p.kClient.GetDailyWeather(ctx, Req)
 }
+
+// NewStreamClient creates a stream client for the service's streaming APIs defined in IDL.
+func NewStreamClient(destService string, opts ...streamclient.Option) (StreamClient, error) {
+	var options []client.Option
+	options = append(options, client.WithDestService(destService))
+	options = append(options, client.WithTransportProtocol(transport.GRPC))
+	options = append(options, streamclient.GetClientOptions(opts)...)
+
+	kc, err := client.NewClient(serviceInfoForStreamClient(), options...)
+	if err != nil {
+		return nil, err
+	}
+	return &kRagServiceStreamClient{
+		kClient: newServiceClient(kc),
+	}, nil
+}
+
+// MustNewStreamClient creates a stream client for the service's streaming APIs defined in IDL.
+// It panics if any error occurs.
+func MustNewStreamClient(destService string, opts ...streamclient.Option) StreamClient {
+	kc, err := NewStreamClient(destService, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return kc
+}
+
+type kRagServiceStreamClient struct {
+	*kClient
+}
+
+func (p *kRagServiceStreamClient) StreamChat(ctx context.Context, Req *rag_svr.ChatReq, callOptions ...streamcall.Option) (stream RagService_StreamChatClient, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, streamcall.GetCallOptions(callOptions))
+	return p.kClient.StreamChat(ctx, Req)
+}
@@ -5,6 +5,7 @@ package ragservice
 import (
 	"context"
 	"errors"
+	"fmt"
 	client "github.com/cloudwego/kitex/client"
 	kitex "github.com/cloudwego/kitex/pkg/serviceinfo"
 	streaming "github.com/cloudwego/kitex/pkg/streaming"
@@ -57,6 +58,13 @@ var serviceMethods = map[string]kitex.MethodInfo{
 		false,
 		kitex.WithStreamingMode(kitex.StreamingUnary),
 	),
+	"UpdateSession": kitex.NewMethodInfo(
+		updateSessionHandler,
+		newUpdateSessionArgs,
+		newUpdateSessionResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
 	"GetSessionList": kitex.NewMethodInfo(
 		getSessionListHandler,
 		newGetSessionListArgs,
@@ -78,6 +86,41 @@ var serviceMethods = map[string]kitex.MethodInfo{
 		false,
 		kitex.WithStreamingMode(kitex.StreamingUnary),
 	),
+	"AddDocumentFile": kitex.NewMethodInfo(
+		addDocumentFileHandler,
+		newAddDocumentFileArgs,
+		newAddDocumentFileResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"UpdateDocument": kitex.NewMethodInfo(
+		updateDocumentHandler,
+		newUpdateDocumentArgs,
+		newUpdateDocumentResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"ListDocumentVersions": kitex.NewMethodInfo(
+		listDocumentVersionsHandler,
+		newListDocumentVersionsArgs,
+		newListDocumentVersionsResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"RestoreDocumentVersion": kitex.NewMethodInfo(
+		restoreDocumentVersionHandler,
+		newRestoreDocumentVersionArgs,
+		newRestoreDocumentVersionResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"GetDocumentStatus": kitex.NewMethodInfo(
+		getDocumentStatusHandler,
+		newGetDocumentStatusArgs,
+		newGetDocumentStatusResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
 	"DeleteDocument": kitex.NewMethodInfo(
 		deleteDocumentHandler,
 		newDeleteDocumentArgs,
@@ -99,6 +142,97 @@ var serviceMethods = map[string]kitex.MethodInfo{
 		false,
 		kitex.WithStreamingMode(kitex.StreamingUnary),
 	),
+	"MoveDocument": kitex.NewMethodInfo(
+		moveDocumentHandler,
+		newMoveDocumentArgs,
+		newMoveDocumentResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"SetDocumentTags": kitex.NewMethodInfo(
+		setDocumentTagsHandler,
+		newSetDocumentTagsArgs,
+		newSetDocumentTagsResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"CreateCollection": kitex.NewMethodInfo(
+		createCollectionHandler,
+		newCreateCollectionArgs,
+		newCreateCollectionResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"ListCollections": kitex.NewMethodInfo(
+		listCollectionsHandler,
+		newListCollectionsArgs,
+		newListCollectionsResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"UpdateCollection": kitex.NewMethodInfo(
+		updateCollectionHandler,
+		newUpdateCollectionArgs,
+		newUpdateCollectionResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"DeleteCollection": kitex.NewMethodInfo(
+		deleteCollectionHandler,
+		newDeleteCollectionArgs,
+		newDeleteCollectionResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"GrantAccess": kitex.NewMethodInfo(
+		grantAccessHandler,
+		newGrantAccessArgs,
+		newGrantAccessResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"RevokeAccess": kitex.NewMethodInfo(
+		revokeAccessHandler,
+		newRevokeAccessArgs,
+		newRevokeAccessResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"ListAccess": kitex.NewMethodInfo(
+		listAccessHandler,
+		newListAccessArgs,
+		newListAccessResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"CreateGroup": kitex.NewMethodInfo(
+		createGroupHandler,
+		newCreateGroupArgs,
+		newCreateGroupResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"AddGroupMembers": kitex.NewMethodInfo(
+		addGroupMembersHandler,
+		newAddGroupMembersArgs,
+		newAddGroupMembersResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"RemoveGroupMembers": kitex.NewMethodInfo(
+		removeGroupMembersHandler,
+		newRemoveGroupMembersArgs,
+		newRemoveGroupMembersResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"ListGroups": kitex.NewMethodInfo(
+		listGroupsHandler,
+		newListGroupsArgs,
+		newListGroupsResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
 	"AddMemory": kitex.NewMethodInfo(
 		addMemoryHandler,
 		newAddMemoryArgs,
@@ -141,6 +275,34 @@ var serviceMethods = map[string]kitex.MethodInfo{
 		false,
 		kitex.WithStreamingMode(kitex.StreamingUnary),
 	),
+	"Chat": kitex.NewMethodInfo(
+		chatHandler,
+		newChatArgs,
+		newChatResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"StreamChat": kitex.NewMethodInfo(
+		streamChatHandler,
+		newStreamChatArgs,
+		newStreamChatResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingServer),
+	),
+	"GetUsage": kitex.NewMethodInfo(
+		getUsageHandler,
+		newGetUsageArgs,
+		newGetUsageResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"GetModelStatus": kitex.NewMethodInfo(
+		getModelStatusHandler,
+		newGetModelStatusArgs,
+		newGetModelStatusResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
 	"GetWeather": kitex.NewMethodInfo(
 		getWeatherHandler,
 		newGetWeatherArgs,
@@ -187,7 +349,7 @@ func serviceInfoForClient() *kitex.ServiceInfo {
 
 // NewServiceInfo creates a new ServiceInfo containing all methods
 func NewServiceInfo() *kitex.ServiceInfo {
-	return newServiceInfo(false, true, true)
+	return newServiceInfo(true, true, true)
 }
 
 // NewServiceInfo creates a new ServiceInfo containing non-streaming methods
@@ -894,6 +1056,117 @@ func (p *EndSessionResult) GetResult() interface{} {
 	return p.Success
 }
 
+func updateSessionHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.UpdateSessionReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).UpdateSession(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *UpdateSessionArgs:
+		success, err := handler.(rag_svr.RagService).UpdateSession(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*UpdateSessionResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newUpdateSessionArgs() interface{} {
+	return &UpdateSessionArgs{}
+}
+
+func newUpdateSessionResult() interface{} {
+	return &UpdateSessionResult{}
+}
+
+type UpdateSessionArgs struct {
+	Req *rag_svr.UpdateSessionReq
+}
+
+func (p *UpdateSessionArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *UpdateSessionArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.UpdateSessionReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var UpdateSessionArgs_Req_DEFAULT *rag_svr.UpdateSessionReq
+
+func (p *UpdateSessionArgs) GetReq() *rag_svr.UpdateSessionReq {
+	if !p.IsSetReq() {
+		return UpdateSessionArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *UpdateSessionArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *UpdateSessionArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type UpdateSessionResult struct {
+	Success *rag_svr.UpdateSessionRsp
+}
+
+var UpdateSessionResult_Success_DEFAULT *rag_svr.UpdateSessionRsp
+
+func (p *UpdateSessionResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *UpdateSessionResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.UpdateSessionRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *UpdateSessionResult) GetSuccess() *rag_svr.UpdateSessionRsp {
+	if !p.IsSetSuccess() {
+		return UpdateSessionResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *UpdateSessionResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.UpdateSessionRsp)
+}
+
+func (p *UpdateSessionResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *UpdateSessionResult) GetResult() interface{} {
+	return p.Success
+}
+
 func getSessionListHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
@@ -1227,52 +1500,52 @@ func (p *AddDocumentResult) GetResult() interface{} {
 	return p.Success
 }
 
-func deleteDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func addDocumentFileHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.DeleteDocumentReq)
+		req := new(rag_svr.AddDocumentFileReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).DeleteDocument(ctx, req)
+		resp, err := handler.(rag_svr.RagService).AddDocumentFile(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *DeleteDocumentArgs:
-		success, err := handler.(rag_svr.RagService).DeleteDocument(ctx, s.Req)
+	case *AddDocumentFileArgs:
+		success, err := handler.(rag_svr.RagService).AddDocumentFile(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*DeleteDocumentResult)
+		realResult := result.(*AddDocumentFileResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newDeleteDocumentArgs() interface{} {
-	return &DeleteDocumentArgs{}
+func newAddDocumentFileArgs() interface{} {
+	return &AddDocumentFileArgs{}
 }
 
-func newDeleteDocumentResult() interface{} {
-	return &DeleteDocumentResult{}
+func newAddDocumentFileResult() interface{} {
+	return &AddDocumentFileResult{}
 }
 
-type DeleteDocumentArgs struct {
-	Req *rag_svr.DeleteDocumentReq
+type AddDocumentFileArgs struct {
+	Req *rag_svr.AddDocumentFileReq
 }
 
-func (p *DeleteDocumentArgs) Marshal(out []byte) ([]byte, error) {
+func (p *AddDocumentFileArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *DeleteDocumentArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.DeleteDocumentReq)
+func (p *AddDocumentFileArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddDocumentFileReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1280,38 +1553,38 @@ func (p *DeleteDocumentArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var DeleteDocumentArgs_Req_DEFAULT *rag_svr.DeleteDocumentReq
+var AddDocumentFileArgs_Req_DEFAULT *rag_svr.AddDocumentFileReq
 
-func (p *DeleteDocumentArgs) GetReq() *rag_svr.DeleteDocumentReq {
+func (p *AddDocumentFileArgs) GetReq() *rag_svr.AddDocumentFileReq {
 	if !p.IsSetReq() {
-		return DeleteDocumentArgs_Req_DEFAULT
+		return AddDocumentFileArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *DeleteDocumentArgs) IsSetReq() bool {
+func (p *AddDocumentFileArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *DeleteDocumentArgs) GetFirstArgument() interface{} {
+func (p *AddDocumentFileArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type DeleteDocumentResult struct {
-	Success *rag_svr.DeleteDocumentRsp
+type AddDocumentFileResult struct {
+	Success *rag_svr.AddDocumentFileRsp
 }
 
-var DeleteDocumentResult_Success_DEFAULT *rag_svr.DeleteDocumentRsp
+var AddDocumentFileResult_Success_DEFAULT *rag_svr.AddDocumentFileRsp
 
-func (p *DeleteDocumentResult) Marshal(out []byte) ([]byte, error) {
+func (p *AddDocumentFileResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *DeleteDocumentResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.DeleteDocumentRsp)
+func (p *AddDocumentFileResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddDocumentFileRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1319,71 +1592,71 @@ func (p *DeleteDocumentResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *DeleteDocumentResult) GetSuccess() *rag_svr.DeleteDocumentRsp {
+func (p *AddDocumentFileResult) GetSuccess() *rag_svr.AddDocumentFileRsp {
 	if !p.IsSetSuccess() {
-		return DeleteDocumentResult_Success_DEFAULT
+		return AddDocumentFileResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *DeleteDocumentResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.DeleteDocumentRsp)
+func (p *AddDocumentFileResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.AddDocumentFileRsp)
 }
 
-func (p *DeleteDocumentResult) IsSetSuccess() bool {
+func (p *AddDocumentFileResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *DeleteDocumentResult) GetResult() interface{} {
+func (p *AddDocumentFileResult) GetResult() interface{} {
 	return p.Success
 }
 
-func searchDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func updateDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.SearchDocumentReq)
+		req := new(rag_svr.UpdateDocumentReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).SearchDocument(ctx, req)
+		resp, err := handler.(rag_svr.RagService).UpdateDocument(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *SearchDocumentArgs:
-		success, err := handler.(rag_svr.RagService).SearchDocument(ctx, s.Req)
+	case *UpdateDocumentArgs:
+		success, err := handler.(rag_svr.RagService).UpdateDocument(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*SearchDocumentResult)
+		realResult := result.(*UpdateDocumentResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newSearchDocumentArgs() interface{} {
-	return &SearchDocumentArgs{}
+func newUpdateDocumentArgs() interface{} {
+	return &UpdateDocumentArgs{}
 }
 
-func newSearchDocumentResult() interface{} {
-	return &SearchDocumentResult{}
+func newUpdateDocumentResult() interface{} {
+	return &UpdateDocumentResult{}
 }
 
-type SearchDocumentArgs struct {
-	Req *rag_svr.SearchDocumentReq
+type UpdateDocumentArgs struct {
+	Req *rag_svr.UpdateDocumentReq
 }
 
-func (p *SearchDocumentArgs) Marshal(out []byte) ([]byte, error) {
+func (p *UpdateDocumentArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *SearchDocumentArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.SearchDocumentReq)
+func (p *UpdateDocumentArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.UpdateDocumentReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1391,38 +1664,38 @@ func (p *SearchDocumentArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var SearchDocumentArgs_Req_DEFAULT *rag_svr.SearchDocumentReq
+var UpdateDocumentArgs_Req_DEFAULT *rag_svr.UpdateDocumentReq
 
-func (p *SearchDocumentArgs) GetReq() *rag_svr.SearchDocumentReq {
+func (p *UpdateDocumentArgs) GetReq() *rag_svr.UpdateDocumentReq {
 	if !p.IsSetReq() {
-		return SearchDocumentArgs_Req_DEFAULT
+		return UpdateDocumentArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *SearchDocumentArgs) IsSetReq() bool {
+func (p *UpdateDocumentArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *SearchDocumentArgs) GetFirstArgument() interface{} {
+func (p *UpdateDocumentArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type SearchDocumentResult struct {
-	Success *rag_svr.SearchDocumentRsp
+type UpdateDocumentResult struct {
+	Success *rag_svr.UpdateDocumentRsp
 }
 
-var SearchDocumentResult_Success_DEFAULT *rag_svr.SearchDocumentRsp
+var UpdateDocumentResult_Success_DEFAULT *rag_svr.UpdateDocumentRsp
 
-func (p *SearchDocumentResult) Marshal(out []byte) ([]byte, error) {
+func (p *UpdateDocumentResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *SearchDocumentResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.SearchDocumentRsp)
+func (p *UpdateDocumentResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.UpdateDocumentRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1430,71 +1703,71 @@ func (p *SearchDocumentResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *SearchDocumentResult) GetSuccess() *rag_svr.SearchDocumentRsp {
+func (p *UpdateDocumentResult) GetSuccess() *rag_svr.UpdateDocumentRsp {
 	if !p.IsSetSuccess() {
-		return SearchDocumentResult_Success_DEFAULT
+		return UpdateDocumentResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *SearchDocumentResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.SearchDocumentRsp)
+func (p *UpdateDocumentResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.UpdateDocumentRsp)
 }
 
-func (p *SearchDocumentResult) IsSetSuccess() bool {
+func (p *UpdateDocumentResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *SearchDocumentResult) GetResult() interface{} {
+func (p *UpdateDocumentResult) GetResult() interface{} {
 	return p.Success
 }
 
-func listDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func listDocumentVersionsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.ListDocumentReq)
+		req := new(rag_svr.ListDocumentVersionsReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).ListDocument(ctx, req)
+		resp, err := handler.(rag_svr.RagService).ListDocumentVersions(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *ListDocumentArgs:
-		success, err := handler.(rag_svr.RagService).ListDocument(ctx, s.Req)
+	case *ListDocumentVersionsArgs:
+		success, err := handler.(rag_svr.RagService).ListDocumentVersions(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*ListDocumentResult)
+		realResult := result.(*ListDocumentVersionsResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newListDocumentArgs() interface{} {
-	return &ListDocumentArgs{}
+func newListDocumentVersionsArgs() interface{} {
+	return &ListDocumentVersionsArgs{}
 }
 
-func newListDocumentResult() interface{} {
-	return &ListDocumentResult{}
+func newListDocumentVersionsResult() interface{} {
+	return &ListDocumentVersionsResult{}
 }
 
-type ListDocumentArgs struct {
-	Req *rag_svr.ListDocumentReq
+type ListDocumentVersionsArgs struct {
+	Req *rag_svr.ListDocumentVersionsReq
 }
 
-func (p *ListDocumentArgs) Marshal(out []byte) ([]byte, error) {
+func (p *ListDocumentVersionsArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *ListDocumentArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.ListDocumentReq)
+func (p *ListDocumentVersionsArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListDocumentVersionsReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1502,38 +1775,38 @@ func (p *ListDocumentArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var ListDocumentArgs_Req_DEFAULT *rag_svr.ListDocumentReq
+var ListDocumentVersionsArgs_Req_DEFAULT *rag_svr.ListDocumentVersionsReq
 
-func (p *ListDocumentArgs) GetReq() *rag_svr.ListDocumentReq {
+func (p *ListDocumentVersionsArgs) GetReq() *rag_svr.ListDocumentVersionsReq {
 	if !p.IsSetReq() {
-		return ListDocumentArgs_Req_DEFAULT
+		return ListDocumentVersionsArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *ListDocumentArgs) IsSetReq() bool {
+func (p *ListDocumentVersionsArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *ListDocumentArgs) GetFirstArgument() interface{} {
+func (p *ListDocumentVersionsArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type ListDocumentResult struct {
-	Success *rag_svr.ListDocumentRsp
+type ListDocumentVersionsResult struct {
+	Success *rag_svr.ListDocumentVersionsRsp
 }
 
-var ListDocumentResult_Success_DEFAULT *rag_svr.ListDocumentRsp
+var ListDocumentVersionsResult_Success_DEFAULT *rag_svr.ListDocumentVersionsRsp
 
-func (p *ListDocumentResult) Marshal(out []byte) ([]byte, error) {
+func (p *ListDocumentVersionsResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *ListDocumentResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.ListDocumentRsp)
+func (p *ListDocumentVersionsResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListDocumentVersionsRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1541,63 +1814,2061 @@ func (p *ListDocumentResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *ListDocumentResult) GetSuccess() *rag_svr.ListDocumentRsp {
+func (p *ListDocumentVersionsResult) GetSuccess() *rag_svr.ListDocumentVersionsRsp {
 	if !p.IsSetSuccess() {
-		return ListDocumentResult_Success_DEFAULT
+		return ListDocumentVersionsResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *ListDocumentResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.ListDocumentRsp)
+func (p *ListDocumentVersionsResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.ListDocumentVersionsRsp)
 }
 
-func (p *ListDocumentResult) IsSetSuccess() bool {
+func (p *ListDocumentVersionsResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *ListDocumentResult) GetResult() interface{} {
+func (p *ListDocumentVersionsResult) GetResult() interface{} {
 	return p.Success
 }
 
-func addMemoryHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func restoreDocumentVersionHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.AddMemoryReq)
+		req := new(rag_svr.RestoreDocumentVersionReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).AddMemory(ctx, req)
+		resp, err := handler.(rag_svr.RagService).RestoreDocumentVersion(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *AddMemoryArgs:
-		success, err := handler.(rag_svr.RagService).AddMemory(ctx, s.Req)
+	case *RestoreDocumentVersionArgs:
+		success, err := handler.(rag_svr.RagService).RestoreDocumentVersion(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*AddMemoryResult)
+		realResult := result.(*RestoreDocumentVersionResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newAddMemoryArgs() interface{} {
-	return &AddMemoryArgs{}
+func newRestoreDocumentVersionArgs() interface{} {
+	return &RestoreDocumentVersionArgs{}
 }
 
-func newAddMemoryResult() interface{} {
-	return &AddMemoryResult{}
+func newRestoreDocumentVersionResult() interface{} {
+	return &RestoreDocumentVersionResult{}
 }
 
-type AddMemoryArgs struct {
-	Req *rag_svr.AddMemoryReq
+type RestoreDocumentVersionArgs struct {
+	Req *rag_svr.RestoreDocumentVersionReq
 }
 
-func (p *AddMemoryArgs) Marshal(out []byte) ([]byte, error) {
+func (p *RestoreDocumentVersionArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *RestoreDocumentVersionArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.RestoreDocumentVersionReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var RestoreDocumentVersionArgs_Req_DEFAULT *rag_svr.RestoreDocumentVersionReq
+
+func (p *RestoreDocumentVersionArgs) GetReq() *rag_svr.RestoreDocumentVersionReq {
+	if !p.IsSetReq() {
+		return RestoreDocumentVersionArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *RestoreDocumentVersionArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *RestoreDocumentVersionArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type RestoreDocumentVersionResult struct {
+	Success *rag_svr.RestoreDocumentVersionRsp
+}
+
+var RestoreDocumentVersionResult_Success_DEFAULT *rag_svr.RestoreDocumentVersionRsp
+
+func (p *RestoreDocumentVersionResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *RestoreDocumentVersionResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.RestoreDocumentVersionRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *RestoreDocumentVersionResult) GetSuccess() *rag_svr.RestoreDocumentVersionRsp {
+	if !p.IsSetSuccess() {
+		return RestoreDocumentVersionResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *RestoreDocumentVersionResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.RestoreDocumentVersionRsp)
+}
+
+func (p *RestoreDocumentVersionResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *RestoreDocumentVersionResult) GetResult() interface{} {
+	return p.Success
+}
+
+func getDocumentStatusHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.GetDocumentStatusReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).GetDocumentStatus(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *GetDocumentStatusArgs:
+		success, err := handler.(rag_svr.RagService).GetDocumentStatus(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*GetDocumentStatusResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newGetDocumentStatusArgs() interface{} {
+	return &GetDocumentStatusArgs{}
+}
+
+func newGetDocumentStatusResult() interface{} {
+	return &GetDocumentStatusResult{}
+}
+
+type GetDocumentStatusArgs struct {
+	Req *rag_svr.GetDocumentStatusReq
+}
+
+func (p *GetDocumentStatusArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *GetDocumentStatusArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetDocumentStatusReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var GetDocumentStatusArgs_Req_DEFAULT *rag_svr.GetDocumentStatusReq
+
+func (p *GetDocumentStatusArgs) GetReq() *rag_svr.GetDocumentStatusReq {
+	if !p.IsSetReq() {
+		return GetDocumentStatusArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *GetDocumentStatusArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *GetDocumentStatusArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type GetDocumentStatusResult struct {
+	Success *rag_svr.GetDocumentStatusRsp
+}
+
+var GetDocumentStatusResult_Success_DEFAULT *rag_svr.GetDocumentStatusRsp
+
+func (p *GetDocumentStatusResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *GetDocumentStatusResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetDocumentStatusRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *GetDocumentStatusResult) GetSuccess() *rag_svr.GetDocumentStatusRsp {
+	if !p.IsSetSuccess() {
+		return GetDocumentStatusResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *GetDocumentStatusResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.GetDocumentStatusRsp)
+}
+
+func (p *GetDocumentStatusResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GetDocumentStatusResult) GetResult() interface{} {
+	return p.Success
+}
+
+func deleteDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.DeleteDocumentReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).DeleteDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *DeleteDocumentArgs:
+		success, err := handler.(rag_svr.RagService).DeleteDocument(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*DeleteDocumentResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newDeleteDocumentArgs() interface{} {
+	return &DeleteDocumentArgs{}
+}
+
+func newDeleteDocumentResult() interface{} {
+	return &DeleteDocumentResult{}
+}
+
+type DeleteDocumentArgs struct {
+	Req *rag_svr.DeleteDocumentReq
+}
+
+func (p *DeleteDocumentArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *DeleteDocumentArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.DeleteDocumentReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var DeleteDocumentArgs_Req_DEFAULT *rag_svr.DeleteDocumentReq
+
+func (p *DeleteDocumentArgs) GetReq() *rag_svr.DeleteDocumentReq {
+	if !p.IsSetReq() {
+		return DeleteDocumentArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *DeleteDocumentArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *DeleteDocumentArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type DeleteDocumentResult struct {
+	Success *rag_svr.DeleteDocumentRsp
+}
+
+var DeleteDocumentResult_Success_DEFAULT *rag_svr.DeleteDocumentRsp
+
+func (p *DeleteDocumentResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *DeleteDocumentResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.DeleteDocumentRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *DeleteDocumentResult) GetSuccess() *rag_svr.DeleteDocumentRsp {
+	if !p.IsSetSuccess() {
+		return DeleteDocumentResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *DeleteDocumentResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.DeleteDocumentRsp)
+}
+
+func (p *DeleteDocumentResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *DeleteDocumentResult) GetResult() interface{} {
+	return p.Success
+}
+
+func searchDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.SearchDocumentReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).SearchDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *SearchDocumentArgs:
+		success, err := handler.(rag_svr.RagService).SearchDocument(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*SearchDocumentResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newSearchDocumentArgs() interface{} {
+	return &SearchDocumentArgs{}
+}
+
+func newSearchDocumentResult() interface{} {
+	return &SearchDocumentResult{}
+}
+
+type SearchDocumentArgs struct {
+	Req *rag_svr.SearchDocumentReq
+}
+
+func (p *SearchDocumentArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *SearchDocumentArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.SearchDocumentReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var SearchDocumentArgs_Req_DEFAULT *rag_svr.SearchDocumentReq
+
+func (p *SearchDocumentArgs) GetReq() *rag_svr.SearchDocumentReq {
+	if !p.IsSetReq() {
+		return SearchDocumentArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *SearchDocumentArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *SearchDocumentArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type SearchDocumentResult struct {
+	Success *rag_svr.SearchDocumentRsp
+}
+
+var SearchDocumentResult_Success_DEFAULT *rag_svr.SearchDocumentRsp
+
+func (p *SearchDocumentResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *SearchDocumentResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.SearchDocumentRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *SearchDocumentResult) GetSuccess() *rag_svr.SearchDocumentRsp {
+	if !p.IsSetSuccess() {
+		return SearchDocumentResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *SearchDocumentResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.SearchDocumentRsp)
+}
+
+func (p *SearchDocumentResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *SearchDocumentResult) GetResult() interface{} {
+	return p.Success
+}
+
+func listDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.ListDocumentReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).ListDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *ListDocumentArgs:
+		success, err := handler.(rag_svr.RagService).ListDocument(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*ListDocumentResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newListDocumentArgs() interface{} {
+	return &ListDocumentArgs{}
+}
+
+func newListDocumentResult() interface{} {
+	return &ListDocumentResult{}
+}
+
+type ListDocumentArgs struct {
+	Req *rag_svr.ListDocumentReq
+}
+
+func (p *ListDocumentArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *ListDocumentArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListDocumentReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var ListDocumentArgs_Req_DEFAULT *rag_svr.ListDocumentReq
+
+func (p *ListDocumentArgs) GetReq() *rag_svr.ListDocumentReq {
+	if !p.IsSetReq() {
+		return ListDocumentArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *ListDocumentArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *ListDocumentArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type ListDocumentResult struct {
+	Success *rag_svr.ListDocumentRsp
+}
+
+var ListDocumentResult_Success_DEFAULT *rag_svr.ListDocumentRsp
+
+func (p *ListDocumentResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *ListDocumentResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListDocumentRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *ListDocumentResult) GetSuccess() *rag_svr.ListDocumentRsp {
+	if !p.IsSetSuccess() {
+		return ListDocumentResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *ListDocumentResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.ListDocumentRsp)
+}
+
+func (p *ListDocumentResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *ListDocumentResult) GetResult() interface{} {
+	return p.Success
+}
+
+func moveDocumentHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.MoveDocumentReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).MoveDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *MoveDocumentArgs:
+		success, err := handler.(rag_svr.RagService).MoveDocument(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*MoveDocumentResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newMoveDocumentArgs() interface{} {
+	return &MoveDocumentArgs{}
+}
+
+func newMoveDocumentResult() interface{} {
+	return &MoveDocumentResult{}
+}
+
+type MoveDocumentArgs struct {
+	Req *rag_svr.MoveDocumentReq
+}
+
+func (p *MoveDocumentArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *MoveDocumentArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.MoveDocumentReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var MoveDocumentArgs_Req_DEFAULT *rag_svr.MoveDocumentReq
+
+func (p *MoveDocumentArgs) GetReq() *rag_svr.MoveDocumentReq {
+	if !p.IsSetReq() {
+		return MoveDocumentArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *MoveDocumentArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *MoveDocumentArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type MoveDocumentResult struct {
+	Success *rag_svr.MoveDocumentRsp
+}
+
+var MoveDocumentResult_Success_DEFAULT *rag_svr.MoveDocumentRsp
+
+func (p *MoveDocumentResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *MoveDocumentResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.MoveDocumentRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *MoveDocumentResult) GetSuccess() *rag_svr.MoveDocumentRsp {
+	if !p.IsSetSuccess() {
+		return MoveDocumentResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *MoveDocumentResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.MoveDocumentRsp)
+}
+
+func (p *MoveDocumentResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *MoveDocumentResult) GetResult() interface{} {
+	return p.Success
+}
+
+func setDocumentTagsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.SetDocumentTagsReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).SetDocumentTags(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *SetDocumentTagsArgs:
+		success, err := handler.(rag_svr.RagService).SetDocumentTags(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*SetDocumentTagsResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newSetDocumentTagsArgs() interface{} {
+	return &SetDocumentTagsArgs{}
+}
+
+func newSetDocumentTagsResult() interface{} {
+	return &SetDocumentTagsResult{}
+}
+
+type SetDocumentTagsArgs struct {
+	Req *rag_svr.SetDocumentTagsReq
+}
+
+func (p *SetDocumentTagsArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *SetDocumentTagsArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.SetDocumentTagsReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var SetDocumentTagsArgs_Req_DEFAULT *rag_svr.SetDocumentTagsReq
+
+func (p *SetDocumentTagsArgs) GetReq() *rag_svr.SetDocumentTagsReq {
+	if !p.IsSetReq() {
+		return SetDocumentTagsArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *SetDocumentTagsArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *SetDocumentTagsArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type SetDocumentTagsResult struct {
+	Success *rag_svr.SetDocumentTagsRsp
+}
+
+var SetDocumentTagsResult_Success_DEFAULT *rag_svr.SetDocumentTagsRsp
+
+func (p *SetDocumentTagsResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *SetDocumentTagsResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.SetDocumentTagsRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *SetDocumentTagsResult) GetSuccess() *rag_svr.SetDocumentTagsRsp {
+	if !p.IsSetSuccess() {
+		return SetDocumentTagsResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *SetDocumentTagsResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.SetDocumentTagsRsp)
+}
+
+func (p *SetDocumentTagsResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *SetDocumentTagsResult) GetResult() interface{} {
+	return p.Success
+}
+
+func createCollectionHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.CreateCollectionReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).CreateCollection(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *CreateCollectionArgs:
+		success, err := handler.(rag_svr.RagService).CreateCollection(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*CreateCollectionResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newCreateCollectionArgs() interface{} {
+	return &CreateCollectionArgs{}
+}
+
+func newCreateCollectionResult() interface{} {
+	return &CreateCollectionResult{}
+}
+
+type CreateCollectionArgs struct {
+	Req *rag_svr.CreateCollectionReq
+}
+
+func (p *CreateCollectionArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *CreateCollectionArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.CreateCollectionReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var CreateCollectionArgs_Req_DEFAULT *rag_svr.CreateCollectionReq
+
+func (p *CreateCollectionArgs) GetReq() *rag_svr.CreateCollectionReq {
+	if !p.IsSetReq() {
+		return CreateCollectionArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *CreateCollectionArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *CreateCollectionArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type CreateCollectionResult struct {
+	Success *rag_svr.CreateCollectionRsp
+}
+
+var CreateCollectionResult_Success_DEFAULT *rag_svr.CreateCollectionRsp
+
+func (p *CreateCollectionResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *CreateCollectionResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.CreateCollectionRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *CreateCollectionResult) GetSuccess() *rag_svr.CreateCollectionRsp {
+	if !p.IsSetSuccess() {
+		return CreateCollectionResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *CreateCollectionResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.CreateCollectionRsp)
+}
+
+func (p *CreateCollectionResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *CreateCollectionResult) GetResult() interface{} {
+	return p.Success
+}
+
+func listCollectionsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.ListCollectionsReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).ListCollections(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *ListCollectionsArgs:
+		success, err := handler.(rag_svr.RagService).ListCollections(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*ListCollectionsResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newListCollectionsArgs() interface{} {
+	return &ListCollectionsArgs{}
+}
+
+func newListCollectionsResult() interface{} {
+	return &ListCollectionsResult{}
+}
+
+type ListCollectionsArgs struct {
+	Req *rag_svr.ListCollectionsReq
+}
+
+func (p *ListCollectionsArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *ListCollectionsArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListCollectionsReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var ListCollectionsArgs_Req_DEFAULT *rag_svr.ListCollectionsReq
+
+func (p *ListCollectionsArgs) GetReq() *rag_svr.ListCollectionsReq {
+	if !p.IsSetReq() {
+		return ListCollectionsArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *ListCollectionsArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *ListCollectionsArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type ListCollectionsResult struct {
+	Success *rag_svr.ListCollectionsRsp
+}
+
+var ListCollectionsResult_Success_DEFAULT *rag_svr.ListCollectionsRsp
+
+func (p *ListCollectionsResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *ListCollectionsResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListCollectionsRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *ListCollectionsResult) GetSuccess() *rag_svr.ListCollectionsRsp {
+	if !p.IsSetSuccess() {
+		return ListCollectionsResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *ListCollectionsResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.ListCollectionsRsp)
+}
+
+func (p *ListCollectionsResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *ListCollectionsResult) GetResult() interface{} {
+	return p.Success
+}
+
+func updateCollectionHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.UpdateCollectionReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).UpdateCollection(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *UpdateCollectionArgs:
+		success, err := handler.(rag_svr.RagService).UpdateCollection(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*UpdateCollectionResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newUpdateCollectionArgs() interface{} {
+	return &UpdateCollectionArgs{}
+}
+
+func newUpdateCollectionResult() interface{} {
+	return &UpdateCollectionResult{}
+}
+
+type UpdateCollectionArgs struct {
+	Req *rag_svr.UpdateCollectionReq
+}
+
+func (p *UpdateCollectionArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *UpdateCollectionArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.UpdateCollectionReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var UpdateCollectionArgs_Req_DEFAULT *rag_svr.UpdateCollectionReq
+
+func (p *UpdateCollectionArgs) GetReq() *rag_svr.UpdateCollectionReq {
+	if !p.IsSetReq() {
+		return UpdateCollectionArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *UpdateCollectionArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *UpdateCollectionArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type UpdateCollectionResult struct {
+	Success *rag_svr.UpdateCollectionRsp
+}
+
+var UpdateCollectionResult_Success_DEFAULT *rag_svr.UpdateCollectionRsp
+
+func (p *UpdateCollectionResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *UpdateCollectionResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.UpdateCollectionRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *UpdateCollectionResult) GetSuccess() *rag_svr.UpdateCollectionRsp {
+	if !p.IsSetSuccess() {
+		return UpdateCollectionResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *UpdateCollectionResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.UpdateCollectionRsp)
+}
+
+func (p *UpdateCollectionResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *UpdateCollectionResult) GetResult() interface{} {
+	return p.Success
+}
+
+func deleteCollectionHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.DeleteCollectionReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).DeleteCollection(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *DeleteCollectionArgs:
+		success, err := handler.(rag_svr.RagService).DeleteCollection(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*DeleteCollectionResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newDeleteCollectionArgs() interface{} {
+	return &DeleteCollectionArgs{}
+}
+
+func newDeleteCollectionResult() interface{} {
+	return &DeleteCollectionResult{}
+}
+
+type DeleteCollectionArgs struct {
+	Req *rag_svr.DeleteCollectionReq
+}
+
+func (p *DeleteCollectionArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *DeleteCollectionArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.DeleteCollectionReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var DeleteCollectionArgs_Req_DEFAULT *rag_svr.DeleteCollectionReq
+
+func (p *DeleteCollectionArgs) GetReq() *rag_svr.DeleteCollectionReq {
+	if !p.IsSetReq() {
+		return DeleteCollectionArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *DeleteCollectionArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *DeleteCollectionArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type DeleteCollectionResult struct {
+	Success *rag_svr.DeleteCollectionRsp
+}
+
+var DeleteCollectionResult_Success_DEFAULT *rag_svr.DeleteCollectionRsp
+
+func (p *DeleteCollectionResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *DeleteCollectionResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.DeleteCollectionRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *DeleteCollectionResult) GetSuccess() *rag_svr.DeleteCollectionRsp {
+	if !p.IsSetSuccess() {
+		return DeleteCollectionResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *DeleteCollectionResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.DeleteCollectionRsp)
+}
+
+func (p *DeleteCollectionResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *DeleteCollectionResult) GetResult() interface{} {
+	return p.Success
+}
+
+func grantAccessHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.GrantAccessReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).GrantAccess(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *GrantAccessArgs:
+		success, err := handler.(rag_svr.RagService).GrantAccess(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*GrantAccessResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newGrantAccessArgs() interface{} {
+	return &GrantAccessArgs{}
+}
+
+func newGrantAccessResult() interface{} {
+	return &GrantAccessResult{}
+}
+
+type GrantAccessArgs struct {
+	Req *rag_svr.GrantAccessReq
+}
+
+func (p *GrantAccessArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *GrantAccessArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GrantAccessReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var GrantAccessArgs_Req_DEFAULT *rag_svr.GrantAccessReq
+
+func (p *GrantAccessArgs) GetReq() *rag_svr.GrantAccessReq {
+	if !p.IsSetReq() {
+		return GrantAccessArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *GrantAccessArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *GrantAccessArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type GrantAccessResult struct {
+	Success *rag_svr.GrantAccessRsp
+}
+
+var GrantAccessResult_Success_DEFAULT *rag_svr.GrantAccessRsp
+
+func (p *GrantAccessResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *GrantAccessResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GrantAccessRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *GrantAccessResult) GetSuccess() *rag_svr.GrantAccessRsp {
+	if !p.IsSetSuccess() {
+		return GrantAccessResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *GrantAccessResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.GrantAccessRsp)
+}
+
+func (p *GrantAccessResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GrantAccessResult) GetResult() interface{} {
+	return p.Success
+}
+
+func revokeAccessHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.RevokeAccessReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).RevokeAccess(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *RevokeAccessArgs:
+		success, err := handler.(rag_svr.RagService).RevokeAccess(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*RevokeAccessResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newRevokeAccessArgs() interface{} {
+	return &RevokeAccessArgs{}
+}
+
+func newRevokeAccessResult() interface{} {
+	return &RevokeAccessResult{}
+}
+
+type RevokeAccessArgs struct {
+	Req *rag_svr.RevokeAccessReq
+}
+
+func (p *RevokeAccessArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *RevokeAccessArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.RevokeAccessReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var RevokeAccessArgs_Req_DEFAULT *rag_svr.RevokeAccessReq
+
+func (p *RevokeAccessArgs) GetReq() *rag_svr.RevokeAccessReq {
+	if !p.IsSetReq() {
+		return RevokeAccessArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *RevokeAccessArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *RevokeAccessArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type RevokeAccessResult struct {
+	Success *rag_svr.RevokeAccessRsp
+}
+
+var RevokeAccessResult_Success_DEFAULT *rag_svr.RevokeAccessRsp
+
+func (p *RevokeAccessResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *RevokeAccessResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.RevokeAccessRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *RevokeAccessResult) GetSuccess() *rag_svr.RevokeAccessRsp {
+	if !p.IsSetSuccess() {
+		return RevokeAccessResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *RevokeAccessResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.RevokeAccessRsp)
+}
+
+func (p *RevokeAccessResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *RevokeAccessResult) GetResult() interface{} {
+	return p.Success
+}
+
+func listAccessHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.ListAccessReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).ListAccess(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *ListAccessArgs:
+		success, err := handler.(rag_svr.RagService).ListAccess(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*ListAccessResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newListAccessArgs() interface{} {
+	return &ListAccessArgs{}
+}
+
+func newListAccessResult() interface{} {
+	return &ListAccessResult{}
+}
+
+type ListAccessArgs struct {
+	Req *rag_svr.ListAccessReq
+}
+
+func (p *ListAccessArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *ListAccessArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListAccessReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var ListAccessArgs_Req_DEFAULT *rag_svr.ListAccessReq
+
+func (p *ListAccessArgs) GetReq() *rag_svr.ListAccessReq {
+	if !p.IsSetReq() {
+		return ListAccessArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *ListAccessArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *ListAccessArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type ListAccessResult struct {
+	Success *rag_svr.ListAccessRsp
+}
+
+var ListAccessResult_Success_DEFAULT *rag_svr.ListAccessRsp
+
+func (p *ListAccessResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *ListAccessResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListAccessRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *ListAccessResult) GetSuccess() *rag_svr.ListAccessRsp {
+	if !p.IsSetSuccess() {
+		return ListAccessResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *ListAccessResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.ListAccessRsp)
+}
+
+func (p *ListAccessResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *ListAccessResult) GetResult() interface{} {
+	return p.Success
+}
+
+func createGroupHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.CreateGroupReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).CreateGroup(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *CreateGroupArgs:
+		success, err := handler.(rag_svr.RagService).CreateGroup(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*CreateGroupResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newCreateGroupArgs() interface{} {
+	return &CreateGroupArgs{}
+}
+
+func newCreateGroupResult() interface{} {
+	return &CreateGroupResult{}
+}
+
+type CreateGroupArgs struct {
+	Req *rag_svr.CreateGroupReq
+}
+
+func (p *CreateGroupArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *CreateGroupArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.CreateGroupReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var CreateGroupArgs_Req_DEFAULT *rag_svr.CreateGroupReq
+
+func (p *CreateGroupArgs) GetReq() *rag_svr.CreateGroupReq {
+	if !p.IsSetReq() {
+		return CreateGroupArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *CreateGroupArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *CreateGroupArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type CreateGroupResult struct {
+	Success *rag_svr.CreateGroupRsp
+}
+
+var CreateGroupResult_Success_DEFAULT *rag_svr.CreateGroupRsp
+
+func (p *CreateGroupResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *CreateGroupResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.CreateGroupRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *CreateGroupResult) GetSuccess() *rag_svr.CreateGroupRsp {
+	if !p.IsSetSuccess() {
+		return CreateGroupResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *CreateGroupResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.CreateGroupRsp)
+}
+
+func (p *CreateGroupResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *CreateGroupResult) GetResult() interface{} {
+	return p.Success
+}
+
+func addGroupMembersHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.AddGroupMembersReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).AddGroupMembers(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *AddGroupMembersArgs:
+		success, err := handler.(rag_svr.RagService).AddGroupMembers(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*AddGroupMembersResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newAddGroupMembersArgs() interface{} {
+	return &AddGroupMembersArgs{}
+}
+
+func newAddGroupMembersResult() interface{} {
+	return &AddGroupMembersResult{}
+}
+
+type AddGroupMembersArgs struct {
+	Req *rag_svr.AddGroupMembersReq
+}
+
+func (p *AddGroupMembersArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *AddGroupMembersArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddGroupMembersReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var AddGroupMembersArgs_Req_DEFAULT *rag_svr.AddGroupMembersReq
+
+func (p *AddGroupMembersArgs) GetReq() *rag_svr.AddGroupMembersReq {
+	if !p.IsSetReq() {
+		return AddGroupMembersArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *AddGroupMembersArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *AddGroupMembersArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type AddGroupMembersResult struct {
+	Success *rag_svr.AddGroupMembersRsp
+}
+
+var AddGroupMembersResult_Success_DEFAULT *rag_svr.AddGroupMembersRsp
+
+func (p *AddGroupMembersResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *AddGroupMembersResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddGroupMembersRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *AddGroupMembersResult) GetSuccess() *rag_svr.AddGroupMembersRsp {
+	if !p.IsSetSuccess() {
+		return AddGroupMembersResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *AddGroupMembersResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.AddGroupMembersRsp)
+}
+
+func (p *AddGroupMembersResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *AddGroupMembersResult) GetResult() interface{} {
+	return p.Success
+}
+
+func removeGroupMembersHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.RemoveGroupMembersReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).RemoveGroupMembers(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *RemoveGroupMembersArgs:
+		success, err := handler.(rag_svr.RagService).RemoveGroupMembers(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*RemoveGroupMembersResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newRemoveGroupMembersArgs() interface{} {
+	return &RemoveGroupMembersArgs{}
+}
+
+func newRemoveGroupMembersResult() interface{} {
+	return &RemoveGroupMembersResult{}
+}
+
+type RemoveGroupMembersArgs struct {
+	Req *rag_svr.RemoveGroupMembersReq
+}
+
+func (p *RemoveGroupMembersArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *RemoveGroupMembersArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.RemoveGroupMembersReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var RemoveGroupMembersArgs_Req_DEFAULT *rag_svr.RemoveGroupMembersReq
+
+func (p *RemoveGroupMembersArgs) GetReq() *rag_svr.RemoveGroupMembersReq {
+	if !p.IsSetReq() {
+		return RemoveGroupMembersArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *RemoveGroupMembersArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *RemoveGroupMembersArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type RemoveGroupMembersResult struct {
+	Success *rag_svr.RemoveGroupMembersRsp
+}
+
+var RemoveGroupMembersResult_Success_DEFAULT *rag_svr.RemoveGroupMembersRsp
+
+func (p *RemoveGroupMembersResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *RemoveGroupMembersResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.RemoveGroupMembersRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *RemoveGroupMembersResult) GetSuccess() *rag_svr.RemoveGroupMembersRsp {
+	if !p.IsSetSuccess() {
+		return RemoveGroupMembersResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *RemoveGroupMembersResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.RemoveGroupMembersRsp)
+}
+
+func (p *RemoveGroupMembersResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *RemoveGroupMembersResult) GetResult() interface{} {
+	return p.Success
+}
+
+func listGroupsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.ListGroupsReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).ListGroups(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *ListGroupsArgs:
+		success, err := handler.(rag_svr.RagService).ListGroups(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*ListGroupsResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newListGroupsArgs() interface{} {
+	return &ListGroupsArgs{}
+}
+
+func newListGroupsResult() interface{} {
+	return &ListGroupsResult{}
+}
+
+type ListGroupsArgs struct {
+	Req *rag_svr.ListGroupsReq
+}
+
+func (p *ListGroupsArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *ListGroupsArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListGroupsReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var ListGroupsArgs_Req_DEFAULT *rag_svr.ListGroupsReq
+
+func (p *ListGroupsArgs) GetReq() *rag_svr.ListGroupsReq {
+	if !p.IsSetReq() {
+		return ListGroupsArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *ListGroupsArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *ListGroupsArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type ListGroupsResult struct {
+	Success *rag_svr.ListGroupsRsp
+}
+
+var ListGroupsResult_Success_DEFAULT *rag_svr.ListGroupsRsp
+
+func (p *ListGroupsResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *ListGroupsResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ListGroupsRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *ListGroupsResult) GetSuccess() *rag_svr.ListGroupsRsp {
+	if !p.IsSetSuccess() {
+		return ListGroupsResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *ListGroupsResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.ListGroupsRsp)
+}
+
+func (p *ListGroupsResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *ListGroupsResult) GetResult() interface{} {
+	return p.Success
+}
+
+func addMemoryHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.AddMemoryReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).AddMemory(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *AddMemoryArgs:
+		success, err := handler.(rag_svr.RagService).AddMemory(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*AddMemoryResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newAddMemoryArgs() interface{} {
+	return &AddMemoryArgs{}
+}
+
+func newAddMemoryResult() interface{} {
+	return &AddMemoryResult{}
+}
+
+type AddMemoryArgs struct {
+	Req *rag_svr.AddMemoryReq
+}
+
+func (p *AddMemoryArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
@@ -1613,38 +3884,371 @@ func (p *AddMemoryArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var AddMemoryArgs_Req_DEFAULT *rag_svr.AddMemoryReq
+var AddMemoryArgs_Req_DEFAULT *rag_svr.AddMemoryReq
+
+func (p *AddMemoryArgs) GetReq() *rag_svr.AddMemoryReq {
+	if !p.IsSetReq() {
+		return AddMemoryArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *AddMemoryArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *AddMemoryArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type AddMemoryResult struct {
+	Success *rag_svr.AddMemoryRsp
+}
+
+var AddMemoryResult_Success_DEFAULT *rag_svr.AddMemoryRsp
+
+func (p *AddMemoryResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *AddMemoryResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddMemoryRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *AddMemoryResult) GetSuccess() *rag_svr.AddMemoryRsp {
+	if !p.IsSetSuccess() {
+		return AddMemoryResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *AddMemoryResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.AddMemoryRsp)
+}
+
+func (p *AddMemoryResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *AddMemoryResult) GetResult() interface{} {
+	return p.Success
+}
+
+func getMemoryHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.GetMemoryReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).GetMemory(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *GetMemoryArgs:
+		success, err := handler.(rag_svr.RagService).GetMemory(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*GetMemoryResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newGetMemoryArgs() interface{} {
+	return &GetMemoryArgs{}
+}
+
+func newGetMemoryResult() interface{} {
+	return &GetMemoryResult{}
+}
+
+type GetMemoryArgs struct {
+	Req *rag_svr.GetMemoryReq
+}
+
+func (p *GetMemoryArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *GetMemoryArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetMemoryReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var GetMemoryArgs_Req_DEFAULT *rag_svr.GetMemoryReq
+
+func (p *GetMemoryArgs) GetReq() *rag_svr.GetMemoryReq {
+	if !p.IsSetReq() {
+		return GetMemoryArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *GetMemoryArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *GetMemoryArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type GetMemoryResult struct {
+	Success *rag_svr.GetMemoryRsp
+}
+
+var GetMemoryResult_Success_DEFAULT *rag_svr.GetMemoryRsp
+
+func (p *GetMemoryResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *GetMemoryResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetMemoryRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *GetMemoryResult) GetSuccess() *rag_svr.GetMemoryRsp {
+	if !p.IsSetSuccess() {
+		return GetMemoryResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *GetMemoryResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.GetMemoryRsp)
+}
+
+func (p *GetMemoryResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GetMemoryResult) GetResult() interface{} {
+	return p.Success
+}
+
+func searchMemoriesHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.SearchMemoriesReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).SearchMemories(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *SearchMemoriesArgs:
+		success, err := handler.(rag_svr.RagService).SearchMemories(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*SearchMemoriesResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newSearchMemoriesArgs() interface{} {
+	return &SearchMemoriesArgs{}
+}
+
+func newSearchMemoriesResult() interface{} {
+	return &SearchMemoriesResult{}
+}
+
+type SearchMemoriesArgs struct {
+	Req *rag_svr.SearchMemoriesReq
+}
+
+func (p *SearchMemoriesArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *SearchMemoriesArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.SearchMemoriesReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var SearchMemoriesArgs_Req_DEFAULT *rag_svr.SearchMemoriesReq
+
+func (p *SearchMemoriesArgs) GetReq() *rag_svr.SearchMemoriesReq {
+	if !p.IsSetReq() {
+		return SearchMemoriesArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *SearchMemoriesArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *SearchMemoriesArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type SearchMemoriesResult struct {
+	Success *rag_svr.SearchMemoriesRsp
+}
+
+var SearchMemoriesResult_Success_DEFAULT *rag_svr.SearchMemoriesRsp
+
+func (p *SearchMemoriesResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *SearchMemoriesResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.SearchMemoriesRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *SearchMemoriesResult) GetSuccess() *rag_svr.SearchMemoriesRsp {
+	if !p.IsSetSuccess() {
+		return SearchMemoriesResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *SearchMemoriesResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.SearchMemoriesRsp)
+}
+
+func (p *SearchMemoriesResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *SearchMemoriesResult) GetResult() interface{} {
+	return p.Success
+}
+
+func deleteMemoryHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.DeleteMemoryReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).DeleteMemory(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *DeleteMemoryArgs:
+		success, err := handler.(rag_svr.RagService).DeleteMemory(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*DeleteMemoryResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newDeleteMemoryArgs() interface{} {
+	return &DeleteMemoryArgs{}
+}
+
+func newDeleteMemoryResult() interface{} {
+	return &DeleteMemoryResult{}
+}
+
+type DeleteMemoryArgs struct {
+	Req *rag_svr.DeleteMemoryReq
+}
+
+func (p *DeleteMemoryArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *DeleteMemoryArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.DeleteMemoryReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var DeleteMemoryArgs_Req_DEFAULT *rag_svr.DeleteMemoryReq
 
-func (p *AddMemoryArgs) GetReq() *rag_svr.AddMemoryReq {
+func (p *DeleteMemoryArgs) GetReq() *rag_svr.DeleteMemoryReq {
 	if !p.IsSetReq() {
-		return AddMemoryArgs_Req_DEFAULT
+		return DeleteMemoryArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *AddMemoryArgs) IsSetReq() bool {
+func (p *DeleteMemoryArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *AddMemoryArgs) GetFirstArgument() interface{} {
+func (p *DeleteMemoryArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type AddMemoryResult struct {
-	Success *rag_svr.AddMemoryRsp
+type DeleteMemoryResult struct {
+	Success *rag_svr.DeleteMemoryRsp
 }
 
-var AddMemoryResult_Success_DEFAULT *rag_svr.AddMemoryRsp
+var DeleteMemoryResult_Success_DEFAULT *rag_svr.DeleteMemoryRsp
 
-func (p *AddMemoryResult) Marshal(out []byte) ([]byte, error) {
+func (p *DeleteMemoryResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *AddMemoryResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.AddMemoryRsp)
+func (p *DeleteMemoryResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.DeleteMemoryRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1652,71 +4256,71 @@ func (p *AddMemoryResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *AddMemoryResult) GetSuccess() *rag_svr.AddMemoryRsp {
+func (p *DeleteMemoryResult) GetSuccess() *rag_svr.DeleteMemoryRsp {
 	if !p.IsSetSuccess() {
-		return AddMemoryResult_Success_DEFAULT
+		return DeleteMemoryResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *AddMemoryResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.AddMemoryRsp)
+func (p *DeleteMemoryResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.DeleteMemoryRsp)
 }
 
-func (p *AddMemoryResult) IsSetSuccess() bool {
+func (p *DeleteMemoryResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *AddMemoryResult) GetResult() interface{} {
+func (p *DeleteMemoryResult) GetResult() interface{} {
 	return p.Success
 }
 
-func getMemoryHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func addChatRecordHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.GetMemoryReq)
+		req := new(rag_svr.AddChatRecordReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).GetMemory(ctx, req)
+		resp, err := handler.(rag_svr.RagService).AddChatRecord(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *GetMemoryArgs:
-		success, err := handler.(rag_svr.RagService).GetMemory(ctx, s.Req)
+	case *AddChatRecordArgs:
+		success, err := handler.(rag_svr.RagService).AddChatRecord(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*GetMemoryResult)
+		realResult := result.(*AddChatRecordResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newGetMemoryArgs() interface{} {
-	return &GetMemoryArgs{}
+func newAddChatRecordArgs() interface{} {
+	return &AddChatRecordArgs{}
 }
 
-func newGetMemoryResult() interface{} {
-	return &GetMemoryResult{}
+func newAddChatRecordResult() interface{} {
+	return &AddChatRecordResult{}
 }
 
-type GetMemoryArgs struct {
-	Req *rag_svr.GetMemoryReq
+type AddChatRecordArgs struct {
+	Req *rag_svr.AddChatRecordReq
 }
 
-func (p *GetMemoryArgs) Marshal(out []byte) ([]byte, error) {
+func (p *AddChatRecordArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *GetMemoryArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.GetMemoryReq)
+func (p *AddChatRecordArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddChatRecordReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1724,38 +4328,38 @@ func (p *GetMemoryArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var GetMemoryArgs_Req_DEFAULT *rag_svr.GetMemoryReq
+var AddChatRecordArgs_Req_DEFAULT *rag_svr.AddChatRecordReq
 
-func (p *GetMemoryArgs) GetReq() *rag_svr.GetMemoryReq {
+func (p *AddChatRecordArgs) GetReq() *rag_svr.AddChatRecordReq {
 	if !p.IsSetReq() {
-		return GetMemoryArgs_Req_DEFAULT
+		return AddChatRecordArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *GetMemoryArgs) IsSetReq() bool {
+func (p *AddChatRecordArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *GetMemoryArgs) GetFirstArgument() interface{} {
+func (p *AddChatRecordArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type GetMemoryResult struct {
-	Success *rag_svr.GetMemoryRsp
+type AddChatRecordResult struct {
+	Success *rag_svr.AddChatRecordRsp
 }
 
-var GetMemoryResult_Success_DEFAULT *rag_svr.GetMemoryRsp
+var AddChatRecordResult_Success_DEFAULT *rag_svr.AddChatRecordRsp
 
-func (p *GetMemoryResult) Marshal(out []byte) ([]byte, error) {
+func (p *AddChatRecordResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *GetMemoryResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.GetMemoryRsp)
+func (p *AddChatRecordResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.AddChatRecordRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1763,71 +4367,71 @@ func (p *GetMemoryResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *GetMemoryResult) GetSuccess() *rag_svr.GetMemoryRsp {
+func (p *AddChatRecordResult) GetSuccess() *rag_svr.AddChatRecordRsp {
 	if !p.IsSetSuccess() {
-		return GetMemoryResult_Success_DEFAULT
+		return AddChatRecordResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *GetMemoryResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.GetMemoryRsp)
+func (p *AddChatRecordResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.AddChatRecordRsp)
 }
 
-func (p *GetMemoryResult) IsSetSuccess() bool {
+func (p *AddChatRecordResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *GetMemoryResult) GetResult() interface{} {
+func (p *AddChatRecordResult) GetResult() interface{} {
 	return p.Success
 }
 
-func searchMemoriesHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func getChatRecordsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.SearchMemoriesReq)
+		req := new(rag_svr.GetChatRecordsReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).SearchMemories(ctx, req)
+		resp, err := handler.(rag_svr.RagService).GetChatRecords(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *SearchMemoriesArgs:
-		success, err := handler.(rag_svr.RagService).SearchMemories(ctx, s.Req)
+	case *GetChatRecordsArgs:
+		success, err := handler.(rag_svr.RagService).GetChatRecords(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*SearchMemoriesResult)
+		realResult := result.(*GetChatRecordsResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newSearchMemoriesArgs() interface{} {
-	return &SearchMemoriesArgs{}
+func newGetChatRecordsArgs() interface{} {
+	return &GetChatRecordsArgs{}
 }
 
-func newSearchMemoriesResult() interface{} {
-	return &SearchMemoriesResult{}
+func newGetChatRecordsResult() interface{} {
+	return &GetChatRecordsResult{}
 }
 
-type SearchMemoriesArgs struct {
-	Req *rag_svr.SearchMemoriesReq
+type GetChatRecordsArgs struct {
+	Req *rag_svr.GetChatRecordsReq
 }
 
-func (p *SearchMemoriesArgs) Marshal(out []byte) ([]byte, error) {
+func (p *GetChatRecordsArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *SearchMemoriesArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.SearchMemoriesReq)
+func (p *GetChatRecordsArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetChatRecordsReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1835,38 +4439,38 @@ func (p *SearchMemoriesArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var SearchMemoriesArgs_Req_DEFAULT *rag_svr.SearchMemoriesReq
+var GetChatRecordsArgs_Req_DEFAULT *rag_svr.GetChatRecordsReq
 
-func (p *SearchMemoriesArgs) GetReq() *rag_svr.SearchMemoriesReq {
+func (p *GetChatRecordsArgs) GetReq() *rag_svr.GetChatRecordsReq {
 	if !p.IsSetReq() {
-		return SearchMemoriesArgs_Req_DEFAULT
+		return GetChatRecordsArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *SearchMemoriesArgs) IsSetReq() bool {
+func (p *GetChatRecordsArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *SearchMemoriesArgs) GetFirstArgument() interface{} {
+func (p *GetChatRecordsArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type SearchMemoriesResult struct {
-	Success *rag_svr.SearchMemoriesRsp
+type GetChatRecordsResult struct {
+	Success *rag_svr.GetChatRecordsRsp
 }
 
-var SearchMemoriesResult_Success_DEFAULT *rag_svr.SearchMemoriesRsp
+var GetChatRecordsResult_Success_DEFAULT *rag_svr.GetChatRecordsRsp
 
-func (p *SearchMemoriesResult) Marshal(out []byte) ([]byte, error) {
+func (p *GetChatRecordsResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *SearchMemoriesResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.SearchMemoriesRsp)
+func (p *GetChatRecordsResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetChatRecordsRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1874,71 +4478,195 @@ func (p *SearchMemoriesResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *SearchMemoriesResult) GetSuccess() *rag_svr.SearchMemoriesRsp {
+func (p *GetChatRecordsResult) GetSuccess() *rag_svr.GetChatRecordsRsp {
 	if !p.IsSetSuccess() {
-		return SearchMemoriesResult_Success_DEFAULT
+		return GetChatRecordsResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *SearchMemoriesResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.SearchMemoriesRsp)
+func (p *GetChatRecordsResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.GetChatRecordsRsp)
 }
 
-func (p *SearchMemoriesResult) IsSetSuccess() bool {
+func (p *GetChatRecordsResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GetChatRecordsResult) GetResult() interface{} {
+	return p.Success
+}
+
+func chatHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(rag_svr.ChatReq)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(rag_svr.RagService).Chat(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *ChatArgs:
+		success, err := handler.(rag_svr.RagService).Chat(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*ChatResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newChatArgs() interface{} {
+	return &ChatArgs{}
+}
+
+func newChatResult() interface{} {
+	return &ChatResult{}
+}
+
+type ChatArgs struct {
+	Req *rag_svr.ChatReq
+}
+
+func (p *ChatArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *ChatArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ChatReq)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var ChatArgs_Req_DEFAULT *rag_svr.ChatReq
+
+func (p *ChatArgs) GetReq() *rag_svr.ChatReq {
+	if !p.IsSetReq() {
+		return ChatArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *ChatArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *ChatArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type ChatResult struct {
+	Success *rag_svr.ChatRsp
+}
+
+var ChatResult_Success_DEFAULT *rag_svr.ChatRsp
+
+func (p *ChatResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *ChatResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ChatRsp)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *ChatResult) GetSuccess() *rag_svr.ChatRsp {
+	if !p.IsSetSuccess() {
+		return ChatResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *ChatResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.ChatRsp)
+}
+
+func (p *ChatResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *SearchMemoriesResult) GetResult() interface{} {
-	return p.Success
+func (p *ChatResult) GetResult() interface{} {
+	return p.Success
+}
+
+func streamChatHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	streamingArgs, ok := arg.(*streaming.Args)
+	if !ok {
+		return errInvalidMessageType
+	}
+	st := streamingArgs.Stream
+	stream := &ragServiceStreamChatServer{st}
+	req := new(rag_svr.ChatReq)
+	if err := st.RecvMsg(req); err != nil {
+		return err
+	}
+	return handler.(rag_svr.RagService).StreamChat(req, stream)
+}
+
+type ragServiceStreamChatClient struct {
+	streaming.Stream
+}
+
+func (x *ragServiceStreamChatClient) DoFinish(err error) {
+	if finisher, ok := x.Stream.(streaming.WithDoFinish); ok {
+		finisher.DoFinish(err)
+	} else {
+		panic(fmt.Sprintf("streaming.WithDoFinish is not implemented by %T", x.Stream))
+	}
+}
+func (x *ragServiceStreamChatClient) Recv() (*rag_svr.StreamChatRsp, error) {
+	m := new(rag_svr.StreamChatRsp)
+	return m, x.Stream.RecvMsg(m)
+}
+
+type ragServiceStreamChatServer struct {
+	streaming.Stream
 }
 
-func deleteMemoryHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
-	switch s := arg.(type) {
-	case *streaming.Args:
-		st := s.Stream
-		req := new(rag_svr.DeleteMemoryReq)
-		if err := st.RecvMsg(req); err != nil {
-			return err
-		}
-		resp, err := handler.(rag_svr.RagService).DeleteMemory(ctx, req)
-		if err != nil {
-			return err
-		}
-		return st.SendMsg(resp)
-	case *DeleteMemoryArgs:
-		success, err := handler.(rag_svr.RagService).DeleteMemory(ctx, s.Req)
-		if err != nil {
-			return err
-		}
-		realResult := result.(*DeleteMemoryResult)
-		realResult.Success = success
-		return nil
-	default:
-		return errInvalidMessageType
-	}
+func (x *ragServiceStreamChatServer) Send(m *rag_svr.StreamChatRsp) error {
+	return x.Stream.SendMsg(m)
 }
-func newDeleteMemoryArgs() interface{} {
-	return &DeleteMemoryArgs{}
+
+func newStreamChatArgs() interface{} {
+	return &StreamChatArgs{}
 }
 
-func newDeleteMemoryResult() interface{} {
-	return &DeleteMemoryResult{}
+func newStreamChatResult() interface{} {
+	return &StreamChatResult{}
 }
 
-type DeleteMemoryArgs struct {
-	Req *rag_svr.DeleteMemoryReq
+type StreamChatArgs struct {
+	Req *rag_svr.ChatReq
 }
 
-func (p *DeleteMemoryArgs) Marshal(out []byte) ([]byte, error) {
+func (p *StreamChatArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *DeleteMemoryArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.DeleteMemoryReq)
+func (p *StreamChatArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.ChatReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1946,38 +4674,38 @@ func (p *DeleteMemoryArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var DeleteMemoryArgs_Req_DEFAULT *rag_svr.DeleteMemoryReq
+var StreamChatArgs_Req_DEFAULT *rag_svr.ChatReq
 
-func (p *DeleteMemoryArgs) GetReq() *rag_svr.DeleteMemoryReq {
+func (p *StreamChatArgs) GetReq() *rag_svr.ChatReq {
 	if !p.IsSetReq() {
-		return DeleteMemoryArgs_Req_DEFAULT
+		return StreamChatArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *DeleteMemoryArgs) IsSetReq() bool {
+func (p *StreamChatArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *DeleteMemoryArgs) GetFirstArgument() interface{} {
+func (p *StreamChatArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type DeleteMemoryResult struct {
-	Success *rag_svr.DeleteMemoryRsp
+type StreamChatResult struct {
+	Success *rag_svr.StreamChatRsp
 }
 
-var DeleteMemoryResult_Success_DEFAULT *rag_svr.DeleteMemoryRsp
+var StreamChatResult_Success_DEFAULT *rag_svr.StreamChatRsp
 
-func (p *DeleteMemoryResult) Marshal(out []byte) ([]byte, error) {
+func (p *StreamChatResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *DeleteMemoryResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.DeleteMemoryRsp)
+func (p *StreamChatResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.StreamChatRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -1985,71 +4713,71 @@ func (p *DeleteMemoryResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *DeleteMemoryResult) GetSuccess() *rag_svr.DeleteMemoryRsp {
+func (p *StreamChatResult) GetSuccess() *rag_svr.StreamChatRsp {
 	if !p.IsSetSuccess() {
-		return DeleteMemoryResult_Success_DEFAULT
+		return StreamChatResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *DeleteMemoryResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.DeleteMemoryRsp)
+func (p *StreamChatResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.StreamChatRsp)
 }
 
-func (p *DeleteMemoryResult) IsSetSuccess() bool {
+func (p *StreamChatResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *DeleteMemoryResult) GetResult() interface{} {
+func (p *StreamChatResult) GetResult() interface{} {
 	return p.Success
 }
 
-func addChatRecordHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func getUsageHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.AddChatRecordReq)
+		req := new(rag_svr.GetUsageReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).AddChatRecord(ctx, req)
+		resp, err := handler.(rag_svr.RagService).GetUsage(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *AddChatRecordArgs:
-		success, err := handler.(rag_svr.RagService).AddChatRecord(ctx, s.Req)
+	case *GetUsageArgs:
+		success, err := handler.(rag_svr.RagService).GetUsage(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*AddChatRecordResult)
+		realResult := result.(*GetUsageResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newAddChatRecordArgs() interface{} {
-	return &AddChatRecordArgs{}
+func newGetUsageArgs() interface{} {
+	return &GetUsageArgs{}
 }
 
-func newAddChatRecordResult() interface{} {
-	return &AddChatRecordResult{}
+func newGetUsageResult() interface{} {
+	return &GetUsageResult{}
 }
 
-type AddChatRecordArgs struct {
-	Req *rag_svr.AddChatRecordReq
+type GetUsageArgs struct {
+	Req *rag_svr.GetUsageReq
 }
 
-func (p *AddChatRecordArgs) Marshal(out []byte) ([]byte, error) {
+func (p *GetUsageArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *AddChatRecordArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.AddChatRecordReq)
+func (p *GetUsageArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetUsageReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -2057,38 +4785,38 @@ func (p *AddChatRecordArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var AddChatRecordArgs_Req_DEFAULT *rag_svr.AddChatRecordReq
+var GetUsageArgs_Req_DEFAULT *rag_svr.GetUsageReq
 
-func (p *AddChatRecordArgs) GetReq() *rag_svr.AddChatRecordReq {
+func (p *GetUsageArgs) GetReq() *rag_svr.GetUsageReq {
 	if !p.IsSetReq() {
-		return AddChatRecordArgs_Req_DEFAULT
+		return GetUsageArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *AddChatRecordArgs) IsSetReq() bool {
+func (p *GetUsageArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *AddChatRecordArgs) GetFirstArgument() interface{} {
+func (p *GetUsageArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type AddChatRecordResult struct {
-	Success *rag_svr.AddChatRecordRsp
+type GetUsageResult struct {
+	Success *rag_svr.GetUsageRsp
 }
 
-var AddChatRecordResult_Success_DEFAULT *rag_svr.AddChatRecordRsp
+var GetUsageResult_Success_DEFAULT *rag_svr.GetUsageRsp
 
-func (p *AddChatRecordResult) Marshal(out []byte) ([]byte, error) {
+func (p *GetUsageResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *AddChatRecordResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.AddChatRecordRsp)
+func (p *GetUsageResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetUsageRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -2096,71 +4824,71 @@ func (p *AddChatRecordResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *AddChatRecordResult) GetSuccess() *rag_svr.AddChatRecordRsp {
+func (p *GetUsageResult) GetSuccess() *rag_svr.GetUsageRsp {
 	if !p.IsSetSuccess() {
-		return AddChatRecordResult_Success_DEFAULT
+		return GetUsageResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *AddChatRecordResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.AddChatRecordRsp)
+func (p *GetUsageResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.GetUsageRsp)
 }
 
-func (p *AddChatRecordResult) IsSetSuccess() bool {
+func (p *GetUsageResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *AddChatRecordResult) GetResult() interface{} {
+func (p *GetUsageResult) GetResult() interface{} {
 	return p.Success
 }
 
-func getChatRecordsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+func getModelStatusHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
 	switch s := arg.(type) {
 	case *streaming.Args:
 		st := s.Stream
-		req := new(rag_svr.GetChatRecordsReq)
+		req := new(rag_svr.GetModelStatusReq)
 		if err := st.RecvMsg(req); err != nil {
 			return err
 		}
-		resp, err := handler.(rag_svr.RagService).GetChatRecords(ctx, req)
+		resp, err := handler.(rag_svr.RagService).GetModelStatus(ctx, req)
 		if err != nil {
 			return err
 		}
 		return st.SendMsg(resp)
-	case *GetChatRecordsArgs:
-		success, err := handler.(rag_svr.RagService).GetChatRecords(ctx, s.Req)
+	case *GetModelStatusArgs:
+		success, err := handler.(rag_svr.RagService).GetModelStatus(ctx, s.Req)
 		if err != nil {
 			return err
 		}
-		realResult := result.(*GetChatRecordsResult)
+		realResult := result.(*GetModelStatusResult)
 		realResult.Success = success
 		return nil
 	default:
 		return errInvalidMessageType
 	}
 }
-func newGetChatRecordsArgs() interface{} {
-	return &GetChatRecordsArgs{}
+func newGetModelStatusArgs() interface{} {
+	return &GetModelStatusArgs{}
 }
 
-func newGetChatRecordsResult() interface{} {
-	return &GetChatRecordsResult{}
+func newGetModelStatusResult() interface{} {
+	return &GetModelStatusResult{}
 }
 
-type GetChatRecordsArgs struct {
-	Req *rag_svr.GetChatRecordsReq
+type GetModelStatusArgs struct {
+	Req *rag_svr.GetModelStatusReq
 }
 
-func (p *GetChatRecordsArgs) Marshal(out []byte) ([]byte, error) {
+func (p *GetModelStatusArgs) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetReq() {
 		return out, nil
 	}
 	return proto.Marshal(p.Req)
 }
 
-func (p *GetChatRecordsArgs) Unmarshal(in []byte) error {
-	msg := new(rag_svr.GetChatRecordsReq)
+func (p *GetModelStatusArgs) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetModelStatusReq)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -2168,38 +4896,38 @@ func (p *GetChatRecordsArgs) Unmarshal(in []byte) error {
 	return nil
 }
 
-var GetChatRecordsArgs_Req_DEFAULT *rag_svr.GetChatRecordsReq
+var GetModelStatusArgs_Req_DEFAULT *rag_svr.GetModelStatusReq
 
-func (p *GetChatRecordsArgs) GetReq() *rag_svr.GetChatRecordsReq {
+func (p *GetModelStatusArgs) GetReq() *rag_svr.GetModelStatusReq {
 	if !p.IsSetReq() {
-		return GetChatRecordsArgs_Req_DEFAULT
+		return GetModelStatusArgs_Req_DEFAULT
 	}
 	return p.Req
 }
 
-func (p *GetChatRecordsArgs) IsSetReq() bool {
+func (p *GetModelStatusArgs) IsSetReq() bool {
 	return p.Req != nil
 }
 
-func (p *GetChatRecordsArgs) GetFirstArgument() interface{} {
+func (p *GetModelStatusArgs) GetFirstArgument() interface{} {
 	return p.Req
 }
 
-type GetChatRecordsResult struct {
-	Success *rag_svr.GetChatRecordsRsp
+type GetModelStatusResult struct {
+	Success *rag_svr.GetModelStatusRsp
 }
 
-var GetChatRecordsResult_Success_DEFAULT *rag_svr.GetChatRecordsRsp
+var GetModelStatusResult_Success_DEFAULT *rag_svr.GetModelStatusRsp
 
-func (p *GetChatRecordsResult) Marshal(out []byte) ([]byte, error) {
+func (p *GetModelStatusResult) Marshal(out []byte) ([]byte, error) {
 	if !p.IsSetSuccess() {
 		return out, nil
 	}
 	return proto.Marshal(p.Success)
 }
 
-func (p *GetChatRecordsResult) Unmarshal(in []byte) error {
-	msg := new(rag_svr.GetChatRecordsRsp)
+func (p *GetModelStatusResult) Unmarshal(in []byte) error {
+	msg := new(rag_svr.GetModelStatusRsp)
 	if err := proto.Unmarshal(in, msg); err != nil {
 		return err
 	}
@@ -2207,22 +4935,22 @@ func (p *GetChatRecordsResult) Unmarshal(in []byte) error {
 	return nil
 }
 
-func (p *GetChatRecordsResult) GetSuccess() *rag_svr.GetChatRecordsRsp {
+func (p *GetModelStatusResult) GetSuccess() *rag_svr.GetModelStatusRsp {
 	if !p.IsSetSuccess() {
-		return GetChatRecordsResult_Success_DEFAULT
+		return GetModelStatusResult_Success_DEFAULT
 	}
 	return p.Success
 }
 
-func (p *GetChatRecordsResult) SetSuccess(x interface{}) {
-	p.Success = x.(*rag_svr.GetChatRecordsRsp)
+func (p *GetModelStatusResult) SetSuccess(x interface{}) {
+	p.Success = x.(*rag_svr.GetModelStatusRsp)
 }
 
-func (p *GetChatRecordsResult) IsSetSuccess() bool {
+func (p *GetModelStatusResult) IsSetSuccess() bool {
 	return p.Success != nil
 }
 
-func (p *GetChatRecordsResult) GetResult() interface{} {
+func (p *GetModelStatusResult) GetResult() interface{} {
 	return p.Success
 }
 
@@ -2629,6 +5357,16 @@ func (p *kClient) EndSession(ctx context.Context, Req *rag_svr.EndSessionReq) (r
 	return _result.GetSuccess(), nil
 }
 
+func (p *kClient) UpdateSession(ctx context.Context, Req *rag_svr.UpdateSessionReq) (r *rag_svr.UpdateSessionRsp, err error) {
+	var _args UpdateSessionArgs
+	_args.Req = Req
+	var _result UpdateSessionResult
+	if err = p.c.Call(ctx, "UpdateSession", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
 func (p *kClient) GetSessionList(ctx context.Context, Req *rag_svr.GetSessionListReq) (r *rag_svr.GetSessionListRsp, err error) {
 	var _args GetSessionListArgs
 	_args.Req = Req
@@ -2659,6 +5397,56 @@ func (p *kClient) AddDocument(ctx context.Context, Req *rag_svr.AddDocumentReq)
 	return _result.GetSuccess(), nil
 }
 
+func (p *kClient) AddDocumentFile(ctx context.Context, Req *rag_svr.AddDocumentFileReq) (r *rag_svr.AddDocumentFileRsp, err error) {
+	var _args AddDocumentFileArgs
+	_args.Req = Req
+	var _result AddDocumentFileResult
+	if err = p.c.Call(ctx, "AddDocumentFile", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) UpdateDocument(ctx context.Context, Req *rag_svr.UpdateDocumentReq) (r *rag_svr.UpdateDocumentRsp, err error) {
+	var _args UpdateDocumentArgs
+	_args.Req = Req
+	var _result UpdateDocumentResult
+	if err = p.c.Call(ctx, "UpdateDocument", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) ListDocumentVersions(ctx context.Context, Req *rag_svr.ListDocumentVersionsReq) (r *rag_svr.ListDocumentVersionsRsp, err error) {
+	var _args ListDocumentVersionsArgs
+	_args.Req = Req
+	var _result ListDocumentVersionsResult
+	if err = p.c.Call(ctx, "ListDocumentVersions", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) RestoreDocumentVersion(ctx context.Context, Req *rag_svr.RestoreDocumentVersionReq) (r *rag_svr.RestoreDocumentVersionRsp, err error) {
+	var _args RestoreDocumentVersionArgs
+	_args.Req = Req
+	var _result RestoreDocumentVersionResult
+	if err = p.c.Call(ctx, "RestoreDocumentVersion", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) GetDocumentStatus(ctx context.Context, Req *rag_svr.GetDocumentStatusReq) (r *rag_svr.GetDocumentStatusRsp, err error) {
+	var _args GetDocumentStatusArgs
+	_args.Req = Req
+	var _result GetDocumentStatusResult
+	if err = p.c.Call(ctx, "GetDocumentStatus", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
 func (p *kClient) DeleteDocument(ctx context.Context, Req *rag_svr.DeleteDocumentReq) (r *rag_svr.DeleteDocumentRsp, err error) {
 	var _args DeleteDocumentArgs
 	_args.Req = Req
@@ -2689,6 +5477,136 @@ func (p *kClient) ListDocument(ctx context.Context, Req *rag_svr.ListDocumentReq
 	return _result.GetSuccess(), nil
 }
 
+func (p *kClient) MoveDocument(ctx context.Context, Req *rag_svr.MoveDocumentReq) (r *rag_svr.MoveDocumentRsp, err error) {
+	var _args MoveDocumentArgs
+	_args.Req = Req
+	var _result MoveDocumentResult
+	if err = p.c.Call(ctx, "MoveDocument", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) SetDocumentTags(ctx context.Context, Req *rag_svr.SetDocumentTagsReq) (r *rag_svr.SetDocumentTagsRsp, err error) {
+	var _args SetDocumentTagsArgs
+	_args.Req = Req
+	var _result SetDocumentTagsResult
+	if err = p.c.Call(ctx, "SetDocumentTags", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) CreateCollection(ctx context.Context, Req *rag_svr.CreateCollectionReq) (r *rag_svr.CreateCollectionRsp, err error) {
+	var _args CreateCollectionArgs
+	_args.Req = Req
+	var _result CreateCollectionResult
+	if err = p.c.Call(ctx, "CreateCollection", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) ListCollections(ctx context.Context, Req *rag_svr.ListCollectionsReq) (r *rag_svr.ListCollectionsRsp, err error) {
+	var _args ListCollectionsArgs
+	_args.Req = Req
+	var _result ListCollectionsResult
+	if err = p.c.Call(ctx, "ListCollections", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) UpdateCollection(ctx context.Context, Req *rag_svr.UpdateCollectionReq) (r *rag_svr.UpdateCollectionRsp, err error) {
+	var _args UpdateCollectionArgs
+	_args.Req = Req
+	var _result UpdateCollectionResult
+	if err = p.c.Call(ctx, "UpdateCollection", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) DeleteCollection(ctx context.Context, Req *rag_svr.DeleteCollectionReq) (r *rag_svr.DeleteCollectionRsp, err error) {
+	var _args DeleteCollectionArgs
+	_args.Req = Req
+	var _result DeleteCollectionResult
+	if err = p.c.Call(ctx, "DeleteCollection", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) GrantAccess(ctx context.Context, Req *rag_svr.GrantAccessReq) (r *rag_svr.GrantAccessRsp, err error) {
+	var _args GrantAccessArgs
+	_args.Req = Req
+	var _result GrantAccessResult
+	if err = p.c.Call(ctx, "GrantAccess", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) RevokeAccess(ctx context.Context, Req *rag_svr.RevokeAccessReq) (r *rag_svr.RevokeAccessRsp, err error) {
+	var _args RevokeAccessArgs
+	_args.Req = Req
+	var _result RevokeAccessResult
+	if err = p.c.Call(ctx, "RevokeAccess", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) ListAccess(ctx context.Context, Req *rag_svr.ListAccessReq) (r *rag_svr.ListAccessRsp, err error) {
+	var _args ListAccessArgs
+	_args.Req = Req
+	var _result ListAccessResult
+	if err = p.c.Call(ctx, "ListAccess", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) CreateGroup(ctx context.Context, Req *rag_svr.CreateGroupReq) (r *rag_svr.CreateGroupRsp, err error) {
+	var _args CreateGroupArgs
+	_args.Req = Req
+	var _result CreateGroupResult
+	if err = p.c.Call(ctx, "CreateGroup", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) AddGroupMembers(ctx context.Context, Req *rag_svr.AddGroupMembersReq) (r *rag_svr.AddGroupMembersRsp, err error) {
+	var _args AddGroupMembersArgs
+	_args.Req = Req
+	var _result AddGroupMembersResult
+	if err = p.c.Call(ctx, "AddGroupMembers", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) RemoveGroupMembers(ctx context.Context, Req *rag_svr.RemoveGroupMembersReq) (r *rag_svr.RemoveGroupMembersRsp, err error) {
+	var _args RemoveGroupMembersArgs
+	_args.Req = Req
+	var _result RemoveGroupMembersResult
+	if err = p.c.Call(ctx, "RemoveGroupMembers", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) ListGroups(ctx context.Context, Req *rag_svr.ListGroupsReq) (r *rag_svr.ListGroupsRsp, err error) {
+	var _args ListGroupsArgs
+	_args.Req = Req
+	var _result ListGroupsResult
+	if err = p.c.Call(ctx, "ListGroups", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
 func (p *kClient) AddMemory(ctx context.Context, Req *rag_svr.AddMemoryReq) (r *rag_svr.AddMemoryRsp, err error) {
 	var _args AddMemoryArgs
 	_args.Req = Req
@@ -2749,6 +5667,57 @@ func (p *kClient) GetChatRecords(ctx context.Context, Req *rag_svr.GetChatRecord
 	return _result.GetSuccess(), nil
 }
 
+func (p *kClient) Chat(ctx context.Context, Req *rag_svr.ChatReq) (r *rag_svr.ChatRsp, err error) {
+	var _args ChatArgs
+	_args.Req = Req
+	var _result ChatResult
+	if err = p.c.Call(ctx, "Chat", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) StreamChat(ctx context.Context, req *rag_svr.ChatReq) (RagService_StreamChatClient, error) {
+	streamClient, ok := p.c.(client.Streaming)
+	if !ok {
+		return nil, fmt.Errorf("client not support streaming")
+	}
+	res := new(streaming.Result)
+	err := streamClient.Stream(ctx, "StreamChat", nil, res)
+	if err != nil {
+		return nil, err
+	}
+	stream := &ragServiceStreamChatClient{res.Stream}
+
+	if err := stream.Stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.Stream.Close(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (p *kClient) GetUsage(ctx context.Context, Req *rag_svr.GetUsageReq) (r *rag_svr.GetUsageRsp, err error) {
+	var _args GetUsageArgs
+	_args.Req = Req
+	var _result GetUsageResult
+	if err = p.c.Call(ctx, "GetUsage", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) GetModelStatus(ctx context.Context, Req *rag_svr.GetModelStatusReq) (r *rag_svr.GetModelStatusRsp, err error) {
+	var _args GetModelStatusArgs
+	_args.Req = Req
+	var _result GetModelStatusResult
+	if err = p.c.Call(ctx, "GetModelStatus", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
 func (p *kClient) GetWeather(ctx context.Context, Req *rag_svr.GetWeatherReq) (r *rag_svr.GetWeatherRsp, err error) {
 	var _args GetWeatherArgs
 	_args.Req = Req
@@ -61,31 +61,22 @@ func main() {
 	}
 	logger.Infof("Milvus 初始化成功")
 
-	// 初始化 Qwen 客户端
+	// 初始化大模型提供方
 	chatModel := config.GlobalConfig.AI.ChatModel
-	qwenClient := ai.NewQwenClient(&struct {
-		APIKey           string
-		Provider         string
-		ModelName        string
-		Temperature      float64
-		MaxTokens        int
-		TopP             float64
-		FrequencyPenalty float64
-		PresencePenalty  float64
-	}{
-		APIKey:           chatModel.APIKey,
-		Provider:         chatModel.Provider,
-		ModelName:        chatModel.ModelName,
-		Temperature:      chatModel.Temperature,
-		MaxTokens:        chatModel.MaxTokens,
-		TopP:             chatModel.TopP,
-		FrequencyPenalty: chatModel.FrequencyPenalty,
-		PresencePenalty:  chatModel.PresencePenalty,
-	})
+	llm, err := ai.GetLLMProvider()
+	if err != nil {
+		logger.Errorf("初始化大模型提供方失败: %v", err)
+		os.Exit(1)
+	}
+	logger.Infof("大模型提供方初始化成功: provider=%s, model=%s", chatModel.Provider, llm.ModelName())
+
+	// 启动文档索引 worker
+	stopIndexWorkers := ai.StartIndexWorkers()
 
 	// 创建服务实例
 	svr := &RagServiceImpl{
-		qwenClient: qwenClient,
+		llm:           llm,
+		promptBuilder: ai.NewPromptBuilder(chatModel.ContextWindow, chatModel.MaxTokens),
 	}
 
 	// 创建并启动Kitex服务器
@@ -116,5 +107,6 @@ func main() {
 	if err := s.Stop(); err != nil {
 		logger.Fatalf("服务器关闭失败: %v", err)
 	}
+	stopIndexWorkers()
 	logger.Infof("服务器已关闭")
 }
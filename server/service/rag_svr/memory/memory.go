@@ -11,7 +11,6 @@ import (
 	"server/framework/logger"
 	"server/framework/milvus"
 	"server/framework/mysql"
-	"server/service/rag_svr/vector"
 )
 
 // 记忆类型
@@ -41,6 +40,29 @@ type Memory struct {
 	Similarity   float32                `json:"similarity"` // 相似度分数
 }
 
+// EmbedFunc 批量生成文本向量
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// embed 由 ai 包注册，记忆与文档共用向量化模型链、缓存和用量记录
+var embed EmbedFunc
+
+// SetEmbedder 注册生成记忆向量的函数
+func SetEmbedder(f EmbedFunc) {
+	embed = f
+}
+
+// getEmbedding 生成单条文本的向量
+func getEmbedding(ctx context.Context, text string) ([]float32, error) {
+	if embed == nil {
+		return nil, fmt.Errorf("向量化模型未注册")
+	}
+	vectors, err := embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
 // 记忆管理器
 type MemoryManager struct {
 	idGen *id_generator.IDGeneratorManager
@@ -61,7 +83,7 @@ func GetInstance() *MemoryManager {
 // AddMemory 添加记忆
 func (m *MemoryManager) AddMemory(ctx context.Context, sessionID, userID uint64, content string, memoryType string, importance float64, metadata map[string]interface{}, tags []string) error {
 	// 生成向量
-	vector, err := vector.GetEmbedding(content)
+	vector, err := getEmbedding(ctx, content)
 	if err != nil {
 		return fmt.Errorf("生成向量失败: %v", err)
 	}
@@ -108,13 +130,13 @@ func (m *MemoryManager) AddMemory(ctx context.Context, sessionID, userID uint64,
 // SearchMemories 搜索记忆
 func (m *MemoryManager) SearchMemories(ctx context.Context, query string, limit int) ([]*Memory, error) {
 	// 获取查询向量的 embedding
-	embedding, err := vector.GetEmbedding(query)
+	embedding, err := getEmbedding(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("获取查询向量失败: %v", err)
 	}
 
 	// 搜索向量
-	ids, scores, err := milvus.SearchVector(ctx, MemoryCollectionName, embedding, limit)
+	ids, scores, _, err := milvus.SearchVector(ctx, MemoryCollectionName, embedding, limit, "", nil)
 	if err != nil {
 		return nil, fmt.Errorf("搜索向量失败: %v", err)
 	}
@@ -150,26 +172,8 @@ func (m *MemoryManager) SearchMemories(ctx context.Context, query string, limit
 	// 转换为 Memory 结构
 	result := make([]*Memory, len(memories))
 	for i, memory := range memories {
-		// 解析 metadata
-		var metadata map[string]interface{}
-		if memory.Metadata != "" {
-			if err := json.Unmarshal([]byte(memory.Metadata), &metadata); err != nil {
-				return nil, fmt.Errorf("解析 metadata 失败: %v", err)
-			}
-		}
-
-		result[i] = &Memory{
-			ID:           memory.ID,
-			SessionID:    memory.SessionID,
-			UserID:       memory.UserID,
-			Content:      memory.Content,
-			Type:         memory.MemoryType,
-			Importance:   float64(memory.Importance),
-			CreatedAt:    memory.CreatedAt,
-			ExpiresAt:    memory.ExpireTime,
-			LastAccessed: memory.UpdatedAt,
-			AccessCount:  memory.AccessCount,
-			Metadata:     metadata,
+		if result[i], err = toMemory(memory); err != nil {
+			return nil, err
 		}
 	}
 
@@ -194,6 +198,43 @@ func (m *MemoryManager) SearchMemories(ctx context.Context, query string, limit
 	return result, nil
 }
 
+// GetMemory 按ID获取未过期的记忆，不存在时返回 nil
+func (m *MemoryManager) GetMemory(ctx context.Context, memoryID uint64) (*Memory, error) {
+	var memories []*mysql.ChatMemory
+	if err := mysql.GetDB().WithContext(ctx).Table("chat_memory").
+		Where("id = ? AND expire_time > ?", memoryID, time.Now()).
+		Limit(1).Find(&memories).Error; err != nil {
+		return nil, fmt.Errorf("获取记忆失败: %v", err)
+	}
+	if len(memories) == 0 {
+		return nil, nil
+	}
+	return toMemory(memories[0])
+}
+
+// toMemory 将数据库记录转换为 Memory 结构
+func toMemory(memory *mysql.ChatMemory) (*Memory, error) {
+	var metadata map[string]interface{}
+	if memory.Metadata != "" {
+		if err := json.Unmarshal([]byte(memory.Metadata), &metadata); err != nil {
+			return nil, fmt.Errorf("解析 metadata 失败: %v", err)
+		}
+	}
+	return &Memory{
+		ID:           memory.ID,
+		SessionID:    memory.SessionID,
+		UserID:       memory.UserID,
+		Content:      memory.Content,
+		Type:         memory.MemoryType,
+		Importance:   float64(memory.Importance),
+		CreatedAt:    memory.CreatedAt,
+		ExpiresAt:    memory.ExpireTime,
+		LastAccessed: memory.UpdatedAt,
+		AccessCount:  memory.AccessCount,
+		Metadata:     metadata,
+	}, nil
+}
+
 // CleanExpiredMemories 清理过期记忆
 func (m *MemoryManager) CleanExpiredMemories(ctx context.Context) error {
 	// 获取过期的记忆 ID
@@ -302,9 +343,21 @@ func (m *MemoryManager) BatchAddMemories(ctx context.Context, memories []*Memory
 		return nil
 	}
 
+	if embed == nil {
+		return fmt.Errorf("向量化模型未注册")
+	}
+	// 批量获取向量表示
+	texts := make([]string, len(memories))
+	for i, memory := range memories {
+		texts[i] = memory.Content
+	}
+	vectors, err := embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("获取向量表示失败: %v", err)
+	}
+
 	// 准备批量插入的数据
 	var memoryRecords []*mysql.ChatMemory
-	var vectors [][]float32
 	var ids []int64
 
 	for _, memory := range memories {
@@ -314,12 +367,6 @@ func (m *MemoryManager) BatchAddMemories(ctx context.Context, memories []*Memory
 			return fmt.Errorf("获取记忆ID失败")
 		}
 
-		// 获取向量表示
-		embedding, err := vector.GetEmbedding(memory.Content)
-		if err != nil {
-			return fmt.Errorf("获取向量表示失败: %v", err)
-		}
-
 		// 将 metadata 转换为 JSON 字符串
 		metadataJSON, err := json.Marshal(memory.Metadata)
 		if err != nil {
@@ -345,7 +392,6 @@ func (m *MemoryManager) BatchAddMemories(ctx context.Context, memories []*Memory
 		}
 
 		memoryRecords = append(memoryRecords, memoryRecord)
-		vectors = append(vectors, embedding)
 		ids = append(ids, int64(memoryID))
 	}
 
@@ -392,7 +438,7 @@ func (m *MemoryManager) BatchDeleteMemories(ctx context.Context, memoryIDs []uin
 // UpdateMemory 更新记忆
 func (m *MemoryManager) UpdateMemory(ctx context.Context, memoryID uint64, content string, importance float64, metadata map[string]interface{}) error {
 	// 获取向量表示
-	embedding, err := vector.GetEmbedding(content)
+	embedding, err := getEmbedding(ctx, content)
 	if err != nil {
 		return fmt.Errorf("获取向量表示失败: %v", err)
 	}
@@ -0,0 +1,56 @@
+// mockllm 独立运行的模拟大模型服务，本地开发时代替 DashScope
+//
+//	go run ./mockllm/cmd -addr :18080 -dimension 1024 -rules rules.json
+//
+// 然后将 chat_model.base_url 设为 http://localhost:18080/api/v1，
+// embedding_model.base_url 设为 http://localhost:18080/compatible-mode/v1，
+// reranker.base_url 设为 http://localhost:18080/api/v1（dashscope）或 http://localhost:18080/v1（jina），
+// DASHSCOPE_API_KEY 和 EMBEDDING_API_KEY 设为 -api-key 的值
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+
+	"server/framework/logger"
+	"server/service/rag_svr/mockllm"
+)
+
+func main() {
+	addr := flag.String("addr", ":18080", "监听地址")
+	apiKey := flag.String("api-key", "mock-api-key", "校验请求的 API Key")
+	dimension := flag.Int("dimension", 1024, "向量维度")
+	maxBatchSize := flag.Int("max-batch-size", 10, "向量化接口单次请求最多的文本数，0 表示不限制")
+	rulesFile := flag.String("rules", "", "回复规则文件，JSON 数组，字段见 mockllm.Rule")
+	flag.Parse()
+
+	srv := mockllm.NewUnstartedServer(mockllm.Options{
+		APIKey:       *apiKey,
+		Dimension:    *dimension,
+		MaxBatchSize: *maxBatchSize,
+	})
+	if *rulesFile != "" {
+		data, err := os.ReadFile(*rulesFile)
+		if err != nil {
+			logger.Errorf("读取规则文件失败: %v", err)
+			os.Exit(1)
+		}
+		var rules []mockllm.Rule
+		if err := json.Unmarshal(data, &rules); err != nil {
+			logger.Errorf("解析规则文件失败: %v", err)
+			os.Exit(1)
+		}
+		for _, rule := range rules {
+			srv.AddRule(rule)
+		}
+		logger.Infof("已加载%d条回复规则", len(rules))
+	}
+
+	logger.Infof("模拟大模型服务启动: addr=%s, dimension=%d", *addr, *dimension)
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		logger.Errorf("模拟大模型服务退出: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,154 @@
+package mockllm
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type embeddingsRequest struct {
+	Model      string          `json:"model"`
+	Input      json.RawMessage `json:"input"` // 字符串或字符串数组
+	Dimensions int             `json:"dimensions"`
+}
+
+type embeddingsResponse struct {
+	Object string          `json:"object"`
+	Data   []embeddingData `json:"data"`
+	Model  string          `json:"model"`
+	Usage  embeddingsUsage `json:"usage"`
+}
+
+type embeddingData struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+type embeddingsUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+func (s *Server) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
+	var req embeddingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", fmt.Sprintf("解析请求失败: %v", err))
+		return
+	}
+	var texts []string
+	if err := json.Unmarshal(req.Input, &texts); err != nil {
+		var text string
+		if err := json.Unmarshal(req.Input, &text); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidParameter", "input 必须是字符串或字符串数组")
+			return
+		}
+		texts = []string{text}
+	}
+	if len(texts) == 0 {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", "input 不能为空")
+		return
+	}
+	if s.opts.MaxBatchSize > 0 && len(texts) > s.opts.MaxBatchSize {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", fmt.Sprintf("batch size is invalid, it should not be larger than %d", s.opts.MaxBatchSize))
+		return
+	}
+
+	s.record(RecordedRequest{
+		Path:  r.URL.Path,
+		Model: req.Model,
+		Input: texts,
+	})
+
+	dimension := s.opts.Dimension
+	if req.Dimensions > 0 {
+		dimension = req.Dimensions
+	}
+	resp := embeddingsResponse{
+		Object: "list",
+		Data:   make([]embeddingData, 0, len(texts)),
+		Model:  req.Model,
+	}
+	for i, text := range texts {
+		resp.Data = append(resp.Data, embeddingData{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: HashEmbedding(text, dimension),
+		})
+		resp.Usage.PromptTokens += utf8.RuneCountInString(text)
+	}
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HashEmbedding 生成文本的确定性向量
+// 英文单词、单个汉字和相邻汉字二元组分别哈希到某一维并累加，最后归一化，
+// 共享词语越多的文本余弦相似度越高，可以用于检索排序的测试
+func HashEmbedding(text string, dimension int) []float32 {
+	vector := make([]float64, dimension)
+	for _, feature := range features(text) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		index := int(sum % uint64(dimension))
+		// 用另一位决定符号，减少哈希冲突带来的偏差
+		if sum>>63 == 0 {
+			vector[index]++
+		} else {
+			vector[index]--
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	result := make([]float32, dimension)
+	if norm == 0 {
+		// 空文本返回固定的单位向量
+		result[0] = 1
+		return result
+	}
+	norm = math.Sqrt(norm)
+	for i, v := range vector {
+		result[i] = float32(v / norm)
+	}
+	return result
+}
+
+// features 提取文本特征：小写英文单词和数字、单个汉字、相邻汉字二元组
+func features(text string) []string {
+	result := make([]string, 0)
+	var word strings.Builder
+	var prev rune
+	flush := func() {
+		if word.Len() > 0 {
+			result = append(result, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			result = append(result, string(r))
+			if prev != 0 {
+				result = append(result, string([]rune{prev, r}))
+			}
+			prev = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+		prev = 0
+	}
+	flush()
+	return result
+}
@@ -0,0 +1,247 @@
+package mockllm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message 对话消息
+type Message struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	Name       string         `json:"name,omitempty"`
+	ToolCalls  []toolCallWire `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type toolCallWire struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type generationRequest struct {
+	Model string `json:"model"`
+	Input struct {
+		Messages []Message `json:"messages"`
+	} `json:"input"`
+	Parameters struct {
+		ResultFormat      string `json:"result_format"`
+		Stream            bool   `json:"stream"`
+		IncrementalOutput bool   `json:"incremental_output"`
+		Tools             []struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		} `json:"tools"`
+	} `json:"parameters"`
+}
+
+type generationResponse struct {
+	Output    generationOutput `json:"output"`
+	Usage     generationUsage  `json:"usage"`
+	RequestID string           `json:"request_id"`
+}
+
+type generationOutput struct {
+	Text         string             `json:"text,omitempty"`
+	FinishReason string             `json:"finish_reason,omitempty"`
+	Choices      []generationChoice `json:"choices,omitempty"`
+}
+
+type generationChoice struct {
+	FinishReason string  `json:"finish_reason"`
+	Message      Message `json:"message"`
+}
+
+type generationUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// reply 一次生成的结果
+type reply struct {
+	content   string
+	toolCalls []toolCallWire
+	status    int
+}
+
+func (s *Server) handleGeneration(w http.ResponseWriter, r *http.Request) {
+	var req generationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", fmt.Sprintf("解析请求失败: %v", err))
+		return
+	}
+	if len(req.Input.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", "messages 不能为空")
+		return
+	}
+
+	tools := make([]string, 0, len(req.Parameters.Tools))
+	for _, tool := range req.Parameters.Tools {
+		tools = append(tools, tool.Function.Name)
+	}
+	// 流式请求通过 X-DashScope-SSE 头或 stream 参数开启
+	stream := req.Parameters.Stream || r.Header.Get("X-DashScope-SSE") == "enable"
+	requestID := s.record(RecordedRequest{
+		Path:     r.URL.Path,
+		Model:    req.Model,
+		Stream:   stream,
+		Messages: req.Input.Messages,
+		Tools:    tools,
+	})
+
+	rep := s.generate(req.Input.Messages, len(tools) > 0)
+	if rep.status != 0 {
+		writeError(w, rep.status, http.StatusText(rep.status), "mock failure")
+		return
+	}
+	usage := generationUsage{
+		InputTokens:  countTokens(req.Input.Messages),
+		OutputTokens: utf8.RuneCountInString(rep.content),
+	}
+	usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+
+	if stream {
+		s.streamGeneration(w, &req, rep, usage, requestID)
+		return
+	}
+
+	resp := generationResponse{Usage: usage, RequestID: requestID}
+	finishReason := "stop"
+	if len(rep.toolCalls) > 0 {
+		finishReason = "tool_calls"
+	}
+	if req.Parameters.ResultFormat == "message" {
+		resp.Output.Choices = []generationChoice{{
+			FinishReason: finishReason,
+			Message: Message{
+				Role:      "assistant",
+				Content:   rep.content,
+				ToolCalls: rep.toolCalls,
+			},
+		}}
+	} else {
+		resp.Output.Text = rep.content
+		resp.Output.FinishReason = finishReason
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// generate 按规则生成回复
+// 规则带工具调用、请求带工具且最后一条消息不是工具结果时返回工具调用，否则返回文本
+func (s *Server) generate(messages []Message, withTools bool) reply {
+	var system, user string
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			system += msg.Content + "\n"
+		case "user":
+			user = msg.Content
+		}
+	}
+
+	rule, ok := s.match(system, user)
+	if !ok {
+		content := s.opts.DefaultReply
+		if content == "" {
+			content = "mock: " + user
+		}
+		return reply{content: content}
+	}
+	if rule.Status != 0 {
+		return reply{status: rule.Status}
+	}
+
+	last := messages[len(messages)-1]
+	if len(rule.ToolCalls) > 0 && withTools && last.Role != "tool" {
+		calls := make([]toolCallWire, 0, len(rule.ToolCalls))
+		for i, call := range rule.ToolCalls {
+			var wire toolCallWire
+			wire.ID = fmt.Sprintf("call_%d", i+1)
+			wire.Type = "function"
+			wire.Function.Name = call.Name
+			wire.Function.Arguments = call.Arguments
+			calls = append(calls, wire)
+		}
+		return reply{toolCalls: calls}
+	}
+	return reply{content: rule.Content}
+}
+
+// streamGeneration 按 DashScope SSE 格式分片输出
+// incremental_output 为 true 时每个分片只包含增量文本，否则包含截至当前的全部文本
+func (s *Server) streamGeneration(w http.ResponseWriter, req *generationRequest, rep reply, usage generationUsage, requestID string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "InternalError", "不支持流式输出")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	runes := []rune(rep.content)
+	chunks := make([]string, 0, len(runes)/s.opts.ChunkSize+1)
+	for start := 0; start < len(runes); start += s.opts.ChunkSize {
+		chunks = append(chunks, string(runes[start:min(start+s.opts.ChunkSize, len(runes))]))
+	}
+	if len(chunks) == 0 {
+		chunks = append(chunks, "")
+	}
+
+	var sent strings.Builder
+	for i, chunk := range chunks {
+		sent.WriteString(chunk)
+		text := chunk
+		if !req.Parameters.IncrementalOutput {
+			text = sent.String()
+		}
+
+		finishReason := "null"
+		var toolCalls []toolCallWire
+		if i == len(chunks)-1 {
+			finishReason = "stop"
+			if len(rep.toolCalls) > 0 {
+				finishReason = "tool_calls"
+				toolCalls = rep.toolCalls
+			}
+		}
+
+		resp := generationResponse{RequestID: requestID}
+		// 中间分片的用量只统计输入，最后一个分片为完整用量
+		resp.Usage = generationUsage{InputTokens: usage.InputTokens}
+		if finishReason != "null" {
+			resp.Usage = usage
+		}
+		if req.Parameters.ResultFormat == "message" {
+			resp.Output.Choices = []generationChoice{{
+				FinishReason: finishReason,
+				Message:      Message{Role: "assistant", Content: text, ToolCalls: toolCalls},
+			}}
+		} else {
+			resp.Output.Text = text
+			resp.Output.FinishReason = finishReason
+		}
+
+		data, _ := json.Marshal(resp)
+		fmt.Fprintf(w, "id:%d\nevent:result\n:HTTP_STATUS/200\ndata:%s\n\n", i+1, data)
+		flusher.Flush()
+	}
+}
+
+// countTokens 按字符数估算输入 token，保证结果确定
+func countTokens(messages []Message) int {
+	tokens := 0
+	for _, msg := range messages {
+		tokens += utf8.RuneCountInString(msg.Content)
+	}
+	return tokens
+}
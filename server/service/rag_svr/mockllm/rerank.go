@@ -0,0 +1,93 @@
+package mockllm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"unicode/utf8"
+)
+
+// rerankRequest 同时兼容 DashScope（query 和 documents 在 input 中）和 Jina/Cohere（在顶层）的请求
+type rerankRequest struct {
+	Model string `json:"model"`
+	Input struct {
+		Query     string   `json:"query"`
+		Documents []string `json:"documents"`
+	} `json:"input"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float32 `json:"relevance_score"`
+}
+
+func (s *Server) handleRerank(w http.ResponseWriter, r *http.Request, dashScope bool) {
+	var req rerankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", fmt.Sprintf("解析请求失败: %v", err))
+		return
+	}
+	query, documents := req.Query, req.Documents
+	if dashScope {
+		query, documents = req.Input.Query, req.Input.Documents
+	}
+	if query == "" || len(documents) == 0 {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", "query 和 documents 不能为空")
+		return
+	}
+
+	requestID := s.record(RecordedRequest{
+		Path:  r.URL.Path,
+		Model: req.Model,
+		Input: append([]string{query}, documents...),
+	})
+
+	tokens := utf8.RuneCountInString(query)
+	results := make([]rerankResult, len(documents))
+	for i, document := range documents {
+		results[i] = rerankResult{Index: i, RelevanceScore: RerankScore(query, document)}
+		tokens += utf8.RuneCountInString(document)
+	}
+	// 与线上接口一致，按分数从高到低返回
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].RelevanceScore > results[j].RelevanceScore
+	})
+
+	usage := map[string]int{"total_tokens": tokens}
+	if dashScope {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"output":     map[string]interface{}{"results": results},
+			"usage":      usage,
+			"request_id": requestID,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"model":   req.Model,
+		"results": results,
+		"usage":   usage,
+	})
+}
+
+// RerankScore 确定性的重排分数：查询特征在文本中出现的比例，取值 [0, 1]
+// 特征与 HashEmbedding 相同，共享词语越多分数越高
+func RerankScore(query, document string) float32 {
+	queryFeatures := features(query)
+	if len(queryFeatures) == 0 {
+		return 0
+	}
+	present := make(map[string]bool)
+	for _, feature := range features(document) {
+		present[feature] = true
+	}
+	matched := 0
+	for _, feature := range queryFeatures {
+		if present[feature] {
+			matched++
+		}
+	}
+	return float32(matched) / float32(len(queryFeatures))
+}
@@ -0,0 +1,279 @@
+// Package mockllm 本地模拟的大模型和向量化服务，用于离线集成测试
+//
+// 支持 DashScope 原生生成接口（含 SSE 流式和 tool_calls）、OpenAI 兼容的 /embeddings 接口，
+// 以及 DashScope 和 Jina/Cohere 兼容的重排接口。回复按规则脚本确定性生成，向量按文本哈希生成，
+// 同一文本总是得到同一向量，重排分数按查询与文本共享的词语计算。
+//
+// 用法：
+//
+//	srv := mockllm.NewServer(mockllm.Options{Dimension: 1024})
+//	defer srv.Close()
+//	srv.AddRule(mockllm.Rule{Match: "天气", ToolCalls: []mockllm.ToolCall{{Name: "get_weather", Arguments: `{"city":"北京"}`}}, Content: "北京今天晴"})
+//	config.GlobalConfig = &config.Config{}
+//	srv.Configure(config.GlobalConfig)
+package mockllm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"server/framework/config"
+)
+
+// 接口路径，按后缀匹配，兼容任意 base_url 前缀
+const (
+	GenerationPath = "/services/aigc/text-generation/generation"
+	EmbeddingsPath = "/embeddings"
+	RerankPath     = "/rerank"                                  // Jina/Cohere 兼容
+	TextRerankPath = "/services/rerank/text-rerank/text-rerank" // DashScope
+
+	// Configure 使用的 base_url 前缀，与 DashScope 线上地址保持一致
+	dashScopePrefix  = "/api/v1"
+	compatiblePrefix = "/compatible-mode/v1"
+)
+
+// 默认配置
+const (
+	defaultDimension = 1024
+	defaultChunkSize = 4
+	defaultAPIKey    = "mock-api-key"
+	defaultModel     = "qwen-mock"
+)
+
+// Options 模拟服务配置
+type Options struct {
+	APIKey       string // 校验请求的 Bearer Token，为空时使用 mock-api-key
+	Dimension    int    // 默认向量维度，请求中的 dimensions 优先
+	ChunkSize    int    // 流式输出每个分片的字符数
+	DefaultReply string // 没有规则命中时的回复，为空时回显最后一条用户消息
+	MaxBatchSize int    // 向量化接口单次请求最多的文本数，超出时返回 400，为 0 时不限制
+}
+
+// ToolCall 规则返回的工具调用
+type ToolCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON 字符串
+}
+
+// Rule 回复规则，按添加顺序匹配，第一个命中的规则生效
+type Rule struct {
+	Match       string     `json:"match"`        // 最后一条用户消息包含该子串时命中，为空时不限制
+	MatchSystem string     `json:"match_system"` // 系统消息包含该子串时命中，为空时不限制，用于区分意图、摘要等单轮调用
+	ToolCalls   []ToolCall `json:"tool_calls"`   // 请求带工具且尚未返回工具结果时返回工具调用
+	Content     string     `json:"content"`      // 回复内容，工具调用结束后也返回该内容
+	Status      int        `json:"status"`       // 非 0 时直接返回该 HTTP 状态码，用于模拟限流和故障
+}
+
+// RecordedRequest 收到的请求，用于断言
+type RecordedRequest struct {
+	Path     string
+	Model    string
+	Stream   bool
+	Messages []Message // 生成接口的消息
+	Tools    []string  // 生成接口的工具名称
+	Input    []string  // 向量化接口的文本，重排接口为查询和候选文本
+}
+
+// Server 模拟服务
+type Server struct {
+	opts   Options
+	server *httptest.Server
+
+	mu         sync.Mutex
+	rules      []Rule
+	failStatus int
+	failCount  int
+	requests   []RecordedRequest
+	sequence   int
+}
+
+// NewServer 创建并启动模拟服务，监听本地随机端口
+func NewServer(opts Options) *Server {
+	s := newServer(opts)
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// NewUnstartedServer 创建模拟服务但不监听端口，可作为 http.Handler 挂到其他服务上
+func NewUnstartedServer(opts Options) *Server {
+	return newServer(opts)
+}
+
+func newServer(opts Options) *Server {
+	if opts.APIKey == "" {
+		opts.APIKey = defaultAPIKey
+	}
+	if opts.Dimension <= 0 {
+		opts.Dimension = defaultDimension
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultChunkSize
+	}
+	return &Server{opts: opts}
+}
+
+// URL 服务地址，NewUnstartedServer 创建时为空
+func (s *Server) URL() string {
+	if s.server == nil {
+		return ""
+	}
+	return s.server.URL
+}
+
+// Close 关闭服务
+func (s *Server) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// Configure 将配置中的对话模型、向量化模型和重排模型指向模拟服务，不需要环境变量中的 API Key
+func (s *Server) Configure(cfg *config.Config) {
+	chatModel := &cfg.AI.ChatModel
+	chatModel.Provider = "dashscope"
+	chatModel.BaseURL = s.URL() + dashScopePrefix
+	chatModel.APIKey = s.opts.APIKey
+	if chatModel.ModelName == "" {
+		chatModel.ModelName = defaultModel
+	}
+
+	embeddingModel := &cfg.AI.EmbeddingModel
+	embeddingModel.Provider = "dashscope"
+	embeddingModel.BaseURL = s.URL() + compatiblePrefix
+	embeddingModel.APIKey = s.opts.APIKey
+	embeddingModel.Dimension = s.opts.Dimension
+	if s.opts.MaxBatchSize > 0 {
+		embeddingModel.MaxBatchSize = s.opts.MaxBatchSize
+	}
+	if embeddingModel.ModelName == "" {
+		embeddingModel.ModelName = "text-embedding-mock"
+	}
+
+	reranker := &cfg.AI.Reranker
+	reranker.Provider = "dashscope"
+	reranker.BaseURL = s.URL() + dashScopePrefix
+	reranker.APIKey = s.opts.APIKey
+	if reranker.ModelName == "" {
+		reranker.ModelName = "rerank-mock"
+	}
+}
+
+// AddRule 添加回复规则
+func (s *Server) AddRule(rule Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rules = append(s.rules, rule)
+}
+
+// FailNext 接下来的 count 个请求返回 status，用于测试重试、切换和熔断
+func (s *Server) FailNext(status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failStatus = status
+	s.failCount = count
+}
+
+// Requests 返回已收到的请求
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// Reset 清空规则、故障设置和请求记录
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rules = nil
+	s.failStatus = 0
+	s.failCount = 0
+	s.requests = nil
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "只支持 POST 请求")
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.opts.APIKey {
+		writeError(w, http.StatusUnauthorized, "InvalidApiKey", "Invalid API-key provided.")
+		return
+	}
+	if status := s.takeFailure(); status != 0 {
+		writeError(w, status, http.StatusText(status), "mock failure")
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, GenerationPath):
+		s.handleGeneration(w, r)
+	case strings.HasSuffix(r.URL.Path, EmbeddingsPath):
+		s.handleEmbeddings(w, r)
+	case strings.HasSuffix(r.URL.Path, TextRerankPath):
+		s.handleRerank(w, r, true)
+	case strings.HasSuffix(r.URL.Path, RerankPath):
+		s.handleRerank(w, r, false)
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "未知接口: "+r.URL.Path)
+	}
+}
+
+func (s *Server) takeFailure() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failCount <= 0 {
+		return 0
+	}
+	s.failCount--
+	return s.failStatus
+}
+
+func (s *Server) record(req RecordedRequest) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	s.sequence++
+	return fmt.Sprintf("mock-%d", s.sequence)
+}
+
+// match 返回第一个命中的规则
+func (s *Server) match(system, user string) (Rule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rule := range s.rules {
+		if rule.Match != "" && !strings.Contains(user, rule.Match) {
+			continue
+		}
+		if rule.MatchSystem != "" && !strings.Contains(system, rule.MatchSystem) {
+			continue
+		}
+		return rule, true
+	}
+	return Rule{}, false
+}
+
+// writeError 按 DashScope 的格式返回错误
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{
+		"code":    code,
+		"message": message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
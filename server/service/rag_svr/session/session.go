@@ -9,7 +9,7 @@ import (
 
 // GetSessionInfo 获取会话信息
 func GetSessionInfo(ctx context.Context, sessionID uint64) (map[string]interface{}, error) {
-	var session mysql.Session
+	var session mysql.ChatSession
 	if err := mysql.GetDB().Where("id = ?", sessionID).First(&session).Error; err != nil {
 		return nil, fmt.Errorf("获取会话信息失败: %v", err)
 	}
@@ -27,8 +27,8 @@ func GetSessionInfo(ctx context.Context, sessionID uint64) (map[string]interface
 // AddMessage 添加消息到会话
 func AddMessage(ctx context.Context, sessionID uint64, message *mysql.ChatRecord) error {
 	// 验证会话是否存在
-	var session mysql.Session
-	if err := mysql.GetDB().Model(&mysql.Session{}).Where("id = ?", sessionID).First(&session).Error; err != nil {
+	var session mysql.ChatSession
+	if err := mysql.GetDB().Model(&mysql.ChatSession{}).Where("id = ?", sessionID).First(&session).Error; err != nil {
 		return fmt.Errorf("会话不存在: %v", err)
 	}
 
@@ -41,7 +41,7 @@ func AddMessage(ctx context.Context, sessionID uint64, message *mysql.ChatRecord
 	}
 
 	// 更新会话的最后活动时间
-	if err := mysql.GetDB().Model(&mysql.Session{}).Where("id = ?", sessionID).
+	if err := mysql.GetDB().Model(&mysql.ChatSession{}).Where("id = ?", sessionID).
 		Update("last_active_time", message.CreatedAt).Error; err != nil {
 		return fmt.Errorf("更新会话活动时间失败: %v", err)
 	}
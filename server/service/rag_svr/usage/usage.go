@@ -0,0 +1,251 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/framework/config"
+	"server/framework/logger"
+	"server/framework/mysql"
+	"server/framework/redis"
+)
+
+// 调用类型
+const (
+	CallTypeChat      = "chat"      // 对话
+	CallTypeIntent    = "intent"    // 意图分析
+	CallTypeSentiment = "sentiment" // 情感分析
+	CallTypeAnalysis  = "analysis"  // 意图和情感合并分析
+	CallTypeSummary   = "summary"   // 摘要
+	CallTypeTitle     = "title"     // 会话标题
+	CallTypeEmbedding = "embedding" // 向量化
+	CallTypeRerank    = "rerank"    // 检索结果重排
+)
+
+const (
+	dailyCounterPrefix   = "usage:daily:"
+	monthlyCounterPrefix = "usage:monthly:"
+	dailyCounterTTL      = 48 * time.Hour
+	monthlyCounterTTL    = 32 * 24 * time.Hour
+)
+
+// ErrQuotaExceeded 用户 token 配额已用完
+var ErrQuotaExceeded = errors.New("token 配额已用完")
+
+type scopeKey struct{}
+
+type callTypeKey struct{}
+
+// scope 用量归属
+type scope struct {
+	userID    uint64
+	sessionID uint64
+}
+
+// WithScope 在 ctx 中记录用量归属的用户和会话
+func WithScope(ctx context.Context, userID, sessionID uint64) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{userID: userID, sessionID: sessionID})
+}
+
+// WithUserScope 在 ctx 中记录用量归属的用户，ctx 已归属到该用户时保留原有的会话
+func WithUserScope(ctx context.Context, userID uint64) context.Context {
+	if s, ok := ctx.Value(scopeKey{}).(scope); ok && s.userID == userID {
+		return ctx
+	}
+	return WithScope(ctx, userID, 0)
+}
+
+// WithCallType 在 ctx 中指定调用类型，覆盖模型客户端的默认类型
+func WithCallType(ctx context.Context, callType string) context.Context {
+	return context.WithValue(ctx, callTypeKey{}, callType)
+}
+
+// Record 记录一次模型调用的用量，写入 model_usage 表并累加 Redis 计数
+// 失败只打日志，不影响业务
+func Record(ctx context.Context, callType, model string, inputTokens, outputTokens, totalTokens int) {
+	// 离线测试等未初始化存储的场景不记录
+	if mysql.GetDB() == nil {
+		return
+	}
+
+	// 流式请求结束时调用方 ctx 可能已取消，用量仍需落库
+	ctx = context.WithoutCancel(ctx)
+
+	s, _ := ctx.Value(scopeKey{}).(scope)
+	if t, ok := ctx.Value(callTypeKey{}).(string); ok && t != "" {
+		callType = t
+	}
+	if totalTokens == 0 {
+		totalTokens = inputTokens + outputTokens
+	}
+
+	record := &mysql.ModelUsage{
+		UserID:       s.userID,
+		SessionID:    s.sessionID,
+		Model:        model,
+		CallType:     callType,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		TotalTokens:  totalTokens,
+	}
+	if err := mysql.GetDB().WithContext(ctx).Create(record).Error; err != nil {
+		logger.Errorf("保存模型用量失败: user_id=%d, call_type=%s, err=%v", s.userID, callType, err)
+	}
+
+	// 未归属到用户的调用不计入配额
+	if s.userID == 0 || totalTokens == 0 {
+		return
+	}
+	now := time.Now()
+	dayStart, monthStart := periodStarts(now)
+	if err := incrCounter(ctx, dailyKey(s.userID, now), s.userID, dayStart, record.ID, int64(totalTokens), dailyCounterTTL); err != nil {
+		logger.Errorf("累加每日用量失败: user_id=%d, err=%v", s.userID, err)
+	}
+	if err := incrCounter(ctx, monthlyKey(s.userID, now), s.userID, monthStart, record.ID, int64(totalTokens), monthlyCounterTTL); err != nil {
+		logger.Errorf("累加每月用量失败: user_id=%d, err=%v", s.userID, err)
+	}
+}
+
+// Quota 返回配置的每日和每月配额，0 表示不限制
+func Quota() (daily, monthly int64) {
+	if config.GlobalConfig == nil {
+		return 0, 0
+	}
+	return config.GlobalConfig.AI.Quota.DailyTokens, config.GlobalConfig.AI.Quota.MonthlyTokens
+}
+
+// CheckQuota 检查用户是否超出配额，超出时返回包装了 ErrQuotaExceeded 的错误
+func CheckQuota(ctx context.Context, userID uint64) error {
+	dailyQuota, monthlyQuota := Quota()
+	if dailyQuota <= 0 && monthlyQuota <= 0 {
+		return nil
+	}
+
+	daily, monthly, err := GetCounters(ctx, userID)
+	if err != nil {
+		// 计数不可用时放行，避免 Redis 故障导致对话不可用
+		logger.Errorf("获取用户用量失败，跳过配额检查: user_id=%d, err=%v", userID, err)
+		return nil
+	}
+	if dailyQuota > 0 && daily >= dailyQuota {
+		return fmt.Errorf("%w: 今日已使用 %d tokens，配额 %d", ErrQuotaExceeded, daily, dailyQuota)
+	}
+	if monthlyQuota > 0 && monthly >= monthlyQuota {
+		return fmt.Errorf("%w: 本月已使用 %d tokens，配额 %d", ErrQuotaExceeded, monthly, monthlyQuota)
+	}
+	return nil
+}
+
+// GetCounters 获取用户今日和本月已使用的 token 数
+func GetCounters(ctx context.Context, userID uint64) (daily, monthly int64, err error) {
+	now := time.Now()
+	dayStart, monthStart := periodStarts(now)
+
+	daily, err = getCounter(ctx, dailyKey(userID, now), userID, dayStart, dailyCounterTTL)
+	if err != nil {
+		return 0, 0, err
+	}
+	monthly, err = getCounter(ctx, monthlyKey(userID, now), userID, monthStart, monthlyCounterTTL)
+	if err != nil {
+		return 0, 0, err
+	}
+	return daily, monthly, nil
+}
+
+// Stat 按模型和调用类型汇总的用量
+type Stat struct {
+	Model        string
+	CallType     string
+	InputTokens  int64
+	OutputTokens int64
+	TotalTokens  int64
+	CallCount    int64
+}
+
+// Summarize 汇总用户在 [start, end) 时间段内的用量
+func Summarize(ctx context.Context, userID uint64, start, end time.Time) ([]*Stat, error) {
+	stats := make([]*Stat, 0)
+	err := mysql.GetDB().WithContext(ctx).Model(&mysql.ModelUsage{}).
+		Select("model, call_type, SUM(input_tokens) AS input_tokens, SUM(output_tokens) AS output_tokens, SUM(total_tokens) AS total_tokens, COUNT(1) AS call_count").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Group("model, call_type").
+		Order("total_tokens DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("汇总模型用量失败: %v", err)
+	}
+	return stats, nil
+}
+
+// getCounter 读取计数，Redis 中不存在时从 model_usage 表重建
+func getCounter(ctx context.Context, key string, userID uint64, since time.Time, ttl time.Duration) (int64, error) {
+	value, err := redis.Get(ctx, key)
+	if err == nil {
+		return strconv.ParseInt(value, 10, 64)
+	}
+	if !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+
+	total, err := sumUsage(ctx, userID, since, 0)
+	if err != nil {
+		return 0, err
+	}
+	// 期间已有新的累加时以 Redis 为准
+	if _, err := redis.SetNX(ctx, key, total, ttl); err != nil {
+		logger.Warnf("重建用量计数失败: key=%s, err=%v", key, err)
+	}
+	return total, nil
+}
+
+// sumUsage 统计用户自 since 起的 token 数，excludeID 不为 0 时排除该条记录
+func sumUsage(ctx context.Context, userID uint64, since time.Time, excludeID uint64) (int64, error) {
+	var total int64
+	if err := mysql.GetDB().WithContext(ctx).Model(&mysql.ModelUsage{}).
+		Select("COALESCE(SUM(total_tokens), 0)").
+		Where("user_id = ? AND created_at >= ? AND id <> ?", userID, since, excludeID).
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("统计模型用量失败: %v", err)
+	}
+	return total, nil
+}
+
+// incrCounter 累加计数；计数已过期或被淘汰时先从 model_usage 表重建，
+// 否则 IncrBy 会新建一个只有本次用量的计数，使配额检查失效。
+// 本次调用的记录 recordID 已写入表中，重建时排除，由随后的 IncrBy 计入
+func incrCounter(ctx context.Context, key string, userID uint64, since time.Time, recordID uint64, value int64, ttl time.Duration) error {
+	exists, err := redis.Exists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		total, err := sumUsage(ctx, userID, since, recordID)
+		if err != nil {
+			return err
+		}
+		// 并发重建时只保留第一个
+		if _, err := redis.SetNX(ctx, key, total, ttl); err != nil {
+			return err
+		}
+	}
+	_, err = redis.IncrBy(ctx, key, value)
+	return err
+}
+
+// periodStarts 返回 t 所在日和所在月的起始时间
+func periodStarts(t time.Time) (dayStart, monthStart time.Time) {
+	dayStart = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	monthStart = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return dayStart, monthStart
+}
+
+func dailyKey(userID uint64, t time.Time) string {
+	return fmt.Sprintf("%s%d:%s", dailyCounterPrefix, userID, t.Format("20060102"))
+}
+
+func monthlyKey(userID uint64, t time.Time) string {
+	return fmt.Sprintf("%s%d:%s", monthlyCounterPrefix, userID, t.Format("200601"))
+}